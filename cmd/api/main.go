@@ -2,20 +2,25 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"os/signal"
-	"strconv"
+	"sync"
 	"syscall"
 	"time"
+	_ "time/tzdata" // availability timezones resolve on images without zoneinfo
 
+	"DBackend/internal/config"
+	"DBackend/internal/logging"
 	"DBackend/internal/server"
+	"DBackend/internal/tracing"
 
 	_ "github.com/joho/godotenv/autoload"
 )
 
-func gracefulShutdown(fiberServer *server.FiberServer, done chan bool) {
+func gracefulShutdown(fiberServer *server.FiberServer, stopBackground, shutdownTracing func(context.Context) error, done chan bool) {
 	// Create context that listens for the interrupt signal from the OS.
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -23,24 +28,78 @@ func gracefulShutdown(fiberServer *server.FiberServer, done chan bool) {
 	// Listen for the interrupt signal.
 	<-ctx.Done()
 
-	log.Println("shutting down gracefully, press Ctrl+C again to force")
+	slog.Info("shutting down gracefully, press Ctrl+C again to force")
 
 	// The context is used to inform the server it has 5 seconds to finish
 	// the request it is currently handling
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := fiberServer.ShutdownWithContext(ctx); err != nil {
-		log.Printf("Server forced to shutdown with error: %v", err)
+		slog.Error("server forced to shutdown", "error", err)
+	}
+	// Interrupt running jobs and let them record how far they got and give
+	// up their leases, so another replica picks them up without waiting for
+	// the leases to lapse
+	if err := stopBackground(ctx); err != nil {
+		slog.Error("stopping background work", "error", err)
+	}
+	// Flush spans of the requests that just finished
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("flushing traces", "error", err)
 	}
 
-	log.Println("Server exiting")
+	slog.Info("server exiting")
 
 	// Notify the main goroutine that the shutdown is complete
 	done <- true
 }
 
+// runBackground starts the server's background work and returns a func that
+// stops it, waiting for work in progress until its context is done
+func runBackground(s *server.FiberServer) func(context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for _, run := range []func(context.Context){s.RunJobs, s.RunEvents} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run(ctx)
+		}()
+	}
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+	return func(wait context.Context) error {
+		cancel()
+		select {
+		case <-stopped:
+			return nil
+		case <-wait.Done():
+			return wait.Err()
+		}
+	}
+}
+
 func main() {
-	server := server.New()
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "path to an optional YAML config file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logging.New(cfg.Log, os.Stdout))
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, os.Stdout)
+	if err != nil {
+		slog.Error("starting tracing", "error", err)
+		os.Exit(1)
+	}
+
+	server := server.New(cfg)
 
 	server.RegisterFiberRoutes()
 
@@ -48,17 +107,19 @@ func main() {
 	done := make(chan bool, 1)
 
 	go func() {
-		port, _ := strconv.Atoi(os.Getenv("PORT"))
-		err := server.Listen(fmt.Sprintf(":%d", port))
+		slog.Info("starting server", "port", cfg.App.Port, "env", cfg.App.Env)
+		err := server.Listen(fmt.Sprintf(":%d", cfg.App.Port))
 		if err != nil {
 			panic(fmt.Sprintf("http server error: %s", err))
 		}
 	}()
 
+	stopBackground := runBackground(server)
+
 	// Run graceful shutdown in a separate goroutine
-	go gracefulShutdown(server, done)
+	go gracefulShutdown(server, stopBackground, shutdownTracing, done)
 
 	// Wait for the graceful shutdown to complete
 	<-done
-	log.Println("Graceful shutdown complete.")
+	slog.Info("graceful shutdown complete")
 }
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/database"
+
+	_ "github.com/joho/godotenv/autoload"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func main() {
+	down := flag.Int("down", 0, "roll back the given number of applied migrations")
+	status := flag.Bool("status", false, "list migrations and whether they have been applied")
+	timeout := flag.Duration("timeout", 5*time.Minute, "overall time limit")
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "path to an optional YAML config file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	client, err := database.Connect(ctx, cfg.Database)
+	if err != nil {
+		log.Fatalf("connecting to database: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	migrator, err := database.NewMigrator(client.Database(cfg.Database.Name))
+	if err != nil {
+		log.Fatalf("loading migrations: %v", err)
+	}
+
+	switch {
+	case *status:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("reading migration status: %v", err)
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%4d  %-25s  %s\n", s.Version, applied, s.Description)
+		}
+	case *down > 0:
+		reverted, err := migrator.Down(ctx, *down)
+		report("rolled back", reverted, err)
+	default:
+		applied, err := migrator.Up(ctx)
+		report("applied", applied, err)
+	}
+}
+
+func report(action string, versions []int, err error) {
+	if len(versions) == 0 && err == nil {
+		fmt.Println("nothing to do")
+	}
+	for _, v := range versions {
+		fmt.Printf("%s migration %d\n", action, v)
+	}
+	if err != nil {
+		log.Printf("migration failed: %v", err)
+		if mongo.IsDuplicateKeyError(err) {
+			log.Print("existing documents share the duplicate key above, so its unique index cannot be built; merge or remove them and run the migrations again")
+		}
+		os.Exit(1)
+	}
+}
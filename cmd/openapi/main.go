@@ -0,0 +1,29 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"DBackend/internal/openapi"
+)
+
+func main() {
+	out := flag.String("o", "", "write the document to this file instead of stdout")
+	flag.Parse()
+
+	data, err := json.MarshalIndent(openapi.Spec(), "", "  ")
+	if err != nil {
+		log.Fatalf("encoding document: %v", err)
+	}
+	data = append(data, '\n')
+
+	if *out == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		log.Fatalf("writing %s: %v", *out, err)
+	}
+}
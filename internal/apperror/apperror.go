@@ -0,0 +1,130 @@
+// Package apperror defines the domain errors shared by the database layer and
+// the HTTP handlers. Each error carries a Kind, which decides the HTTP status,
+// and a stable machine-readable Code that clients can switch on.
+package apperror
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Kind classifies a domain error
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindRateLimited
+)
+
+// Error is a domain error. Message is safe to show to clients; Err is the
+// underlying cause and is only logged.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Err     error
+	// Fields lists the invalid request fields of a validation error
+	Fields []FieldError
+	// RetryAfter tells a rate limited client when to try again
+	RetryAfter time.Duration
+	// Details is machine-readable context for the client, such as the
+	// meetings a scheduling conflict is with
+	Details interface{}
+}
+
+// FieldError describes why one request field is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NotFound reports a missing resource
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+// Conflict reports a write that clashes with existing state
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// TooManyRequests reports a client over a rate limit or a locked account,
+// which may try again after retryAfter
+func TooManyRequests(code, message string, retryAfter time.Duration) *Error {
+	return &Error{Kind: KindRateLimited, Code: code, Message: message, RetryAfter: retryAfter}
+}
+
+// Validation reports a malformed or invalid request
+func Validation(code, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+// Invalid reports a request body whose fields failed validation
+func Invalid(fields []FieldError) *Error {
+	messages := make([]string, len(fields))
+	for i, f := range fields {
+		messages[i] = f.Field + " " + f.Message
+	}
+	return &Error{
+		Kind:    KindValidation,
+		Code:    "validation_failed",
+		Message: strings.Join(messages, "; "),
+		Fields:  fields,
+	}
+}
+
+// Unauthorized reports missing or invalid credentials
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+// Forbidden reports an authenticated caller without access
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+// Internal reports an unexpected failure with an optional cause
+func Internal(message string, cause error) *Error {
+	return &Error{Kind: KindInternal, Code: "internal", Message: message, Err: cause}
+}
+
+// Wrap returns err unchanged when it already is a domain error, so not found
+// and conflict errors from the database keep their meaning, and otherwise
+// wraps it as an internal error described by message
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return err
+	}
+	return Internal(message, err)
+}
+
+// KindOf returns the kind of err, or KindInternal for non-domain errors
+func KindOf(err error) Kind {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Kind
+	}
+	return KindInternal
+}
@@ -0,0 +1,28 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	notFound := NotFound("deal_not_found", "Deal not found")
+	if got := Wrap(fmt.Errorf("lookup: %w", notFound), "Failed to load deal"); !errors.Is(got, notFound) || KindOf(got) != KindNotFound {
+		t.Errorf("Wrap(domain error) = %v, want it to stay a not found error", got)
+	}
+
+	cause := errors.New("connection reset")
+	got := Wrap(cause, "Failed to load deal")
+	var appErr *Error
+	if !errors.As(got, &appErr) || appErr.Kind != KindInternal || appErr.Message != "Failed to load deal" {
+		t.Fatalf("Wrap(plain error) = %#v, want an internal error with the given message", got)
+	}
+	if !errors.Is(got, cause) {
+		t.Errorf("Wrap(plain error) does not unwrap to its cause")
+	}
+
+	if Wrap(nil, "unused") != nil {
+		t.Errorf("Wrap(nil) != nil")
+	}
+}
@@ -0,0 +1,653 @@
+// Package config loads the application configuration from defaults, an
+// optional YAML file and environment variables (in increasing precedence)
+// into a typed struct, and validates it before anything else starts.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"DBackend/internal/cron"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultJWTSecret and defaultCalendarTokenKey are only accepted outside production
+const (
+	defaultJWTSecret        = "your-secure-secret-key"
+	defaultCalendarTokenKey = "your-calendar-token-key"
+)
+
+// Config is the complete application configuration
+type Config struct {
+	App       App       `yaml:"app"`
+	Database  Database  `yaml:"database"`
+	CORS      CORS      `yaml:"cors"`
+	ML        ML        `yaml:"ml"`
+	Calendar  Calendar  `yaml:"calendar"`
+	Storage   Storage   `yaml:"storage"`
+	Auth      Auth      `yaml:"auth"`
+	Log       Log       `yaml:"log"`
+	Metrics   Metrics   `yaml:"metrics"`
+	Tracing   Tracing   `yaml:"tracing"`
+	RateLimit RateLimit `yaml:"rate_limit"`
+	Mail      Mail      `yaml:"mail"`
+	Reminders Reminders `yaml:"reminders"`
+	Jobs      Jobs      `yaml:"jobs"`
+	Events    Events    `yaml:"events"`
+}
+
+// App holds HTTP server settings
+type App struct {
+	Env  string `yaml:"env"`
+	Port int    `yaml:"port"`
+}
+
+// Database holds MongoDB connection settings
+type Database struct {
+	Host    string `yaml:"host"`
+	Port    string `yaml:"port"`
+	URI     string `yaml:"uri"` // overrides Host and Port when set
+	Name    string `yaml:"name"`
+	Migrate bool   `yaml:"migrate"` // apply pending migrations at startup; on by default
+}
+
+// CORS holds the cross-origin policy for the API
+type CORS struct {
+	AllowOrigins []string `yaml:"allow_origins"`
+}
+
+// ML holds the matchmaking service client settings
+type ML struct {
+	URL     string        `yaml:"url"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Calendar holds where meeting events are created. The local provider only
+// hands out invites and meeting links, google creates them on the organizer's
+// connected Google calendar and caldav stores them on a CalDAV server.
+type Calendar struct {
+	Provider        string `yaml:"provider"`         // local, google or caldav
+	MeetingURL      string `yaml:"meeting_url"`      // base of the meeting links given out without Google Meet
+	GoogleMeet      bool   `yaml:"google_meet"`      // add a Google Meet conference to Google events
+	CredentialsFile string `yaml:"credentials_file"` // OAuth client JSON from the Google Cloud console
+	RedirectURL     string `yaml:"redirect_url"`     // this API's /calendar/google/callback URL
+	ReturnURL       string `yaml:"return_url"`       // where the browser goes once connected; JSON when empty
+	TokenKey        string `yaml:"token_key"`        // encrypts stored refresh tokens and signs meeting links
+	CalDAV          CalDAV `yaml:"caldav"`
+}
+
+// CalDAV holds the calendar collection meeting events are stored in
+type CalDAV struct {
+	URL      string `yaml:"url"` // collection URL, e.g. https://dav.example.com/calendars/team/meetings/
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// Storage holds where uploaded files are kept
+type Storage struct {
+	UploadDir string `yaml:"upload_dir"`
+}
+
+// Auth holds token signing settings
+type Auth struct {
+	JWTSecret string `yaml:"jwt_secret"`
+}
+
+// Log holds structured logging settings
+type Log struct {
+	Level  string `yaml:"level"`  // debug, info, warn or error
+	Format string `yaml:"format"` // json or text
+}
+
+// Metrics holds the Prometheus endpoint settings
+type Metrics struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`
+}
+
+// Tracing holds OpenTelemetry settings. Spans go to the OTLP/HTTP collector at
+// Endpoint, or to stdout when no endpoint is set.
+type Tracing struct {
+	Enabled     bool    `yaml:"enabled"`
+	Endpoint    string  `yaml:"endpoint"`
+	ServiceName string  `yaml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio"` // share of new traces recorded, 0 to 1
+}
+
+// RateLimit holds the request limits of each route group and the lockout
+// applied to repeated failed logins
+type RateLimit struct {
+	Enabled  bool          `yaml:"enabled"`
+	Store    string        `yaml:"store"` // memory, or mongo to share limits between instances
+	Login    RateLimitRule `yaml:"login"`
+	Register RateLimitRule `yaml:"register"`
+	Writes   RateLimitRule `yaml:"writes"` // POST, PUT, PATCH and DELETE on every other route
+	Lockout  Lockout       `yaml:"lockout"`
+}
+
+// RateLimitRule limits a route group per client IP and per account
+type RateLimitRule struct {
+	PerIP      Bucket `yaml:"per_ip"`
+	PerAccount Bucket `yaml:"per_account"`
+}
+
+// Bucket is a token bucket holding Requests tokens, refilled at Requests per
+// Period. Zero Requests disables it.
+type Bucket struct {
+	Requests int           `yaml:"requests"`
+	Period   time.Duration `yaml:"period"`
+}
+
+// Lockout locks an account for Base after Threshold failed logins within
+// Window, doubling the lock with every further failure up to Max
+type Lockout struct {
+	Threshold int           `yaml:"threshold"` // 0 disables lockout
+	Window    time.Duration `yaml:"window"`
+	Base      time.Duration `yaml:"base"`
+	Max       time.Duration `yaml:"max"`
+}
+
+// Mail holds the SMTP server email is sent through. Without a host, emails
+// are written to the log instead.
+type Mail struct {
+	SMTPHost string `yaml:"smtp_host"`
+	SMTPPort int    `yaml:"smtp_port"`
+	Username string `yaml:"username"` // PLAIN auth is used when set
+	Password string `yaml:"password"`
+	From     string `yaml:"from"` // e.g. DBackend <no-reply@example.com>
+}
+
+// Reminders holds when participants are reminded of upcoming meetings.
+// Before is the default for meetings that do not set their own reminders;
+// jobs.schedules.reminders sets how often due reminders are looked for.
+type Reminders struct {
+	Enabled bool            `yaml:"enabled"`
+	Before  []time.Duration `yaml:"before"`
+}
+
+// JobsOff is the schedule that turns a job off
+const JobsOff = "off"
+
+// Jobs holds the background job scheduler. Every replica runs it, and a
+// lease on each job stored in MongoDB lets one replica at a time run it.
+type Jobs struct {
+	Enabled      bool          `yaml:"enabled"`
+	PollInterval time.Duration `yaml:"poll_interval"` // how often due jobs are looked for
+	Lease        time.Duration `yaml:"lease"`         // how long a job stays taken by a replica that stopped renewing it
+	Timeout      time.Duration `yaml:"timeout"`       // longest a run may take
+	Retries      int           `yaml:"retries"`       // further attempts after a failed run
+	Backoff      time.Duration `yaml:"backoff"`       // wait before the first retry, doubled for each further one
+	Schedules    JobSchedules  `yaml:"schedules"`
+}
+
+// JobSchedules holds when each job runs, as cron expressions in UTC or off
+type JobSchedules struct {
+	Reminders    string `yaml:"reminders"`     // meeting reminders
+	Matching     string `yaml:"matching"`      // match scores of investor and founder pairs
+	Valuations   string `yaml:"valuations"`    // daily portfolio valuation snapshots
+	Deadlines    string `yaml:"deadlines"`     // alerts for tasks and grants about to close
+	TokenCleanup string `yaml:"token_cleanup"` // expired blacklisted tokens
+}
+
+// Each returns the schedule of every job by name
+func (s JobSchedules) Each() map[string]string {
+	return map[string]string{
+		"reminders":     s.Reminders,
+		"matching":      s.Matching,
+		"valuations":    s.Valuations,
+		"deadlines":     s.Deadlines,
+		"token_cleanup": s.TokenCleanup,
+	}
+}
+
+// Events holds delivery of domain events from the outbox to their
+// subscribers. Every replica delivers them, and a lease on each event
+// stored in MongoDB lets one replica at a time deliver it.
+type Events struct {
+	Enabled      bool          `yaml:"enabled"`
+	PollInterval time.Duration `yaml:"poll_interval"` // how often the outbox is looked at for due events
+	Lease        time.Duration `yaml:"lease"`         // how long a replica has to deliver an event it took
+	Timeout      time.Duration `yaml:"timeout"`       // longest one subscriber may take to handle an event
+	Retries      int           `yaml:"retries"`       // further attempts after a failed delivery
+	Backoff      time.Duration `yaml:"backoff"`       // wait before the first retry, doubled for each further one
+}
+
+// Default returns the configuration used when nothing is overridden
+func Default() Config {
+	return Config{
+		App:      App{Env: "development", Port: 8080},
+		Database: Database{Host: "localhost", Port: "27017", Name: "ddb", Migrate: true},
+		CORS:     CORS{AllowOrigins: []string{"http://localhost:3000"}},
+		ML:       ML{URL: "http://127.0.0.1:4040/predict/", Timeout: 10 * time.Second},
+		Calendar: Calendar{
+			Provider:        "local",
+			MeetingURL:      "https://meet.jit.si",
+			GoogleMeet:      true,
+			CredentialsFile: "credentials.json",
+			RedirectURL:     "http://localhost:8080/api/v1/calendar/google/callback",
+			TokenKey:        defaultCalendarTokenKey,
+		},
+		Storage: Storage{UploadDir: "./uploads"},
+		Auth:    Auth{JWTSecret: defaultJWTSecret},
+		Log:     Log{Level: "info", Format: "json"},
+		Metrics: Metrics{Enabled: true, Path: "/metrics"},
+		Tracing: Tracing{ServiceName: "dbackend", SampleRatio: 1},
+		RateLimit: RateLimit{
+			Enabled: true,
+			Store:   "memory",
+			Login: RateLimitRule{
+				PerIP:      Bucket{Requests: 20, Period: time.Minute},
+				PerAccount: Bucket{Requests: 10, Period: time.Minute},
+			},
+			Register: RateLimitRule{
+				PerIP:      Bucket{Requests: 10, Period: time.Hour},
+				PerAccount: Bucket{Requests: 5, Period: time.Hour},
+			},
+			Writes: RateLimitRule{
+				PerIP:      Bucket{Requests: 300, Period: time.Minute},
+				PerAccount: Bucket{Requests: 120, Period: time.Minute},
+			},
+			Lockout: Lockout{Threshold: 5, Window: 15 * time.Minute, Base: time.Minute, Max: time.Hour},
+		},
+		Mail:      Mail{SMTPPort: 587, From: "DBackend <no-reply@localhost>"},
+		Reminders: Reminders{Enabled: true, Before: []time.Duration{24 * time.Hour, time.Hour}},
+		Jobs: Jobs{
+			Enabled:      true,
+			PollInterval: 15 * time.Second,
+			Lease:        time.Minute,
+			Timeout:      10 * time.Minute,
+			Retries:      3,
+			Backoff:      30 * time.Second,
+			Schedules: JobSchedules{
+				Reminders:    "* * * * *",
+				Matching:     "0 2 * * *",
+				Valuations:   "0 0 * * *",
+				Deadlines:    "0 * * * *",
+				TokenCleanup: "30 3 * * *",
+			},
+		},
+		Events: Events{
+			Enabled:      true,
+			PollInterval: time.Second,
+			Lease:        time.Minute,
+			Timeout:      10 * time.Second,
+			Retries:      8,
+			Backoff:      5 * time.Second,
+		},
+	}
+}
+
+// Load builds the configuration from defaults, the YAML file at path (skipped
+// when path is empty) and the environment, then validates it.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("config: reading %s: %w", path, err)
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("config: parsing %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// applyEnv overrides fields from environment variables that are set
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	var errs []error
+	str := func(key string, dst *string) {
+		if v, ok := lookup(key); ok && v != "" {
+			*dst = v
+		}
+	}
+	integer := func(key string, dst *int) {
+		if v, ok := lookup(key); ok && v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not an integer", key, v))
+				return
+			}
+			*dst = n
+		}
+	}
+	boolean := func(key string, dst *bool) {
+		if v, ok := lookup(key); ok && v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a boolean", key, v))
+				return
+			}
+			*dst = b
+		}
+	}
+	number := func(key string, dst *float64) {
+		if v, ok := lookup(key); ok && v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a number", key, v))
+				return
+			}
+			*dst = f
+		}
+	}
+	duration := func(key string, dst *time.Duration) {
+		if v, ok := lookup(key); ok && v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a duration", key, v))
+				return
+			}
+			*dst = d
+		}
+	}
+	list := func(key string, dst *[]string) {
+		if v, ok := lookup(key); ok && v != "" {
+			var items []string
+			for _, item := range strings.Split(v, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			*dst = items
+		}
+	}
+	durations := func(key string, dst *[]time.Duration) {
+		var items []string
+		list(key, &items)
+		if items == nil {
+			return
+		}
+		parsed := make([]time.Duration, 0, len(items))
+		for _, item := range items {
+			d, err := time.ParseDuration(item)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a duration", key, item))
+				return
+			}
+			parsed = append(parsed, d)
+		}
+		*dst = parsed
+	}
+
+	str("APP_ENV", &c.App.Env)
+	integer("PORT", &c.App.Port)
+	str("BLUEPRINT_DB_HOST", &c.Database.Host)
+	str("BLUEPRINT_DB_PORT", &c.Database.Port)
+	str("BLUEPRINT_DB_URI", &c.Database.URI)
+	str("BLUEPRINT_DB_DATABASE", &c.Database.Name)
+	boolean("BLUEPRINT_DB_MIGRATE", &c.Database.Migrate)
+	list("CORS_ALLOW_ORIGINS", &c.CORS.AllowOrigins)
+	str("ML_SERVICE_URL", &c.ML.URL)
+	duration("ML_SERVICE_TIMEOUT", &c.ML.Timeout)
+	str("CALENDAR_PROVIDER", &c.Calendar.Provider)
+	str("CALENDAR_MEETING_URL", &c.Calendar.MeetingURL)
+	boolean("GOOGLE_MEET_ENABLED", &c.Calendar.GoogleMeet)
+	str("GOOGLE_CREDENTIALS_FILE", &c.Calendar.CredentialsFile)
+	str("GOOGLE_REDIRECT_URL", &c.Calendar.RedirectURL)
+	str("CALENDAR_RETURN_URL", &c.Calendar.ReturnURL)
+	str("CALENDAR_TOKEN_KEY", &c.Calendar.TokenKey)
+	str("CALDAV_URL", &c.Calendar.CalDAV.URL)
+	str("CALDAV_USERNAME", &c.Calendar.CalDAV.Username)
+	str("CALDAV_PASSWORD", &c.Calendar.CalDAV.Password)
+	str("UPLOAD_DIR", &c.Storage.UploadDir)
+	str("JWT_SECRET", &c.Auth.JWTSecret)
+	str("LOG_LEVEL", &c.Log.Level)
+	str("LOG_FORMAT", &c.Log.Format)
+	boolean("METRICS_ENABLED", &c.Metrics.Enabled)
+	str("METRICS_PATH", &c.Metrics.Path)
+	boolean("TRACING_ENABLED", &c.Tracing.Enabled)
+	str("OTEL_EXPORTER_OTLP_ENDPOINT", &c.Tracing.Endpoint)
+	str("OTEL_SERVICE_NAME", &c.Tracing.ServiceName)
+	number("TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio)
+	boolean("RATE_LIMIT_ENABLED", &c.RateLimit.Enabled)
+	str("RATE_LIMIT_STORE", &c.RateLimit.Store)
+	str("SMTP_HOST", &c.Mail.SMTPHost)
+	integer("SMTP_PORT", &c.Mail.SMTPPort)
+	str("SMTP_USERNAME", &c.Mail.Username)
+	str("SMTP_PASSWORD", &c.Mail.Password)
+	str("MAIL_FROM", &c.Mail.From)
+	boolean("REMINDERS_ENABLED", &c.Reminders.Enabled)
+	durations("REMINDERS_BEFORE", &c.Reminders.Before)
+	boolean("JOBS_ENABLED", &c.Jobs.Enabled)
+	duration("JOBS_POLL_INTERVAL", &c.Jobs.PollInterval)
+	duration("JOBS_LEASE", &c.Jobs.Lease)
+	duration("JOBS_TIMEOUT", &c.Jobs.Timeout)
+	integer("JOBS_RETRIES", &c.Jobs.Retries)
+	duration("JOBS_BACKOFF", &c.Jobs.Backoff)
+	str("JOBS_REMINDERS", &c.Jobs.Schedules.Reminders)
+	str("JOBS_MATCHING", &c.Jobs.Schedules.Matching)
+	str("JOBS_VALUATIONS", &c.Jobs.Schedules.Valuations)
+	str("JOBS_DEADLINES", &c.Jobs.Schedules.Deadlines)
+	str("JOBS_TOKEN_CLEANUP", &c.Jobs.Schedules.TokenCleanup)
+	boolean("EVENTS_ENABLED", &c.Events.Enabled)
+	duration("EVENTS_POLL_INTERVAL", &c.Events.PollInterval)
+	duration("EVENTS_LEASE", &c.Events.Lease)
+	duration("EVENTS_TIMEOUT", &c.Events.Timeout)
+	integer("EVENTS_RETRIES", &c.Events.Retries)
+	duration("EVENTS_BACKOFF", &c.Events.Backoff)
+
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid setting at once
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(field, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	switch c.App.Env {
+	case "development", "test", "staging", "production":
+	default:
+		invalid("app.env", "must be development, test, staging or production, got %q", c.App.Env)
+	}
+	if c.App.Port <= 0 || c.App.Port > 65535 {
+		invalid("app.port", "must be between 1 and 65535, got %d", c.App.Port)
+	}
+
+	if c.Database.URI == "" {
+		if c.Database.Host == "" {
+			invalid("database.host", "is required when database.uri is not set")
+		}
+		if p, err := strconv.Atoi(c.Database.Port); err != nil || p <= 0 || p > 65535 {
+			invalid("database.port", "must be a port number, got %q", c.Database.Port)
+		}
+	} else if !strings.HasPrefix(c.Database.URI, "mongodb://") && !strings.HasPrefix(c.Database.URI, "mongodb+srv://") {
+		invalid("database.uri", "must start with mongodb:// or mongodb+srv://")
+	}
+	if c.Database.Name == "" {
+		invalid("database.name", "is required")
+	}
+
+	if len(c.CORS.AllowOrigins) == 0 {
+		invalid("cors.allow_origins", "needs at least one origin")
+	}
+	for _, origin := range c.CORS.AllowOrigins {
+		if origin == "*" {
+			invalid("cors.allow_origins", "cannot be * because credentials are allowed")
+		} else if !isHTTPURL(origin) {
+			invalid("cors.allow_origins", "%q is not an http(s) origin", origin)
+		}
+	}
+
+	if !isHTTPURL(c.ML.URL) {
+		invalid("ml.url", "%q is not an http(s) URL", c.ML.URL)
+	}
+	if c.ML.Timeout <= 0 {
+		invalid("ml.timeout", "must be positive")
+	}
+
+	switch c.Calendar.Provider {
+	case "local", "google":
+	case "caldav":
+		if !isHTTPURL(c.Calendar.CalDAV.URL) {
+			invalid("calendar.caldav.url", "%q is not an http(s) URL", c.Calendar.CalDAV.URL)
+		}
+	default:
+		invalid("calendar.provider", "must be local, google or caldav, got %q", c.Calendar.Provider)
+	}
+	if !isHTTPURL(c.Calendar.MeetingURL) {
+		invalid("calendar.meeting_url", "%q is not an http(s) URL", c.Calendar.MeetingURL)
+	}
+	if c.Calendar.Provider == "google" && c.Calendar.CredentialsFile == "" {
+		invalid("calendar.credentials_file", "is required with the google provider")
+	}
+	if !isHTTPURL(c.Calendar.RedirectURL) {
+		invalid("calendar.redirect_url", "%q is not an http(s) URL", c.Calendar.RedirectURL)
+	}
+	if c.Calendar.ReturnURL != "" && !isHTTPURL(c.Calendar.ReturnURL) {
+		invalid("calendar.return_url", "%q is not an http(s) URL", c.Calendar.ReturnURL)
+	}
+	if c.Calendar.TokenKey == "" {
+		invalid("calendar.token_key", "is required")
+	} else if c.App.Env == "production" && (c.Calendar.TokenKey == defaultCalendarTokenKey || len(c.Calendar.TokenKey) < 32) {
+		invalid("calendar.token_key", "must be set to a secret of at least 32 characters in production")
+	}
+
+	if c.Storage.UploadDir == "" {
+		invalid("storage.upload_dir", "is required")
+	}
+
+	if c.Auth.JWTSecret == "" {
+		invalid("auth.jwt_secret", "is required")
+	} else if c.App.Env == "production" && (c.Auth.JWTSecret == defaultJWTSecret || len(c.Auth.JWTSecret) < 32) {
+		invalid("auth.jwt_secret", "must be set to a secret of at least 32 characters in production")
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		invalid("log.level", "must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	switch c.Log.Format {
+	case "json", "text":
+	default:
+		invalid("log.format", "must be json or text, got %q", c.Log.Format)
+	}
+
+	if c.Metrics.Enabled && !strings.HasPrefix(c.Metrics.Path, "/") {
+		invalid("metrics.path", "must start with /, got %q", c.Metrics.Path)
+	}
+
+	if c.Tracing.Endpoint != "" && !isHTTPURL(c.Tracing.Endpoint) {
+		invalid("tracing.endpoint", "%q is not an http(s) URL", c.Tracing.Endpoint)
+	}
+	if c.Tracing.Enabled && c.Tracing.ServiceName == "" {
+		invalid("tracing.service_name", "is required when tracing is enabled")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		invalid("tracing.sample_ratio", "must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+	}
+
+	switch c.RateLimit.Store {
+	case "memory", "mongo":
+	default:
+		invalid("rate_limit.store", "must be memory or mongo, got %q", c.RateLimit.Store)
+	}
+	for name, rule := range map[string]RateLimitRule{
+		"login": c.RateLimit.Login, "register": c.RateLimit.Register, "writes": c.RateLimit.Writes,
+	} {
+		for scope, b := range map[string]Bucket{"per_ip": rule.PerIP, "per_account": rule.PerAccount} {
+			if b.Requests < 0 || (b.Requests > 0 && b.Period <= 0) {
+				invalid("rate_limit."+name+"."+scope, "needs non-negative requests and a positive period")
+			}
+		}
+	}
+	if l := c.RateLimit.Lockout; l.Threshold < 0 {
+		invalid("rate_limit.lockout.threshold", "must not be negative")
+	} else if l.Threshold > 0 && (l.Window <= 0 || l.Base <= 0 || l.Max < l.Base) {
+		invalid("rate_limit.lockout", "needs a positive window and base, and max of at least base")
+	}
+
+	if c.Mail.SMTPHost != "" && (c.Mail.SMTPPort <= 0 || c.Mail.SMTPPort > 65535) {
+		invalid("mail.smtp_port", "must be between 1 and 65535, got %d", c.Mail.SMTPPort)
+	}
+	if _, err := mail.ParseAddress(c.Mail.From); err != nil {
+		invalid("mail.from", "%q is not an email address", c.Mail.From)
+	}
+
+	if len(c.Reminders.Before) > 5 {
+		invalid("reminders.before", "allows at most 5 reminders, got %d", len(c.Reminders.Before))
+	}
+	for _, d := range c.Reminders.Before {
+		if d < time.Minute || d > 4*7*24*time.Hour {
+			invalid("reminders.before", "must be between 1m and 672h, got %v", d)
+		}
+	}
+
+	if c.Jobs.Enabled {
+		if c.Jobs.PollInterval <= 0 {
+			invalid("jobs.poll_interval", "must be positive")
+		}
+		if c.Jobs.Lease < 3*c.Jobs.PollInterval {
+			invalid("jobs.lease", "must be at least three poll intervals, got %v", c.Jobs.Lease)
+		}
+		if c.Jobs.Timeout <= 0 {
+			invalid("jobs.timeout", "must be positive")
+		}
+	}
+	if c.Jobs.Retries < 0 || c.Jobs.Retries > 10 {
+		invalid("jobs.retries", "must be between 0 and 10, got %d", c.Jobs.Retries)
+	} else if c.Jobs.Retries > 0 && c.Jobs.Backoff <= 0 {
+		invalid("jobs.backoff", "must be positive when failed runs are retried")
+	}
+	for name, schedule := range c.Jobs.Schedules.Each() {
+		if schedule == JobsOff {
+			continue
+		}
+		if _, err := cron.Parse(schedule); err != nil {
+			invalid("jobs.schedules."+name, "%v", err)
+		}
+	}
+
+	if c.Events.Enabled {
+		if c.Events.PollInterval <= 0 {
+			invalid("events.poll_interval", "must be positive")
+		}
+		if c.Events.Timeout <= 0 {
+			invalid("events.timeout", "must be positive")
+		}
+		if c.Events.Lease <= c.Events.Timeout {
+			invalid("events.lease", "must be longer than the timeout, got %v", c.Events.Lease)
+		}
+	}
+	if c.Events.Retries < 0 || c.Events.Retries > 20 {
+		invalid("events.retries", "must be between 0 and 20, got %d", c.Events.Retries)
+	} else if c.Events.Retries > 0 && c.Events.Backoff <= 0 {
+		invalid("events.backoff", "must be positive when failed deliveries are retried")
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// MongoURI returns the connection string for the database
+func (d Database) MongoURI() string {
+	if d.URI != "" {
+		return d.URI
+	}
+	return fmt.Sprintf("mongodb://%s:%s", d.Host, d.Port)
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
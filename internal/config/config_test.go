@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Database.Name == "" || cfg.App.Port == 0 || len(cfg.CORS.AllowOrigins) == 0 {
+		t.Errorf("Load() = %+v, want defaults filled in", cfg)
+	}
+	if !cfg.Database.Migrate {
+		t.Error("migrations are not applied at startup by default")
+	}
+}
+
+func TestLoadFileThenEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := "app:\n  port: 9000\nml:\n  url: http://ml:4040/predict/\n  timeout: 3s\ncors:\n  allow_origins: [\"https://app.example.com\"]\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PORT", "9100")
+	t.Setenv("BLUEPRINT_DB_DATABASE", "from_env")
+	t.Setenv("REMINDERS_BEFORE", "48h, 30m")
+	t.Setenv("JOBS_MATCHING", "off")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.App.Port != 9100 {
+		t.Errorf("port = %d, want env to override file", cfg.App.Port)
+	}
+	if cfg.ML.URL != "http://ml:4040/predict/" || cfg.ML.Timeout != 3*time.Second {
+		t.Errorf("ml = %+v, want values from file", cfg.ML)
+	}
+	if cfg.Database.Name != "from_env" || cfg.CORS.AllowOrigins[0] != "https://app.example.com" {
+		t.Errorf("cfg = %+v, want file and env merged", cfg)
+	}
+	if len(cfg.Reminders.Before) != 2 || cfg.Reminders.Before[0] != 48*time.Hour || cfg.Reminders.Before[1] != 30*time.Minute {
+		t.Errorf("reminders.before = %v, want the env list", cfg.Reminders.Before)
+	}
+	if cfg.Jobs.Schedules.Matching != JobsOff || cfg.Jobs.Schedules.Deadlines != "0 * * * *" {
+		t.Errorf("jobs.schedules = %+v, want matching off and the default deadlines", cfg.Jobs.Schedules)
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	cfg := Default()
+	cfg.App.Env = "production"
+	cfg.App.Port = 0
+	cfg.CORS.AllowOrigins = []string{"*"}
+	cfg.ML.URL = "not a url"
+	cfg.Calendar.Provider = "caldav"
+	cfg.Log.Level = "verbose"
+	cfg.Metrics.Path = "metrics"
+	cfg.Tracing.SampleRatio = 2
+	cfg.RateLimit.Store = "redis"
+	cfg.RateLimit.Login.PerIP.Period = 0
+	cfg.Mail.From = "nobody"
+	cfg.Reminders.Before = []time.Duration{0}
+	cfg.Jobs.Lease = time.Second
+	cfg.Jobs.Schedules.Valuations = "every day"
+	cfg.Events.Lease = cfg.Events.Timeout
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want errors")
+	}
+	for _, field := range []string{"app.port", "cors.allow_origins", "ml.url", "auth.jwt_secret", "calendar.caldav.url", "calendar.token_key", "log.level", "metrics.path", "tracing.sample_ratio",
+		"rate_limit.store", "rate_limit.login.per_ip", "mail.from", "reminders.before", "jobs.lease", "jobs.schedules.valuations", "events.lease"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("Validate() error %q does not mention %s", err, field)
+		}
+	}
+}
+
+func TestLoadRejectsMalformedEnv(t *testing.T) {
+	t.Setenv("ML_SERVICE_TIMEOUT", "soon")
+	if _, err := Load(""); err == nil || !strings.Contains(err.Error(), "ML_SERVICE_TIMEOUT") {
+		t.Errorf("Load() error = %v, want ML_SERVICE_TIMEOUT error", err)
+	}
+}
@@ -0,0 +1,46 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	from := time.Date(2026, 3, 2, 12, 30, 15, 0, time.UTC) // a Monday
+	for _, tc := range []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 2, 12, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 2, 12, 45, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, 3, 3, 3, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 2, 13, 0, 0, 0, time.UTC)},
+		{"0 9 * * fri", time.Date(2026, 3, 6, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan-mar *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 8 1-7 * 1-5", time.Date(2026, 3, 3, 8, 30, 0, 0, time.UTC)},
+		// both day fields restricted: the 15th or any Sunday
+		{"0 0 15 * sun", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+		{"5/20 10 * * *", time.Date(2026, 3, 3, 10, 5, 0, 0, time.UTC)},
+	} {
+		s, err := Parse(tc.spec)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tc.spec, err)
+			continue
+		}
+		if got := s.Next(from); !got.Equal(tc.want) {
+			t.Errorf("%q.Next = %v, want %v", tc.spec, got, tc.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *", "@weekdays"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded", spec)
+		}
+	}
+}
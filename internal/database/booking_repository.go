@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BookingRepository stores investors' availability, one per investor, and
+// the booking links founders book meetings through
+type BookingRepository interface {
+	// SaveAvailability replaces the investor's availability
+	SaveAvailability(ctx context.Context, availability model.Availability) error
+	GetAvailability(ctx context.Context, investorID primitive.ObjectID) (*model.Availability, error)
+	CreateBookingLink(ctx context.Context, link model.BookingLink) (*mongo.InsertOneResult, error)
+	GetBookingLink(ctx context.Context, slug string) (*model.BookingLink, error)
+	ListBookingLinks(ctx context.Context, investorID primitive.ObjectID) ([]model.BookingLink, error)
+	// DeleteBookingLink removes one of the investor's links
+	DeleteBookingLink(ctx context.Context, investorID, id primitive.ObjectID) error
+}
+
+type bookingRepository struct {
+	availabilityCollection *mongo.Collection
+	linkCollection         *mongo.Collection
+}
+
+// NewBookingRepository returns the MongoDB booking repository
+func NewBookingRepository(db *mongo.Database) BookingRepository {
+	return &bookingRepository{
+		availabilityCollection: db.Collection("availability"),
+		linkCollection:         db.Collection("booking_links"),
+	}
+}
+
+// SaveAvailability upserts the investor's availability
+func (s *bookingRepository) SaveAvailability(ctx context.Context, availability model.Availability) error {
+	update := bson.M{"$set": bson.M{
+		"timezone":   availability.Timezone,
+		"rules":      availability.Rules,
+		"duration":   availability.Duration,
+		"buffer":     availability.Buffer,
+		"min_notice": availability.MinNotice,
+		"horizon":    availability.Horizon,
+		"updated_at": time.Now(),
+	}}
+	filter := bson.M{"investor_id": availability.InvestorID}
+	_, err := s.availabilityCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// GetAvailability retrieves the investor's availability
+func (s *bookingRepository) GetAvailability(ctx context.Context, investorID primitive.ObjectID) (*model.Availability, error) {
+	var availability model.Availability
+	if err := s.availabilityCollection.FindOne(ctx, bson.M{"investor_id": investorID}).Decode(&availability); err != nil {
+		return nil, NotFound(err, ErrAvailabilityNotFound)
+	}
+	return &availability, nil
+}
+
+// CreateBookingLink stores a new booking link
+func (s *bookingRepository) CreateBookingLink(ctx context.Context, link model.BookingLink) (*mongo.InsertOneResult, error) {
+	if link.ID.IsZero() {
+		link.ID = primitive.NewObjectID()
+	}
+	link.CreatedAt = time.Now()
+	return s.linkCollection.InsertOne(ctx, link)
+}
+
+// GetBookingLink retrieves a booking link by its slug
+func (s *bookingRepository) GetBookingLink(ctx context.Context, slug string) (*model.BookingLink, error) {
+	var link model.BookingLink
+	if err := s.linkCollection.FindOne(ctx, bson.M{"slug": slug}).Decode(&link); err != nil {
+		return nil, NotFound(err, ErrBookingLinkNotFound)
+	}
+	return &link, nil
+}
+
+// ListBookingLinks retrieves the investor's booking links, newest first
+func (s *bookingRepository) ListBookingLinks(ctx context.Context, investorID primitive.ObjectID) ([]model.BookingLink, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := s.linkCollection.Find(ctx, bson.M{"investor_id": investorID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	links := []model.BookingLink{}
+	if err := cursor.All(ctx, &links); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// DeleteBookingLink removes one of the investor's booking links
+func (s *bookingRepository) DeleteBookingLink(ctx context.Context, investorID, id primitive.ObjectID) error {
+	result, err := s.linkCollection.DeleteOne(ctx, bson.M{"_id": id, "investor_id": investorID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrBookingLinkNotFound
+	}
+	return nil
+}
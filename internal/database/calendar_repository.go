@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CalendarRepository stores the calendar accounts users have connected, one
+// per user and provider, and each user's subscribable feed
+type CalendarRepository interface {
+	// SaveConnection stores conn, replacing the user's earlier connection to
+	// the same provider
+	SaveConnection(ctx context.Context, conn model.CalendarConnection) error
+	GetConnection(ctx context.Context, userID primitive.ObjectID, provider string) (*model.CalendarConnection, error)
+	DeleteConnection(ctx context.Context, userID primitive.ObjectID, provider string) error
+	// SaveFeed sets the token hash of the user's feed, creating the feed or
+	// rotating its token so the old URL stops working
+	SaveFeed(ctx context.Context, userID primitive.ObjectID, tokenHash string) error
+	GetFeed(ctx context.Context, userID primitive.ObjectID) (*model.CalendarFeed, error)
+	GetFeedByToken(ctx context.Context, tokenHash string) (*model.CalendarFeed, error)
+	DeleteFeed(ctx context.Context, userID primitive.ObjectID) error
+}
+
+type calendarRepository struct {
+	connectionCollection *mongo.Collection
+	feedCollection       *mongo.Collection
+}
+
+// NewCalendarRepository returns the MongoDB calendar repository
+func NewCalendarRepository(db *mongo.Database) CalendarRepository {
+	return &calendarRepository{
+		connectionCollection: db.Collection("calendar_connections"),
+		feedCollection:       db.Collection("calendar_feeds"),
+	}
+}
+
+// SaveConnection upserts the connection for its user and provider
+func (s *calendarRepository) SaveConnection(ctx context.Context, conn model.CalendarConnection) error {
+	now := time.Now()
+	filter := bson.M{"user_id": conn.UserID, "provider": conn.Provider}
+	update := bson.M{"$set": bson.M{
+		"refresh_token": conn.RefreshToken,
+		"connected_at":  now,
+		"updated_at":    now,
+	}}
+	_, err := s.connectionCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// GetConnection retrieves the user's connection to provider
+func (s *calendarRepository) GetConnection(ctx context.Context, userID primitive.ObjectID, provider string) (*model.CalendarConnection, error) {
+	var conn model.CalendarConnection
+	err := s.connectionCollection.FindOne(ctx, bson.M{"user_id": userID, "provider": provider}).Decode(&conn)
+	if err != nil {
+		return nil, NotFound(err, ErrCalendarNotConnected)
+	}
+	return &conn, nil
+}
+
+// DeleteConnection removes the user's connection to provider
+func (s *calendarRepository) DeleteConnection(ctx context.Context, userID primitive.ObjectID, provider string) error {
+	result, err := s.connectionCollection.DeleteOne(ctx, bson.M{"user_id": userID, "provider": provider})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrCalendarNotConnected
+	}
+	return nil
+}
+
+// SaveFeed upserts the user's feed with a new token hash
+func (s *calendarRepository) SaveFeed(ctx context.Context, userID primitive.ObjectID, tokenHash string) error {
+	now := time.Now()
+	update := bson.M{
+		"$set":         bson.M{"token_hash": tokenHash, "rotated_at": now},
+		"$setOnInsert": bson.M{"created_at": now},
+	}
+	_, err := s.feedCollection.UpdateOne(ctx, bson.M{"user_id": userID}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// GetFeed retrieves the user's feed
+func (s *calendarRepository) GetFeed(ctx context.Context, userID primitive.ObjectID) (*model.CalendarFeed, error) {
+	return s.findFeed(ctx, bson.M{"user_id": userID})
+}
+
+// GetFeedByToken retrieves the feed whose token hashes to tokenHash
+func (s *calendarRepository) GetFeedByToken(ctx context.Context, tokenHash string) (*model.CalendarFeed, error) {
+	return s.findFeed(ctx, bson.M{"token_hash": tokenHash})
+}
+
+func (s *calendarRepository) findFeed(ctx context.Context, filter bson.M) (*model.CalendarFeed, error) {
+	var feed model.CalendarFeed
+	if err := s.feedCollection.FindOne(ctx, filter).Decode(&feed); err != nil {
+		return nil, NotFound(err, ErrCalendarFeedNotFound)
+	}
+	return &feed, nil
+}
+
+// DeleteFeed removes the user's feed
+func (s *calendarRepository) DeleteFeed(ctx context.Context, userID primitive.ObjectID) error {
+	result, err := s.feedCollection.DeleteOne(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrCalendarFeedNotFound
+	}
+	return nil
+}
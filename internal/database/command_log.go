@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+
+	"DBackend/internal/metrics"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// commandMonitor logs and times MongoDB commands, then hands each event to
+// next, which traces them. Records carry the request ID of the context the
+// command was issued under: successes are logged at debug level and failures
+// at warn. Command bodies are left out because they may hold password hashes
+// and tokens.
+func commandMonitor(next *event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: next.Started,
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			next.Succeeded(ctx, e)
+			metrics.MongoDuration.WithLabelValues(e.CommandName, "success").Observe(e.Duration.Seconds())
+			slog.DebugContext(ctx, "mongo command",
+				"command", e.CommandName,
+				"database", e.DatabaseName,
+				"duration_ms", float64(e.Duration.Microseconds())/1000,
+			)
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			next.Failed(ctx, e)
+			metrics.MongoDuration.WithLabelValues(e.CommandName, "error").Observe(e.Duration.Seconds())
+			slog.WarnContext(ctx, "mongo command failed",
+				"command", e.CommandName,
+				"database", e.DatabaseName,
+				"duration_ms", float64(e.Duration.Microseconds())/1000,
+				"error", e.Failure,
+			)
+		},
+	}
+}
@@ -2,94 +2,206 @@ package database
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"sync/atomic"
 	"time"
 
+	"DBackend/internal/config"
+	"DBackend/internal/migrate"
+	"DBackend/internal/ratelimit"
+
 	_ "github.com/joho/godotenv/autoload"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
+// Service exposes one repository per persisted entity
 type Service interface {
-	Health() map[string]string
-	User() UserService
-	DealFlow() DealFlowService
-	Founder() FounderService
-	Investor() InvestorService
-	Investment() InvestmentService
+	Health(ctx context.Context) error
+	// PendingMigrations lists the versions of the migrations not applied yet
+	PendingMigrations(ctx context.Context) ([]int, error)
+	// Transaction runs fn once, committing every write repositories make with
+	// the ctx it is given only if fn returns nil
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+	Users() UserRepository
+	Founders() FounderRepository
+	Investors() InvestorRepository
+	Deals() DealRepository
+	Meetings() MeetingRepository
+	Calendars() CalendarRepository
+	Bookings() BookingRepository
+	Tasks() TaskRepository
+	Grants() GrantRepository
+	Notifications() NotificationRepository
+	Investments() InvestmentRepository
+	Search() SearchService
+	RateLimits() ratelimit.Store
+	Jobs() JobRepository
+	Outbox() OutboxRepository
 }
 
 type service struct {
-	db         *mongo.Client
-	user       UserService
-	dealFlow   DealFlowService
-	founder    FounderService
-	investor   InvestorService
-	investment InvestmentService
+	db            *mongo.Client
+	migrator      *migrate.Runner
+	migrated      atomic.Bool // every migration was applied when last checked
+	users         UserRepository
+	founders      FounderRepository
+	investors     InvestorRepository
+	deals         DealRepository
+	meetings      MeetingRepository
+	calendars     CalendarRepository
+	bookings      BookingRepository
+	tasks         TaskRepository
+	grants        GrantRepository
+	notifications NotificationRepository
+	investments   InvestmentRepository
+	search        SearchService
+	rateLimits    ratelimit.Store
+	jobs          JobRepository
+	outbox        OutboxRepository
+	transactions  transactionSupport
 }
 
-var (
-	host = os.Getenv("BLUEPRINT_DB_HOST")
-	port = os.Getenv("BLUEPRINT_DB_PORT")
+// Connect opens a client to the configured MongoDB server
+func Connect(ctx context.Context, cfg config.Database) (*mongo.Client, error) {
+	return mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoURI()).SetMonitor(commandMonitor(otelmongo.NewMonitor())))
+}
 
-// database = os.Getenv("BLUEPRINT_DB_DATABASE")
-)
+func New(cfg config.Database) Service {
+	client, err := Connect(context.Background(), cfg)
+	if err != nil {
+		slog.Error("connecting to database", "error", err)
+		os.Exit(1)
+	}
 
-func New() Service {
-	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(fmt.Sprintf("mongodb://%s:%s", host, port)))
+	db := client.Database(cfg.Name)
+
+	migrator, err := NewMigrator(db)
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("loading migrations", "error", err)
+		os.Exit(1)
 	}
-	
-	dbName := os.Getenv("BLUEPRINT_DB_DATABASE")
-	if dbName == "" {
-		dbName = "ddb" // Fallback name
+	if cfg.Migrate {
+		migrateCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := runMigrations(migrateCtx, migrator); err != nil {
+			slog.Error("failed to apply migrations", "error", err)
+		}
 	}
-	
-	db := client.Database(dbName)
-	
+
 	return &service{
-		db:         client,
-		user:       NewUserService(client),
-		investor:   NewInvestorService(client),
-		founder:    NewFounderService(client),
-		dealFlow:   NewDealFlowService(client),
-		investment: NewInvestmentService(db),
+		db:            client,
+		migrator:      migrator,
+		users:         NewUserRepository(db),
+		founders:      NewFounderRepository(db),
+		investors:     NewInvestorRepository(db),
+		deals:         NewDealRepository(db),
+		meetings:      NewMeetingRepository(db),
+		calendars:     NewCalendarRepository(db),
+		bookings:      NewBookingRepository(db),
+		tasks:         NewTaskRepository(db),
+		grants:        NewGrantRepository(db),
+		notifications: NewNotificationRepository(db),
+		investments:   NewInvestmentRepository(db),
+		search:        NewSearchService(db),
+		rateLimits:    NewRateLimitStore(db),
+		jobs:          NewJobRepository(db),
+		outbox:        NewOutboxRepository(db),
 	}
 }
 
-func (s *service) Health() map[string]string {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-	defer cancel()
+func runMigrations(ctx context.Context, migrator *migrate.Runner) error {
+	applied, err := migrator.Up(ctx)
+	if len(applied) > 0 {
+		slog.InfoContext(ctx, "applied migrations", "versions", applied)
+	}
+	return err
+}
 
-	err := s.db.Ping(ctx, nil)
+// Health pings the primary and reports the error instead of exiting
+func (s *service) Health(ctx context.Context) error {
+	return s.db.Ping(ctx, readpref.Primary())
+}
+
+// PendingMigrations reads the applied versions until every migration is
+// applied; a newer release only starts against a migrated database again.
+func (s *service) PendingMigrations(ctx context.Context) ([]int, error) {
+	if s.migrated.Load() {
+		return nil, nil
+	}
+	statuses, err := s.migrator.Status(ctx)
 	if err != nil {
-		log.Fatalf("db down: %v", err)
+		return nil, err
 	}
-
-	return map[string]string{
-		"message": "It's healthy",
+	var pending []int
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending = append(pending, status.Version)
+		}
 	}
+	s.migrated.Store(len(pending) == 0)
+	return pending, nil
+}
+
+func (s *service) Users() UserRepository {
+	return s.users
+}
+
+func (s *service) Founders() FounderRepository {
+	return s.founders
+}
+
+func (s *service) Investors() InvestorRepository {
+	return s.investors
+}
+
+func (s *service) Deals() DealRepository {
+	return s.deals
+}
+
+func (s *service) Meetings() MeetingRepository {
+	return s.meetings
+}
+
+func (s *service) Calendars() CalendarRepository {
+	return s.calendars
+}
+
+func (s *service) Bookings() BookingRepository {
+	return s.bookings
+}
+
+func (s *service) Tasks() TaskRepository {
+	return s.tasks
+}
+
+func (s *service) Grants() GrantRepository {
+	return s.grants
+}
+
+func (s *service) Notifications() NotificationRepository {
+	return s.notifications
 }
 
-func (s *service) User() UserService {
-	return s.user
+func (s *service) Investments() InvestmentRepository {
+	return s.investments
 }
 
-func (s *service) DealFlow() DealFlowService {
-	return s.dealFlow
+func (s *service) Search() SearchService {
+	return s.search
 }
 
-func (s *service) Investor() InvestorService {
-	return s.investor
+func (s *service) RateLimits() ratelimit.Store {
+	return s.rateLimits
 }
 
-func (s *service) Founder() FounderService {
-	return s.founder
+func (s *service) Jobs() JobRepository {
+	return s.jobs
 }
 
-func (s *service) Investment() InvestmentService {
-	return s.investment
+func (s *service) Outbox() OutboxRepository {
+	return s.outbox
 }
@@ -3,12 +3,22 @@ package database
 import (
 	"context"
 	"log"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"DBackend/internal/config"
 
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
+var testConfig config.Database
+
 func mustStartMongoContainer() (func(context.Context, ...testcontainers.TerminateOption) error, error) {
 	dbContainer, err := mongodb.Run(context.Background(), "mongo:latest")
 	if err != nil {
@@ -25,8 +35,9 @@ func mustStartMongoContainer() (func(context.Context, ...testcontainers.Terminat
 		return dbContainer.Terminate, err
 	}
 
-	host = dbHost
-	port = dbPort.Port()
+	testConfig = config.Default().Database
+	testConfig.Host = dbHost
+	testConfig.Port = dbPort.Port()
 
 	return dbContainer.Terminate, err
 }
@@ -45,18 +56,190 @@ func TestMain(m *testing.M) {
 }
 
 func TestNew(t *testing.T) {
-	srv := New()
+	srv := New(testConfig)
 	if srv == nil {
 		t.Fatal("New() returned nil")
 	}
 }
 
 func TestHealth(t *testing.T) {
-	srv := New()
+	srv := New(testConfig)
+
+	if err := srv.Health(context.Background()); err != nil {
+		t.Fatalf("expected database to be healthy, got %v", err)
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	ctx := context.Background()
+	client, err := Connect(ctx, testConfig)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	db := client.Database("migrations_test")
+	defer db.Drop(ctx)
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if len(applied) != len(Migrations) {
+		t.Fatalf("Up() applied %v, want %d migrations", applied, len(Migrations))
+	}
+	if again, err := migrator.Up(ctx); err != nil || len(again) != 0 {
+		t.Fatalf("second Up() = %v, %v; want nothing to apply", again, err)
+	}
+
+	reverted, err := migrator.Down(ctx, len(Migrations))
+	if err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+	if len(reverted) != len(Migrations) {
+		t.Fatalf("Down() reverted %v, want %d migrations", reverted, len(Migrations))
+	}
+}
+
+func TestConcurrentMigrationsApplyOnce(t *testing.T) {
+	ctx := context.Background()
+	client, err := Connect(ctx, testConfig)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	db := client.Database("concurrent_migrations_test")
+	defer db.Drop(ctx)
+
+	meeting := primitive.NewObjectID()
+	if _, err := db.Collection("meetings").InsertOne(ctx, bson.M{"_id": meeting, "investor_id": primitive.NewObjectID(), "notes": "Strong team"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// two replicas starting together
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	applied := make([][]int, 2)
+	for i := range errs {
+		migrator, err := NewMigrator(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			applied[i], errs[i] = migrator.Up(ctx)
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("runner %d: Up() error = %v", i, err)
+		}
+	}
+	if total := len(applied[0]) + len(applied[1]); total != len(Migrations) {
+		t.Errorf("runners applied %v and %v, want each migration once", applied[0], applied[1])
+	}
+	if n, _ := db.Collection("meeting_notes").CountDocuments(ctx, bson.M{"meeting_id": meeting}); n != 1 {
+		t.Errorf("meeting notes copied %d times, want once", n)
+	}
+	if n, _ := db.Collection("schema_migrations").CountDocuments(ctx, bson.M{"_id": "lock"}); n != 0 {
+		t.Error("the migration lock was not released")
+	}
+}
+
+func TestMigrationNormalizesUserEmails(t *testing.T) {
+	ctx := context.Background()
+	client, err := Connect(ctx, testConfig)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	db := client.Database("emails_test")
+	defer db.Drop(ctx)
 
-	stats := srv.Health()
+	grace, ada, shouting := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	_, err = db.Collection("users").InsertMany(ctx, []interface{}{
+		bson.M{"_id": grace, "email": " Grace@Example.com"},
+		bson.M{"_id": ada, "email": "ada@example.com"},
+		bson.M{"_id": shouting, "email": "ADA@example.com "},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied, err := migrator.Up(ctx)
+	if err == nil || len(applied) != len(Migrations)-1 || !strings.Contains(err.Error(), "ada@example.com (users "+ada.Hex()) {
+		t.Fatalf("Up() = %v, %v; want migration 18 to fail listing ada's accounts", applied, err)
+	}
+	if n, _ := db.Collection("users").CountDocuments(ctx, bson.M{"_id": grace, "email": " Grace@Example.com"}); n != 1 {
+		t.Error("an email was changed although the migration failed")
+	}
 
-	if stats["message"] != "It's healthy" {
-		t.Fatalf("expected message to be 'It's healthy', got %s", stats["message"])
+	if _, err := db.Collection("users").DeleteOne(ctx, bson.M{"_id": shouting}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up() after removing the duplicate error = %v", err)
+	}
+	if n, _ := db.Collection("users").CountDocuments(ctx, bson.M{"_id": grace, "email": "grace@example.com"}); n != 1 {
+		t.Error("grace's email was not lower-cased")
+	}
+	_, err = db.Collection("users").InsertOne(ctx, bson.M{"email": "grace@example.com"})
+	if !mongo.IsDuplicateKeyError(err) {
+		t.Errorf("inserting a duplicate email error = %v, want a duplicate key error", err)
+	}
+}
+
+func TestMigrationMergesDuplicateDeals(t *testing.T) {
+	ctx := context.Background()
+	client, err := Connect(ctx, testConfig)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	db := client.Database("dedupe_test")
+	defer db.Drop(ctx)
+
+	investor, founder := primitive.NewObjectID(), primitive.NewObjectID()
+	oldest, newer := primitive.NewObjectID(), primitive.NewObjectID()
+	created := time.Now().Add(-time.Hour)
+	_, err = db.Collection("deal_flow").InsertMany(ctx, []interface{}{
+		bson.M{"_id": newer, "investor_id": investor, "founder_id": founder, "created_at": created.Add(time.Minute)},
+		bson.M{"_id": oldest, "investor_id": investor, "founder_id": founder, "created_at": created},
+		bson.M{"investor_id": investor, "founder_id": primitive.NewObjectID(), "created_at": created},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Collection("activities").InsertOne(ctx, bson.M{"deal_id": newer, "type": "deal"}); err != nil {
+		t.Fatal(err)
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if n, _ := db.Collection("deal_flow").CountDocuments(ctx, bson.M{"investor_id": investor, "founder_id": founder}); n != 1 {
+		t.Fatalf("deals with the founder = %d, want the oldest only", n)
+	}
+	if n, _ := db.Collection("deal_flow").CountDocuments(ctx, bson.M{"_id": oldest}); n != 1 {
+		t.Error("the oldest deal was not kept")
+	}
+	if n, _ := db.Collection("deal_flow_duplicates").CountDocuments(ctx, bson.M{"_id": newer, "merged_into": oldest}); n != 1 {
+		t.Error("the duplicate was not archived")
+	}
+	if n, _ := db.Collection("activities").CountDocuments(ctx, bson.M{"deal_id": oldest}); n != 1 {
+		t.Error("the duplicate's activity was not moved to the kept deal")
+	}
+	_, err = db.Collection("deal_flow").InsertOne(ctx, bson.M{"investor_id": investor, "founder_id": founder})
+	if !mongo.IsDuplicateKeyError(err) {
+		t.Errorf("inserting a duplicate deal error = %v, want a duplicate key error", err)
 	}
 }
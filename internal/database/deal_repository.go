@@ -0,0 +1,327 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"DBackend/internal/query"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DealRepository stores the startups investors track in their deal flow.
+// Implementations share one contract, pinned by the dealtest suite:
+// writes to a missing deal return ErrDealNotFound, adding a deal and
+// changing its stage publish DealAdded and DealStageChanged events, and
+// changing its status and adding a meeting record an activity for the
+// investor.
+type DealRepository interface {
+	// AddStartupToDealFlow rejects a second deal for the same investor and
+	// startup with ErrDealExists
+	AddStartupToDealFlow(ctx context.Context, deal model.DealFlow) (*mongo.InsertOneResult, error)
+	GetDealFlowByID(ctx context.Context, id primitive.ObjectID) (*model.DealFlow, error)
+	// FindDeal retrieves the investor's deal with the founder's startup
+	FindDeal(ctx context.Context, investorID, founderID primitive.ObjectID) (*model.DealFlow, error)
+	ListDealsByInvestorID(ctx context.Context, investorID primitive.ObjectID, params query.Params) (query.Page[bson.M], error)
+	// UpdateDealFlow sets plain fields; stage changes go through UpdateDealStage
+	UpdateDealFlow(ctx context.Context, id primitive.ObjectID, updateFields bson.M) (*mongo.UpdateResult, error)
+	// UpdateDealStage publishes DealStageChanged when the stage is a new one
+	UpdateDealStage(ctx context.Context, id primitive.ObjectID, stage string) (*mongo.UpdateResult, error)
+	UpdateDealStatus(ctx context.Context, id primitive.ObjectID, status string) (*mongo.UpdateResult, error)
+	UpdateDealFundRequired(ctx context.Context, dealID primitive.ObjectID, amountChange float64) (*mongo.UpdateResult, error)
+	DeleteDealFlow(ctx context.Context, id primitive.ObjectID) (*mongo.DeleteResult, error)
+	AddMeeting(ctx context.Context, dealID primitive.ObjectID, meeting model.Meeting) (*mongo.UpdateResult, error)
+	AddDocument(ctx context.Context, dealID primitive.ObjectID, document model.Document) (*mongo.UpdateResult, error)
+	AddNote(ctx context.Context, dealID primitive.ObjectID, note model.Note) error
+	// AddActivity records an entry on the deal's activity timeline and in its
+	// investor's feed, and bumps the deal's last_activity
+	AddActivity(ctx context.Context, activity model.Activity) error
+	// ListActivities returns the deal's activity timeline, newest first
+	ListActivities(ctx context.Context, dealID primitive.ObjectID) ([]model.RecentActivity, error)
+}
+
+type dealRepository struct {
+	dealFlowCollection *mongo.Collection
+	activityCollection *mongo.Collection
+	outbox             OutboxRepository
+}
+
+// NewDealRepository returns the MongoDB deal flow repository
+func NewDealRepository(db *mongo.Database) DealRepository {
+	return &dealRepository{
+		dealFlowCollection: db.Collection("deal_flow"),
+		activityCollection: db.Collection("activities"),
+		outbox:             NewOutboxRepository(db),
+	}
+}
+
+// AddStartupToDealFlow adds a startup to an investor's deal flow. The
+// unique deal_flow_investor_founder index rejects a second deal with the
+// same founder, however many requests race. Subscribers to the DealAdded
+// event put it on the timeline and notify the founder.
+func (s *dealRepository) AddStartupToDealFlow(ctx context.Context, deal model.DealFlow) (*mongo.InsertOneResult, error) {
+	if deal.ID.IsZero() {
+		deal.ID = primitive.NewObjectID()
+	}
+	deal.CreatedAt = time.Now()
+	deal.UpdatedAt = time.Now()
+	result, err := s.dealFlowCollection.InsertOne(ctx, deal)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrDealExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = s.outbox.Publish(ctx, model.DealAdded{DealID: deal.ID, InvestorID: deal.InvestorID, StartupID: deal.StartupID})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindDeal retrieves the deal between an investor and a founder
+func (s *dealRepository) FindDeal(ctx context.Context, investorID, founderID primitive.ObjectID) (*model.DealFlow, error) {
+	var deal model.DealFlow
+	err := s.dealFlowCollection.FindOne(ctx, bson.M{"investor_id": investorID, "founder_id": founderID}).Decode(&deal)
+	if err != nil {
+		return nil, NotFound(err, ErrDealNotFound)
+	}
+	return &deal, nil
+}
+
+// Get a specific deal flow entry
+func (s *dealRepository) GetDealFlowByID(ctx context.Context, id primitive.ObjectID) (*model.DealFlow, error) {
+	var deal model.DealFlow
+	err := s.dealFlowCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&deal)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrDealNotFound
+		}
+		return nil, err
+	}
+	return &deal, nil
+}
+
+// ListDealsByInvestorID retrieves a page of deal flow entries for a given investor ID
+func (s *dealRepository) ListDealsByInvestorID(ctx context.Context, investorID primitive.ObjectID, params query.Params) (query.Page[bson.M], error) {
+	base := bson.M{"investor_id": investorID}
+	total, err := s.dealFlowCollection.CountDocuments(ctx, params.CountFilter(base))
+	if err != nil {
+		return query.Page[bson.M]{}, err
+	}
+
+	pipeline := append(params.Stages(base), mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "founders"},
+			{Key: "localField", Value: "founder_id"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "startup"},
+		}}},
+		{{Key: "$unwind", Value: "$startup"}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "users"},
+			{Key: "localField", Value: "startup.user_id"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "founder"},
+		}}},
+		{{Key: "$unwind", Value: "$founder"}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 1},
+			{Key: "investor_id", Value: 1},
+			{Key: "founder_id", Value: 1},
+			{Key: "stage", Value: 1},
+			{Key: "status", Value: 1},
+			{Key: "match_score", Value: 1},
+			{Key: "created_at", Value: 1},
+			{Key: "updated_at", Value: 1},
+			{Key: "last_activity", Value: 1},
+			{Key: "startup", Value: 1},
+			{Key: "founder_name", Value: bson.D{
+				{Key: "$concat", Value: bson.A{"$founder.first_name", " ", "$founder.last_name"}},
+			}},
+			{Key: "founder_email", Value: "$founder.email"},
+			{Key: "founder_avatar", Value: "$founder.avatar"},
+		}}},
+	}...)
+	cursor, err := s.dealFlowCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return query.Page[bson.M]{}, err
+	}
+	return query.Collect[bson.M](ctx, cursor, params, total)
+}
+
+// Update deal flow details
+func (s *dealRepository) UpdateDealFlow(ctx context.Context, id primitive.ObjectID, updateFields bson.M) (*mongo.UpdateResult, error) {
+	updateFields["updated_at"] = time.Now()
+	return s.updateOne(ctx, id, bson.M{"$set": updateFields})
+}
+
+// UpdateDealStage moves a deal to a new stage. The deal as it was before the
+// update gives the stage it moved from.
+func (s *dealRepository) UpdateDealStage(ctx context.Context, id primitive.ObjectID, stage string) (*mongo.UpdateResult, error) {
+	var deal model.DealFlow
+	err := s.dealFlowCollection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"stage": stage, "updated_at": time.Now()}}).Decode(&deal)
+	if err != nil {
+		return nil, NotFound(err, ErrDealNotFound)
+	}
+	if deal.Stage == stage {
+		return &mongo.UpdateResult{MatchedCount: 1}, nil
+	}
+	err = s.outbox.Publish(ctx, model.DealStageChanged{
+		DealID:     deal.ID,
+		InvestorID: deal.InvestorID,
+		StartupID:  deal.StartupID,
+		From:       deal.Stage,
+		To:         stage,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+// UpdateDealStatus changes a deal's status and records the activity
+func (s *dealRepository) UpdateDealStatus(ctx context.Context, id primitive.ObjectID, status string) (*mongo.UpdateResult, error) {
+	return s.setAndRecord(ctx, id, bson.M{"status": status}, fmt.Sprintf("Deal status updated to %s", status))
+}
+
+// setAndRecord sets fields on a deal and records a deal_update activity for its investor
+func (s *dealRepository) setAndRecord(ctx context.Context, id primitive.ObjectID, fields bson.M, description string) (*mongo.UpdateResult, error) {
+	fields["updated_at"] = time.Now()
+	var deal model.DealFlow
+	err := s.dealFlowCollection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": fields}).Decode(&deal)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrDealNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.addActivity(ctx, deal.InvestorID, deal.ID, "deal_update", description); err != nil {
+		return nil, err
+	}
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+// UpdateDealFundRequired updates the fund required for a deal
+func (s *dealRepository) UpdateDealFundRequired(ctx context.Context, dealID primitive.ObjectID, amountChange float64) (*mongo.UpdateResult, error) {
+	update := bson.M{
+		"$inc": bson.M{"fund_required": amountChange},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+	return s.updateOne(ctx, dealID, update)
+}
+
+// Remove a startup from deal flow
+func (s *dealRepository) DeleteDealFlow(ctx context.Context, id primitive.ObjectID) (*mongo.DeleteResult, error) {
+	result, err := s.dealFlowCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	if result.DeletedCount == 0 {
+		return nil, ErrDealNotFound
+	}
+	return result, nil
+}
+
+// AddMeeting adds a meeting to a deal flow and records the activity
+func (s *dealRepository) AddMeeting(ctx context.Context, dealID primitive.ObjectID, meeting model.Meeting) (*mongo.UpdateResult, error) {
+	if meeting.ID.IsZero() {
+		meeting.ID = primitive.NewObjectID()
+	}
+	result, err := s.push(ctx, dealID, "meetings", meeting)
+	if err != nil {
+		return nil, err
+	}
+	deal, err := s.GetDealFlowByID(ctx, dealID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.addActivity(ctx, deal.InvestorID, deal.ID, "meeting", fmt.Sprintf("Meeting scheduled: %s", meeting.Title)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Add a document to deal flow
+func (s *dealRepository) AddDocument(ctx context.Context, dealID primitive.ObjectID, document model.Document) (*mongo.UpdateResult, error) {
+	return s.push(ctx, dealID, "documents", document)
+}
+
+// Add a note to a deal flow
+func (s *dealRepository) AddNote(ctx context.Context, dealID primitive.ObjectID, note model.Note) error {
+	_, err := s.push(ctx, dealID, "notes", note)
+	return err
+}
+
+// push appends v to an array field of a deal
+func (s *dealRepository) push(ctx context.Context, dealID primitive.ObjectID, field string, v interface{}) (*mongo.UpdateResult, error) {
+	return s.updateOne(ctx, dealID, bson.M{
+		"$push": bson.M{field: v},
+		"$set":  bson.M{"updated_at": time.Now()},
+	})
+}
+
+// updateOne applies update to a deal, returning ErrDealNotFound when it does not exist
+func (s *dealRepository) updateOne(ctx context.Context, id primitive.ObjectID, update bson.M) (*mongo.UpdateResult, error) {
+	result, err := s.dealFlowCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, ErrDealNotFound
+	}
+	return result, nil
+}
+
+// addActivity creates an activity record for an investor on a deal's timeline
+func (s *dealRepository) addActivity(ctx context.Context, investorID, dealID primitive.ObjectID, activityType, description string) error {
+	activity := model.Activity{
+		ID:          primitive.NewObjectID(),
+		InvestorID:  investorID,
+		DealID:      dealID,
+		Type:        activityType,
+		Description: description,
+		Date:        time.Now(),
+	}
+	_, err := s.activityCollection.InsertOne(ctx, activity)
+	return err
+}
+
+// AddActivity records an activity on a deal's timeline
+func (s *dealRepository) AddActivity(ctx context.Context, activity model.Activity) error {
+	if activity.ID.IsZero() {
+		activity.ID = primitive.NewObjectID()
+	}
+	if activity.Date.IsZero() {
+		activity.Date = time.Now()
+	}
+	if _, err := s.updateOne(ctx, activity.DealID, bson.M{"$set": bson.M{"last_activity": activity.Date}}); err != nil {
+		return err
+	}
+	_, err := s.activityCollection.InsertOne(ctx, activity)
+	return err
+}
+
+// ListActivities returns the activities on a deal's timeline, newest first
+func (s *dealRepository) ListActivities(ctx context.Context, dealID primitive.ObjectID) ([]model.RecentActivity, error) {
+	// IDs order entries recorded in the same millisecond
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := s.activityCollection.Find(ctx, bson.M{"deal_id": dealID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var docs []model.Activity
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	activities := make([]model.RecentActivity, 0, len(docs))
+	for _, a := range docs {
+		activities = append(activities, model.RecentActivity{ID: a.ID, Type: a.Type, Description: a.Description, Date: a.Date})
+	}
+	return activities, nil
+}
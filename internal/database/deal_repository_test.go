@@ -0,0 +1,43 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"DBackend/internal/database"
+	"DBackend/internal/database/dealtest"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDealRepositoryContract(t *testing.T) {
+	ctx := context.Background()
+	client, err := database.Connect(ctx, database.MongoConfig())
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	dealtest.Run(t, func(t *testing.T) dealtest.Harness {
+		db := client.Database("deals_" + primitive.NewObjectID().Hex())
+		t.Cleanup(func() { db.Drop(ctx) })
+		// the unique index on investor and founder rejects duplicate deals
+		migrator, err := database.NewMigrator(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := migrator.Up(ctx); err != nil {
+			t.Fatal(err)
+		}
+		return dealtest.Harness{
+			Deals: database.NewDealRepository(db),
+			Count: func(collection string, filter bson.M) int {
+				n, err := db.Collection(collection).CountDocuments(ctx, filter)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return int(n)
+			},
+		}
+	})
+}
@@ -0,0 +1,257 @@
+// Package dealtest pins the behavior every database.DealRepository must
+// share. Each implementation runs the same suite from its own tests so the
+// MongoDB repository and the in-memory fake cannot drift apart.
+package dealtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"DBackend/internal/database"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Harness is a fresh repository plus a way to count the side effects it
+// writes to other collections
+type Harness struct {
+	Deals database.DealRepository
+	Count func(collection string, filter bson.M) int
+}
+
+// Run executes the deal repository contract against harnesses built by newHarness
+func Run(t *testing.T, newHarness func(t *testing.T) Harness) {
+	t.Run("AddStartupToDealFlow", func(t *testing.T) {
+		h := newHarness(t)
+		ctx := context.Background()
+		investorID, founderID := primitive.NewObjectID(), primitive.NewObjectID()
+		deal := model.DealFlow{InvestorID: investorID, StartupID: founderID, Stage: "screening"}
+
+		result, err := h.Deals.AddStartupToDealFlow(ctx, deal)
+		if err != nil {
+			t.Fatalf("AddStartupToDealFlow() error = %v", err)
+		}
+		id, ok := result.InsertedID.(primitive.ObjectID)
+		if !ok {
+			t.Fatalf("InsertedID = %v, want an ObjectID", result.InsertedID)
+		}
+		stored, err := h.Deals.GetDealFlowByID(ctx, id)
+		if err != nil {
+			t.Fatalf("GetDealFlowByID() error = %v", err)
+		}
+		if stored.InvestorID != investorID || stored.StartupID != founderID || stored.CreatedAt.IsZero() {
+			t.Errorf("stored deal = %+v, want investor, founder and created_at set", stored)
+		}
+
+		if found, err := h.Deals.FindDeal(ctx, investorID, founderID); err != nil || found.ID != id {
+			t.Errorf("FindDeal() = %+v, %v; want the deal", found, err)
+		}
+		if _, err := h.Deals.FindDeal(ctx, founderID, investorID); !errors.Is(err, database.ErrDealNotFound) {
+			t.Errorf("FindDeal() with the roles swapped error = %v, want ErrDealNotFound", err)
+		}
+
+		if _, err := h.Deals.AddStartupToDealFlow(ctx, deal); !errors.Is(err, database.ErrDealExists) {
+			t.Errorf("second AddStartupToDealFlow() error = %v, want ErrDealExists", err)
+		}
+		other := model.DealFlow{InvestorID: primitive.NewObjectID(), StartupID: founderID}
+		if _, err := h.Deals.AddStartupToDealFlow(ctx, other); err != nil {
+			t.Errorf("AddStartupToDealFlow() for another investor error = %v", err)
+		}
+
+		if n := h.Count("outbox", bson.M{"type": model.EventDealAdded, "payload.startup_id": founderID}); n != 2 {
+			t.Errorf("DealAdded events = %d, want 2", n)
+		}
+		added := bson.M{"type": model.EventDealAdded, "status": model.EventPending, "payload.deal_id": id, "payload.investor_id": investorID}
+		if n := h.Count("outbox", added); n != 1 {
+			t.Errorf("DealAdded events for the deal = %d, want 1 pending", n)
+		}
+		if n := h.Count("activities", bson.M{}) + h.Count("notifications", bson.M{}); n != 0 {
+			t.Errorf("side effects written directly = %d, want them left to subscribers", n)
+		}
+	})
+
+	t.Run("StageAndStatus", func(t *testing.T) {
+		h := newHarness(t)
+		ctx := context.Background()
+		investorID := primitive.NewObjectID()
+		id := add(t, h, investorID)
+
+		if _, err := h.Deals.UpdateDealStage(ctx, id, "negotiation"); err != nil {
+			t.Fatalf("UpdateDealStage() error = %v", err)
+		}
+		if _, err := h.Deals.UpdateDealStage(ctx, id, "negotiation"); err != nil {
+			t.Fatalf("UpdateDealStage() to the same stage error = %v", err)
+		}
+		if _, err := h.Deals.UpdateDealStatus(ctx, id, "paused"); err != nil {
+			t.Fatalf("UpdateDealStatus() error = %v", err)
+		}
+		stored, err := h.Deals.GetDealFlowByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.Stage != "negotiation" || stored.Status != "paused" {
+			t.Errorf("stage, status = %q, %q; want negotiation, paused", stored.Stage, stored.Status)
+		}
+		changed := bson.M{"type": model.EventDealStageChanged, "payload.deal_id": id, "payload.from": "screening", "payload.to": "negotiation"}
+		if n := h.Count("outbox", changed); n != 1 {
+			t.Errorf("DealStageChanged events = %d, want 1 from screening to negotiation", n)
+		}
+		if n := h.Count("outbox", bson.M{"type": model.EventDealStageChanged}); n != 1 {
+			t.Errorf("DealStageChanged events = %d, want none for staying in a stage", n)
+		}
+		if n := h.Count("activities", bson.M{"investor_id": investorID, "type": "deal_update"}); n != 1 {
+			t.Errorf("deal_update activities = %d, want 1 for the status", n)
+		}
+	})
+
+	t.Run("AddMeeting", func(t *testing.T) {
+		h := newHarness(t)
+		ctx := context.Background()
+		investorID := primitive.NewObjectID()
+		id := add(t, h, investorID)
+
+		if _, err := h.Deals.AddMeeting(ctx, id, model.Meeting{Title: "Intro"}); err != nil {
+			t.Fatalf("AddMeeting() error = %v", err)
+		}
+		stored, err := h.Deals.GetDealFlowByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stored.Meetings) != 1 || stored.Meetings[0].ID.IsZero() {
+			t.Errorf("meetings = %+v, want one meeting with an ID", stored.Meetings)
+		}
+		if n := h.Count("activities", bson.M{"investor_id": investorID, "type": "meeting"}); n != 1 {
+			t.Errorf("meeting activities = %d, want 1", n)
+		}
+	})
+
+	t.Run("Timeline", func(t *testing.T) {
+		h := newHarness(t)
+		ctx := context.Background()
+		investorID := primitive.NewObjectID()
+		id := add(t, h, investorID)
+		other := add(t, h, investorID)
+
+		if _, err := h.Deals.UpdateDealStatus(ctx, id, "paused"); err != nil {
+			t.Fatal(err)
+		}
+		added := time.Now().Add(-time.Minute)
+		if err := h.Deals.AddActivity(ctx, model.Activity{InvestorID: investorID, DealID: id, Type: "deal", Date: added}); err != nil {
+			t.Fatalf("AddActivity() error = %v", err)
+		}
+		held := time.Now().Add(time.Minute)
+		err := h.Deals.AddActivity(ctx, model.Activity{InvestorID: investorID, DealID: id, Type: "meeting_outcome", Description: "Intro: positive", Date: held})
+		if err != nil {
+			t.Fatalf("AddActivity() error = %v", err)
+		}
+		timeline, err := h.Deals.ListActivities(ctx, id)
+		if err != nil {
+			t.Fatalf("ListActivities() error = %v", err)
+		}
+		var types []string
+		for _, a := range timeline {
+			types = append(types, a.Type)
+		}
+		if len(types) != 3 || types[0] != "meeting_outcome" || types[1] != "deal_update" || types[2] != "deal" {
+			t.Errorf("timeline = %v, want the outcome, the status change and the deal being added, newest first", types)
+		}
+		if n := h.Count("deal_flow", bson.M{"_id": id, "last_activity": bson.M{"$gte": held.Add(-time.Second)}}); n != 1 {
+			t.Errorf("last_activity was not bumped")
+		}
+		if others, _ := h.Deals.ListActivities(ctx, other); len(others) != 0 {
+			t.Errorf("other deal's timeline = %v, want it empty", others)
+		}
+	})
+
+	t.Run("FundRequired", func(t *testing.T) {
+		h := newHarness(t)
+		ctx := context.Background()
+		id := add(t, h, primitive.NewObjectID())
+
+		if _, err := h.Deals.UpdateDealFlow(ctx, id, bson.M{"fund_required": 1000.0}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := h.Deals.UpdateDealFundRequired(ctx, id, -250); err != nil {
+			t.Fatalf("UpdateDealFundRequired() error = %v", err)
+		}
+		if n := h.Count("deal_flow", bson.M{"_id": id, "fund_required": 750.0}); n != 1 {
+			t.Errorf("fund_required was not reduced to 750")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		h := newHarness(t)
+		ctx := context.Background()
+		id := add(t, h, primitive.NewObjectID())
+
+		if _, err := h.Deals.DeleteDealFlow(ctx, id); err != nil {
+			t.Fatalf("DeleteDealFlow() error = %v", err)
+		}
+		if _, err := h.Deals.GetDealFlowByID(ctx, id); !errors.Is(err, database.ErrDealNotFound) {
+			t.Errorf("GetDealFlowByID() after delete error = %v, want ErrDealNotFound", err)
+		}
+	})
+
+	t.Run("MissingDeal", func(t *testing.T) {
+		h := newHarness(t)
+		ctx := context.Background()
+		missing := primitive.NewObjectID()
+
+		writes := map[string]func() error{
+			"GetDealFlowByID": func() error { _, err := h.Deals.GetDealFlowByID(ctx, missing); return err },
+			"UpdateDealFlow": func() error {
+				_, err := h.Deals.UpdateDealFlow(ctx, missing, bson.M{"priority": "high"})
+				return err
+			},
+			"UpdateDealStage":  func() error { _, err := h.Deals.UpdateDealStage(ctx, missing, "closed"); return err },
+			"UpdateDealStatus": func() error { _, err := h.Deals.UpdateDealStatus(ctx, missing, "paused"); return err },
+			"UpdateDealFundRequired": func() error {
+				_, err := h.Deals.UpdateDealFundRequired(ctx, missing, -1)
+				return err
+			},
+			"DeleteDealFlow": func() error { _, err := h.Deals.DeleteDealFlow(ctx, missing); return err },
+			"AddMeeting": func() error {
+				_, err := h.Deals.AddMeeting(ctx, missing, model.Meeting{Title: "Intro"})
+				return err
+			},
+			"AddDocument": func() error {
+				_, err := h.Deals.AddDocument(ctx, missing, model.Document{Name: "Deck"})
+				return err
+			},
+			"AddNote": func() error { return h.Deals.AddNote(ctx, missing, model.Note{Content: "hi"}) },
+			"AddActivity": func() error {
+				return h.Deals.AddActivity(ctx, model.Activity{DealID: missing, Type: "meeting_outcome"})
+			},
+		}
+		for name, write := range writes {
+			if err := write(); !errors.Is(err, database.ErrDealNotFound) {
+				t.Errorf("%s() on a missing deal error = %v, want ErrDealNotFound", name, err)
+			}
+		}
+		if n := h.Count("activities", bson.M{}); n != 0 {
+			t.Errorf("activities = %d, want none for a missing deal", n)
+		}
+		if n := h.Count("outbox", bson.M{}); n != 0 {
+			t.Errorf("events = %d, want none for a missing deal", n)
+		}
+	})
+}
+
+// add inserts a deal for investorID and returns its ID
+func add(t *testing.T, h Harness, investorID primitive.ObjectID) primitive.ObjectID {
+	t.Helper()
+	result, err := h.Deals.AddStartupToDealFlow(context.Background(), model.DealFlow{
+		InvestorID: investorID,
+		StartupID:  primitive.NewObjectID(),
+		Stage:      "screening",
+		Status:     "active",
+	})
+	if err != nil {
+		t.Fatalf("AddStartupToDealFlow() error = %v", err)
+	}
+	return result.InsertedID.(primitive.ObjectID)
+}
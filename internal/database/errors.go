@@ -0,0 +1,49 @@
+package database
+
+import (
+	"errors"
+
+	"DBackend/internal/apperror"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Domain errors returned by every repository implementation. Handlers pass
+// them through and the server's error handler maps their kind to a status.
+var (
+	ErrUserNotFound             = apperror.NotFound("user_not_found", "User not found")
+	ErrFounderNotFound          = apperror.NotFound("founder_not_found", "Founder not found")
+	ErrInvestorNotFound         = apperror.NotFound("investor_not_found", "Investor not found")
+	ErrDealNotFound             = apperror.NotFound("deal_not_found", "Deal not found")
+	ErrDealExists               = apperror.Conflict("deal_exists", "Deal already exists for this startup and investor")
+	ErrTaskNotFound             = apperror.NotFound("task_not_found", "Task not found")
+	ErrMeetingNotFound          = apperror.NotFound("meeting_not_found", "Meeting not found")
+	ErrGrantNotFound            = apperror.NotFound("grant_not_found", "Grant not found")
+	ErrGrantApplicationNotFound = apperror.NotFound("application_not_found", "Application not found")
+	ErrNotificationNotFound     = apperror.NotFound("notification_not_found", "Notification not found")
+	ErrCalendarNotConnected     = apperror.NotFound("calendar_not_connected", "Calendar not connected")
+	ErrCalendarFeedNotFound     = apperror.NotFound("calendar_feed_not_found", "Calendar feed not found")
+	ErrAvailabilityNotFound     = apperror.NotFound("availability_not_found", "Availability not set")
+	ErrBookingLinkNotFound      = apperror.NotFound("booking_link_not_found", "Booking link not found")
+	ErrOccurrenceNotFound       = apperror.NotFound("occurrence_not_found", "Occurrence not found")
+	ErrMeetingNotRecurring      = apperror.Validation("meeting_not_recurring", "Meeting does not recur")
+	ErrSlotTaken                = apperror.Conflict("slot_taken", "That slot is no longer available")
+	ErrNoteNotFound             = apperror.NotFound("note_not_found", "Note not found")
+	ErrNoteEdited               = apperror.Conflict("note_edited", "The note was edited since it was read")
+	ErrActionItemNotFound       = apperror.NotFound("action_item_not_found", "Action item not found")
+	ErrActionItemHasTask        = apperror.Conflict("action_item_has_task", "The action item already has a task")
+	ErrJobNotFound              = apperror.NotFound("job_not_found", "Job not found")
+	ErrJobLeaseLost             = apperror.Conflict("job_lease_lost", "Another replica took over the job")
+	ErrEventNotFound            = apperror.NotFound("event_not_found", "No failed event with that ID")
+	ErrEventLeaseLost           = apperror.Conflict("event_lease_lost", "Another replica took over the event")
+	ErrInvalidRole              = apperror.Validation("invalid_role", "Invalid role")
+)
+
+// NotFound replaces mongo.ErrNoDocuments with the repository's domain error
+// and returns any other error unchanged
+func NotFound(err, notFound error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return notFound
+	}
+	return err
+}
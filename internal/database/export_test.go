@@ -0,0 +1,8 @@
+package database
+
+import "DBackend/internal/config"
+
+// MongoConfig exposes the container's connection settings to external test packages
+func MongoConfig() config.Database {
+	return testConfig
+}
@@ -0,0 +1,306 @@
+package database
+
+import (
+	"DBackend/model"
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// FounderRepository stores founder profiles and their startup dashboards
+type FounderRepository interface {
+	GetFounderByUserID(ctx context.Context, userID primitive.ObjectID) (*model.Founder, error)
+	UpdateFounder(ctx context.Context, userID primitive.ObjectID, founder model.Founder) (*mongo.UpdateResult, error)
+	// UpdateStartupInvestment adds amount to the total invested in the founder's startup
+	UpdateStartupInvestment(ctx context.Context, userID primitive.ObjectID, amount float64) (*mongo.UpdateResult, error)
+	GetFounderProfileWithMatch(ctx context.Context, userID primitive.ObjectID) (bson.M, error)
+	DiscoverStartups(ctx context.Context, q StartupQuery) (*StartupPage, error)
+	EnsureStartupIndexes(ctx context.Context) error
+	GetFundraisingSummary(ctx context.Context, founderID primitive.ObjectID) (*model.FundraisingSummary, error)
+	GetInvestorEngagement(ctx context.Context, founderID primitive.ObjectID) (*model.InvestorEngagement, error)
+	SubmitInvestorApplication(ctx context.Context, application model.InvestorApplication) (primitive.ObjectID, error)
+}
+
+// NewFounderRepository returns the MongoDB founder repository
+func NewFounderRepository(db *mongo.Database) FounderRepository {
+	return &founderRepository{
+		founderCollection:     db.Collection("founders"),
+		applicationCollection: db.Collection("applications"),
+	}
+}
+
+type founderRepository struct {
+	founderCollection     *mongo.Collection
+	applicationCollection *mongo.Collection
+}
+
+// GetFundraisingSummary returns a summary of the founder's fundraising activities
+func (s *founderRepository) GetFundraisingSummary(ctx context.Context, founderID primitive.ObjectID) (*model.FundraisingSummary, error) {
+	// Get founder details
+	founder, err := s.GetFounderByUserID(ctx, founderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get investment data
+	investments, err := s.GetFounderInvestments(ctx, founderID)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	// Calculate metrics with safe defaults
+	totalRaised := 0.0
+	numberOfInvestors := 0
+	averageInvestment := 0.0
+
+	if len(investments) > 0 {
+		// Calculate total raised and other metrics
+		for _, inv := range investments {
+			totalRaised += inv.Amount
+		}
+		numberOfInvestors = len(investments)
+		if numberOfInvestors > 0 {
+			averageInvestment = totalRaised / float64(numberOfInvestors)
+		}
+	}
+
+	// Ensure fundingGoal is never nil
+	fundingGoal := founder.FundRequired
+	if fundingGoal <= 0 {
+		fundingGoal = 1 // Prevent division by zero
+	}
+
+	// Calculate percentage with bounds checking
+	percentageComplete := 0
+	if fundingGoal > 0 {
+		percentageComplete = int((totalRaised * 100) / float64(fundingGoal))
+	}
+
+	return &model.FundraisingSummary{
+		TotalRaised:        totalRaised,
+		FundingGoal:        float64(fundingGoal),
+		PercentageComplete: percentageComplete,
+		NumberOfInvestors:  numberOfInvestors,
+		AverageInvestment:  averageInvestment,
+	}, nil
+}
+
+// GetInvestorEngagement returns metrics about investor engagement with the founder
+func (s *founderRepository) GetInvestorEngagement(ctx context.Context, founderID primitive.ObjectID) (*model.InvestorEngagement, error) {
+	// Get matches data
+	matches, err := s.GetFounderMatches(ctx, founderID)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	// Initialize with safe defaults
+	totalMatches := 0
+	newThisMonth := 0
+	inDueDiligence := 0
+	topMatches := []model.TopMatch{}
+
+	// Current month for filtering
+	currentMonth := time.Now().Month()
+
+	if matches != nil {
+		totalMatches = len(matches)
+
+		// Process matches
+		for _, match := range matches {
+			// Count new matches this month
+			if match.CreatedAt.Month() == currentMonth {
+				newThisMonth++
+			}
+
+			// Count deals in due diligence
+			if match.Stage == "Due Diligence" {
+				inDueDiligence++
+			}
+
+			// Add to top matches if score is high enough
+			if match.MatchScore > 70 {
+				topMatches = append(topMatches, model.TopMatch{
+					InvestorID:            match.InvestorID,
+					Name:                  match.InvestorName,
+					MatchPercentage:       match.MatchScore,
+					Industry:              match.Industry,
+					TotalInvested:         match.TotalInvested,
+					PreferredFundingStage: match.PreferredFundingStage,
+				})
+			}
+		}
+	}
+
+	return &model.InvestorEngagement{
+		TotalMatches:   totalMatches,
+		NewThisMonth:   newThisMonth,
+		InDueDiligence: inDueDiligence,
+		TopMatches:     topMatches,
+	}, nil
+}
+
+// SubmitInvestorApplication submits an investor application
+func (s *founderRepository) SubmitInvestorApplication(ctx context.Context, application model.InvestorApplication) (primitive.ObjectID, error) {
+	// Implement investor application submission logic
+	result, err := s.applicationCollection.InsertOne(ctx, application)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+// GetFounderMatches retrieves matches for a specific founder
+func (s *founderRepository) GetFounderMatches(ctx context.Context, founderID primitive.ObjectID) ([]model.Match, error) {
+	var matches []model.Match
+	cursor, err := s.applicationCollection.Find(ctx, primitive.M{"founder_id": founderID, "type": "match"})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &matches)
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// GetFounderByUserID retrieves founder details by user ID
+func (s *founderRepository) GetFounderByUserID(ctx context.Context, userID primitive.ObjectID) (*model.Founder, error) {
+	var founder model.Founder
+	err := s.founderCollection.FindOne(ctx, primitive.M{"user_id": userID}).Decode(&founder)
+	if err != nil {
+		return nil, NotFound(err, ErrFounderNotFound)
+	}
+	return &founder, nil
+}
+
+// GetFounderInvestments retrieves all investments made to a founder's startup
+func (s *founderRepository) GetFounderInvestments(ctx context.Context, founderID primitive.ObjectID) ([]model.Investment, error) {
+	var investments []model.Investment
+	cursor, err := s.applicationCollection.Find(ctx, primitive.M{
+		"founder_id": founderID,
+		"type":       "investment",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &investments)
+	if err != nil {
+		return nil, err
+	}
+	return investments, nil
+}
+
+func (s *founderRepository) UpdateFounder(ctx context.Context, userID primitive.ObjectID, founder model.Founder) (*mongo.UpdateResult, error) {
+	filter := bson.M{"user_id": userID}
+	updateFields := bson.M{
+		"startup_name":       founder.StartupName,
+		"mission_statement":  founder.MissionStatement,
+		"industry":           founder.Industry,
+		"funding_stage":      founder.FundingStage,
+		"funding_allocation": founder.FundingAllocation,
+		"bussiness_model":    founder.BussinessModel,
+		"revenue_streams":    founder.RevenueStreams,
+		"traction":           founder.Traction,
+		"scaling_potential":  founder.ScalingPotential,
+		"total_invested":     founder.TotalInvested,
+		"fund_required":      founder.FundRequired,
+		"competition":        founder.Competition,
+		"leadership_team":    founder.LeadershipTeam,
+		"team_size":          founder.TeamSize,
+		"location":           founder.Location,
+		"startup_website":    founder.StartupWebsite,
+	}
+	update := bson.M{"$set": updateFields}
+	return s.founderCollection.UpdateOne(ctx, filter, update)
+}
+
+// UpdateStartupInvestment adds amount to the founder's total invested
+func (s *founderRepository) UpdateStartupInvestment(ctx context.Context, userID primitive.ObjectID, amount float64) (*mongo.UpdateResult, error) {
+	filter := bson.M{"user_id": userID}
+	update := bson.M{"$inc": bson.M{"total_invested": amount}}
+	return s.founderCollection.UpdateOne(ctx, filter, update)
+}
+
+// GetFounderProfileWithMatch retrieves a founder profile with nested user details,
+// adds a "match" field as a URL with the founder id as a parameter, and sets "tags" and "bookmark" empty.
+func (s *founderRepository) GetFounderProfileWithMatch(ctx context.Context, userID primitive.ObjectID) (bson.M, error) {
+	// Build the aggregation pipeline
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "user_id", Value: userID}}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "users"},
+			{Key: "localField", Value: "user_id"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "user"},
+		}}},
+		// Stage 3: Unwind the user array.
+		{{Key: "$unwind", Value: "$user"}},
+		// Stage 4: Add computed fields: match URL, empty tags, and bookmark false.
+		{{Key: "$addFields", Value: bson.D{
+			{Key: "tags", Value: bson.A{}},
+			{Key: "match", Value: "http://localhost:8080/api/v1/match/data/" + userID.Hex()},
+			{Key: "bookmark", Value: false},
+		}}},
+		// Stage 5: Project the desired fields.
+		{{Key: "$project", Value: bson.D{
+			{Key: "startup_name", Value: 1},
+			{Key: "mission_statement", Value: 1},
+			{Key: "industry", Value: 1},
+			{Key: "funding_stage", Value: 1},
+			{Key: "funding_allocation", Value: 1},
+			{Key: "bussiness_model", Value: 1},
+			{Key: "revenue_streams", Value: 1},
+			{Key: "traction", Value: 1},
+			{Key: "total_invested", Value: 1},
+			{Key: "fund_required", Value: 1},
+			{Key: "year_founded", Value: 1},
+			{Key: "scaling_potential", Value: 1},
+			{Key: "competition", Value: 1},
+			{Key: "leadership_team", Value: 1},
+			{Key: "team_size", Value: 1},
+			{Key: "avatar", Value: 1},
+			{Key: "founded", Value: 1},
+			{Key: "location", Value: 1},
+			{Key: "startup_website", Value: 1},
+			{Key: "pitch_deck", Value: 1},
+			{Key: "created_at", Value: 1},
+			{Key: "updated_at", Value: 1},
+			{Key: "match", Value: 1},
+			{Key: "tags", Value: 1},
+			{Key: "bookmark", Value: 1},
+			{Key: "user.first_name", Value: 1},
+			{Key: "user.second_name", Value: 1},
+			{Key: "user.email", Value: 1},
+			{Key: "user.avatar", Value: 1},
+			{Key: "user.created_at", Value: 1},
+			{Key: "user.updated_at", Value: 1},
+		}}},
+	}
+
+	cursor, err := s.founderCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		slog.ErrorContext(ctx, "founder profile aggregation failed", "error", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []bson.M
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, ErrFounderNotFound
+	}
+
+	return results[0], nil
+}
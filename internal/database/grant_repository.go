@@ -0,0 +1,217 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"DBackend/internal/query"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GrantRepository stores grants and the applications founders submit for them
+type GrantRepository interface {
+	// GetGrants lists grants, optionally narrowed to a category and region
+	GetGrants(ctx context.Context, category, region string) ([]model.Grant, error)
+	// GetGrantsClosingBetween lists the grants whose deadline is in [from, to)
+	GetGrantsClosingBetween(ctx context.Context, from, to time.Time) ([]model.Grant, error)
+	CreateGrant(ctx context.Context, grant model.Grant) (primitive.ObjectID, error)
+	GetGrantByID(ctx context.Context, id primitive.ObjectID) (model.Grant, error)
+	UpdateGrant(ctx context.Context, id primitive.ObjectID, updates model.Grant) error
+	DeleteGrant(ctx context.Context, id primitive.ObjectID) error
+	// SubmitGrantApplication publishes GrantApplicationSubmitted
+	SubmitGrantApplication(ctx context.Context, application model.GrantApplication) (primitive.ObjectID, error)
+	// GetGrantApplicantIDs returns the user IDs of the founders who applied for the grant
+	GetGrantApplicantIDs(ctx context.Context, grantID primitive.ObjectID) ([]primitive.ObjectID, error)
+	GetAllGrantApplications(ctx context.Context, params query.Params) (query.Page[model.GrantApplication], error)
+	GetFounderGrantApplications(ctx context.Context, founderID primitive.ObjectID, params query.Params) (query.Page[model.GrantApplication], error)
+	GetGrantApplicationByID(ctx context.Context, id primitive.ObjectID) (model.GrantApplication, error)
+	UpdateGrantApplication(ctx context.Context, id primitive.ObjectID, status string, remarks string) error
+}
+
+type grantRepository struct {
+	grantCollection       *mongo.Collection
+	applicationCollection *mongo.Collection
+	outbox                OutboxRepository
+}
+
+// NewGrantRepository returns the MongoDB grant repository
+func NewGrantRepository(db *mongo.Database) GrantRepository {
+	return &grantRepository{
+		grantCollection:       db.Collection("grants"),
+		applicationCollection: db.Collection("applications"),
+		outbox:                NewOutboxRepository(db),
+	}
+}
+
+// GetGrants returns available grants based on category and region
+func (s *grantRepository) GetGrants(ctx context.Context, category, region string) ([]model.Grant, error) {
+	filter := bson.M{}
+	if category != "" {
+		filter["category"] = category
+	}
+	if region != "" {
+		filter["region"] = region
+	}
+
+	cursor, err := s.grantCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	grants := []model.Grant{}
+	if err := cursor.All(ctx, &grants); err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+// GetGrantsClosingBetween returns the grants with a deadline from from up to to
+func (s *grantRepository) GetGrantsClosingBetween(ctx context.Context, from, to time.Time) ([]model.Grant, error) {
+	cursor, err := s.grantCollection.Find(ctx, bson.M{"deadline": bson.M{"$gte": from, "$lt": to}})
+	if err != nil {
+		return nil, err
+	}
+	grants := []model.Grant{}
+	if err := cursor.All(ctx, &grants); err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+// CreateGrant creates a new grant
+func (s *grantRepository) CreateGrant(ctx context.Context, grant model.Grant) (primitive.ObjectID, error) {
+	result, err := s.grantCollection.InsertOne(ctx, grant)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+// GetGrantByID retrieves a grant by its ID
+func (s *grantRepository) GetGrantByID(ctx context.Context, id primitive.ObjectID) (model.Grant, error) {
+	var grant model.Grant
+	err := s.grantCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&grant)
+	if err != nil {
+		return model.Grant{}, NotFound(err, ErrGrantNotFound)
+	}
+	return grant, nil
+}
+
+// UpdateGrant updates an existing grant
+func (s *grantRepository) UpdateGrant(ctx context.Context, id primitive.ObjectID, updates model.Grant) error {
+	result, err := s.grantCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": updates},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrGrantNotFound
+	}
+	return nil
+}
+
+// DeleteGrant deletes a grant
+func (s *grantRepository) DeleteGrant(ctx context.Context, id primitive.ObjectID) error {
+	result, err := s.grantCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrGrantNotFound
+	}
+	return nil
+}
+
+// SubmitGrantApplication submits a grant application
+func (s *grantRepository) SubmitGrantApplication(ctx context.Context, application model.GrantApplication) (primitive.ObjectID, error) {
+	if application.ID.IsZero() {
+		application.ID = primitive.NewObjectID()
+	}
+	if _, err := s.applicationCollection.InsertOne(ctx, application); err != nil {
+		return primitive.NilObjectID, err
+	}
+	err := s.outbox.Publish(ctx, model.GrantApplicationSubmitted{
+		ApplicationID: application.ID,
+		GrantID:       application.GrantID,
+		FounderID:     application.FounderID,
+	})
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return application.ID, nil
+}
+
+// GetGrantApplicantIDs returns the distinct founders that applied for the grant
+func (s *grantRepository) GetGrantApplicantIDs(ctx context.Context, grantID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	values, err := s.applicationCollection.Distinct(ctx, "founder_id", bson.M{"grant_id": grantID})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(primitive.ObjectID); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// GetAllGrantApplications retrieves a page of grant applications
+func (s *grantRepository) GetAllGrantApplications(ctx context.Context, params query.Params) (query.Page[model.GrantApplication], error) {
+	return s.listGrantApplications(ctx, nil, params)
+}
+
+// GetFounderGrantApplications retrieves a page of grant applications for a specific founder
+func (s *grantRepository) GetFounderGrantApplications(ctx context.Context, founderID primitive.ObjectID, params query.Params) (query.Page[model.GrantApplication], error) {
+	return s.listGrantApplications(ctx, bson.M{"founder_id": founderID}, params)
+}
+
+func (s *grantRepository) listGrantApplications(ctx context.Context, base bson.M, params query.Params) (query.Page[model.GrantApplication], error) {
+	total, err := s.applicationCollection.CountDocuments(ctx, params.CountFilter(base))
+	if err != nil {
+		return query.Page[model.GrantApplication]{}, err
+	}
+
+	cursor, err := s.applicationCollection.Find(ctx, params.Match(base), params.FindOptions())
+	if err != nil {
+		return query.Page[model.GrantApplication]{}, err
+	}
+	return query.Collect[model.GrantApplication](ctx, cursor, params, total)
+}
+
+// GetGrantApplicationByID retrieves a specific grant application
+func (s *grantRepository) GetGrantApplicationByID(ctx context.Context, id primitive.ObjectID) (model.GrantApplication, error) {
+	var application model.GrantApplication
+	err := s.applicationCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&application)
+	if err != nil {
+		return model.GrantApplication{}, NotFound(err, ErrGrantApplicationNotFound)
+	}
+	return application, nil
+}
+
+// UpdateGrantApplication updates the status and remarks of a grant application
+func (s *grantRepository) UpdateGrantApplication(ctx context.Context, id primitive.ObjectID, status string, remarks string) error {
+	result, err := s.applicationCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{
+			"status":  status,
+			"remarks": remarks,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrGrantApplicationNotFound
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"DBackend/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InvestmentRepository stores the investments made through closed deals
+type InvestmentRepository interface {
+	// CreateInvestment publishes InvestmentRecorded
+	CreateInvestment(ctx context.Context, investment model.Investment) (*mongo.InsertOneResult, error)
+	GetInvestmentsByInvestorID(ctx context.Context, investorID primitive.ObjectID) ([]model.Investment, error)
+	GetInvestmentsByFounderID(ctx context.Context, founderID primitive.ObjectID) ([]model.Investment, error)
+	GetInvestmentsByDealID(ctx context.Context, dealID primitive.ObjectID) ([]model.Investment, error)
+}
+
+type investmentRepository struct {
+	investmentCollection *mongo.Collection
+	outbox               OutboxRepository
+}
+
+// NewInvestmentRepository creates a new investment service
+func NewInvestmentRepository(db *mongo.Database) InvestmentRepository {
+	return &investmentRepository{
+		investmentCollection: db.Collection("investments"),
+		outbox:               NewOutboxRepository(db),
+	}
+}
+
+// CreateInvestment creates a new investment record
+func (s *investmentRepository) CreateInvestment(ctx context.Context, investment model.Investment) (*mongo.InsertOneResult, error) {
+	if investment.ID.IsZero() {
+		investment.ID = primitive.NewObjectID()
+	}
+	investment.CreatedAt = time.Now()
+	investment.UpdatedAt = time.Now()
+	result, err := s.investmentCollection.InsertOne(ctx, investment)
+	if err != nil {
+		return nil, err
+	}
+	err = s.outbox.Publish(ctx, model.InvestmentRecorded{
+		InvestmentID: investment.ID,
+		DealID:       investment.DealID,
+		InvestorID:   investment.InvestorID,
+		FounderID:    investment.FounderID,
+		Amount:       investment.Amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetInvestmentsByInvestorID retrieves all investments made by an investor
+func (s *investmentRepository) GetInvestmentsByInvestorID(ctx context.Context, investorID primitive.ObjectID) ([]model.Investment, error) {
+	cursor, err := s.investmentCollection.Find(ctx, bson.M{"investor_id": investorID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var investments []model.Investment
+	if err = cursor.All(ctx, &investments); err != nil {
+		return nil, err
+	}
+	return investments, nil
+}
+
+// GetInvestmentsByFounderID retrieves all investments received by a founder
+func (s *investmentRepository) GetInvestmentsByFounderID(ctx context.Context, founderID primitive.ObjectID) ([]model.Investment, error) {
+	cursor, err := s.investmentCollection.Find(ctx, bson.M{"founder_id": founderID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var investments []model.Investment
+	if err = cursor.All(ctx, &investments); err != nil {
+		return nil, err
+	}
+	return investments, nil
+}
+
+// GetInvestmentsByDealID retrieves all investments for a specific deal
+func (s *investmentRepository) GetInvestmentsByDealID(ctx context.Context, dealID primitive.ObjectID) ([]model.Investment, error) {
+	cursor, err := s.investmentCollection.Find(ctx, bson.M{"deal_id": dealID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var investments []model.Investment
+	if err = cursor.All(ctx, &investments); err != nil {
+		return nil, err
+	}
+	return investments, nil
+}
@@ -4,54 +4,137 @@ import (
 	"DBackend/model"
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"math"
 	"math/rand"
-	"os"
 	"sort"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// InvestorService defines methods for investor-related operations
-type InvestorService interface {
-	GetPortfolioSummary(ctx context.Context, investorID primitive.ObjectID) (map[string]interface{}, error)
-	GetPipelineSummary(ctx context.Context, investorID primitive.ObjectID) (map[string]interface{}, error)
-	GetPerformanceData(ctx context.Context, investorID primitive.ObjectID, period string) ([]map[string]interface{}, error)
-	GetPerformanceMetrics(ctx context.Context, investorID primitive.ObjectID, period string) (map[string]interface{}, error)
-	GetRecentActivities(ctx context.Context, investorID primitive.ObjectID) ([]map[string]interface{}, error)
-	// Other methods...
+// InvestorRepository stores investor profiles, their matches and dashboards
+type InvestorRepository interface {
+	GetInvestorByUserID(ctx context.Context, userID primitive.ObjectID) (*model.Investor, error)
+	UpdateInvestor(ctx context.Context, userID primitive.ObjectID, investor model.Investor) (*mongo.UpdateResult, error)
+	// UpdateInvestorPortfolio adds amount to the investor's total and records the startup in their portfolio
+	UpdateInvestorPortfolio(ctx context.Context, investorID, startupID primitive.ObjectID, amount float64) (*mongo.UpdateResult, error)
+	GetInvestors(ctx context.Context, industry, stage string) ([]model.Investor, error)
+	// ListInvestors returns up to limit investors with an _id after after, in
+	// _id order, so jobs can go through every investor a page at a time
+	ListInvestors(ctx context.Context, after primitive.ObjectID, limit int) ([]model.Investor, error)
+	AddMatch(ctx context.Context, match model.MatchInvestorFounder) (*mongo.InsertOneResult, error)
+	// StoreMatch records the match score of an investor and founder, replacing an earlier one
+	StoreMatch(ctx context.Context, match model.MatchInvestorFounder) (*mongo.InsertOneResult, error)
+	// GetMatchTimes returns when the investor's match with each founder was
+	// last scored, by founder user ID
+	GetMatchTimes(ctx context.Context, investorID primitive.ObjectID) (map[primitive.ObjectID]time.Time, error)
+	// RecordValuation snapshots the value of the investor's portfolio on date,
+	// replacing an earlier snapshot of that date
+	RecordValuation(ctx context.Context, investorID primitive.ObjectID, date time.Time) (*model.PortfolioValuation, error)
+	GetPortfolioSummary(ctx context.Context, investorID primitive.ObjectID) (*model.PortfolioSummary, error)
+	GetPipelineSummary(ctx context.Context, investorID primitive.ObjectID) (*model.PipelineSummary, error)
+	GetPerformanceData(ctx context.Context, investorID primitive.ObjectID, period string) ([]model.PerformancePoint, error)
+	GetPerformanceMetrics(ctx context.Context, investorID primitive.ObjectID, period string) (*model.PerformanceMetrics, error)
+	GetRecentActivities(ctx context.Context, investorID primitive.ObjectID) ([]model.RecentActivity, error)
 }
 
-type investorService struct {
-	investorCollection *mongo.Collection
-	dealFlowCollection *mongo.Collection
-	activityCollection *mongo.Collection
-	founderCollection  *mongo.Collection
-	matchCollection    *mongo.Collection
+type investorRepository struct {
+	investorCollection             *mongo.Collection
+	dealFlowCollection             *mongo.Collection
+	activityCollection             *mongo.Collection
+	founderCollection              *mongo.Collection
+	matchCollection                *mongo.Collection
+	matchFounderInvestorCollection *mongo.Collection
+	valuationCollection            *mongo.Collection
 }
 
-// NewInvestorService initializes the investor service
-func NewInvestorService(client *mongo.Client) InvestorService {
-	dbName := os.Getenv("BLUEPRINT_DB_DATABASE")
-	if dbName == "" {
-		dbName = "ddb" // Fallback name
+// NewInvestorRepository returns the MongoDB investor repository
+func NewInvestorRepository(db *mongo.Database) InvestorRepository {
+	return &investorRepository{
+		investorCollection:             db.Collection("investors"),
+		dealFlowCollection:             db.Collection("deal_flow"),
+		activityCollection:             db.Collection("activities"),
+		founderCollection:              db.Collection("founders"),
+		matchCollection:                db.Collection("matches"),
+		matchFounderInvestorCollection: db.Collection("match_founder_investor"),
+		valuationCollection:            db.Collection("portfolio_valuations"),
 	}
+}
+
+// GetInvestorByUserID retrieves investor details by user ID
+func (s *investorRepository) GetInvestorByUserID(ctx context.Context, userID primitive.ObjectID) (*model.Investor, error) {
+	var investor model.Investor
+	if err := s.investorCollection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&investor); err != nil {
+		return nil, NotFound(err, ErrInvestorNotFound)
+	}
+	return &investor, nil
+}
+
+func (s *investorRepository) UpdateInvestor(ctx context.Context, userID primitive.ObjectID, investor model.Investor) (*mongo.UpdateResult, error) {
+	filter := bson.M{"user_id": userID}
+	updateFields := bson.M{
+		//		"investment_portfolio":    investor.InvestmentPortfolio,
+		"total_invested":          investor.TotalInvested,
+		"investor_type":           investor.InvestorType,
+		"thesis":                  investor.Thesis,
+		"preferred_funding_stage": investor.PreferredFundingStage,
+		"investment_range":        investor.InvestmentRange,
+		"investment_frequency":    investor.InvestmentFrequency,
+		"risk_tolerance":          investor.RiskTolerance,
+		"exit_strategy":           investor.ExitStrategy,
+		"preferred_industries":    investor.PreferredIndustries,
+		"preferred_regions":       investor.PreferredRegions,
+	}
+	update := bson.M{"$set": updateFields}
+	return s.investorCollection.UpdateOne(ctx, filter, update)
+}
+
+// Update investor's total investment and add to portfolio
+func (s *investorRepository) UpdateInvestorPortfolio(ctx context.Context, investorID, startupID primitive.ObjectID, amount float64) (*mongo.UpdateResult, error) {
+	filter := bson.M{"user_id": investorID}
+	update := bson.M{
+		"$inc": bson.M{"total_invested": amount},
+		"$push": bson.M{"investment_portfolio": bson.M{
+			"startup_id": startupID,
+			"amount":     amount,
+		}},
+	}
+	return s.investorCollection.UpdateOne(ctx, filter, update)
+}
 
-	return &investorService{
-		investorCollection: client.Database(dbName).Collection("investors"),
-		dealFlowCollection: client.Database(dbName).Collection("deal_flow"),
-		activityCollection: client.Database(dbName).Collection("activities"),
-		founderCollection:  client.Database(dbName).Collection("founders"),
-		matchCollection:    client.Database(dbName).Collection("matches"),
+// GetInvestors returns available investors based on industry and stage
+func (s *investorRepository) GetInvestors(ctx context.Context, industry, stage string) ([]model.Investor, error) {
+	// Implement investors retrieval logic
+	return []model.Investor{}, nil
+}
+
+// ListInvestors returns a page of investors in _id order
+func (s *investorRepository) ListInvestors(ctx context.Context, after primitive.ObjectID, limit int) ([]model.Investor, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit))
+	cursor, err := s.investorCollection.Find(ctx, bson.M{"_id": bson.M{"$gt": after}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	investors := []model.Investor{}
+	if err := cursor.All(ctx, &investors); err != nil {
+		return nil, err
 	}
+	return investors, nil
+}
+
+// AddMatch adds a new match between an investor and a founder
+func (s *investorRepository) AddMatch(ctx context.Context, match model.MatchInvestorFounder) (*mongo.InsertOneResult, error) {
+	match.CreatedAt = time.Now()
+	match.UpdatedAt = time.Now()
+	return s.matchFounderInvestorCollection.InsertOne(ctx, match)
 }
 
 // GetPortfolioSummary returns a summary of the investor's portfolio
-func (s *investorService) GetPortfolioSummary(ctx context.Context, investorID primitive.ObjectID) (map[string]interface{}, error) {
+func (s *investorRepository) GetPortfolioSummary(ctx context.Context, investorID primitive.ObjectID) (*model.PortfolioSummary, error) {
 	// Query the investor collection to get the investor's portfolio
 	filter := bson.M{"user_id": investorID}
 	var investorData bson.M
@@ -59,20 +142,15 @@ func (s *investorService) GetPortfolioSummary(ctx context.Context, investorID pr
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			// Return empty data if investor not found
-			return map[string]interface{}{
-				"totalInvested": 0,
-				"totalStartups": 0,
-				"avgReturn":     0,
-				"topPerformers": []map[string]interface{}{},
-			}, nil
+			return &model.PortfolioSummary{TopPerformers: []model.TopPerformer{}}, nil
 		}
 		return nil, err
 	}
 
-	totalInvested := 0
+	totalInvested := 0.0
 	totalStartups := 0
 	avgReturn := 0.0
-	var topPerformers []map[string]interface{}
+	topPerformers := []model.TopPerformer{}
 	var performanceData []struct {
 		startup      bson.M
 		roi          float64
@@ -83,11 +161,11 @@ func (s *investorService) GetPortfolioSummary(ctx context.Context, investorID pr
 	// Extract total invested amount
 	if val, ok := investorData["total_invested"]; ok {
 		if totalInv, ok := val.(int32); ok {
-			totalInvested = int(totalInv)
+			totalInvested = float64(totalInv)
 		} else if totalInv, ok := val.(int64); ok {
-			totalInvested = int(totalInv)
+			totalInvested = float64(totalInv)
 		} else if totalInv, ok := val.(float64); ok {
-			totalInvested = int(totalInv)
+			totalInvested = totalInv
 		}
 	}
 
@@ -200,28 +278,27 @@ func (s *investorService) GetPortfolioSummary(ctx context.Context, investorID pr
 			industry = ind
 		}
 
-		performer := map[string]interface{}{
-			"id":           data.startup["_id"],
-			"name":         startupName,
-			"industry":     industry,
-			"growth":       data.roi,
-			"invested":     data.invested,
-			"currentValue": data.currentValue,
-			"roi":          data.roi,
-		}
-		topPerformers = append(topPerformers, performer)
+		id, _ := data.startup["_id"].(primitive.ObjectID)
+		topPerformers = append(topPerformers, model.TopPerformer{
+			ID:           id,
+			Name:         startupName,
+			Industry:     industry,
+			Invested:     data.invested,
+			CurrentValue: data.currentValue,
+			ROI:          data.roi,
+		})
 	}
 
-	return map[string]interface{}{
-		"totalInvested": totalInvested,
-		"totalStartups": totalStartups,
-		"avgReturn":     avgReturn,
-		"topPerformers": topPerformers,
+	return &model.PortfolioSummary{
+		TotalInvested: totalInvested,
+		TotalStartups: totalStartups,
+		AvgReturn:     avgReturn,
+		TopPerformers: topPerformers,
 	}, nil
 }
 
 // GetPipelineSummary returns a summary of the investor's pipeline
-func (s *investorService) GetPipelineSummary(ctx context.Context, investorID primitive.ObjectID) (map[string]interface{}, error) {
+func (s *investorRepository) GetPipelineSummary(ctx context.Context, investorID primitive.ObjectID) (*model.PipelineSummary, error) {
 	// Query the deal flow collection to get the investor's pipeline
 	pipeline := mongo.Pipeline{
 		{{Key: "$match", Value: bson.D{{Key: "investor_id", Value: investorID}}}},
@@ -255,7 +332,7 @@ func (s *investorService) GetPipelineSummary(ctx context.Context, investorID pri
 					{Key: "founder_id", Value: 1},
 					{Key: "status", Value: 1},
 					{Key: "stage", Value: 1},
-					{Key: "match_percentage", Value: 1},
+					{Key: "match_score", Value: 1},
 					{Key: "updated_at", Value: 1},
 					{Key: "startup_name", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$founder.startup_name", 0}}}},
 					{Key: "industry", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$founder.industry", 0}}}},
@@ -270,82 +347,66 @@ func (s *investorService) GetPipelineSummary(ctx context.Context, investorID pri
 	}
 	defer cursor.Close(ctx)
 
-	var results []bson.M
+	var results []struct {
+		TotalDeals []struct {
+			Count int `bson:"count"`
+		} `bson:"totalDeals"`
+		ByStage []struct {
+			Stage string `bson:"_id"`
+			Count int    `bson:"count"`
+		} `bson:"byStage"`
+		RecentDeals []struct {
+			ID          primitive.ObjectID `bson:"_id"`
+			FounderID   primitive.ObjectID `bson:"founder_id"`
+			StartupName string             `bson:"startup_name"`
+			Industry    string             `bson:"industry"`
+			Stage       string             `bson:"stage"`
+			MatchScore  float64            `bson:"match_score"`
+			UpdatedAt   time.Time          `bson:"updated_at"`
+		} `bson:"recentDeals"`
+	}
 	if err = cursor.All(ctx, &results); err != nil {
 		return nil, err
 	}
 
-	totalDeals := 0
-	pendingDeals := 0
-	closedDeals := 0
-	var recentDeals []map[string]interface{}
-
-	if len(results) > 0 {
-		result := results[0]
-
-		// Extract total deals count
-		if totalDealsArr, ok := result["totalDeals"].(primitive.A); ok && len(totalDealsArr) > 0 {
-			if countDoc, ok := totalDealsArr[0].(bson.M); ok {
-				if count, ok := countDoc["count"].(int32); ok {
-					totalDeals = int(count)
-				}
-			}
-		}
+	summary := &model.PipelineSummary{RecentDeals: []model.PipelineDeal{}}
+	if len(results) == 0 {
+		return summary, nil
+	}
+	result := results[0]
 
-		// Extract deals by stage
-		if byStage, ok := result["byStage"].(primitive.A); ok {
-			for _, stageGroup := range byStage {
-				if group, ok := stageGroup.(bson.M); ok {
-					stage, hasStage := group["_id"].(string)
-					count, hasCount := group["count"].(int32)
-
-					if hasStage && hasCount {
-						if stage == "closed_won" || stage == "closed_lost" {
-							closedDeals += int(count)
-						} else {
-							pendingDeals += int(count)
-						}
-					}
-				}
-			}
+	if len(result.TotalDeals) > 0 {
+		summary.TotalDeals = result.TotalDeals[0].Count
+	}
+	for _, group := range result.ByStage {
+		if group.Stage == "closed_won" || group.Stage == "closed_lost" {
+			summary.ClosedDeals += group.Count
+		} else {
+			summary.PendingDeals += group.Count
 		}
-
-		// Extract recent deals
-		if recentDealsArr, ok := result["recentDeals"].(primitive.A); ok {
-			for _, deal := range recentDealsArr {
-				if dealDoc, ok := deal.(bson.M); ok {
-					stage := dealDoc["stage"].(string)
-					status := "pending"
-					if stage == "Closed Won" || stage == "Closed Lost" {
-						status = "closed"
-					}
-
-					recentDeal := map[string]interface{}{
-						"id":               dealDoc["_id"],
-						"founder_id":       dealDoc["founder_id"],
-						"startup_name":     dealDoc["startup_name"],
-						"industry":         dealDoc["industry"],
-						"status":           status,
-						"stage":            stage,
-						"match_percentage": dealDoc["match_percentage"],
-						"updated_at":       dealDoc["updated_at"],
-					}
-					recentDeals = append(recentDeals, recentDeal)
-				}
-			}
+	}
+	for _, deal := range result.RecentDeals {
+		status := "pending"
+		if deal.Stage == "Closed Won" || deal.Stage == "Closed Lost" {
+			status = "closed"
 		}
+		summary.RecentDeals = append(summary.RecentDeals, model.PipelineDeal{
+			ID:              deal.ID,
+			FounderID:       deal.FounderID,
+			StartupName:     deal.StartupName,
+			Industry:        deal.Industry,
+			Status:          status,
+			Stage:           deal.Stage,
+			MatchPercentage: deal.MatchScore,
+			UpdatedAt:       deal.UpdatedAt,
+		})
 	}
 
-	return map[string]interface{}{
-		"totalDeals":   totalDeals,
-		"pendingDeals": pendingDeals,
-		"closedDeals":  closedDeals,
-		"recentDeals":  recentDeals,
-	}, nil
+	return summary, nil
 }
 
 // GetPerformanceData returns performance data for the specified period
-func (s *investorService) GetPerformanceData(ctx context.Context, investorID primitive.ObjectID, period string) ([]map[string]interface{}, error) {
+func (s *investorRepository) GetPerformanceData(ctx context.Context, investorID primitive.ObjectID, period string) ([]model.PerformancePoint, error) {
 	// Determine date range based on period
 	endDate := time.Now()
 	var startDate time.Time
@@ -369,10 +430,10 @@ func (s *investorService) GetPerformanceData(ctx context.Context, investorID pri
 
 	// Query the investor's portfolio and valuation history
 	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.D{{Key: "_id", Value: investorID}}}},
+		{{Key: "$match", Value: bson.D{{Key: "user_id", Value: investorID}}}},
 		{{Key: "$lookup", Value: bson.D{
 			{Key: "from", Value: "portfolio_valuations"},
-			{Key: "localField", Value: "_id"},
+			{Key: "localField", Value: "user_id"},
 			{Key: "foreignField", Value: "investor_id"},
 			{Key: "as", Value: "valuations"},
 		}}},
@@ -404,25 +465,18 @@ func (s *investorService) GetPerformanceData(ctx context.Context, investorID pri
 	}
 
 	// Process the performance data
-	var performanceData []map[string]interface{}
+	performanceData := []model.PerformancePoint{}
 
 	if len(results) > 0 && len(results[0]) > 0 {
 		if valuations, ok := results[0]["valuations"].(primitive.A); ok {
-			// Sort valuations by date
-			type valuation struct {
-				Date  time.Time
-				Value float64
-			}
-
-			var valuationData []valuation
 			for _, v := range valuations {
 				if val, ok := v.(bson.M); ok {
-					date, hasDate := val["date"].(time.Time)
+					date, hasDate := val["date"].(primitive.DateTime)
 					value, hasValue := val["value"].(float64)
 
 					if hasDate && hasValue {
-						valuationData = append(valuationData, valuation{
-							Date:  date,
+						performanceData = append(performanceData, model.PerformancePoint{
+							Date:  date.Time(),
 							Value: value,
 						})
 					}
@@ -430,18 +484,10 @@ func (s *investorService) GetPerformanceData(ctx context.Context, investorID pri
 			}
 
 			// Sort by date
-			sort.Slice(valuationData, func(i, j int) bool {
-				return valuationData[i].Date.Before(valuationData[j].Date)
+			sort.Slice(performanceData, func(i, j int) bool {
+				return performanceData[i].Date.Before(performanceData[j].Date)
 			})
 
-			// Convert to the required format
-			for _, v := range valuationData {
-				performanceData = append(performanceData, map[string]interface{}{
-					"date":  v.Date,
-					"value": v.Value,
-				})
-			}
-
 			// If no data points exist, create synthetic data for visualization
 			if len(performanceData) == 0 {
 				// Generate monthly data points for the period
@@ -449,9 +495,9 @@ func (s *investorService) GetPerformanceData(ctx context.Context, investorID pri
 				initialValue := 100000.0 // Example initial portfolio value
 
 				for currentDate.Before(endDate) {
-					performanceData = append(performanceData, map[string]interface{}{
-						"date":  currentDate,
-						"value": initialValue,
+					performanceData = append(performanceData, model.PerformancePoint{
+						Date:  currentDate,
+						Value: initialValue,
 					})
 
 					// Move to next month and add some random variation
@@ -467,7 +513,7 @@ func (s *investorService) GetPerformanceData(ctx context.Context, investorID pri
 }
 
 // GetPerformanceMetrics returns performance metrics for the specified period
-func (s *investorService) GetPerformanceMetrics(ctx context.Context, investorID primitive.ObjectID, period string) (map[string]interface{}, error) {
+func (s *investorRepository) GetPerformanceMetrics(ctx context.Context, investorID primitive.ObjectID, period string) (*model.PerformanceMetrics, error) {
 	// Get performance data for the period
 	performanceData, err := s.GetPerformanceData(ctx, investorID, period)
 	if err != nil {
@@ -482,15 +528,15 @@ func (s *investorService) GetPerformanceMetrics(ctx context.Context, investorID
 	// Calculate metrics if we have data
 	if len(performanceData) > 1 {
 		// Get initial and final values
-		initialValue := performanceData[0]["value"].(float64)
-		finalValue := performanceData[len(performanceData)-1]["value"].(float64)
+		initialValue := performanceData[0].Value
+		finalValue := performanceData[len(performanceData)-1].Value
 
 		// Calculate total return
 		totalReturn = ((finalValue - initialValue) / initialValue) * 100
 
 		// Calculate time period in years
-		startDate := performanceData[0]["date"].(time.Time)
-		endDate := performanceData[len(performanceData)-1]["date"].(time.Time)
+		startDate := performanceData[0].Date
+		endDate := performanceData[len(performanceData)-1].Date
 		yearsDiff := endDate.Sub(startDate).Hours() / 24 / 365
 
 		// Calculate annualized return
@@ -504,7 +550,7 @@ func (s *investorService) GetPerformanceMetrics(ctx context.Context, investorID
 			var prevValue float64
 
 			for i, data := range performanceData {
-				currentValue := data["value"].(float64)
+				currentValue := data.Value
 
 				if i > 0 {
 					periodReturn := (currentValue - prevValue) / prevValue
@@ -536,15 +582,15 @@ func (s *investorService) GetPerformanceMetrics(ctx context.Context, investorID
 		}
 	}
 
-	return map[string]interface{}{
-		"totalReturn":      totalReturn,
-		"annualizedReturn": annualizedReturn,
-		"volatility":       volatility,
+	return &model.PerformanceMetrics{
+		TotalReturn:      totalReturn,
+		AnnualizedReturn: annualizedReturn,
+		Volatility:       volatility,
 	}, nil
 }
 
 // GetRecentActivities returns recent activities for an investor
-func (s *investorService) GetRecentActivities(ctx context.Context, investorID primitive.ObjectID) ([]map[string]interface{}, error) {
+func (s *investorRepository) GetRecentActivities(ctx context.Context, investorID primitive.ObjectID) ([]model.RecentActivity, error) {
 	// Query the activities collection to get recent investor activities
 	pipeline := mongo.Pipeline{
 		{{Key: "$match", Value: bson.D{{Key: "investor_id", Value: investorID}}}},
@@ -564,21 +610,20 @@ func (s *investorService) GetRecentActivities(ctx context.Context, investorID pr
 	}
 	defer cursor.Close(ctx)
 
-	var activities []map[string]interface{}
-	if err = cursor.All(ctx, &activities); err != nil {
+	var docs []model.Activity
+	if err = cursor.All(ctx, &docs); err != nil {
 		return nil, err
 	}
 
-	// If no activities found, return empty array
-	if activities == nil {
-		activities = []map[string]interface{}{}
+	activities := make([]model.RecentActivity, 0, len(docs))
+	for _, a := range docs {
+		activities = append(activities, model.RecentActivity{ID: a.ID, Type: a.Type, Description: a.Description, Date: a.Date})
 	}
-
 	return activities, nil
 }
 
 // GetMatches returns matches for an investor
-func (s *investorService) GetMatches(ctx context.Context, investorID primitive.ObjectID) ([]map[string]interface{}, error) {
+func (s *investorRepository) GetMatches(ctx context.Context, investorID primitive.ObjectID) ([]map[string]interface{}, error) {
 	pipeline := mongo.Pipeline{
 		{{Key: "$match", Value: bson.D{{Key: "investor_id", Value: investorID}}}},
 		{{Key: "$lookup", Value: bson.D{
@@ -615,8 +660,83 @@ func (s *investorService) GetMatches(ctx context.Context, investorID primitive.O
 	return matches, nil
 }
 
+// GetMatchTimes returns when each of the investor's stored matches was last updated
+func (s *investorRepository) GetMatchTimes(ctx context.Context, investorID primitive.ObjectID) (map[primitive.ObjectID]time.Time, error) {
+	opts := options.Find().SetProjection(bson.M{"founder_id": 1, "updated_at": 1})
+	cursor, err := s.matchCollection.Find(ctx, bson.M{"investor_id": investorID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var matches []model.MatchInvestorFounder
+	if err := cursor.All(ctx, &matches); err != nil {
+		return nil, err
+	}
+	times := make(map[primitive.ObjectID]time.Time, len(matches))
+	for _, m := range matches {
+		times[m.FounderID] = m.UpdatedAt
+	}
+	return times, nil
+}
+
+// RecordValuation values each startup in the investor's portfolio as the
+// portfolio summary does: at its current_value, else at the amount invested
+// grown by the startup's growth rate
+func (s *investorRepository) RecordValuation(ctx context.Context, investorID primitive.ObjectID, date time.Time) (*model.PortfolioValuation, error) {
+	var investor struct {
+		Portfolio []struct {
+			StartupID    primitive.ObjectID `bson:"startup_id"`
+			Amount       float64            `bson:"amount"`
+			CurrentValue *float64           `bson:"current_value"`
+		} `bson:"investment_portfolio"`
+	}
+	if err := s.investorCollection.FindOne(ctx, bson.M{"user_id": investorID}).Decode(&investor); err != nil {
+		return nil, NotFound(err, ErrInvestorNotFound)
+	}
+
+	valuation := model.PortfolioValuation{InvestorID: investorID, Date: date, Assets: []model.PortfolioAsset{}}
+	assets := map[primitive.ObjectID]int{}
+	for _, investment := range investor.Portfolio {
+		var startup struct {
+			Name       string  `bson:"startup_name"`
+			GrowthRate float64 `bson:"growth_rate"`
+		}
+		err := s.founderCollection.FindOne(ctx, bson.M{"user_id": investment.StartupID}).Decode(&startup)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+		value := investment.Amount * (1 + startup.GrowthRate/100)
+		if investment.CurrentValue != nil {
+			value = *investment.CurrentValue
+		}
+		valuation.Value += value
+		if i, ok := assets[investment.StartupID]; ok {
+			valuation.Assets[i].Value += value
+			continue
+		}
+		assets[investment.StartupID] = len(valuation.Assets)
+		valuation.Assets = append(valuation.Assets, model.PortfolioAsset{
+			StartupID:         investment.StartupID,
+			StartupName:       startup.Name,
+			Value:             value,
+			LastValuationDate: date,
+		})
+	}
+
+	err := s.valuationCollection.FindOneAndUpdate(ctx,
+		bson.M{"investor_id": investorID, "date": date},
+		bson.M{
+			"$set":         bson.M{"value": valuation.Value, "assets": valuation.Assets},
+			"$setOnInsert": bson.M{"_id": primitive.NewObjectID()},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&valuation)
+	if err != nil {
+		return nil, err
+	}
+	return &valuation, nil
+}
+
 // StoreMatch stores a match between investor and founder
-func (s *investorService) StoreMatch(ctx context.Context, match model.MatchInvestorFounder) (*mongo.InsertOneResult, error) {
+func (s *investorRepository) StoreMatch(ctx context.Context, match model.MatchInvestorFounder) (*mongo.InsertOneResult, error) {
 	// Check if match already exists
 	filter := bson.M{
 		"investor_id": match.InvestorID,
@@ -665,7 +785,7 @@ func (s *investorService) StoreMatch(ctx context.Context, match model.MatchInves
 	_, err = s.activityCollection.InsertOne(ctx, activity)
 	if err != nil {
 		// Log error but don't fail the match creation
-		log.Printf("Failed to create activity record: %v", err)
+		slog.WarnContext(ctx, "failed to create activity record", "investor_id", match.InvestorID.Hex(), "error", err)
 	}
 
 	return result, nil
@@ -0,0 +1,166 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// JobRunRetention is how long the history of job runs is kept
+const JobRunRetention = 30 * 24 * time.Hour
+
+// JobRepository stores the schedule, lease and run history of background
+// jobs. Leases make sure one replica at a time runs each job.
+type JobRepository interface {
+	// RegisterJob creates the job due at next, or moves it to next when its
+	// schedule changed. A job already on schedule is left as it is.
+	RegisterJob(ctx context.Context, name, schedule string, next time.Time) error
+	// ClaimJob gives owner the lease on the job until until when the job is
+	// due at now and nobody holds it, and reports whether it did
+	ClaimJob(ctx context.Context, name, owner string, now, until time.Time) (*model.Job, bool, error)
+	// RenewLease extends owner's lease and reports false once it was lost
+	RenewLease(ctx context.Context, name, owner string, until time.Time) (bool, error)
+	// ReleaseJob stores when the job is next due and how its last run went,
+	// giving up owner's lease. It fails with ErrJobLeaseLost when owner no
+	// longer holds it.
+	ReleaseJob(ctx context.Context, job model.Job, owner string) error
+	RecordJobRun(ctx context.Context, run model.JobRun) error
+	ListJobs(ctx context.Context) ([]model.Job, error)
+	// GetJobRuns returns the latest runs of a job, newest first
+	GetJobRuns(ctx context.Context, name string, limit int) ([]model.JobRun, error)
+	// TriggerJob makes the job due at at
+	TriggerJob(ctx context.Context, name string, at time.Time) error
+}
+
+type jobRepository struct {
+	jobCollection *mongo.Collection
+	runCollection *mongo.Collection
+}
+
+// NewJobRepository returns the MongoDB job repository
+func NewJobRepository(db *mongo.Database) JobRepository {
+	return &jobRepository{jobCollection: db.Collection("jobs"), runCollection: db.Collection("job_runs")}
+}
+
+// RegisterJob upserts the job when its stored schedule differs; the upsert
+// of a job that is already on schedule fails on the _id and is ignored
+func (s *jobRepository) RegisterJob(ctx context.Context, name, schedule string, next time.Time) error {
+	now := time.Now()
+	_, err := s.jobCollection.UpdateOne(ctx,
+		bson.M{"_id": name, "schedule": bson.M{"$ne": schedule}},
+		bson.M{
+			"$set":         bson.M{"schedule": schedule, "next_run": next, "attempt": 0, "updated_at": now},
+			"$setOnInsert": bson.M{"lease_owner": "", "lease_until": time.Time{}},
+		},
+		options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// ClaimJob takes the lease with a single conditional update, so replicas
+// racing for a due job cannot both get it
+func (s *jobRepository) ClaimJob(ctx context.Context, name, owner string, now, until time.Time) (*model.Job, bool, error) {
+	var job model.Job
+	err := s.jobCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": name, "next_run": bson.M{"$lte": now}, "lease_until": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"lease_owner": owner, "lease_until": until, "updated_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return &job, true, nil
+}
+
+// RenewLease extends the lease while owner still holds it
+func (s *jobRepository) RenewLease(ctx context.Context, name, owner string, until time.Time) (bool, error) {
+	result, err := s.jobCollection.UpdateOne(ctx,
+		bson.M{"_id": name, "lease_owner": owner},
+		bson.M{"$set": bson.M{"lease_until": until, "updated_at": time.Now()}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// ReleaseJob stores the outcome of owner's run and clears the lease
+func (s *jobRepository) ReleaseJob(ctx context.Context, job model.Job, owner string) error {
+	result, err := s.jobCollection.UpdateOne(ctx,
+		bson.M{"_id": job.Name, "lease_owner": owner},
+		bson.M{"$set": bson.M{
+			"next_run":    job.NextRun,
+			"attempt":     job.Attempt,
+			"last_run":    job.LastRun,
+			"last_status": job.LastStatus,
+			"last_error":  job.LastError,
+			"lease_owner": "",
+			"lease_until": time.Time{},
+			"updated_at":  time.Now(),
+		}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrJobLeaseLost
+	}
+	return nil
+}
+
+// RecordJobRun adds a run to the history; a TTL index drops runs after
+// JobRunRetention
+func (s *jobRepository) RecordJobRun(ctx context.Context, run model.JobRun) error {
+	if run.ID.IsZero() {
+		run.ID = primitive.NewObjectID()
+	}
+	_, err := s.runCollection.InsertOne(ctx, run)
+	return err
+}
+
+// ListJobs returns every registered job by name
+func (s *jobRepository) ListJobs(ctx context.Context) ([]model.Job, error) {
+	cursor, err := s.jobCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	jobs := []model.Job{}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// GetJobRuns returns up to limit runs of the job, newest first
+func (s *jobRepository) GetJobRuns(ctx context.Context, name string, limit int) ([]model.JobRun, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := s.runCollection.Find(ctx, bson.M{"job": name}, opts)
+	if err != nil {
+		return nil, err
+	}
+	runs := []model.JobRun{}
+	if err := cursor.All(ctx, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// TriggerJob moves the job's next run to at
+func (s *jobRepository) TriggerJob(ctx context.Context, name string, at time.Time) error {
+	result, err := s.jobCollection.UpdateOne(ctx, bson.M{"_id": name}, bson.M{"$set": bson.M{"next_run": at, "updated_at": time.Now()}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrJobNotFound
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+
+	"DBackend/internal/query"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TaskListSpec whitelists the task fields list requests may filter and sort on
+var TaskListSpec = query.Spec{
+	DefaultSort: "-created_at",
+	Fields: map[string]query.Field{
+		"title":      {Path: "title", Type: query.String, Sortable: true},
+		"completed":  {Path: "completed", Type: query.Bool},
+		"priority":   {Path: "priority", Type: query.String, Sortable: true},
+		"created_by": {Path: "created_by", Type: query.ObjectID},
+		"due_date":   {Path: "due_date", Type: query.Time, Sortable: true},
+		"created_at": {Path: "created_at", Type: query.Time, Sortable: true},
+	},
+}
+
+// MeetingListSpec whitelists the meeting fields list requests may filter and sort on
+var MeetingListSpec = query.Spec{
+	DefaultSort: "-start_time",
+	Fields: map[string]query.Field{
+		"title":       {Path: "title", Type: query.String, Sortable: true},
+		"investor_id": {Path: "investor_id", Type: query.ObjectID},
+		"founder_id":  {Path: "founder_id", Type: query.ObjectID},
+		"start_time":  {Path: "start_time", Type: query.Time, Sortable: true},
+		"end_time":    {Path: "end_time", Type: query.Time, Sortable: true},
+		"created_at":  {Path: "created_at", Type: query.Time, Sortable: true},
+	},
+}
+
+// GrantApplicationListSpec whitelists the grant application fields list requests may filter and sort on
+var GrantApplicationListSpec = query.Spec{
+	DefaultSort: "-created_at",
+	Fields: map[string]query.Field{
+		"status":       {Path: "status", Type: query.String, Sortable: true},
+		"grant_id":     {Path: "grant_id", Type: query.ObjectID},
+		"startup_name": {Path: "startup_name", Type: query.String, Sortable: true},
+		"created_at":   {Path: "created_at", Type: query.Time, Sortable: true},
+	},
+}
+
+// DealFlowListSpec whitelists the deal flow fields list requests may filter and sort on
+var DealFlowListSpec = query.Spec{
+	DefaultSort: "-updated_at",
+	Fields: map[string]query.Field{
+		"stage":         {Path: "stage", Type: query.String, Sortable: true},
+		"status":        {Path: "status", Type: query.String, Sortable: true},
+		"priority":      {Path: "priority", Type: query.String, Sortable: true},
+		"match_score":   {Path: "match_score", Type: query.Number, Sortable: true},
+		"last_activity": {Path: "last_activity", Type: query.Time, Sortable: true},
+		"created_at":    {Path: "created_at", Type: query.Time, Sortable: true},
+		"updated_at":    {Path: "updated_at", Type: query.Time, Sortable: true},
+	},
+}
+
+// NotificationListSpec whitelists the notification fields list requests may filter and sort on
+var NotificationListSpec = query.Spec{
+	DefaultSort: "-created_at",
+	Fields: map[string]query.Field{
+		"notification_type": {Path: "notification_type", Type: query.String},
+		"read_status":       {Path: "read_status", Type: query.Bool},
+		"created_at":        {Path: "created_at", Type: query.Time, Sortable: true},
+	},
+}
+
+// countAggregate returns the number of documents produced by pipeline
+func countAggregate(ctx context.Context, collection *mongo.Collection, pipeline []bson.D) (int64, error) {
+	stages := append(append([]bson.D{}, pipeline...), bson.D{{Key: "$count", Value: "count"}})
+	cursor, err := collection.Aggregate(ctx, stages)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var result []struct {
+		Count int64 `bson:"count"`
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+	return result[0].Count, nil
+}
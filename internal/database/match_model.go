@@ -2,7 +2,7 @@ package database
 
 import (
 	"encoding/json"
-	"fmt"
+	"log/slog"
 	"os"
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -35,7 +35,7 @@ func GenerateMatchmakingData(founders []bson.M, investors []bson.M) {
 	// Save to JSON
 	file, _ := json.MarshalIndent(matches, "", "  ")
 	_ = os.WriteFile("matchmaking_data.json", file, 0o644)
-	fmt.Println("✅ Matchmaking data saved as matchmaking_data.json")
+	slog.Info("matchmaking data saved", "path", "matchmaking_data.json", "matches", len(matches))
 }
 
 // Helper function to check if an array contains a value
@@ -0,0 +1,468 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"DBackend/internal/query"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MeetingRepository stores meetings between investors and founders
+type MeetingRepository interface {
+	// CreateMeeting publishes MeetingScheduled
+	CreateMeeting(ctx context.Context, meeting model.Meeting) (*mongo.InsertOneResult, error)
+	GetMeetingByID(ctx context.Context, id primitive.ObjectID) (*model.Meeting, error)
+	// GetMeetings lists the meetings the user takes part in as investor or founder
+	GetMeetings(ctx context.Context, userID primitive.ObjectID) ([]model.Meeting, error)
+	// GetBusyMeetings lists the meetings any of users takes part in, in any
+	// role, that overlap from to to; recurring ones are listed when their
+	// series does, whether or not an occurrence falls in the span
+	GetBusyMeetings(ctx context.Context, users []primitive.ObjectID, from, to time.Time) ([]model.Meeting, error)
+	// GetMeetingsBetween lists every meeting that overlaps from to to, in the
+	// same way as GetBusyMeetings
+	GetMeetingsBetween(ctx context.Context, from, to time.Time) ([]model.Meeting, error)
+	// ListMeetings returns a page of the meetings userID takes part in, in
+	// any role, or of every meeting when userID is zero
+	ListMeetings(ctx context.Context, userID primitive.ObjectID, params query.Params) (query.Page[model.Meeting], error)
+	UpdateMeeting(ctx context.Context, id primitive.ObjectID, updates model.Meeting) error
+	// UpdateOccurrences replaces the cancelled and edited occurrences of a
+	// recurring meeting, and the series end they may have moved
+	UpdateOccurrences(ctx context.Context, id primitive.ObjectID, exceptions []time.Time, overrides []model.OccurrenceOverride, seriesEnd time.Time) error
+	DeleteMeeting(ctx context.Context, id primitive.ObjectID) (*mongo.DeleteResult, error)
+	CreateNote(ctx context.Context, note model.MeetingNote) (*mongo.InsertOneResult, error)
+	GetNote(ctx context.Context, meetingID, noteID primitive.ObjectID) (*model.MeetingNote, error)
+	// ListNotes returns the meeting's shared notes and viewerID's private
+	// ones, oldest first
+	ListNotes(ctx context.Context, meetingID, viewerID primitive.ObjectID) ([]model.MeetingNote, error)
+	// UpdateNote replaces the note's visibility, sections, action items and editor
+	// and adds revision, the version being replaced, to its history. It
+	// fails with ErrNoteEdited when the note is no longer at that version.
+	UpdateNote(ctx context.Context, note model.MeetingNote, revision model.NoteRevision) error
+	DeleteNote(ctx context.Context, meetingID, noteID primitive.ObjectID) error
+	// LinkActionItem records the task an action item was turned into
+	LinkActionItem(ctx context.Context, noteID, itemID, taskID primitive.ObjectID) error
+	// SetActionItemDone marks the action item turned into taskID done or
+	// open again; it does nothing when no action item has the task
+	SetActionItemDone(ctx context.Context, taskID primitive.ObjectID, done bool) error
+	AddMeetingParticipant(ctx context.Context, meetingID, userID primitive.ObjectID) error
+	// RemoveMeetingParticipant also drops the participant's RSVP
+	RemoveMeetingParticipant(ctx context.Context, meetingID, userID primitive.ObjectID) error
+	// SetRSVP records a participant's answer, replacing an earlier one
+	SetRSVP(ctx context.Context, meetingID primitive.ObjectID, rsvp model.RSVP) error
+	// SetOutcome records the outcome of an occurrence, replacing an earlier
+	// one for the same occurrence
+	SetOutcome(ctx context.Context, meetingID primitive.ObjectID, outcome model.MeetingOutcome) error
+	// ClaimReminder records a reminder as sent and reports whether it had not
+	// been already
+	ClaimReminder(ctx context.Context, reminder model.MeetingReminder) (bool, error)
+}
+
+// MaxNoteRevisions is how many earlier versions of a meeting note are kept
+const MaxNoteRevisions = 50
+
+type meetingRepository struct {
+	meetingCollection  *mongo.Collection
+	reminderCollection *mongo.Collection
+	noteCollection     *mongo.Collection
+	outbox             OutboxRepository
+}
+
+// NewMeetingRepository returns the MongoDB meeting repository
+func NewMeetingRepository(db *mongo.Database) MeetingRepository {
+	return &meetingRepository{
+		meetingCollection:  db.Collection("meetings"),
+		reminderCollection: db.Collection("meeting_reminders"),
+		noteCollection:     db.Collection("meeting_notes"),
+		outbox:             NewOutboxRepository(db),
+	}
+}
+
+// CreateMeeting stores a new meeting. A booked meeting in a slot another
+// booking already took fails with ErrSlotTaken.
+func (s *meetingRepository) CreateMeeting(ctx context.Context, meeting model.Meeting) (*mongo.InsertOneResult, error) {
+	if meeting.ID.IsZero() {
+		meeting.ID = primitive.NewObjectID()
+	}
+	if meeting.SeriesEnd.IsZero() {
+		meeting.SeriesEnd = meeting.EndTime
+	}
+	result, err := s.meetingCollection.InsertOne(ctx, meeting)
+	if mongo.IsDuplicateKeyError(err) && !meeting.BookingLinkID.IsZero() {
+		return nil, ErrSlotTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = s.outbox.Publish(ctx, model.MeetingScheduled{
+		MeetingID:  meeting.ID,
+		InvestorID: meeting.InvestorID,
+		FounderID:  meeting.FounderID,
+		Title:      meeting.Title,
+		StartTime:  meeting.StartTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetMeetingByID retrieves a meeting by ID
+func (s *meetingRepository) GetMeetingByID(ctx context.Context, id primitive.ObjectID) (*model.Meeting, error) {
+	var meeting model.Meeting
+	if err := s.meetingCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&meeting); err != nil {
+		return nil, NotFound(err, ErrMeetingNotFound)
+	}
+	return &meeting, nil
+}
+
+// GetMeetings retrieves all meetings for a given user ID
+func (s *meetingRepository) GetMeetings(ctx context.Context, userID primitive.ObjectID) ([]model.Meeting, error) {
+	var meetings []model.Meeting
+	filter := bson.M{"$or": bson.A{bson.M{"investor_id": userID}, bson.M{"founder_id": userID}}}
+	cursor, err := s.meetingCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var meeting model.Meeting
+		if err := cursor.Decode(&meeting); err != nil {
+			return nil, err
+		}
+		meetings = append(meetings, meeting)
+	}
+
+	return meetings, nil
+}
+
+// GetBusyMeetings retrieves the meetings of users overlapping from to to
+func (s *meetingRepository) GetBusyMeetings(ctx context.Context, users []primitive.ObjectID, from, to time.Time) ([]model.Meeting, error) {
+	if len(users) == 0 {
+		return []model.Meeting{}, nil
+	}
+	filter := busyFilter(users, from, to)
+	cursor, err := s.meetingCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	meetings := []model.Meeting{}
+	if err := cursor.All(ctx, &meetings); err != nil {
+		return nil, err
+	}
+	return meetings, nil
+}
+
+// ListMeetings retrieves a page of meetings from the meetings collection
+func (s *meetingRepository) ListMeetings(ctx context.Context, userID primitive.ObjectID, params query.Params) (query.Page[model.Meeting], error) {
+	var base bson.M
+	if !userID.IsZero() {
+		base = attendedBy([]primitive.ObjectID{userID})
+	}
+	total, err := s.meetingCollection.CountDocuments(ctx, params.CountFilter(base))
+	if err != nil {
+		return query.Page[model.Meeting]{}, err
+	}
+
+	cursor, err := s.meetingCollection.Find(ctx, params.Match(base), params.FindOptions())
+	if err != nil {
+		return query.Page[model.Meeting]{}, err
+	}
+	return query.Collect[model.Meeting](ctx, cursor, params, total)
+}
+
+// UpdateMeeting updates an existing meeting by ID
+func (s *meetingRepository) UpdateMeeting(ctx context.Context, id primitive.ObjectID, updates model.Meeting) error {
+	updates.UpdatedAt = time.Now()
+
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{
+		"title":           updates.Title,
+		"description":     updates.Notes,
+		"start_time":      updates.StartTime,
+		"end_time":        updates.EndTime,
+		"google_meet_url": updates.GoogleMeetURL,
+		"timezone":        updates.Timezone,
+		"recurrence":      updates.Recurrence,
+		"series_end":      updates.SeriesEnd,
+		"reminders":       updates.Reminders,
+		"updated_at":      updates.UpdatedAt,
+	}}
+
+	result, err := s.meetingCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrMeetingNotFound
+	}
+
+	return nil
+}
+
+// UpdateOccurrences sets the exceptions and overrides of a recurring meeting
+func (s *meetingRepository) UpdateOccurrences(ctx context.Context, id primitive.ObjectID, exceptions []time.Time, overrides []model.OccurrenceOverride, seriesEnd time.Time) error {
+	update := bson.M{"$set": bson.M{
+		"exceptions": exceptions,
+		"overrides":  overrides,
+		"series_end": seriesEnd,
+		"updated_at": time.Now(),
+	}}
+	result, err := s.meetingCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrMeetingNotFound
+	}
+	return nil
+}
+
+// GetMeetingsBetween retrieves every meeting overlapping from to to
+func (s *meetingRepository) GetMeetingsBetween(ctx context.Context, from, to time.Time) ([]model.Meeting, error) {
+	cursor, err := s.meetingCollection.Find(ctx, busyFilter(nil, from, to))
+	if err != nil {
+		return nil, err
+	}
+	meetings := []model.Meeting{}
+	if err := cursor.All(ctx, &meetings); err != nil {
+		return nil, err
+	}
+	return meetings, nil
+}
+
+// busyFilter matches the meetings of users, or of anyone when users is nil,
+// that overlap from to to. Meetings stored before series_end existed only
+// have end_time.
+func busyFilter(users []primitive.ObjectID, from, to time.Time) bson.M {
+	and := bson.A{
+		bson.M{"$or": bson.A{
+			bson.M{"end_time": bson.M{"$gt": from}},
+			bson.M{"series_end": bson.M{"$gt": from}},
+		}},
+	}
+	if users != nil {
+		and = append(and, attendedBy(users))
+	}
+	return bson.M{"start_time": bson.M{"$lt": to}, "$and": and}
+}
+
+// attendedBy matches the meetings any of users takes part in, as investor,
+// founder or invited participant
+func attendedBy(users []primitive.ObjectID) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"investor_id": bson.M{"$in": users}},
+		bson.M{"founder_id": bson.M{"$in": users}},
+		bson.M{"participants": bson.M{"$in": users}},
+	}}
+}
+
+// DeleteMeeting removes a meeting
+func (s *meetingRepository) DeleteMeeting(ctx context.Context, id primitive.ObjectID) (*mongo.DeleteResult, error) {
+	result, err := s.meetingCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	if result.DeletedCount == 0 {
+		return nil, ErrMeetingNotFound
+	}
+	return result, nil
+}
+
+// CreateNote stores a new meeting note
+func (s *meetingRepository) CreateNote(ctx context.Context, note model.MeetingNote) (*mongo.InsertOneResult, error) {
+	if note.ID.IsZero() {
+		note.ID = primitive.NewObjectID()
+	}
+	return s.noteCollection.InsertOne(ctx, note)
+}
+
+// GetNote retrieves a note taken in a meeting
+func (s *meetingRepository) GetNote(ctx context.Context, meetingID, noteID primitive.ObjectID) (*model.MeetingNote, error) {
+	var note model.MeetingNote
+	if err := s.noteCollection.FindOne(ctx, bson.M{"_id": noteID, "meeting_id": meetingID}).Decode(&note); err != nil {
+		return nil, NotFound(err, ErrNoteNotFound)
+	}
+	return &note, nil
+}
+
+// ListNotes retrieves the notes of a meeting the viewer can read
+func (s *meetingRepository) ListNotes(ctx context.Context, meetingID, viewerID primitive.ObjectID) ([]model.MeetingNote, error) {
+	filter := bson.M{"meeting_id": meetingID, "$or": bson.A{
+		bson.M{"visibility": "shared"},
+		bson.M{"author_id": viewerID},
+	}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.noteCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	notes := []model.MeetingNote{}
+	if err := cursor.All(ctx, &notes); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// UpdateNote replaces a note's content if it is still at the revision's
+// version, keeping the last MaxNoteRevisions versions
+func (s *meetingRepository) UpdateNote(ctx context.Context, note model.MeetingNote, revision model.NoteRevision) error {
+	filter := bson.M{"_id": note.ID, "meeting_id": note.MeetingID, "version": revision.Version}
+	update := bson.M{
+		"$set": bson.M{
+			"visibility":   note.Visibility,
+			"sections":     note.Sections,
+			"action_items": note.ActionItems,
+			"edited_by":    note.EditedBy,
+			"version":      revision.Version + 1,
+			"updated_at":   time.Now(),
+		},
+		"$push": bson.M{"history": bson.M{"$each": bson.A{revision}, "$slice": -MaxNoteRevisions}},
+	}
+	result, err := s.noteCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNoteEdited
+	}
+	return nil
+}
+
+// DeleteNote deletes a meeting note
+func (s *meetingRepository) DeleteNote(ctx context.Context, meetingID, noteID primitive.ObjectID) error {
+	result, err := s.noteCollection.DeleteOne(ctx, bson.M{"_id": noteID, "meeting_id": meetingID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNoteNotFound
+	}
+	return nil
+}
+
+// LinkActionItem sets the task of an action item
+func (s *meetingRepository) LinkActionItem(ctx context.Context, noteID, itemID, taskID primitive.ObjectID) error {
+	filter := bson.M{"_id": noteID, "action_items._id": itemID}
+	update := bson.M{"$set": bson.M{"action_items.$.task_id": taskID, "updated_at": time.Now()}}
+	result, err := s.noteCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrActionItemNotFound
+	}
+	return nil
+}
+
+// SetActionItemDone sets completion on the action item linked to a task
+func (s *meetingRepository) SetActionItemDone(ctx context.Context, taskID primitive.ObjectID, done bool) error {
+	filter := bson.M{"action_items.task_id": taskID}
+	update := bson.M{"$set": bson.M{"action_items.$.done": done, "updated_at": time.Now()}}
+	_, err := s.noteCollection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// AddMeetingParticipant adds a participant to a meeting
+func (s *meetingRepository) AddMeetingParticipant(ctx context.Context, meetingID, userID primitive.ObjectID) error {
+	filter := bson.M{"_id": meetingID}
+	update := bson.M{
+		"$addToSet": bson.M{"participants": userID},
+		"$set":      bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := s.meetingCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrMeetingNotFound
+	}
+
+	return nil
+}
+
+// RemoveMeetingParticipant removes a participant from a meeting
+func (s *meetingRepository) RemoveMeetingParticipant(ctx context.Context, meetingID, userID primitive.ObjectID) error {
+	filter := bson.M{"_id": meetingID}
+	update := bson.M{
+		"$pull": bson.M{"participants": userID, "rsvps": bson.M{"user_id": userID}},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := s.meetingCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrMeetingNotFound
+	}
+
+	return nil
+}
+
+// SetRSVP replaces the participant's answer in a single update
+func (s *meetingRepository) SetRSVP(ctx context.Context, meetingID primitive.ObjectID, rsvp model.RSVP) error {
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"rsvps":      replaceElement("$rsvps", "user_id", rsvp.UserID, rsvp),
+		"updated_at": time.Now(),
+	}}}}
+	result, err := s.meetingCollection.UpdateOne(ctx, bson.M{"_id": meetingID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrMeetingNotFound
+	}
+	return nil
+}
+
+// SetOutcome replaces the outcome of the occurrence in a single update
+func (s *meetingRepository) SetOutcome(ctx context.Context, meetingID primitive.ObjectID, outcome model.MeetingOutcome) error {
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"outcomes":   replaceElement("$outcomes", "occurrence_start", outcome.OccurrenceStart, outcome),
+		"updated_at": time.Now(),
+	}}}}
+	result, err := s.meetingCollection.UpdateOne(ctx, bson.M{"_id": meetingID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrMeetingNotFound
+	}
+	return nil
+}
+
+// replaceElement is an aggregation expression for the array field with the
+// elements whose key equals value replaced by element
+func replaceElement(field, key string, value, element interface{}) bson.M {
+	return bson.M{"$concatArrays": bson.A{
+		bson.M{"$filter": bson.M{
+			"input": bson.M{"$ifNull": bson.A{field, bson.A{}}},
+			"cond":  bson.M{"$ne": bson.A{"$$this." + key, value}},
+		}},
+		bson.A{bson.M{"$literal": element}},
+	}}
+}
+
+// ClaimReminder inserts the reminder; the unique index on meeting,
+// occurrence and offset turns a second claim into a duplicate key error
+func (s *meetingRepository) ClaimReminder(ctx context.Context, reminder model.MeetingReminder) (bool, error) {
+	if reminder.ID.IsZero() {
+		reminder.ID = primitive.NewObjectID()
+	}
+	_, err := s.reminderCollection.InsertOne(ctx, reminder)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
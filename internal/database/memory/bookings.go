@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"DBackend/internal/database"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type bookings struct{ s *Store }
+
+func (r bookings) SaveAvailability(ctx context.Context, availability model.Availability) error {
+	availability.UpdatedAt = time.Now()
+	filter := bson.M{"investor_id": availability.InvestorID}
+	result, err := r.s.set("availability", filter, bson.M{
+		"timezone":   availability.Timezone,
+		"rules":      availability.Rules,
+		"duration":   availability.Duration,
+		"buffer":     availability.Buffer,
+		"min_notice": availability.MinNotice,
+		"horizon":    availability.Horizon,
+		"updated_at": availability.UpdatedAt,
+	})
+	if err != nil || result.MatchedCount > 0 {
+		return err
+	}
+	availability.ID = primitive.NilObjectID
+	_, err = r.s.Insert("availability", availability)
+	return err
+}
+
+func (r bookings) GetAvailability(ctx context.Context, investorID primitive.ObjectID) (*model.Availability, error) {
+	var availability model.Availability
+	if err := r.s.findOne("availability", bson.M{"investor_id": investorID}, &availability); err != nil {
+		return nil, database.NotFound(err, database.ErrAvailabilityNotFound)
+	}
+	return &availability, nil
+}
+
+func (r bookings) CreateBookingLink(ctx context.Context, link model.BookingLink) (*mongo.InsertOneResult, error) {
+	link.CreatedAt = time.Now()
+	id, err := r.s.Insert("booking_links", link)
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.InsertOneResult{InsertedID: id}, nil
+}
+
+func (r bookings) GetBookingLink(ctx context.Context, slug string) (*model.BookingLink, error) {
+	var link model.BookingLink
+	if err := r.s.findOne("booking_links", bson.M{"slug": slug}, &link); err != nil {
+		return nil, database.NotFound(err, database.ErrBookingLinkNotFound)
+	}
+	return &link, nil
+}
+
+func (r bookings) ListBookingLinks(ctx context.Context, investorID primitive.ObjectID) ([]model.BookingLink, error) {
+	links, err := findAll[model.BookingLink](r.s, "booking_links", bson.M{"investor_id": investorID})
+	sort.SliceStable(links, func(i, j int) bool { return links[i].CreatedAt.After(links[j].CreatedAt) })
+	return links, err
+}
+
+func (r bookings) DeleteBookingLink(ctx context.Context, investorID, id primitive.ObjectID) error {
+	if r.s.remove("booking_links", bson.M{"_id": id, "investor_id": investorID}, false).DeletedCount == 0 {
+		return database.ErrBookingLinkNotFound
+	}
+	return nil
+}
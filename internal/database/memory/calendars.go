@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"DBackend/internal/database"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type calendars struct{ s *Store }
+
+func (r calendars) SaveConnection(ctx context.Context, conn model.CalendarConnection) error {
+	now := time.Now()
+	filter := bson.M{"user_id": conn.UserID, "provider": conn.Provider}
+	result, err := r.s.set("calendar_connections", filter, bson.M{
+		"refresh_token": conn.RefreshToken,
+		"connected_at":  now,
+		"updated_at":    now,
+	})
+	if err != nil || result.MatchedCount > 0 {
+		return err
+	}
+	conn.ID = primitive.NilObjectID
+	conn.ConnectedAt, conn.UpdatedAt = now, now
+	_, err = r.s.Insert("calendar_connections", conn)
+	return err
+}
+
+func (r calendars) GetConnection(ctx context.Context, userID primitive.ObjectID, provider string) (*model.CalendarConnection, error) {
+	var conn model.CalendarConnection
+	if err := r.s.findOne("calendar_connections", bson.M{"user_id": userID, "provider": provider}, &conn); err != nil {
+		return nil, database.NotFound(err, database.ErrCalendarNotConnected)
+	}
+	return &conn, nil
+}
+
+func (r calendars) DeleteConnection(ctx context.Context, userID primitive.ObjectID, provider string) error {
+	if r.s.remove("calendar_connections", bson.M{"user_id": userID, "provider": provider}, false).DeletedCount == 0 {
+		return database.ErrCalendarNotConnected
+	}
+	return nil
+}
+
+func (r calendars) SaveFeed(ctx context.Context, userID primitive.ObjectID, tokenHash string) error {
+	now := time.Now()
+	result, err := r.s.set("calendar_feeds", bson.M{"user_id": userID}, bson.M{"token_hash": tokenHash, "rotated_at": now})
+	if err != nil || result.MatchedCount > 0 {
+		return err
+	}
+	_, err = r.s.Insert("calendar_feeds", model.CalendarFeed{UserID: userID, TokenHash: tokenHash, CreatedAt: now, RotatedAt: now})
+	return err
+}
+
+func (r calendars) GetFeed(ctx context.Context, userID primitive.ObjectID) (*model.CalendarFeed, error) {
+	return r.findFeed(bson.M{"user_id": userID})
+}
+
+func (r calendars) GetFeedByToken(ctx context.Context, tokenHash string) (*model.CalendarFeed, error) {
+	return r.findFeed(bson.M{"token_hash": tokenHash})
+}
+
+func (r calendars) findFeed(filter bson.M) (*model.CalendarFeed, error) {
+	var feed model.CalendarFeed
+	if err := r.s.findOne("calendar_feeds", filter, &feed); err != nil {
+		return nil, database.NotFound(err, database.ErrCalendarFeedNotFound)
+	}
+	return &feed, nil
+}
+
+func (r calendars) DeleteFeed(ctx context.Context, userID primitive.ObjectID) error {
+	if r.s.remove("calendar_feeds", bson.M{"user_id": userID}, false).DeletedCount == 0 {
+		return database.ErrCalendarFeedNotFound
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"DBackend/internal/database"
+	"DBackend/internal/query"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type deals struct{ s *Store }
+
+// dealListFields are the deal fields ListDealsByInvestorID projects
+var dealListFields = []string{
+	"_id", "investor_id", "founder_id", "stage", "status", "match_score",
+	"created_at", "updated_at", "last_activity",
+}
+
+func (r deals) AddStartupToDealFlow(ctx context.Context, deal model.DealFlow) (*mongo.InsertOneResult, error) {
+	// stands in for the unique index on investor and founder
+	if len(r.s.Find("deal_flow", bson.M{"investor_id": deal.InvestorID, "founder_id": deal.StartupID})) > 0 {
+		return nil, database.ErrDealExists
+	}
+	deal.CreatedAt = time.Now()
+	deal.UpdatedAt = time.Now()
+	id, err := r.s.Insert("deal_flow", deal)
+	if err != nil {
+		return nil, err
+	}
+	err = outbox{r.s}.Publish(ctx, model.DealAdded{DealID: id, InvestorID: deal.InvestorID, StartupID: deal.StartupID})
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.InsertOneResult{InsertedID: id}, nil
+}
+
+func (r deals) FindDeal(ctx context.Context, investorID, founderID primitive.ObjectID) (*model.DealFlow, error) {
+	var deal model.DealFlow
+	if err := r.s.findOne("deal_flow", bson.M{"investor_id": investorID, "founder_id": founderID}, &deal); err != nil {
+		return nil, database.NotFound(err, database.ErrDealNotFound)
+	}
+	return &deal, nil
+}
+
+func (r deals) GetDealFlowByID(ctx context.Context, id primitive.ObjectID) (*model.DealFlow, error) {
+	var deal model.DealFlow
+	if err := r.s.findOne("deal_flow", bson.M{"_id": id}, &deal); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, database.ErrDealNotFound
+		}
+		return nil, err
+	}
+	return &deal, nil
+}
+
+// ListDealsByInvestorID joins each deal with its startup and the founder's
+// user, dropping deals whose startup or user is missing as $unwind does
+func (r deals) ListDealsByInvestorID(ctx context.Context, investorID primitive.ObjectID, params query.Params) (query.Page[bson.M], error) {
+	page, err := query.Slice[bson.M](r.s.Find("deal_flow", nil), bson.M{"investor_id": investorID}, params)
+	if err != nil {
+		return query.Page[bson.M]{}, err
+	}
+
+	items := []bson.M{}
+	for _, deal := range page.Items {
+		startups := r.s.Find("founders", bson.M{"_id": deal["founder_id"]})
+		if len(startups) == 0 {
+			continue
+		}
+		founders := r.s.Find("users", bson.M{"_id": startups[0]["user_id"]})
+		if len(founders) == 0 {
+			continue
+		}
+
+		item := bson.M{}
+		for _, field := range dealListFields {
+			if v, ok := deal[field]; ok {
+				item[field] = v
+			}
+		}
+		item["startup"] = startups[0]
+		founder := founders[0]
+		if first, ok := founder["first_name"].(string); ok {
+			if last, ok := founder["last_name"].(string); ok {
+				item["founder_name"] = first + " " + last
+			}
+		}
+		item["founder_email"] = founder["email"]
+		item["founder_avatar"] = founder["avatar"]
+		items = append(items, item)
+	}
+	page.Items = items
+	return page, nil
+}
+
+func (r deals) UpdateDealFlow(ctx context.Context, id primitive.ObjectID, updateFields bson.M) (*mongo.UpdateResult, error) {
+	updateFields["updated_at"] = time.Now()
+	result, err := r.s.set("deal_flow", bson.M{"_id": id}, updateFields)
+	if err != nil {
+		return nil, err
+	}
+	return found(result)
+}
+
+func (r deals) UpdateDealStage(ctx context.Context, id primitive.ObjectID, stage string) (*mongo.UpdateResult, error) {
+	var before model.DealFlow
+	var err error
+	result := r.s.update("deal_flow", bson.M{"_id": id}, false, func(doc bson.M) bool {
+		err = decode(doc, &before)
+		doc["stage"], doc["updated_at"] = stage, toValue(time.Now())
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := found(result); err != nil {
+		return nil, err
+	}
+	if before.Stage == stage {
+		return &mongo.UpdateResult{MatchedCount: 1}, nil
+	}
+	err = outbox{r.s}.Publish(ctx, model.DealStageChanged{
+		DealID:     before.ID,
+		InvestorID: before.InvestorID,
+		StartupID:  before.StartupID,
+		From:       before.Stage,
+		To:         stage,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r deals) UpdateDealStatus(ctx context.Context, id primitive.ObjectID, status string) (*mongo.UpdateResult, error) {
+	return r.setAndRecord(ctx, id, bson.M{"status": status}, fmt.Sprintf("Deal status updated to %s", status))
+}
+
+func (r deals) setAndRecord(ctx context.Context, id primitive.ObjectID, fields bson.M, description string) (*mongo.UpdateResult, error) {
+	result, err := r.UpdateDealFlow(ctx, id, fields)
+	if err != nil {
+		return nil, err
+	}
+	deal, err := r.GetDealFlowByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.addActivity(deal.InvestorID, deal.ID, "deal_update", description); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r deals) UpdateDealFundRequired(ctx context.Context, dealID primitive.ObjectID, amountChange float64) (*mongo.UpdateResult, error) {
+	return found(r.s.update("deal_flow", bson.M{"_id": dealID}, false, func(doc bson.M) bool {
+		inc(doc, "fund_required", amountChange)
+		doc["updated_at"] = toValue(time.Now())
+		return true
+	}))
+}
+
+func (r deals) DeleteDealFlow(ctx context.Context, id primitive.ObjectID) (*mongo.DeleteResult, error) {
+	result := r.s.remove("deal_flow", bson.M{"_id": id}, false)
+	if result.DeletedCount == 0 {
+		return nil, database.ErrDealNotFound
+	}
+	return result, nil
+}
+
+func (r deals) AddMeeting(ctx context.Context, dealID primitive.ObjectID, meeting model.Meeting) (*mongo.UpdateResult, error) {
+	if meeting.ID.IsZero() {
+		meeting.ID = primitive.NewObjectID()
+	}
+	result, err := r.push(dealID, "meetings", meeting)
+	if err != nil {
+		return nil, err
+	}
+	deal, err := r.GetDealFlowByID(ctx, dealID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.addActivity(deal.InvestorID, deal.ID, "meeting", fmt.Sprintf("Meeting scheduled: %s", meeting.Title)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r deals) AddDocument(ctx context.Context, dealID primitive.ObjectID, document model.Document) (*mongo.UpdateResult, error) {
+	return r.push(dealID, "documents", document)
+}
+
+func (r deals) AddNote(ctx context.Context, dealID primitive.ObjectID, note model.Note) error {
+	_, err := r.push(dealID, "notes", note)
+	return err
+}
+
+// push appends v to an array field of a deal and bumps its updated_at
+func (r deals) push(dealID primitive.ObjectID, field string, v interface{}) (*mongo.UpdateResult, error) {
+	return found(r.s.update("deal_flow", bson.M{"_id": dealID}, false, func(doc bson.M) bool {
+		push(doc, field, v)
+		doc["updated_at"] = toValue(time.Now())
+		return true
+	}))
+}
+
+func (r deals) addActivity(investorID, dealID primitive.ObjectID, activityType, description string) error {
+	_, err := r.s.Insert("activities", model.Activity{
+		InvestorID:  investorID,
+		DealID:      dealID,
+		Type:        activityType,
+		Description: description,
+		Date:        time.Now(),
+	})
+	return err
+}
+
+func (r deals) AddActivity(ctx context.Context, activity model.Activity) error {
+	if activity.Date.IsZero() {
+		activity.Date = time.Now()
+	}
+	result, err := r.s.set("deal_flow", bson.M{"_id": activity.DealID}, bson.M{"last_activity": activity.Date})
+	if err != nil {
+		return err
+	}
+	if _, err := found(result); err != nil {
+		return err
+	}
+	_, err = r.s.Insert("activities", activity)
+	return err
+}
+
+func (r deals) ListActivities(ctx context.Context, dealID primitive.ObjectID) ([]model.RecentActivity, error) {
+	activities, err := findAll[model.Activity](r.s, "activities", bson.M{"deal_id": dealID})
+	if err != nil {
+		return nil, err
+	}
+	// newest first, and by insertion for entries recorded in the same instant
+	sort.SliceStable(activities, func(i, j int) bool {
+		if !activities[i].Date.Equal(activities[j].Date) {
+			return activities[i].Date.After(activities[j].Date)
+		}
+		return bytes.Compare(activities[i].ID[:], activities[j].ID[:]) > 0
+	})
+	timeline := make([]model.RecentActivity, 0, len(activities))
+	for _, a := range activities {
+		timeline = append(timeline, model.RecentActivity{ID: a.ID, Type: a.Type, Description: a.Description, Date: a.Date})
+	}
+	return timeline, nil
+}
+
+// found maps an update that matched no deal to database.ErrDealNotFound
+func found(result *mongo.UpdateResult) (*mongo.UpdateResult, error) {
+	if result.MatchedCount == 0 {
+		return nil, database.ErrDealNotFound
+	}
+	return result, nil
+}
@@ -0,0 +1,19 @@
+package memory
+
+import (
+	"testing"
+
+	"DBackend/internal/database/dealtest"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDealsContract(t *testing.T) {
+	dealtest.Run(t, func(t *testing.T) dealtest.Harness {
+		s := New()
+		return dealtest.Harness{
+			Deals: s.Deals(),
+			Count: func(collection string, filter bson.M) int { return len(s.Find(collection, filter)) },
+		}
+	})
+}
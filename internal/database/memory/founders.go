@@ -0,0 +1,218 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"DBackend/internal/database"
+	"DBackend/internal/query"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type founders struct{ s *Store }
+
+// founderProfileFields are the founder fields GetFounderProfileWithMatch projects
+var founderProfileFields = []string{
+	"_id", "startup_name", "mission_statement", "industry", "funding_stage",
+	"funding_allocation", "bussiness_model", "revenue_streams", "traction",
+	"total_invested", "fund_required", "year_founded", "scaling_potential",
+	"competition", "leadership_team", "team_size", "avatar", "founded",
+	"location", "startup_website", "pitch_deck", "created_at", "updated_at",
+}
+
+// startupFacetFields are the founder fields DiscoverStartups counts
+var startupFacetFields = []string{"industry", "funding_stage", "location", "team_size"}
+
+func (r founders) GetFounderByUserID(ctx context.Context, userID primitive.ObjectID) (*model.Founder, error) {
+	var founder model.Founder
+	if err := r.s.findOne("founders", bson.M{"user_id": userID}, &founder); err != nil {
+		return nil, database.NotFound(err, database.ErrFounderNotFound)
+	}
+	return &founder, nil
+}
+
+func (r founders) UpdateFounder(ctx context.Context, userID primitive.ObjectID, founder model.Founder) (*mongo.UpdateResult, error) {
+	return r.s.set("founders", bson.M{"user_id": userID}, bson.M{
+		"startup_name":       founder.StartupName,
+		"mission_statement":  founder.MissionStatement,
+		"industry":           founder.Industry,
+		"funding_stage":      founder.FundingStage,
+		"funding_allocation": founder.FundingAllocation,
+		"bussiness_model":    founder.BussinessModel,
+		"revenue_streams":    founder.RevenueStreams,
+		"traction":           founder.Traction,
+		"scaling_potential":  founder.ScalingPotential,
+		"total_invested":     founder.TotalInvested,
+		"fund_required":      founder.FundRequired,
+		"competition":        founder.Competition,
+		"leadership_team":    founder.LeadershipTeam,
+		"team_size":          founder.TeamSize,
+		"location":           founder.Location,
+		"startup_website":    founder.StartupWebsite,
+	})
+}
+
+func (r founders) UpdateStartupInvestment(ctx context.Context, userID primitive.ObjectID, amount float64) (*mongo.UpdateResult, error) {
+	return r.s.update("founders", bson.M{"user_id": userID}, false, func(doc bson.M) bool {
+		inc(doc, "total_invested", amount)
+		return true
+	}), nil
+}
+
+func (r founders) GetFounderProfileWithMatch(ctx context.Context, userID primitive.ObjectID) (bson.M, error) {
+	founderDocs := r.s.Find("founders", bson.M{"user_id": userID})
+	if len(founderDocs) == 0 {
+		return nil, database.ErrFounderNotFound
+	}
+	userDocs := r.s.Find("users", bson.M{"_id": userID})
+	if len(userDocs) == 0 {
+		return nil, database.ErrFounderNotFound
+	}
+
+	profile := bson.M{}
+	for _, field := range founderProfileFields {
+		if v, ok := founderDocs[0][field]; ok {
+			profile[field] = v
+		}
+	}
+	user := bson.M{}
+	for _, field := range []string{"first_name", "second_name", "email", "avatar", "created_at", "updated_at"} {
+		if v, ok := userDocs[0][field]; ok {
+			user[field] = v
+		}
+	}
+	profile["user"] = user
+	profile["tags"] = bson.A{}
+	profile["match"] = "http://localhost:8080/api/v1/match/data/" + userID.Hex()
+	profile["bookmark"] = false
+	return profile, nil
+}
+
+func (r founders) DiscoverStartups(ctx context.Context, q database.StartupQuery) (*database.StartupPage, error) {
+	params, err := database.StartupParams(q)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := r.s.Find("founders", nil)
+	page, err := query.Slice[model.Founder](docs, nil, params)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]map[string]int{}
+	for _, field := range startupFacetFields {
+		counts[field] = map[string]int{}
+	}
+	// each facet counts over every filter but its own
+	for _, doc := range docs {
+		for _, field := range startupFacetFields {
+			if !query.Matches(doc, database.StartupFacetFilter(params.Filter, field)) {
+				continue
+			}
+			if v, ok := doc[field].(string); ok {
+				counts[field][v]++
+			}
+		}
+	}
+	facets := map[string][]database.FacetCount{}
+	for field, values := range counts {
+		facet := []database.FacetCount{}
+		for value, n := range values {
+			facet = append(facet, database.FacetCount{Value: value, Count: n})
+		}
+		sort.Slice(facet, func(i, j int) bool {
+			if facet[i].Count != facet[j].Count {
+				return facet[i].Count > facet[j].Count
+			}
+			return facet[i].Value < facet[j].Value
+		})
+		facets[field] = facet
+	}
+
+	return &database.StartupPage{
+		Founders:   page.Items,
+		Facets:     facets,
+		Total:      int(page.Total),
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+func (r founders) EnsureStartupIndexes(ctx context.Context) error {
+	return nil
+}
+
+func (r founders) GetFundraisingSummary(ctx context.Context, founderID primitive.ObjectID) (*model.FundraisingSummary, error) {
+	founder, err := r.GetFounderByUserID(ctx, founderID)
+	if err != nil {
+		return nil, err
+	}
+	investments, err := findAll[model.Investment](r.s, "applications", bson.M{"founder_id": founderID, "type": "investment"})
+	if err != nil {
+		return nil, err
+	}
+
+	totalRaised, averageInvestment := 0.0, 0.0
+	for _, inv := range investments {
+		totalRaised += inv.Amount
+	}
+	if len(investments) > 0 {
+		averageInvestment = totalRaised / float64(len(investments))
+	}
+	fundingGoal := founder.FundRequired
+	if fundingGoal <= 0 {
+		fundingGoal = 1
+	}
+
+	return &model.FundraisingSummary{
+		TotalRaised:        totalRaised,
+		FundingGoal:        float64(fundingGoal),
+		PercentageComplete: int((totalRaised * 100) / float64(fundingGoal)),
+		NumberOfInvestors:  len(investments),
+		AverageInvestment:  averageInvestment,
+	}, nil
+}
+
+func (r founders) GetInvestorEngagement(ctx context.Context, founderID primitive.ObjectID) (*model.InvestorEngagement, error) {
+	matches, err := findAll[model.Match](r.s, "applications", bson.M{"founder_id": founderID, "type": "match"})
+	if err != nil {
+		return nil, err
+	}
+
+	newThisMonth, inDueDiligence := 0, 0
+	topMatches := []model.TopMatch{}
+	for _, match := range matches {
+		if match.CreatedAt.Month() == time.Now().Month() {
+			newThisMonth++
+		}
+		if match.Stage == "Due Diligence" {
+			inDueDiligence++
+		}
+		if match.MatchScore > 70 {
+			topMatches = append(topMatches, model.TopMatch{
+				InvestorID:            match.InvestorID,
+				Name:                  match.InvestorName,
+				MatchPercentage:       match.MatchScore,
+				Industry:              match.Industry,
+				TotalInvested:         match.TotalInvested,
+				PreferredFundingStage: match.PreferredFundingStage,
+			})
+		}
+	}
+
+	return &model.InvestorEngagement{
+		TotalMatches:   len(matches),
+		NewThisMonth:   newThisMonth,
+		InDueDiligence: inDueDiligence,
+		TopMatches:     topMatches,
+	}, nil
+}
+
+func (r founders) SubmitInvestorApplication(ctx context.Context, application model.InvestorApplication) (primitive.ObjectID, error) {
+	return r.s.Insert("applications", application)
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"DBackend/internal/database"
+	"DBackend/internal/query"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type grants struct{ s *Store }
+
+func (r grants) GetGrants(ctx context.Context, category, region string) ([]model.Grant, error) {
+	filter := bson.M{}
+	if category != "" {
+		filter["category"] = category
+	}
+	if region != "" {
+		filter["region"] = region
+	}
+	return findAll[model.Grant](r.s, "grants", filter)
+}
+
+func (r grants) GetGrantsClosingBetween(ctx context.Context, from, to time.Time) ([]model.Grant, error) {
+	return findAll[model.Grant](r.s, "grants", bson.M{"deadline": bson.M{"$gte": from, "$lt": to}})
+}
+
+func (r grants) CreateGrant(ctx context.Context, grant model.Grant) (primitive.ObjectID, error) {
+	return r.s.Insert("grants", grant)
+}
+
+func (r grants) GetGrantByID(ctx context.Context, id primitive.ObjectID) (model.Grant, error) {
+	var grant model.Grant
+	err := r.s.findOne("grants", bson.M{"_id": id}, &grant)
+	return grant, database.NotFound(err, database.ErrGrantNotFound)
+}
+
+func (r grants) UpdateGrant(ctx context.Context, id primitive.ObjectID, updates model.Grant) error {
+	result, err := r.s.set("grants", bson.M{"_id": id}, updates)
+	return matched(result, err, database.ErrGrantNotFound)
+}
+
+func (r grants) DeleteGrant(ctx context.Context, id primitive.ObjectID) error {
+	if r.s.remove("grants", bson.M{"_id": id}, false).DeletedCount == 0 {
+		return database.ErrGrantNotFound
+	}
+	return nil
+}
+
+func (r grants) SubmitGrantApplication(ctx context.Context, application model.GrantApplication) (primitive.ObjectID, error) {
+	id, err := r.s.Insert("applications", application)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	err = outbox{r.s}.Publish(ctx, model.GrantApplicationSubmitted{ApplicationID: id, GrantID: application.GrantID, FounderID: application.FounderID})
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return id, nil
+}
+
+func (r grants) GetGrantApplicantIDs(ctx context.Context, grantID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	seen := map[primitive.ObjectID]bool{}
+	ids := []primitive.ObjectID{}
+	for _, doc := range r.s.Find("applications", bson.M{"grant_id": grantID}) {
+		if id, ok := doc["founder_id"].(primitive.ObjectID); ok && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r grants) GetAllGrantApplications(ctx context.Context, params query.Params) (query.Page[model.GrantApplication], error) {
+	return query.Slice[model.GrantApplication](r.s.Find("applications", nil), nil, params)
+}
+
+func (r grants) GetFounderGrantApplications(ctx context.Context, founderID primitive.ObjectID, params query.Params) (query.Page[model.GrantApplication], error) {
+	return query.Slice[model.GrantApplication](r.s.Find("applications", nil), bson.M{"founder_id": founderID}, params)
+}
+
+func (r grants) GetGrantApplicationByID(ctx context.Context, id primitive.ObjectID) (model.GrantApplication, error) {
+	var application model.GrantApplication
+	err := r.s.findOne("applications", bson.M{"_id": id}, &application)
+	return application, database.NotFound(err, database.ErrGrantApplicationNotFound)
+}
+
+func (r grants) UpdateGrantApplication(ctx context.Context, id primitive.ObjectID, status string, remarks string) error {
+	result, err := r.s.set("applications", bson.M{"_id": id}, bson.M{"status": status, "remarks": remarks})
+	return matched(result, err, database.ErrGrantApplicationNotFound)
+}
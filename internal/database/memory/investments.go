@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type investments struct{ s *Store }
+
+func (r investments) CreateInvestment(ctx context.Context, investment model.Investment) (*mongo.InsertOneResult, error) {
+	investment.CreatedAt = time.Now()
+	investment.UpdatedAt = time.Now()
+	id, err := r.s.Insert("investments", investment)
+	if err != nil {
+		return nil, err
+	}
+	err = outbox{r.s}.Publish(ctx, model.InvestmentRecorded{
+		InvestmentID: id,
+		DealID:       investment.DealID,
+		InvestorID:   investment.InvestorID,
+		FounderID:    investment.FounderID,
+		Amount:       investment.Amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.InsertOneResult{InsertedID: id}, nil
+}
+
+func (r investments) GetInvestmentsByInvestorID(ctx context.Context, investorID primitive.ObjectID) ([]model.Investment, error) {
+	return findAll[model.Investment](r.s, "investments", bson.M{"investor_id": investorID})
+}
+
+func (r investments) GetInvestmentsByFounderID(ctx context.Context, founderID primitive.ObjectID) ([]model.Investment, error) {
+	return findAll[model.Investment](r.s, "investments", bson.M{"founder_id": founderID})
+}
+
+func (r investments) GetInvestmentsByDealID(ctx context.Context, dealID primitive.ObjectID) ([]model.Investment, error) {
+	return findAll[model.Investment](r.s, "investments", bson.M{"deal_id": dealID})
+}
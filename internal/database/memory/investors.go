@@ -0,0 +1,239 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"DBackend/internal/database"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type investors struct{ s *Store }
+
+func (r investors) GetInvestorByUserID(ctx context.Context, userID primitive.ObjectID) (*model.Investor, error) {
+	var investor model.Investor
+	if err := r.s.findOne("investors", bson.M{"user_id": userID}, &investor); err != nil {
+		return nil, database.NotFound(err, database.ErrInvestorNotFound)
+	}
+	return &investor, nil
+}
+
+func (r investors) UpdateInvestor(ctx context.Context, userID primitive.ObjectID, investor model.Investor) (*mongo.UpdateResult, error) {
+	return r.s.set("investors", bson.M{"user_id": userID}, bson.M{
+		"total_invested":          investor.TotalInvested,
+		"investor_type":           investor.InvestorType,
+		"thesis":                  investor.Thesis,
+		"preferred_funding_stage": investor.PreferredFundingStage,
+		"investment_range":        investor.InvestmentRange,
+		"investment_frequency":    investor.InvestmentFrequency,
+		"risk_tolerance":          investor.RiskTolerance,
+		"exit_strategy":           investor.ExitStrategy,
+		"preferred_industries":    investor.PreferredIndustries,
+		"preferred_regions":       investor.PreferredRegions,
+	})
+}
+
+func (r investors) UpdateInvestorPortfolio(ctx context.Context, investorID, startupID primitive.ObjectID, amount float64) (*mongo.UpdateResult, error) {
+	return r.s.update("investors", bson.M{"user_id": investorID}, false, func(doc bson.M) bool {
+		inc(doc, "total_invested", amount)
+		push(doc, "investment_portfolio", bson.M{"startup_id": startupID, "amount": amount})
+		return true
+	}), nil
+}
+
+func (r investors) GetInvestors(ctx context.Context, industry, stage string) ([]model.Investor, error) {
+	return []model.Investor{}, nil
+}
+
+func (r investors) AddMatch(ctx context.Context, match model.MatchInvestorFounder) (*mongo.InsertOneResult, error) {
+	match.CreatedAt = time.Now()
+	match.UpdatedAt = time.Now()
+	id, err := r.s.Insert("match_founder_investor", match)
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.InsertOneResult{InsertedID: id}, nil
+}
+
+func (r investors) ListInvestors(ctx context.Context, after primitive.ObjectID, limit int) ([]model.Investor, error) {
+	list, err := findAll[model.Investor](r.s, "investors", bson.M{"_id": bson.M{"$gt": after}})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID.Hex() < list[j].ID.Hex() })
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (r investors) StoreMatch(ctx context.Context, match model.MatchInvestorFounder) (*mongo.InsertOneResult, error) {
+	filter := bson.M{"investor_id": match.InvestorID, "founder_id": match.FounderID}
+	var existing model.MatchInvestorFounder
+	if err := r.s.findOne("matches", filter, &existing); err == nil {
+		if _, err := r.s.set("matches", filter, bson.M{"match_percentage": match.MatchPercentage, "updated_at": time.Now()}); err != nil {
+			return nil, err
+		}
+		return &mongo.InsertOneResult{InsertedID: existing.ID}, nil
+	}
+	match.CreatedAt = time.Now()
+	match.UpdatedAt = time.Now()
+	id, err := r.s.Insert("matches", match)
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.InsertOneResult{InsertedID: id}, nil
+}
+
+func (r investors) GetMatchTimes(ctx context.Context, investorID primitive.ObjectID) (map[primitive.ObjectID]time.Time, error) {
+	matches, err := findAll[model.MatchInvestorFounder](r.s, "matches", bson.M{"investor_id": investorID})
+	if err != nil {
+		return nil, err
+	}
+	times := make(map[primitive.ObjectID]time.Time, len(matches))
+	for _, m := range matches {
+		times[m.FounderID] = m.UpdatedAt
+	}
+	return times, nil
+}
+
+// RecordValuation values startups at their current_value, else at the amount
+// invested grown by the startup's growth rate
+func (r investors) RecordValuation(ctx context.Context, investorID primitive.ObjectID, date time.Time) (*model.PortfolioValuation, error) {
+	var investor struct {
+		Portfolio []struct {
+			StartupID    primitive.ObjectID `bson:"startup_id"`
+			Amount       float64            `bson:"amount"`
+			CurrentValue *float64           `bson:"current_value"`
+		} `bson:"investment_portfolio"`
+	}
+	if err := r.s.findOne("investors", bson.M{"user_id": investorID}, &investor); err != nil {
+		return nil, database.NotFound(err, database.ErrInvestorNotFound)
+	}
+
+	valuation := model.PortfolioValuation{InvestorID: investorID, Date: date, Assets: []model.PortfolioAsset{}}
+	assets := map[primitive.ObjectID]int{}
+	for _, investment := range investor.Portfolio {
+		var startup struct {
+			Name       string  `bson:"startup_name"`
+			GrowthRate float64 `bson:"growth_rate"`
+		}
+		_ = r.s.findOne("founders", bson.M{"user_id": investment.StartupID}, &startup)
+		value := investment.Amount * (1 + startup.GrowthRate/100)
+		if investment.CurrentValue != nil {
+			value = *investment.CurrentValue
+		}
+		valuation.Value += value
+		if i, ok := assets[investment.StartupID]; ok {
+			valuation.Assets[i].Value += value
+			continue
+		}
+		assets[investment.StartupID] = len(valuation.Assets)
+		valuation.Assets = append(valuation.Assets, model.PortfolioAsset{
+			StartupID:         investment.StartupID,
+			StartupName:       startup.Name,
+			Value:             value,
+			LastValuationDate: date,
+		})
+	}
+
+	filter := bson.M{"investor_id": investorID, "date": date}
+	var existing model.PortfolioValuation
+	if err := r.s.findOne("portfolio_valuations", filter, &existing); err == nil {
+		valuation.ID = existing.ID
+		_, err := r.s.set("portfolio_valuations", filter, bson.M{"value": valuation.Value, "assets": valuation.Assets})
+		return &valuation, err
+	}
+	id, err := r.s.Insert("portfolio_valuations", valuation)
+	if err != nil {
+		return nil, err
+	}
+	valuation.ID = id
+	return &valuation, nil
+}
+
+// GetPortfolioSummary reports the invested total and portfolio size; returns
+// are not simulated
+func (r investors) GetPortfolioSummary(ctx context.Context, investorID primitive.ObjectID) (*model.PortfolioSummary, error) {
+	summary := &model.PortfolioSummary{TopPerformers: []model.TopPerformer{}}
+	docs := r.s.Find("investors", bson.M{"user_id": investorID})
+	if len(docs) == 0 {
+		return summary, nil
+	}
+	portfolio, _ := docs[0]["investment_portfolio"].(bson.A)
+	summary.TotalInvested = number(docs[0]["total_invested"])
+	summary.TotalStartups = len(portfolio)
+	return summary, nil
+}
+
+func (r investors) GetPipelineSummary(ctx context.Context, investorID primitive.ObjectID) (*model.PipelineSummary, error) {
+	deals, err := findAll[model.DealFlow](r.s, "deal_flow", bson.M{"investor_id": investorID})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(deals, func(i, j int) bool { return deals[i].UpdatedAt.After(deals[j].UpdatedAt) })
+
+	summary := &model.PipelineSummary{TotalDeals: len(deals), RecentDeals: []model.PipelineDeal{}}
+	for i, deal := range deals {
+		if deal.Stage == "closed_won" || deal.Stage == "closed_lost" {
+			summary.ClosedDeals++
+		} else {
+			summary.PendingDeals++
+		}
+		if i < 5 {
+			status := "pending"
+			if deal.Stage == "Closed Won" || deal.Stage == "Closed Lost" {
+				status = "closed"
+			}
+			recent := model.PipelineDeal{
+				ID:              deal.ID,
+				FounderID:       deal.StartupID,
+				Status:          status,
+				Stage:           deal.Stage,
+				MatchPercentage: deal.MatchScore,
+				UpdatedAt:       deal.UpdatedAt,
+			}
+			if founder, err := (founders{r.s}).GetFounderByUserID(ctx, deal.StartupID); err == nil {
+				recent.StartupName = founder.StartupName
+				recent.Industry = founder.Industry
+			}
+			summary.RecentDeals = append(summary.RecentDeals, recent)
+		}
+	}
+	return summary, nil
+}
+
+func (r investors) GetPerformanceData(ctx context.Context, investorID primitive.ObjectID, period string) ([]model.PerformancePoint, error) {
+	return []model.PerformancePoint{}, nil
+}
+
+func (r investors) GetPerformanceMetrics(ctx context.Context, investorID primitive.ObjectID, period string) (*model.PerformanceMetrics, error) {
+	return &model.PerformanceMetrics{}, nil
+}
+
+func (r investors) GetRecentActivities(ctx context.Context, investorID primitive.ObjectID) ([]model.RecentActivity, error) {
+	activities, err := findAll[model.Activity](r.s, "activities", bson.M{"investor_id": investorID})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(activities, func(i, j int) bool { return activities[i].Date.After(activities[j].Date) })
+
+	recent := []model.RecentActivity{}
+	for i, activity := range activities {
+		if i == 5 {
+			break
+		}
+		recent = append(recent, model.RecentActivity{
+			ID:          activity.ID,
+			Type:        activity.Type,
+			Description: activity.Description,
+			Date:        activity.Date,
+		})
+	}
+	return recent, nil
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"DBackend/internal/database"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type jobs struct{ s *Store }
+
+func (r jobs) RegisterJob(ctx context.Context, name, schedule string, next time.Time) error {
+	// a replica registering the job at the same time makes the insert fail
+	if _, err := r.s.Insert("jobs", model.Job{Name: name, Schedule: schedule, NextRun: next, UpdatedAt: time.Now()}); err == nil {
+		return nil
+	}
+	_, err := r.s.set("jobs", bson.M{"_id": name, "schedule": bson.M{"$ne": schedule}}, bson.M{
+		"schedule": schedule, "next_run": next, "attempt": 0, "updated_at": time.Now(),
+	})
+	return err
+}
+
+func (r jobs) ClaimJob(ctx context.Context, name, owner string, now, until time.Time) (*model.Job, bool, error) {
+	var job model.Job
+	var err error
+	// update holds the store's lock, so the check and the claim are atomic
+	result := r.s.update("jobs", bson.M{"_id": name, "next_run": bson.M{"$lte": now}, "lease_until": bson.M{"$lte": now}}, false, func(doc bson.M) bool {
+		doc["lease_owner"], doc["lease_until"], doc["updated_at"] = owner, toValue(until), toValue(now)
+		err = decode(doc, &job)
+		return true
+	})
+	if err != nil || result.MatchedCount == 0 {
+		return nil, false, err
+	}
+	return &job, true, nil
+}
+
+func (r jobs) RenewLease(ctx context.Context, name, owner string, until time.Time) (bool, error) {
+	result, err := r.s.set("jobs", bson.M{"_id": name, "lease_owner": owner}, bson.M{"lease_until": until, "updated_at": time.Now()})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (r jobs) ReleaseJob(ctx context.Context, job model.Job, owner string) error {
+	result, err := r.s.set("jobs", bson.M{"_id": job.Name, "lease_owner": owner}, bson.M{
+		"next_run":    job.NextRun,
+		"attempt":     job.Attempt,
+		"last_run":    job.LastRun,
+		"last_status": job.LastStatus,
+		"last_error":  job.LastError,
+		"lease_owner": "",
+		"lease_until": time.Time{},
+		"updated_at":  time.Now(),
+	})
+	return matched(result, err, database.ErrJobLeaseLost)
+}
+
+func (r jobs) RecordJobRun(ctx context.Context, run model.JobRun) error {
+	_, err := r.s.Insert("job_runs", run)
+	return err
+}
+
+func (r jobs) ListJobs(ctx context.Context) ([]model.Job, error) {
+	list, err := findAll[model.Job](r.s, "jobs", nil)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (r jobs) GetJobRuns(ctx context.Context, name string, limit int) ([]model.JobRun, error) {
+	runs, err := findAll[model.JobRun](r.s, "job_runs", bson.M{"job": name})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].StartedAt.After(runs[j].StartedAt) })
+	if len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return runs, nil
+}
+
+func (r jobs) TriggerJob(ctx context.Context, name string, at time.Time) error {
+	result, err := r.s.set("jobs", bson.M{"_id": name}, bson.M{"next_run": at, "updated_at": time.Now()})
+	return matched(result, err, database.ErrJobNotFound)
+}
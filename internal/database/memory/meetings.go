@@ -0,0 +1,332 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"DBackend/internal/database"
+	"DBackend/internal/query"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type meetings struct{ s *Store }
+
+func (r meetings) CreateMeeting(ctx context.Context, meeting model.Meeting) (*mongo.InsertOneResult, error) {
+	// stands in for the unique index on booked slots
+	if !meeting.BookingLinkID.IsZero() {
+		booked := bson.M{"investor_id": meeting.InvestorID, "start_time": meeting.StartTime, "booking_link_id": bson.M{"$ne": nil}}
+		if len(r.s.Find("meetings", booked)) > 0 {
+			return nil, database.ErrSlotTaken
+		}
+	}
+	if meeting.SeriesEnd.IsZero() {
+		meeting.SeriesEnd = meeting.EndTime
+	}
+	id, err := r.s.Insert("meetings", meeting)
+	if err != nil {
+		return nil, err
+	}
+	err = outbox{r.s}.Publish(ctx, model.MeetingScheduled{
+		MeetingID:  id,
+		InvestorID: meeting.InvestorID,
+		FounderID:  meeting.FounderID,
+		Title:      meeting.Title,
+		StartTime:  meeting.StartTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.InsertOneResult{InsertedID: id}, nil
+}
+
+func (r meetings) GetMeetingByID(ctx context.Context, id primitive.ObjectID) (*model.Meeting, error) {
+	var meeting model.Meeting
+	if err := r.s.findOne("meetings", bson.M{"_id": id}, &meeting); err != nil {
+		return nil, database.NotFound(err, database.ErrMeetingNotFound)
+	}
+	return &meeting, nil
+}
+
+func (r meetings) GetMeetings(ctx context.Context, userID primitive.ObjectID) ([]model.Meeting, error) {
+	return findAll[model.Meeting](r.s, "meetings", bson.M{"$or": bson.A{
+		bson.M{"investor_id": userID},
+		bson.M{"founder_id": userID},
+	}})
+}
+
+func (r meetings) GetBusyMeetings(ctx context.Context, users []primitive.ObjectID, from, to time.Time) ([]model.Meeting, error) {
+	return findAll[model.Meeting](r.s, "meetings", bson.M{
+		"start_time": bson.M{"$lt": to},
+		"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"end_time": bson.M{"$gt": from}},
+				bson.M{"series_end": bson.M{"$gt": from}},
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"investor_id": bson.M{"$in": users}},
+				bson.M{"founder_id": bson.M{"$in": users}},
+				bson.M{"participants": bson.M{"$in": users}},
+			}},
+		},
+	})
+}
+
+func (r meetings) GetMeetingsBetween(ctx context.Context, from, to time.Time) ([]model.Meeting, error) {
+	return findAll[model.Meeting](r.s, "meetings", bson.M{
+		"start_time": bson.M{"$lt": to},
+		"$or": bson.A{
+			bson.M{"end_time": bson.M{"$gt": from}},
+			bson.M{"series_end": bson.M{"$gt": from}},
+		},
+	})
+}
+
+func (r meetings) ListMeetings(ctx context.Context, userID primitive.ObjectID, params query.Params) (query.Page[model.Meeting], error) {
+	var base bson.M
+	if !userID.IsZero() {
+		base = bson.M{"$or": bson.A{
+			bson.M{"investor_id": userID},
+			bson.M{"founder_id": userID},
+			bson.M{"participants": userID},
+		}}
+	}
+	return query.Slice[model.Meeting](r.s.Find("meetings", nil), base, params)
+}
+
+func (r meetings) UpdateMeeting(ctx context.Context, id primitive.ObjectID, updates model.Meeting) error {
+	result, err := r.s.set("meetings", bson.M{"_id": id}, bson.M{
+		"title":           updates.Title,
+		"description":     updates.Notes,
+		"start_time":      updates.StartTime,
+		"end_time":        updates.EndTime,
+		"google_meet_url": updates.GoogleMeetURL,
+		"timezone":        updates.Timezone,
+		"recurrence":      updates.Recurrence,
+		"series_end":      updates.SeriesEnd,
+		"reminders":       updates.Reminders,
+		"updated_at":      time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return database.ErrMeetingNotFound
+	}
+	return nil
+}
+
+func (r meetings) UpdateOccurrences(ctx context.Context, id primitive.ObjectID, exceptions []time.Time, overrides []model.OccurrenceOverride, seriesEnd time.Time) error {
+	result, err := r.s.set("meetings", bson.M{"_id": id}, bson.M{
+		"exceptions": exceptions,
+		"overrides":  overrides,
+		"series_end": seriesEnd,
+		"updated_at": time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return database.ErrMeetingNotFound
+	}
+	return nil
+}
+
+func (r meetings) DeleteMeeting(ctx context.Context, id primitive.ObjectID) (*mongo.DeleteResult, error) {
+	result := r.s.remove("meetings", bson.M{"_id": id}, false)
+	if result.DeletedCount == 0 {
+		return nil, database.ErrMeetingNotFound
+	}
+	return result, nil
+}
+
+func (r meetings) CreateNote(ctx context.Context, note model.MeetingNote) (*mongo.InsertOneResult, error) {
+	id, err := r.s.Insert("meeting_notes", note)
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.InsertOneResult{InsertedID: id}, nil
+}
+
+func (r meetings) GetNote(ctx context.Context, meetingID, noteID primitive.ObjectID) (*model.MeetingNote, error) {
+	var note model.MeetingNote
+	if err := r.s.findOne("meeting_notes", bson.M{"_id": noteID, "meeting_id": meetingID}, &note); err != nil {
+		return nil, database.NotFound(err, database.ErrNoteNotFound)
+	}
+	return &note, nil
+}
+
+func (r meetings) ListNotes(ctx context.Context, meetingID, viewerID primitive.ObjectID) ([]model.MeetingNote, error) {
+	notes, err := findAll[model.MeetingNote](r.s, "meeting_notes", bson.M{"meeting_id": meetingID, "$or": bson.A{
+		bson.M{"visibility": "shared"},
+		bson.M{"author_id": viewerID},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		if !notes[i].CreatedAt.Equal(notes[j].CreatedAt) {
+			return notes[i].CreatedAt.Before(notes[j].CreatedAt)
+		}
+		return bytes.Compare(notes[i].ID[:], notes[j].ID[:]) < 0
+	})
+	return notes, nil
+}
+
+func (r meetings) UpdateNote(ctx context.Context, note model.MeetingNote, revision model.NoteRevision) error {
+	filter := bson.M{"_id": note.ID, "meeting_id": note.MeetingID, "version": revision.Version}
+	result := r.s.update("meeting_notes", filter, false, func(doc bson.M) bool {
+		doc["visibility"] = note.Visibility
+		doc["sections"] = toValue(note.Sections)
+		doc["action_items"] = toValue(note.ActionItems)
+		doc["edited_by"] = note.EditedBy
+		doc["version"] = toValue(revision.Version + 1)
+		doc["updated_at"] = toValue(time.Now())
+		push(doc, "history", revision)
+		if history := doc["history"].(bson.A); len(history) > database.MaxNoteRevisions {
+			doc["history"] = history[len(history)-database.MaxNoteRevisions:]
+		}
+		return true
+	})
+	if result.MatchedCount == 0 {
+		return database.ErrNoteEdited
+	}
+	return nil
+}
+
+func (r meetings) DeleteNote(ctx context.Context, meetingID, noteID primitive.ObjectID) error {
+	if r.s.remove("meeting_notes", bson.M{"_id": noteID, "meeting_id": meetingID}, false).DeletedCount == 0 {
+		return database.ErrNoteNotFound
+	}
+	return nil
+}
+
+func (r meetings) LinkActionItem(ctx context.Context, noteID, itemID, taskID primitive.ObjectID) error {
+	linked := false
+	r.s.update("meeting_notes", bson.M{"_id": noteID}, false, func(doc bson.M) bool {
+		linked = setActionItem(doc, "_id", itemID, "task_id", taskID)
+		return linked
+	})
+	if !linked {
+		return database.ErrActionItemNotFound
+	}
+	return nil
+}
+
+func (r meetings) SetActionItemDone(ctx context.Context, taskID primitive.ObjectID, done bool) error {
+	set := false
+	r.s.update("meeting_notes", nil, true, func(doc bson.M) bool {
+		if set {
+			return false
+		}
+		set = setActionItem(doc, "task_id", taskID, "done", done)
+		return set
+	})
+	return nil
+}
+
+// setActionItem sets field on the first action item of a note whose key
+// equals value, as the positional $ operator does
+func setActionItem(doc bson.M, key string, value interface{}, field string, v interface{}) bool {
+	items, _ := doc["action_items"].(bson.A)
+	for _, item := range items {
+		if it, ok := item.(bson.M); ok && it[key] == value {
+			it[field] = toValue(v)
+			doc["updated_at"] = toValue(time.Now())
+			return true
+		}
+	}
+	return false
+}
+
+func (r meetings) AddMeetingParticipant(ctx context.Context, meetingID, userID primitive.ObjectID) error {
+	result := r.s.update("meetings", bson.M{"_id": meetingID}, false, func(doc bson.M) bool {
+		doc["updated_at"] = toValue(time.Now())
+		participants, _ := doc["participants"].(bson.A)
+		for _, p := range participants {
+			if p == userID {
+				return true
+			}
+		}
+		push(doc, "participants", userID)
+		return true
+	})
+	if result.MatchedCount == 0 {
+		return database.ErrMeetingNotFound
+	}
+	return nil
+}
+
+func (r meetings) RemoveMeetingParticipant(ctx context.Context, meetingID, userID primitive.ObjectID) error {
+	result := r.s.update("meetings", bson.M{"_id": meetingID}, false, func(doc bson.M) bool {
+		doc["updated_at"] = toValue(time.Now())
+		participants, _ := doc["participants"].(bson.A)
+		kept := bson.A{}
+		for _, p := range participants {
+			if p != userID {
+				kept = append(kept, p)
+			}
+		}
+		doc["participants"] = kept
+		rsvps, _ := doc["rsvps"].(bson.A)
+		answered := bson.A{}
+		for _, rsvp := range rsvps {
+			if answer, ok := rsvp.(bson.M); !ok || answer["user_id"] != userID {
+				answered = append(answered, rsvp)
+			}
+		}
+		doc["rsvps"] = answered
+		return true
+	})
+	if result.MatchedCount == 0 {
+		return database.ErrMeetingNotFound
+	}
+	return nil
+}
+
+func (r meetings) SetRSVP(ctx context.Context, meetingID primitive.ObjectID, rsvp model.RSVP) error {
+	return r.replaceElement(meetingID, "rsvps", "user_id", rsvp.UserID, rsvp)
+}
+
+func (r meetings) SetOutcome(ctx context.Context, meetingID primitive.ObjectID, outcome model.MeetingOutcome) error {
+	return r.replaceElement(meetingID, "outcomes", "occurrence_start", toValue(outcome.OccurrenceStart), outcome)
+}
+
+// replaceElement replaces the elements of an array field whose key equals
+// value with element
+func (r meetings) replaceElement(meetingID primitive.ObjectID, field, key string, value, element interface{}) error {
+	result := r.s.update("meetings", bson.M{"_id": meetingID}, false, func(doc bson.M) bool {
+		doc["updated_at"] = toValue(time.Now())
+		elements, _ := doc[field].(bson.A)
+		kept := bson.A{}
+		for _, e := range elements {
+			if el, ok := e.(bson.M); !ok || el[key] != value {
+				kept = append(kept, e)
+			}
+		}
+		doc[field] = kept
+		push(doc, field, element)
+		return true
+	})
+	if result.MatchedCount == 0 {
+		return database.ErrMeetingNotFound
+	}
+	return nil
+}
+
+func (r meetings) ClaimReminder(ctx context.Context, reminder model.MeetingReminder) (bool, error) {
+	// stands in for the unique index on sent reminders
+	claimed := bson.M{"meeting_id": reminder.MeetingID, "occurrence_start": reminder.OccurrenceStart, "before": reminder.Before}
+	if len(r.s.Find("meeting_reminders", claimed)) > 0 {
+		return false, nil
+	}
+	if _, err := r.s.Insert("meeting_reminders", reminder); err != nil {
+		return false, err
+	}
+	return true, nil
+}
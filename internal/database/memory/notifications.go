@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"DBackend/internal/database"
+	"DBackend/internal/query"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type notifications struct{ s *Store }
+
+func (r notifications) CreateNotification(ctx context.Context, notification model.Notification) (*mongo.InsertOneResult, error) {
+	notification.CreatedAt = time.Now()
+	notification.UpdatedAt = notification.CreatedAt
+	id, err := r.s.Insert("notifications", notification)
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.InsertOneResult{InsertedID: id}, nil
+}
+
+func (r notifications) ClaimDeadlineAlert(ctx context.Context, alert model.DeadlineAlert) (bool, error) {
+	// stands in for the unique index on sent alerts
+	claimed := bson.M{"subject_id": alert.SubjectID, "user_id": alert.UserID, "deadline": alert.Deadline}
+	if len(r.s.Find("deadline_alerts", claimed)) > 0 {
+		return false, nil
+	}
+	if _, err := r.s.Insert("deadline_alerts", alert); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r notifications) GetAllNotificationsByFounder(ctx context.Context, founderID primitive.ObjectID, params query.Params) (query.Page[model.Notification], error) {
+	return query.Slice[model.Notification](r.s.Find("notifications", nil), bson.M{"founder_id": founderID}, params)
+}
+
+func (r notifications) UpdateNotification(ctx context.Context, notificationID primitive.ObjectID, updateData bson.M) error {
+	result, err := r.s.set("notifications", bson.M{"_id": notificationID}, updateData)
+	return matched(result, err, database.ErrNotificationNotFound)
+}
+
+func (r notifications) DeleteNotification(ctx context.Context, notificationID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	result := r.s.remove("notifications", bson.M{"_id": notificationID}, false)
+	if result.DeletedCount == 0 {
+		return nil, database.ErrNotificationNotFound
+	}
+	return result, nil
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"DBackend/internal/database"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type outbox struct{ s *Store }
+
+func (r outbox) Publish(ctx context.Context, e model.DomainEvent) error {
+	event, err := model.NewEvent(e)
+	if err != nil {
+		return err
+	}
+	_, err = r.s.Insert("outbox", event)
+	return err
+}
+
+func (r outbox) ClaimEvent(ctx context.Context, owner string, now, until time.Time) (*model.Event, error) {
+	due := bson.M{"status": model.EventPending, "next_attempt": bson.M{"$lte": now}, "lease_until": bson.M{"$lte": now}}
+	candidates, err := findAll[model.Event](r.s, "outbox", due)
+	if err != nil || len(candidates) == 0 {
+		return nil, err
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if !candidates[i].NextAttempt.Equal(candidates[j].NextAttempt) {
+			return candidates[i].NextAttempt.Before(candidates[j].NextAttempt)
+		}
+		return bytes.Compare(candidates[i].ID[:], candidates[j].ID[:]) < 0
+	})
+
+	// update holds the store's lock, so a replica that claimed the event
+	// since it was found makes the filter miss
+	due["_id"] = candidates[0].ID
+	var event model.Event
+	result := r.s.update("outbox", due, false, func(doc bson.M) bool {
+		doc["lease_owner"], doc["lease_until"] = owner, toValue(until)
+		err = decode(doc, &event)
+		return true
+	})
+	if err != nil || result.MatchedCount == 0 {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r outbox) ReleaseEvent(ctx context.Context, event model.Event, owner string) error {
+	fields := bson.M{
+		"status":       event.Status,
+		"delivered":    event.Delivered,
+		"attempt":      event.Attempt,
+		"next_attempt": event.NextAttempt,
+		"last_error":   event.LastError,
+		"lease_owner":  "",
+		"lease_until":  time.Time{},
+	}
+	if !event.DeliveredAt.IsZero() {
+		fields["delivered_at"] = event.DeliveredAt
+	}
+	result, err := r.s.set("outbox", bson.M{"_id": event.ID, "lease_owner": owner}, fields)
+	return matched(result, err, database.ErrEventLeaseLost)
+}
+
+func (r outbox) ListEvents(ctx context.Context, status string, limit int) ([]model.Event, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	events, err := findAll[model.Event](r.s, "outbox", filter)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool { return bytes.Compare(events[i].ID[:], events[j].ID[:]) > 0 })
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func (r outbox) RetryEvent(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	result, err := r.s.set("outbox", bson.M{"_id": id, "status": model.EventFailed}, bson.M{
+		"status": model.EventPending, "attempt": 0, "next_attempt": at,
+	})
+	return matched(result, err, database.ErrEventNotFound)
+}
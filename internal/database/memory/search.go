@@ -0,0 +1,264 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"DBackend/internal/database"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// search approximates MongoDB text search with case-insensitive term matching
+// over the same fields and weights as the text indexes
+type search struct{ s *Store }
+
+func (r search) EnsureSearchIndexes(ctx context.Context) error {
+	return nil
+}
+
+func (r search) Search(ctx context.Context, q database.SearchQuery) ([]database.SearchResult, error) {
+	q.Limit = database.SearchLimit(q.Limit)
+	wanted := map[string]bool{}
+	for _, t := range q.Types {
+		wanted[t] = true
+	}
+	include := func(t string) bool { return len(wanted) == 0 || wanted[t] }
+	admin := q.Scope.Has("admin")
+
+	results := []database.SearchResult{}
+
+	if include(database.SearchFounder) {
+		docs, err := textSearch[model.Founder](r.s, "founders", q, nil)
+		if err != nil {
+			return nil, err
+		}
+		for i := range docs {
+			results = append(results, database.SearchResult{
+				Type: database.SearchFounder, ID: docs[i].doc.ID, Title: docs[i].doc.StartupName,
+				Score: docs[i].score, Founder: &docs[i].doc,
+			})
+		}
+	}
+
+	if include(database.SearchInvestor) {
+		filter := bson.M{}
+		if !admin && !q.Scope.Has("founder") {
+			filter["user_id"] = q.Scope.UserID
+		}
+		docs, err := textSearch[model.Investor](r.s, "investors", q, filter)
+		if err != nil {
+			return nil, err
+		}
+		for i := range docs {
+			results = append(results, database.SearchResult{
+				Type: database.SearchInvestor, ID: docs[i].doc.ID, Title: docs[i].doc.InvestorType,
+				Score: docs[i].score, Investor: &docs[i].doc,
+			})
+		}
+	}
+
+	if include(database.SearchGrant) {
+		docs, err := textSearch[model.Grant](r.s, "grants", q, nil)
+		if err != nil {
+			return nil, err
+		}
+		for i := range docs {
+			results = append(results, database.SearchResult{
+				Type: database.SearchGrant, ID: docs[i].doc.ID, Title: docs[i].doc.Name,
+				Score: docs[i].score, Grant: &docs[i].doc,
+			})
+		}
+	}
+
+	if include(database.SearchGrantApplication) {
+		filter := bson.M{}
+		if !admin {
+			filter["founder_id"] = q.Scope.UserID
+		}
+		docs, err := textSearch[model.GrantApplication](r.s, "applications", q, filter)
+		if err != nil {
+			return nil, err
+		}
+		for i := range docs {
+			results = append(results, database.SearchResult{
+				Type: database.SearchGrantApplication, ID: docs[i].doc.ID, Title: docs[i].doc.StartupName,
+				Score: docs[i].score, GrantApplication: &docs[i].doc,
+			})
+		}
+	}
+
+	if include(database.SearchDeal) {
+		deals, err := r.searchDeals(q)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, deals...)
+	}
+
+	if include(database.SearchMeeting) {
+		docs, err := textSearch[model.Meeting](r.s, "meetings", q, database.ParticipantFilter(q.Scope))
+		if err != nil {
+			return nil, err
+		}
+		for i := range docs {
+			results = append(results, database.SearchResult{
+				Type: database.SearchMeeting, ID: docs[i].doc.ID, Title: docs[i].doc.Title,
+				Score: docs[i].score, Meeting: &docs[i].doc,
+			})
+		}
+	}
+
+	if include(database.SearchTask) {
+		filter := bson.M{}
+		if !admin {
+			filter["created_by"] = q.Scope.UserID
+		}
+		docs, err := textSearch[model.Task](r.s, "tasks", q, filter)
+		if err != nil {
+			return nil, err
+		}
+		for i := range docs {
+			results = append(results, database.SearchResult{
+				Type: database.SearchTask, ID: docs[i].doc.ID, Title: docs[i].doc.Title,
+				Score: docs[i].score, Task: &docs[i].doc,
+			})
+		}
+	}
+
+	database.SortByRelevance(results)
+	return results, nil
+}
+
+func (r search) searchDeals(q database.SearchQuery) ([]database.SearchResult, error) {
+	scope := database.ParticipantFilter(q.Scope)
+	best := map[primitive.ObjectID]database.SearchResult{}
+	keep := func(res database.SearchResult) {
+		if prev, ok := best[res.ID]; !ok || res.Score > prev.Score {
+			best[res.ID] = res
+		}
+	}
+
+	hits, err := textSearch[model.DealFlow](r.s, "deal_flow", q, scope)
+	if err != nil {
+		return nil, err
+	}
+	for i := range hits {
+		keep(database.SearchResult{Type: database.SearchDeal, ID: hits[i].doc.ID, Score: hits[i].score, Deal: &hits[i].doc})
+	}
+
+	deals, err := findAll[model.DealFlow](r.s, "deal_flow", scope)
+	if err != nil {
+		return nil, err
+	}
+	startupIDs := bson.A{}
+	for _, deal := range deals {
+		startupIDs = append(startupIDs, deal.StartupID)
+	}
+	founders, err := textSearch[model.Founder](r.s, "founders", q, bson.M{"_id": bson.M{"$in": startupIDs}})
+	if err != nil {
+		return nil, err
+	}
+	scores := map[primitive.ObjectID]float64{}
+	for _, f := range founders {
+		scores[f.doc.ID] = f.score
+	}
+	for i := range deals {
+		if score, ok := scores[deals[i].StartupID]; ok {
+			keep(database.SearchResult{Type: database.SearchDeal, ID: deals[i].ID, Score: score, Deal: &deals[i]})
+		}
+	}
+
+	results := make([]database.SearchResult, 0, len(best))
+	for _, res := range best {
+		var founder model.Founder
+		if err := r.s.findOne("founders", bson.M{"_id": res.Deal.StartupID}, &founder); err == nil {
+			res.Title = founder.StartupName
+		}
+		results = append(results, res)
+	}
+	database.SortByRelevance(results)
+	if len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+	return results, nil
+}
+
+type hit[T any] struct {
+	doc   T
+	score float64
+}
+
+// textSearch scores each document in collection matching filter by the
+// weighted number of indexed fields containing a query term, best first
+func textSearch[T any](s *Store, collection string, q database.SearchQuery, filter bson.M) ([]hit[T], error) {
+	terms := strings.Fields(strings.ToLower(q.Text))
+	weights := database.SearchWeights(collection)
+
+	var hits []hit[T]
+	var docs []bson.M
+	for _, doc := range s.Find(collection, filter) {
+		var score float64
+		for _, field := range weights {
+			text := strings.ToLower(fieldText(pathValue(doc, field.Key)))
+			for _, term := range terms {
+				if strings.Contains(text, term) {
+					score += number(field.Value)
+				}
+			}
+		}
+		if score > 0 {
+			hits = append(hits, hit[T]{score: score})
+			docs = append(docs, doc)
+		}
+	}
+	for i := range hits {
+		if err := decode(docs[i], &hits[i].doc); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+	if len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+	return hits, nil
+}
+
+// pathValue resolves a dotted path as MongoDB does, collecting the values
+// of the elements of an array along the way
+func pathValue(v interface{}, path string) interface{} {
+	if path == "" {
+		return v
+	}
+	key, rest, _ := strings.Cut(path, ".")
+	switch x := v.(type) {
+	case bson.M:
+		return pathValue(x[key], rest)
+	case bson.A:
+		values := bson.A{}
+		for _, item := range x {
+			values = append(values, pathValue(item, path))
+		}
+		return values
+	}
+	return nil
+}
+
+// fieldText flattens a string or array of strings into searchable text
+func fieldText(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case bson.A:
+		parts := make([]string, 0, len(x))
+		for _, item := range x {
+			parts = append(parts, fieldText(item))
+		}
+		return strings.Join(parts, " ")
+	}
+	return ""
+}
@@ -0,0 +1,324 @@
+// Package memory implements database.Service over in-process collections of
+// BSON documents. It behaves like a tiny MongoDB: documents are stored under
+// the same collection names and field names, filters use the query package's
+// matcher, and not-found cases return the same domain errors as the MongoDB
+// repositories. It exists so handlers can be tested with plain `go test`.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"DBackend/internal/database"
+	"DBackend/internal/query"
+	"DBackend/internal/ratelimit"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var _ database.Service = (*Store)(nil)
+
+// Store holds every collection in memory
+type Store struct {
+	mu          sync.Mutex
+	txMu        sync.Mutex // serializes transactions
+	collections map[string][]bson.M
+	rateLimits  *ratelimit.MemoryStore
+}
+
+// New returns an empty store
+func New() *Store {
+	return &Store{collections: map[string][]bson.M{}, rateLimits: ratelimit.NewMemoryStore()}
+}
+
+// Health always succeeds
+func (s *Store) Health(ctx context.Context) error {
+	return nil
+}
+
+// PendingMigrations reports none, since the store has no indexes to create
+func (s *Store) PendingMigrations(ctx context.Context) ([]int, error) {
+	return nil, nil
+}
+
+// Transaction runs fn and, when it fails, restores every collection to how
+// it was before. Writes made by others while fn runs are rolled back too,
+// which is fine for tests.
+func (s *Store) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	s.mu.Lock()
+	snapshot := make(map[string][]bson.M, len(s.collections))
+	for name, docs := range s.collections {
+		for _, doc := range docs {
+			snapshot[name] = append(snapshot[name], clone(doc))
+		}
+	}
+	s.mu.Unlock()
+
+	err := fn(ctx)
+	if err != nil {
+		s.mu.Lock()
+		s.collections = snapshot
+		s.mu.Unlock()
+	}
+	return err
+}
+
+func (s *Store) Users() database.UserRepository {
+	return users{s}
+}
+
+func (s *Store) Founders() database.FounderRepository {
+	return founders{s}
+}
+
+func (s *Store) Investors() database.InvestorRepository {
+	return investors{s}
+}
+
+func (s *Store) Deals() database.DealRepository {
+	return deals{s}
+}
+
+func (s *Store) Meetings() database.MeetingRepository {
+	return meetings{s}
+}
+
+func (s *Store) Calendars() database.CalendarRepository {
+	return calendars{s}
+}
+
+func (s *Store) Bookings() database.BookingRepository {
+	return bookings{s}
+}
+
+func (s *Store) Tasks() database.TaskRepository {
+	return tasks{s}
+}
+
+func (s *Store) Grants() database.GrantRepository {
+	return grants{s}
+}
+
+func (s *Store) Notifications() database.NotificationRepository {
+	return notifications{s}
+}
+
+func (s *Store) Investments() database.InvestmentRepository {
+	return investments{s}
+}
+
+func (s *Store) Search() database.SearchService {
+	return search{s}
+}
+
+func (s *Store) RateLimits() ratelimit.Store {
+	return s.rateLimits
+}
+
+func (s *Store) Jobs() database.JobRepository {
+	return jobs{s}
+}
+
+func (s *Store) Outbox() database.OutboxRepository {
+	return outbox{s}
+}
+
+// Insert stores v in collection, assigning an _id when it has none, and
+// returns the document's ID, which is zero for documents keyed by something
+// other than an ObjectID. A taken _id fails like MongoDB's duplicate key
+// error, so mongo.IsDuplicateKeyError recognizes it. Tests use it to seed
+// fixtures.
+func (s *Store) Insert(collection string, v interface{}) (primitive.ObjectID, error) {
+	doc, err := toDoc(v)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	id, isObjectID := doc["_id"].(primitive.ObjectID)
+	if doc["_id"] == nil || isObjectID && id.IsZero() {
+		id = primitive.NewObjectID()
+		doc["_id"] = id
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.collections[collection] {
+		if existing["_id"] == doc["_id"] {
+			return primitive.NilObjectID, mongo.WriteException{WriteErrors: mongo.WriteErrors{{
+				Code:    11000,
+				Message: fmt.Sprintf("E11000 duplicate key error collection: %s dup key: { _id: %v }", collection, doc["_id"]),
+			}}}
+		}
+	}
+	s.collections[collection] = append(s.collections[collection], doc)
+	return id, nil
+}
+
+// Find returns copies of the documents in collection matching filter
+func (s *Store) Find(collection string, filter bson.M) []bson.M {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var docs []bson.M
+	for _, doc := range s.collections[collection] {
+		if query.Matches(doc, filter) {
+			docs = append(docs, clone(doc))
+		}
+	}
+	return docs
+}
+
+// findOne decodes the first document matching filter into out
+func (s *Store) findOne(collection string, filter bson.M, out interface{}) error {
+	docs := s.Find(collection, filter)
+	if len(docs) == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return decode(docs[0], out)
+}
+
+// findAll decodes every document in collection matching filter
+func findAll[T any](s *Store, collection string, filter bson.M) ([]T, error) {
+	items := []T{}
+	for _, doc := range s.Find(collection, filter) {
+		var item T
+		if err := decode(doc, &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// update applies fn to the first (or, with many, every) document matching
+// filter; fn reports whether it changed the document
+func (s *Store) update(collection string, filter bson.M, many bool, fn func(doc bson.M) bool) *mongo.UpdateResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &mongo.UpdateResult{}
+	for _, doc := range s.collections[collection] {
+		if !query.Matches(doc, filter) {
+			continue
+		}
+		result.MatchedCount++
+		if fn(doc) {
+			result.ModifiedCount++
+		}
+		if !many {
+			break
+		}
+	}
+	return result
+}
+
+// set is an update that assigns fields, like $set
+func (s *Store) set(collection string, filter bson.M, fields interface{}) (*mongo.UpdateResult, error) {
+	values, err := toDoc(fields)
+	if err != nil {
+		return nil, err
+	}
+	return s.update(collection, filter, false, func(doc bson.M) bool {
+		for k, v := range values {
+			doc[k] = v
+		}
+		return true
+	}), nil
+}
+
+// matched returns notFound when an otherwise successful update matched nothing
+func matched(result *mongo.UpdateResult, err, notFound error) error {
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// remove deletes the first (or, with many, every) document matching filter
+func (s *Store) remove(collection string, filter bson.M, many bool) *mongo.DeleteResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &mongo.DeleteResult{}
+	kept := s.collections[collection][:0]
+	for _, doc := range s.collections[collection] {
+		if query.Matches(doc, filter) && (many || result.DeletedCount == 0) {
+			result.DeletedCount++
+			continue
+		}
+		kept = append(kept, doc)
+	}
+	s.collections[collection] = kept
+	return result
+}
+
+// toDoc converts a struct or map into a document with nested bson.M values
+func toDoc(v interface{}) (bson.M, error) {
+	data, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	doc := bson.M{}
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// toValue converts v into the representation it has once stored
+func toValue(v interface{}) interface{} {
+	doc, err := toDoc(bson.M{"v": v})
+	if err != nil {
+		return v
+	}
+	return doc["v"]
+}
+
+func clone(doc bson.M) bson.M {
+	copied, err := toDoc(doc)
+	if err != nil {
+		panic(err)
+	}
+	return copied
+}
+
+func decode(doc bson.M, out interface{}) error {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(data, out)
+}
+
+// push appends v to the array field, creating it when missing
+func push(doc bson.M, field string, v interface{}) {
+	arr, _ := doc[field].(bson.A)
+	doc[field] = append(arr, toValue(v))
+}
+
+// inc adds amount to a numeric field, treating a missing field as zero
+func inc(doc bson.M, field string, amount float64) {
+	doc[field] = number(doc[field]) + amount
+}
+
+func number(v interface{}) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
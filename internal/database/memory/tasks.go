@@ -0,0 +1,209 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"DBackend/internal/database"
+	"DBackend/internal/query"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// tasks stores tasks embedded in deal_flow documents, and standalone ones in
+// the tasks collection, like the MongoDB repository
+type tasks struct{ s *Store }
+
+// all returns every deal and standalone task that satisfies filter
+func (r tasks) all(filter bson.M) []bson.M {
+	return append(r.embedded(filter), r.s.Find("tasks", filter)...)
+}
+
+// visible returns the tasks in userID's deals and the ones anywhere that
+// userID created or was assigned
+func (r tasks) visible(userID primitive.ObjectID) []bson.M {
+	mine := bson.M{"$or": bson.A{bson.M{"created_by": userID}, bson.M{"assigned_to": userID}}}
+	var docs []bson.M
+	for _, deal := range r.s.Find("deal_flow", nil) {
+		party := deal["investor_id"] == userID || deal["founder_id"] == userID
+		items, _ := deal["tasks"].(bson.A)
+		for _, item := range items {
+			if task, ok := item.(bson.M); ok && (party || query.Matches(task, mine)) {
+				docs = append(docs, task)
+			}
+		}
+	}
+	return append(docs, r.s.Find("tasks", mine)...)
+}
+
+// embedded returns every task across deal flows that satisfies filter
+func (r tasks) embedded(filter bson.M) []bson.M {
+	var docs []bson.M
+	for _, deal := range r.s.Find("deal_flow", nil) {
+		items, _ := deal["tasks"].(bson.A)
+		for _, item := range items {
+			if task, ok := item.(bson.M); ok && query.Matches(task, filter) {
+				docs = append(docs, task)
+			}
+		}
+	}
+	return docs
+}
+
+// updateTask applies fn to the first task with id in the first deal matching
+// dealFilter, or with a nil dealFilter to the standalone task when no deal has it
+func (r tasks) updateTask(dealFilter bson.M, id primitive.ObjectID, fn func(task bson.M)) *mongo.UpdateResult {
+	result := r.updateEmbedded(dealFilter, id, fn)
+	if result.MatchedCount > 0 || dealFilter != nil {
+		return result
+	}
+	return r.s.update("tasks", bson.M{"_id": id}, false, func(task bson.M) bool {
+		fn(task)
+		return true
+	})
+}
+
+// updateEmbedded applies fn to the first task with id in the first deal matching dealFilter
+func (r tasks) updateEmbedded(dealFilter bson.M, id primitive.ObjectID, fn func(task bson.M)) *mongo.UpdateResult {
+	result := &mongo.UpdateResult{}
+	r.s.update("deal_flow", dealFilter, true, func(deal bson.M) bool {
+		if result.MatchedCount > 0 {
+			return false
+		}
+		items, _ := deal["tasks"].(bson.A)
+		for _, item := range items {
+			if task, ok := item.(bson.M); ok && task["_id"] == id {
+				fn(task)
+				result.MatchedCount, result.ModifiedCount = 1, 1
+				return true
+			}
+		}
+		return false
+	})
+	return result
+}
+
+func (r tasks) AddTask(ctx context.Context, dealID primitive.ObjectID, task model.Task) (*mongo.UpdateResult, error) {
+	if task.ID.IsZero() {
+		task.ID = primitive.NewObjectID()
+	}
+	if dealID.IsZero() {
+		if _, err := r.s.Insert("tasks", task); err != nil {
+			return nil, err
+		}
+		return &mongo.UpdateResult{UpsertedCount: 1, UpsertedID: task.ID}, nil
+	}
+	return r.s.update("deal_flow", bson.M{"_id": dealID}, false, func(doc bson.M) bool {
+		push(doc, "tasks", task)
+		return true
+	}), nil
+}
+
+func (r tasks) GetAllTasks(ctx context.Context, userID primitive.ObjectID, params query.Params) (query.Page[model.Task], error) {
+	if userID.IsZero() {
+		return query.Slice[model.Task](r.all(nil), nil, params)
+	}
+	return query.Slice[model.Task](r.visible(userID), nil, params)
+}
+
+func (r tasks) GetTaskByID(ctx context.Context, id primitive.ObjectID) (model.Task, error) {
+	docs := r.all(bson.M{"_id": id})
+	if len(docs) == 0 {
+		return model.Task{}, database.ErrTaskNotFound
+	}
+	var task model.Task
+	err := decode(docs[0], &task)
+	return task, err
+}
+
+func (r tasks) GetTasksByUser(ctx context.Context, userID primitive.ObjectID) ([]model.Task, error) {
+	return decodeTasks(r.all(bson.M{"created_by": userID}))
+}
+
+func (r tasks) GetDueTasks(ctx context.Context, userID primitive.ObjectID) ([]model.Task, error) {
+	var due []bson.M
+	for _, doc := range r.visible(userID) {
+		if t, ok := doc["due_date"].(primitive.DateTime); ok && t.Time().After(time.Time{}) {
+			due = append(due, doc)
+		}
+	}
+	return decodeTasks(due)
+}
+
+func (r tasks) GetTasksDueBetween(ctx context.Context, from, to time.Time) ([]model.Task, error) {
+	due := bson.M{"due_date": bson.M{"$gte": from, "$lt": to}, "completed": false}
+	var docs []bson.M
+	for _, deal := range r.s.Find("deal_flow", nil) {
+		items, _ := deal["tasks"].(bson.A)
+		for _, item := range items {
+			if task, ok := item.(bson.M); ok && query.Matches(task, due) {
+				docs = append(docs, task)
+			}
+		}
+	}
+	return decodeTasks(append(docs, r.s.Find("tasks", due)...))
+}
+
+func decodeTasks(docs []bson.M) ([]model.Task, error) {
+	var items []model.Task
+	for _, doc := range docs {
+		var task model.Task
+		if err := decode(doc, &task); err != nil {
+			return nil, err
+		}
+		items = append(items, task)
+	}
+	return items, nil
+}
+
+func (r tasks) UpdateTask(ctx context.Context, id primitive.ObjectID, updates model.Task) error {
+	result := r.updateTask(nil, id, func(task bson.M) {
+		task["title"] = updates.Title
+		task["completed"] = updates.Completed
+		task["due_date"] = toValue(updates.DueDate)
+		task["priority"] = updates.Priority
+		task["updated_at"] = toValue(time.Now())
+	})
+	if result.MatchedCount == 0 {
+		return database.ErrTaskNotFound
+	}
+	return nil
+}
+
+func (r tasks) UpdateTaskStatus(ctx context.Context, dealID primitive.ObjectID, taskID primitive.ObjectID, completed bool) (*mongo.UpdateResult, error) {
+	var filter bson.M
+	if !dealID.IsZero() {
+		filter = bson.M{"_id": dealID}
+	}
+	return r.updateTask(filter, taskID, func(task bson.M) {
+		task["completed"] = completed
+		task["updated_at"] = toValue(time.Now())
+	}), nil
+}
+
+func (r tasks) AssignTask(ctx context.Context, taskID primitive.ObjectID, userID primitive.ObjectID) error {
+	r.updateTask(nil, taskID, func(task bson.M) {
+		task["assigned_to"] = userID
+		task["updated_at"] = toValue(time.Now())
+	})
+	return nil
+}
+
+func (r tasks) DeleteTask(ctx context.Context, id primitive.ObjectID) error {
+	r.s.update("deal_flow", nil, true, func(deal bson.M) bool {
+		items, _ := deal["tasks"].(bson.A)
+		kept := bson.A{}
+		for _, item := range items {
+			if task, ok := item.(bson.M); !ok || task["_id"] != id {
+				kept = append(kept, item)
+			}
+		}
+		deal["tasks"] = kept
+		return len(kept) != len(items)
+	})
+	r.s.remove("tasks", bson.M{"_id": id}, false)
+	return nil
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"DBackend/internal/database"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type users struct{ s *Store }
+
+func (r users) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	var user model.User
+	if err := r.s.findOne("users", bson.M{"email": email}, &user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r users) FindByID(ctx context.Context, id primitive.ObjectID) (*model.User, error) {
+	var user model.User
+	if err := r.s.findOne("users", bson.M{"_id": id}, &user); err != nil {
+		return nil, database.NotFound(err, database.ErrUserNotFound)
+	}
+	return &user, nil
+}
+
+func (r users) CreateUser(ctx context.Context, user model.User) (*mongo.InsertOneResult, error) {
+	id, err := r.s.Insert("users", user)
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.InsertOneResult{InsertedID: id}, nil
+}
+
+func (r users) UpdateRoles(ctx context.Context, email string, roles []string) (*mongo.UpdateResult, error) {
+	return r.s.set("users", bson.M{"email": email}, bson.M{"roles": roles})
+}
+
+func (r users) SetTimezone(ctx context.Context, id primitive.ObjectID, timezone string) error {
+	result, err := r.s.set("users", bson.M{"_id": id}, bson.M{"timezone": timezone, "updated_at": time.Now()})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return database.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r users) CreateRoleData(ctx context.Context, userID primitive.ObjectID, role string) error {
+	var err error
+	switch role {
+	case "founder":
+		_, err = r.s.Insert("founders", model.Founder{UserID: userID})
+	case "investor":
+		_, err = r.s.Insert("investors", model.Investor{UserID: userID})
+	case "admin":
+		_, err = r.s.Insert("admins", model.Admin{UserID: userID})
+	default:
+		err = database.ErrInvalidRole
+	}
+	return err
+}
+
+func (r users) GetUserCount(ctx context.Context) (int64, error) {
+	return int64(len(r.s.Find("users", nil))), nil
+}
+
+func (r users) BlacklistToken(ctx context.Context, token string) error {
+	_, err := r.s.Insert("blacklist_tokens", bson.M{
+		"token":     token,
+		"expiredAt": time.Now().Add(24 * time.Hour),
+	})
+	return err
+}
+
+func (r users) DeleteExpiredTokens(ctx context.Context, now time.Time) (int64, error) {
+	return r.s.remove("blacklist_tokens", bson.M{"expiredAt": bson.M{"$lt": now}}, true).DeletedCount, nil
+}
+
+func (r users) IsTokenBlacklisted(ctx context.Context, token string) (bool, error) {
+	return len(r.s.Find("blacklist_tokens", bson.M{"token": token})) > 0, nil
+}
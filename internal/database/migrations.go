@@ -0,0 +1,499 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"DBackend/internal/migrate"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Migrations is the ordered list of schema changes for the application database.
+// Append new entries with the next version; never renumber or edit applied ones.
+var Migrations = []migrate.Migration{
+	{
+		Version:     1,
+		Description: "unique index on users.email",
+		Up: migrate.CreateIndexes("users", mongo.IndexModel{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetName("users_email_unique").SetUnique(true),
+		}),
+		Down: migrate.DropIndexes("users", "users_email_unique"),
+	},
+	{
+		Version:     2,
+		Description: "TTL and lookup indexes on blacklist_tokens",
+		Up: migrate.CreateIndexes("blacklist_tokens",
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "expiredAt", Value: 1}},
+				Options: options.Index().SetName("blacklist_tokens_ttl").SetExpireAfterSeconds(0),
+			},
+			migrate.Index("blacklist_tokens_token", "token"),
+		),
+		Down: migrate.DropIndexes("blacklist_tokens", "blacklist_tokens_ttl", "blacklist_tokens_token"),
+	},
+	{
+		Version:     3,
+		Description: "founder, investor and owner reference indexes",
+		Up: migrate.Steps(
+			migrate.CreateIndexes("founders", migrate.Index("founders_user_id", "user_id")),
+			migrate.CreateIndexes("investors", migrate.Index("investors_user_id", "user_id")),
+			migrate.CreateIndexes("deal_flow",
+				migrate.Index("deal_flow_investor_id", "investor_id"),
+				migrate.Index("deal_flow_founder_id", "founder_id"),
+			),
+			migrate.CreateIndexes("meetings",
+				migrate.Index("meetings_investor_id", "investor_id"),
+				migrate.Index("meetings_founder_id", "founder_id"),
+			),
+			migrate.CreateIndexes("notifications", migrate.Index("notifications_founder_id", "founder_id", "created_at")),
+			migrate.CreateIndexes("applications",
+				migrate.Index("applications_founder_id", "founder_id"),
+				migrate.Index("applications_grant_id", "grant_id"),
+			),
+			migrate.CreateIndexes("tasks", migrate.Index("tasks_created_by", "created_by")),
+			migrate.CreateIndexes("activities", migrate.Index("activities_investor_id", "investor_id")),
+		),
+		Down: migrate.Steps(
+			migrate.DropIndexes("founders", "founders_user_id"),
+			migrate.DropIndexes("investors", "investors_user_id"),
+			migrate.DropIndexes("deal_flow", "deal_flow_investor_id", "deal_flow_founder_id"),
+			migrate.DropIndexes("meetings", "meetings_investor_id", "meetings_founder_id"),
+			migrate.DropIndexes("notifications", "notifications_founder_id"),
+			migrate.DropIndexes("applications", "applications_founder_id", "applications_grant_id"),
+			migrate.DropIndexes("tasks", "tasks_created_by"),
+			migrate.DropIndexes("activities", "activities_investor_id"),
+		),
+	},
+	{
+		Version:     4,
+		Description: "startup discovery facet and sort indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return NewFounderRepository(db).EnsureStartupIndexes(ctx)
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			names := []string{"industry_1", "funding_stage_1", "location_1", "team_size_1"}
+			for field := range startupSortFields {
+				names = append(names, field+"_1__id_1")
+			}
+			return migrate.DropIndexes("founders", names...)(ctx, db)
+		},
+	},
+	{
+		Version:     5,
+		Description: "search text indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return (&searchService{db: db}).EnsureSearchIndexes(ctx)
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			for collection := range searchIndexes {
+				if err := migrate.DropIndexes(collection, "search_text")(ctx, db); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     6,
+		Description: "normalize applications.grant_id to ObjectID",
+		Up:          normalizeGrantApplicationIDs,
+		Down:        restoreLegacyGrantApplicationIDs,
+	},
+	{
+		Version:     7,
+		Description: "TTL index on rate_limits",
+		Up: migrate.CreateIndexes("rate_limits", mongo.IndexModel{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("rate_limits_ttl").SetExpireAfterSeconds(0),
+		}),
+		Down: migrate.DropIndexes("rate_limits", "rate_limits_ttl"),
+	},
+	{
+		Version:     8,
+		Description: "unique index on calendar_connections user and provider",
+		Up: migrate.CreateIndexes("calendar_connections", mongo.IndexModel{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "provider", Value: 1}},
+			Options: options.Index().SetName("calendar_connections_user_provider").SetUnique(true),
+		}),
+		Down: migrate.DropIndexes("calendar_connections", "calendar_connections_user_provider"),
+	},
+	{
+		Version:     9,
+		Description: "unique calendar_feeds indexes and tasks assignee index",
+		Up: migrate.Steps(
+			migrate.CreateIndexes("calendar_feeds",
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "user_id", Value: 1}},
+					Options: options.Index().SetName("calendar_feeds_user_id").SetUnique(true),
+				},
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "token_hash", Value: 1}},
+					Options: options.Index().SetName("calendar_feeds_token_hash").SetUnique(true),
+				},
+			),
+			migrate.CreateIndexes("tasks", migrate.Index("tasks_assigned_to", "assigned_to")),
+		),
+		Down: migrate.Steps(
+			migrate.DropIndexes("calendar_feeds", "calendar_feeds_user_id", "calendar_feeds_token_hash"),
+			migrate.DropIndexes("tasks", "tasks_assigned_to"),
+		),
+	},
+	{
+		Version:     10,
+		Description: "availability, booking_links and booked meeting slot indexes",
+		Up: migrate.Steps(
+			migrate.CreateIndexes("availability", mongo.IndexModel{
+				Keys:    bson.D{{Key: "investor_id", Value: 1}},
+				Options: options.Index().SetName("availability_investor_id").SetUnique(true),
+			}),
+			migrate.CreateIndexes("booking_links",
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "slug", Value: 1}},
+					Options: options.Index().SetName("booking_links_slug").SetUnique(true),
+				},
+				migrate.Index("booking_links_investor_id", "investor_id"),
+			),
+			// two founders booking the same slot at once cannot both win
+			migrate.CreateIndexes("meetings", mongo.IndexModel{
+				Keys: bson.D{{Key: "investor_id", Value: 1}, {Key: "start_time", Value: 1}},
+				Options: options.Index().SetName("meetings_booked_slot").SetUnique(true).
+					SetPartialFilterExpression(bson.M{"booking_link_id": bson.M{"$exists": true}}),
+			}),
+		),
+		Down: migrate.Steps(
+			migrate.DropIndexes("availability", "availability_investor_id"),
+			migrate.DropIndexes("booking_links", "booking_links_slug", "booking_links_investor_id"),
+			migrate.DropIndexes("meetings", "meetings_booked_slot"),
+		),
+	},
+	{
+		Version:     11,
+		Description: "meetings participants index for overlap checks",
+		Up: migrate.Steps(
+			migrate.CreateIndexes("meetings", migrate.Index("meetings_participants", "participants")),
+		),
+		Down: migrate.Steps(
+			migrate.DropIndexes("meetings", "meetings_participants"),
+		),
+	},
+	{
+		Version:     12,
+		Description: "sent meeting reminders and deal activity timeline indexes",
+		Up: migrate.Steps(
+			migrate.CreateIndexes("meeting_reminders",
+				// a reminder claimed by one server is not sent by another
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "meeting_id", Value: 1}, {Key: "occurrence_start", Value: 1}, {Key: "before", Value: 1}},
+					Options: options.Index().SetName("meeting_reminders_occurrence").SetUnique(true),
+				},
+				// the longest reminder is a few weeks ahead, so older records are of no use
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "sent_at", Value: 1}},
+					Options: options.Index().SetName("meeting_reminders_ttl").SetExpireAfterSeconds(90 * 24 * 60 * 60),
+				},
+			),
+			migrate.CreateIndexes("activities", mongo.IndexModel{
+				Keys:    bson.D{{Key: "deal_id", Value: 1}, {Key: "date", Value: -1}},
+				Options: options.Index().SetName("activities_deal_timeline"),
+			}),
+		),
+		Down: migrate.Steps(
+			migrate.DropIndexes("meeting_reminders", "meeting_reminders_occurrence", "meeting_reminders_ttl"),
+			migrate.DropIndexes("activities", "activities_deal_timeline"),
+		),
+	},
+	{
+		Version:     13,
+		Description: "structured meeting notes, seeded from the notes string of each meeting",
+		Up: migrate.Steps(
+			migrate.CreateIndexes("meeting_notes",
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "meeting_id", Value: 1}, {Key: "created_at", Value: 1}},
+					Options: options.Index().SetName("meeting_notes_meeting"),
+				},
+				// completing a task looks up the action item it was made from
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "action_items.task_id", Value: 1}},
+					Options: options.Index().SetName("meeting_notes_action_item_task").SetSparse(true),
+				},
+			),
+			copyMeetingNotes,
+		),
+		// the copied notes are kept; the meetings still have their notes string
+		Down: migrate.DropIndexes("meeting_notes", "meeting_notes_meeting", "meeting_notes_action_item_task"),
+	},
+	{
+		Version:     14,
+		Description: "background job run history, portfolio valuation snapshots, deadline alerts and stored matches",
+		Up: migrate.Steps(
+			migrate.CreateIndexes("job_runs",
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "job", Value: 1}, {Key: "started_at", Value: -1}},
+					Options: options.Index().SetName("job_runs_job"),
+				},
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "finished_at", Value: 1}},
+					Options: options.Index().SetName("job_runs_ttl").SetExpireAfterSeconds(int32(JobRunRetention.Seconds())),
+				},
+			),
+			// a second snapshot on the same day replaces the first
+			migrate.CreateIndexes("portfolio_valuations", mongo.IndexModel{
+				Keys:    bson.D{{Key: "investor_id", Value: 1}, {Key: "date", Value: 1}},
+				Options: options.Index().SetName("portfolio_valuations_day").SetUnique(true),
+			}),
+			migrate.CreateIndexes("deadline_alerts",
+				// a user is alerted of a deadline once, by whichever server claims it
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "subject_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "deadline", Value: 1}},
+					Options: options.Index().SetName("deadline_alerts_subject").SetUnique(true),
+				},
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "sent_at", Value: 1}},
+					Options: options.Index().SetName("deadline_alerts_ttl").SetExpireAfterSeconds(90 * 24 * 60 * 60),
+				},
+			),
+			migrate.CreateIndexes("matches", migrate.Index("matches_investor_founder", "investor_id", "founder_id")),
+		),
+		Down: migrate.Steps(
+			migrate.DropIndexes("job_runs", "job_runs_job", "job_runs_ttl"),
+			migrate.DropIndexes("portfolio_valuations", "portfolio_valuations_day"),
+			migrate.DropIndexes("deadline_alerts", "deadline_alerts_subject", "deadline_alerts_ttl"),
+			migrate.DropIndexes("matches", "matches_investor_founder"),
+		),
+	},
+	{
+		Version:     15,
+		Description: "outbox of domain events awaiting delivery",
+		Up: migrate.CreateIndexes("outbox",
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "next_attempt", Value: 1}},
+				Options: options.Index().SetName("outbox_due"),
+			},
+			// only delivered events have delivered_at, so failed ones are kept
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "delivered_at", Value: 1}},
+				Options: options.Index().SetName("outbox_ttl").SetExpireAfterSeconds(int32(EventRetention.Seconds())),
+			},
+		),
+		Down: migrate.DropIndexes("outbox", "outbox_due", "outbox_ttl"),
+	},
+	{
+		Version:     16,
+		Description: "unique index on deal_flow investor and founder, merging duplicate deals",
+		Up: migrate.Steps(
+			mergeDuplicateDeals,
+			migrate.CreateIndexes("deal_flow", mongo.IndexModel{
+				Keys: bson.D{{Key: "investor_id", Value: 1}, {Key: "founder_id", Value: 1}},
+				Options: options.Index().SetName("deal_flow_investor_founder").SetUnique(true).
+					SetPartialFilterExpression(dealWithFounder),
+			}),
+		),
+		// the merged duplicates stay in deal_flow_duplicates
+		Down: migrate.DropIndexes("deal_flow", "deal_flow_investor_founder"),
+	},
+	{
+		Version:     17,
+		Description: "search text index on deal_flow tasks, meetings, notes and documents",
+		Up:          migrate.CreateIndexes("deal_flow", textIndex(dealSearchIndex)),
+		Down:        migrate.DropIndexes("deal_flow", "search_text"),
+	},
+	{
+		Version:     18,
+		Description: "lower-case users.email",
+		Up:          normalizeUserEmails,
+		// the emails stay lower-cased; their original case is not kept
+		Down: func(ctx context.Context, db *mongo.Database) error { return nil },
+	},
+}
+
+// normalizeUserEmails trims and lower-cases every user's email, the form
+// registration and login look them up in. Accounts whose emails differ only
+// in case hold separate passwords and role data, so they are not merged here:
+// the migration fails listing them, before changing any email, until an
+// operator merges or removes the extras.
+func normalizeUserEmails(ctx context.Context, db *mongo.Database) error {
+	users := db.Collection("users")
+	normalized := bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$email"}}}
+	cursor, err := users.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"email": bson.M{"$type": "string"}}}},
+		{{Key: "$group", Value: bson.M{"_id": normalized, "ids": bson.M{"$push": "$_id"}}}},
+		{{Key: "$match", Value: bson.M{"ids.1": bson.M{"$exists": true}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return err
+	}
+	var groups []struct {
+		Email interface{}          `bson:"_id"`
+		IDs   []primitive.ObjectID `bson:"ids"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		_, err := users.UpdateMany(ctx,
+			bson.M{"email": bson.M{"$type": "string"}, "$expr": bson.M{"$ne": bson.A{"$email", normalized}}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{"email": normalized}}}},
+		)
+		return err
+	}
+	duplicates := make([]string, len(groups))
+	for i, group := range groups {
+		ids := make([]string, len(group.IDs))
+		for j, id := range group.IDs {
+			ids[j] = id.Hex()
+		}
+		duplicates[i] = fmt.Sprintf("%v (users %s)", group.Email, strings.Join(ids, ", "))
+	}
+	return fmt.Errorf("%d emails belong to more than one user: %s; merge or remove the extra accounts, then run the migrations again",
+		len(groups), strings.Join(duplicates, "; "))
+}
+
+// dealWithFounder selects the deals the unique deal_flow index covers
+var dealWithFounder = bson.M{"founder_id": bson.M{"$type": "objectId"}}
+
+// mergeDuplicateDeals keeps the oldest of the deals an investor has with the
+// same founder. The others are moved to deal_flow_duplicates, marked with the
+// deal they were merged into, and their activities and investments are
+// pointed at that deal.
+func mergeDuplicateDeals(ctx context.Context, db *mongo.Database) error {
+	deals := db.Collection("deal_flow")
+	cursor, err := deals.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: dealWithFounder}},
+		{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"investor_id": "$investor_id", "founder_id": "$founder_id"},
+			"ids": bson.M{"$push": "$_id"},
+		}}},
+		{{Key: "$match", Value: bson.M{"ids.1": bson.M{"$exists": true}}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return err
+	}
+	var groups []struct {
+		IDs []primitive.ObjectID `bson:"ids"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		kept, duplicates := group.IDs[0], bson.M{"_id": bson.M{"$in": group.IDs[1:]}}
+		docs, err := deals.Find(ctx, duplicates)
+		if err != nil {
+			return err
+		}
+		var archived []interface{}
+		for docs.Next(ctx) {
+			var doc bson.M
+			if err := docs.Decode(&doc); err != nil {
+				docs.Close(ctx)
+				return err
+			}
+			doc["merged_into"] = kept
+			archived = append(archived, doc)
+		}
+		docs.Close(ctx)
+		if err := docs.Err(); err != nil {
+			return err
+		}
+		// a rerun after a failure part way finds some already archived
+		_, err = db.Collection("deal_flow_duplicates").InsertMany(ctx, archived, options.InsertMany().SetOrdered(false))
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+		moved := bson.M{"deal_id": bson.M{"$in": group.IDs[1:]}}
+		for _, collection := range []string{"activities", "investments"} {
+			if _, err := db.Collection(collection).UpdateMany(ctx, moved, bson.M{"$set": bson.M{"deal_id": kept}}); err != nil {
+				return err
+			}
+		}
+		if _, err := deals.DeleteMany(ctx, duplicates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeGrantApplicationIDs converts hex string grant_id values to ObjectIDs.
+// Legacy numeric IDs cannot be mapped to a grant, so they are moved to
+// legacy_grant_id and grant_id is cleared.
+func normalizeGrantApplicationIDs(ctx context.Context, db *mongo.Database) error {
+	applications := db.Collection("applications")
+
+	_, err := applications.UpdateMany(ctx,
+		bson.M{"grant_id": bson.M{"$type": "string", "$regex": "^[0-9a-fA-F]{24}$"}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"grant_id": bson.M{"$toObjectId": "$grant_id"}}}}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = applications.UpdateMany(ctx,
+		bson.M{"grant_id": bson.M{"$type": "number"}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"legacy_grant_id": "$grant_id", "grant_id": nil}}}},
+	)
+	return err
+}
+
+// restoreLegacyGrantApplicationIDs moves legacy numeric IDs back into grant_id
+func restoreLegacyGrantApplicationIDs(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("applications").UpdateMany(ctx,
+		bson.M{"legacy_grant_id": bson.M{"$exists": true}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{"grant_id": "$legacy_grant_id"}}},
+			{{Key: "$unset", Value: "legacy_grant_id"}},
+		},
+	)
+	return err
+}
+
+// copyMeetingNotes turns the notes string of every meeting without
+// structured notes into a shared note by the meeting's investor
+func copyMeetingNotes(ctx context.Context, db *mongo.Database) error {
+	notes := db.Collection("meeting_notes")
+	cursor, err := db.Collection("meetings").Find(ctx, bson.M{"notes": bson.M{"$nin": bson.A{"", nil}}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var meeting model.Meeting
+		if err := cursor.Decode(&meeting); err != nil {
+			return err
+		}
+		n, err := notes.CountDocuments(ctx, bson.M{"meeting_id": meeting.ID})
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			continue
+		}
+		_, err = notes.InsertOne(ctx, model.MeetingNote{
+			ID:          primitive.NewObjectID(),
+			MeetingID:   meeting.ID,
+			AuthorID:    meeting.InvestorID,
+			Visibility:  "shared",
+			Sections:    []model.NoteSection{{Heading: "Notes", Body: meeting.Notes}},
+			ActionItems: []model.ActionItem{},
+			CreatedAt:   meeting.UpdatedAt,
+			UpdatedAt:   meeting.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// NewMigrator returns a runner for the application migrations against db
+func NewMigrator(db *mongo.Database) (*migrate.Runner, error) {
+	return migrate.New(db, Migrations)
+}
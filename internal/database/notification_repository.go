@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"DBackend/internal/query"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// NotificationRepository stores the notifications shown to founders
+type NotificationRepository interface {
+	CreateNotification(ctx context.Context, notification model.Notification) (*mongo.InsertOneResult, error)
+	GetAllNotificationsByFounder(ctx context.Context, founderID primitive.ObjectID, params query.Params) (query.Page[model.Notification], error)
+	UpdateNotification(ctx context.Context, notificationID primitive.ObjectID, updateData bson.M) error
+	DeleteNotification(ctx context.Context, notificationID primitive.ObjectID) (*mongo.DeleteResult, error)
+	// ClaimDeadlineAlert records a deadline alert as sent and reports whether
+	// it had not been already
+	ClaimDeadlineAlert(ctx context.Context, alert model.DeadlineAlert) (bool, error)
+}
+
+type notificationRepository struct {
+	notificationCollection *mongo.Collection
+	alertCollection        *mongo.Collection
+}
+
+// NewNotificationRepository returns the MongoDB notification repository
+func NewNotificationRepository(db *mongo.Database) NotificationRepository {
+	return &notificationRepository{
+		notificationCollection: db.Collection("notifications"),
+		alertCollection:        db.Collection("deadline_alerts"),
+	}
+}
+
+// ClaimDeadlineAlert inserts the alert; the unique index on subject, user
+// and deadline turns a second claim into a duplicate key error. A moved
+// deadline is alerted again.
+func (s *notificationRepository) ClaimDeadlineAlert(ctx context.Context, alert model.DeadlineAlert) (bool, error) {
+	if alert.ID.IsZero() {
+		alert.ID = primitive.NewObjectID()
+	}
+	_, err := s.alertCollection.InsertOne(ctx, alert)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// CreateNotification stores a new notification
+func (s *notificationRepository) CreateNotification(ctx context.Context, notification model.Notification) (*mongo.InsertOneResult, error) {
+	if notification.ID.IsZero() {
+		notification.ID = primitive.NewObjectID()
+	}
+	notification.CreatedAt = time.Now()
+	notification.UpdatedAt = notification.CreatedAt
+	return s.notificationCollection.InsertOne(ctx, notification)
+}
+
+// GetAllNotificationsByFounder retrieves a page of notifications for a specific founder, ordered by the latest first by default.
+func (s *notificationRepository) GetAllNotificationsByFounder(ctx context.Context, founderID primitive.ObjectID, params query.Params) (query.Page[model.Notification], error) {
+	base := bson.M{"founder_id": founderID}
+	total, err := s.notificationCollection.CountDocuments(ctx, params.CountFilter(base))
+	if err != nil {
+		return query.Page[model.Notification]{}, err
+	}
+
+	cursor, err := s.notificationCollection.Find(ctx, params.Match(base), params.FindOptions())
+	if err != nil {
+		return query.Page[model.Notification]{}, err
+	}
+	return query.Collect[model.Notification](ctx, cursor, params, total)
+}
+
+// UpdateNotification updates a specific notification by its ID.
+func (s *notificationRepository) UpdateNotification(ctx context.Context, notificationID primitive.ObjectID, updateData bson.M) error {
+	update := bson.M{
+		"$set": updateData,
+	}
+	result, err := s.notificationCollection.UpdateOne(ctx, bson.M{"_id": notificationID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// DeleteNotification deletes a specific notification.
+func (s *notificationRepository) DeleteNotification(ctx context.Context, notificationID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	result, err := s.notificationCollection.DeleteOne(ctx, bson.M{"_id": notificationID})
+	if err != nil {
+		return nil, err
+	}
+	if result.DeletedCount == 0 {
+		return nil, ErrNotificationNotFound
+	}
+	return result, nil
+}
@@ -0,0 +1,135 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EventRetention is how long delivered events stay in the outbox
+const EventRetention = 7 * 24 * time.Hour
+
+// OutboxRepository stores domain events until every subscriber has handled
+// them. Repositories publish the events of their own writes; callers run
+// those writes in Service.Transaction so the change and its event are kept
+// or dropped together. A lease on each event lets one replica at a time
+// deliver it.
+type OutboxRepository interface {
+	// Publish adds the event to the outbox, due for delivery at once
+	Publish(ctx context.Context, event model.DomainEvent) error
+	// ClaimEvent gives owner the lease until until on the oldest pending
+	// event due at now that nobody holds, and returns nil when there is none
+	ClaimEvent(ctx context.Context, owner string, now, until time.Time) (*model.Event, error)
+	// ReleaseEvent stores how delivering the event went, giving up owner's
+	// lease. It fails with ErrEventLeaseLost when owner no longer holds it.
+	ReleaseEvent(ctx context.Context, event model.Event, owner string) error
+	// ListEvents returns the latest events with the status, or of any status
+	// when it is empty, newest first
+	ListEvents(ctx context.Context, status string, limit int) ([]model.Event, error)
+	// RetryEvent makes a failed event pending again, due at at with its
+	// retries restored. Subscribers that handled it are not called again.
+	RetryEvent(ctx context.Context, id primitive.ObjectID, at time.Time) error
+}
+
+type outboxRepository struct {
+	eventCollection *mongo.Collection
+}
+
+// NewOutboxRepository returns the MongoDB outbox
+func NewOutboxRepository(db *mongo.Database) OutboxRepository {
+	return &outboxRepository{eventCollection: db.Collection("outbox")}
+}
+
+// Publish inserts the event with the ctx of the write it records, so it
+// joins that write's transaction
+func (s *outboxRepository) Publish(ctx context.Context, e model.DomainEvent) error {
+	event, err := model.NewEvent(e)
+	if err != nil {
+		return err
+	}
+	_, err = s.eventCollection.InsertOne(ctx, event)
+	return err
+}
+
+// ClaimEvent takes the lease with a single conditional update, so replicas
+// racing for an event cannot both get it
+func (s *outboxRepository) ClaimEvent(ctx context.Context, owner string, now, until time.Time) (*model.Event, error) {
+	var event model.Event
+	err := s.eventCollection.FindOneAndUpdate(ctx,
+		bson.M{"status": model.EventPending, "next_attempt": bson.M{"$lte": now}, "lease_until": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"lease_owner": owner, "lease_until": until}},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "next_attempt", Value: 1}, {Key: "_id", Value: 1}}).
+			SetReturnDocument(options.After)).Decode(&event)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// ReleaseEvent stores the outcome of owner's delivery and clears the lease;
+// a TTL index drops delivered events after EventRetention
+func (s *outboxRepository) ReleaseEvent(ctx context.Context, event model.Event, owner string) error {
+	set := bson.M{
+		"status":       event.Status,
+		"delivered":    event.Delivered,
+		"attempt":      event.Attempt,
+		"next_attempt": event.NextAttempt,
+		"last_error":   event.LastError,
+		"lease_owner":  "",
+		"lease_until":  time.Time{},
+	}
+	if !event.DeliveredAt.IsZero() {
+		set["delivered_at"] = event.DeliveredAt
+	}
+	result, err := s.eventCollection.UpdateOne(ctx, bson.M{"_id": event.ID, "lease_owner": owner}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrEventLeaseLost
+	}
+	return nil
+}
+
+// ListEvents returns up to limit events, newest first
+func (s *outboxRepository) ListEvents(ctx context.Context, status string, limit int) ([]model.Event, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := s.eventCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	events := []model.Event{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// RetryEvent resets a failed event; events in any other status are left
+// alone and reported as not found
+func (s *outboxRepository) RetryEvent(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	result, err := s.eventCollection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": model.EventFailed},
+		bson.M{"$set": bson.M{"status": model.EventPending, "attempt": 0, "next_attempt": at}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrEventNotFound
+	}
+	return nil
+}
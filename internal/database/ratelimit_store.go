@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/ratelimit"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// rateLimitStore keeps token buckets and login lockouts in the rate_limits
+// collection, so every API instance enforces the same limits. Each change is
+// a single pipeline update on one document, which MongoDB applies atomically;
+// a TTL index on expires_at removes state nobody has touched in a while.
+type rateLimitStore struct {
+	collection *mongo.Collection
+}
+
+// NewRateLimitStore returns the MongoDB rate limit store
+func NewRateLimitStore(db *mongo.Database) ratelimit.Store {
+	return &rateLimitStore{collection: db.Collection("rate_limits")}
+}
+
+// upsertAfter upserts and returns the updated document
+var upsertAfter = options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+// Take implements ratelimit.Store
+func (s *rateLimitStore) Take(ctx context.Context, key string, b config.Bucket, now time.Time) (ratelimit.Decision, error) {
+	capacity := float64(b.Requests)
+	refill := bson.M{"$min": bson.A{capacity, bson.M{"$add": bson.A{
+		bson.M{"$ifNull": bson.A{"$tokens", capacity}},
+		bson.M{"$divide": bson.A{
+			bson.M{"$multiply": bson.A{
+				bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{now, bson.M{"$ifNull": bson.A{"$updated_at", now}}}}}},
+				capacity,
+			}},
+			b.Period.Milliseconds(),
+		}},
+	}}}}
+	hasToken := bson.M{"$gte": bson.A{"$tokens", 1}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"tokens": refill}}},
+		{{Key: "$set", Value: bson.M{
+			"allowed":    hasToken,
+			"tokens":     bson.M{"$cond": bson.A{hasToken, bson.M{"$subtract": bson.A{"$tokens", 1}}, "$tokens"}},
+			"updated_at": now,
+			"expires_at": now.Add(b.Period),
+		}}},
+	}
+
+	var doc struct {
+		Tokens  float64 `bson:"tokens"`
+		Allowed bool    `bson:"allowed"`
+	}
+	if err := s.upsert(ctx, key, pipeline, &doc); err != nil {
+		return ratelimit.Decision{}, err
+	}
+	return ratelimit.NewDecision(doc.Allowed, doc.Tokens, b), nil
+}
+
+// Fail implements ratelimit.Store. Failures are forgotten once Window passes
+// after the last failure or the end of the lock, whichever is later.
+func (s *rateLimitStore) Fail(ctx context.Context, key string, l config.Lockout, now time.Time) (time.Time, error) {
+	lastActivity := bson.M{"$max": bson.A{
+		bson.M{"$ifNull": bson.A{"$last_failure", now}},
+		bson.M{"$ifNull": bson.A{"$locked_until", now}},
+	}}
+	stale := bson.M{"$gt": bson.A{bson.M{"$subtract": bson.A{now, lastActivity}}, l.Window.Milliseconds()}}
+	pipeline := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"failures":     bson.M{"$cond": bson.A{stale, 1, bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$failures", 0}}, 1}}}},
+		"last_failure": now,
+		"expires_at":   bson.M{"$add": bson.A{bson.M{"$max": bson.A{now, bson.M{"$ifNull": bson.A{"$locked_until", now}}}}, l.Window.Milliseconds()}},
+	}}}}
+
+	var doc struct {
+		Failures    int       `bson:"failures"`
+		LockedUntil time.Time `bson:"locked_until"`
+	}
+	if err := s.upsert(ctx, key, pipeline, &doc); err != nil {
+		return time.Time{}, err
+	}
+
+	d := ratelimit.LockDuration(doc.Failures, l)
+	if d == 0 {
+		return doc.LockedUntil, nil
+	}
+	until := now.Add(d)
+	_, err := s.collection.UpdateOne(ctx, bson.M{"_id": key}, bson.M{
+		"$max": bson.M{"locked_until": until, "expires_at": until.Add(l.Window)},
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if doc.LockedUntil.After(until) {
+		return doc.LockedUntil, nil
+	}
+	return until, nil
+}
+
+// LockedUntil implements ratelimit.Store
+func (s *rateLimitStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	var doc struct {
+		LockedUntil time.Time `bson:"locked_until"`
+	}
+	err := s.collection.FindOne(ctx, bson.M{"_id": key}, options.FindOne().SetProjection(bson.M{"locked_until": 1})).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return time.Time{}, nil
+	}
+	return doc.LockedUntil, err
+}
+
+// Reset implements ratelimit.Store
+func (s *rateLimitStore) Reset(ctx context.Context, key string) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
+
+// upsert applies pipeline to the document at key, creating it if needed, and
+// decodes the result into out. Two instances creating the same key at once
+// can race on the insert; the loser retries as an update.
+func (s *rateLimitStore) upsert(ctx context.Context, key string, pipeline mongo.Pipeline, out interface{}) error {
+	err := s.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline, upsertAfter).Decode(out)
+	if mongo.IsDuplicateKeyError(err) {
+		err = s.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline, upsertAfter).Decode(out)
+	}
+	return err
+}
@@ -0,0 +1,26 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"DBackend/internal/database"
+	"DBackend/internal/ratelimit"
+	"DBackend/internal/ratelimit/storetest"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRateLimitStoreContract(t *testing.T) {
+	ctx := context.Background()
+	client, err := database.Connect(ctx, database.MongoConfig())
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	storetest.Run(t, func(t *testing.T) ratelimit.Store {
+		db := client.Database("rate_limits_" + primitive.NewObjectID().Hex())
+		t.Cleanup(func() { db.Drop(ctx) })
+		return database.NewRateLimitStore(db)
+	})
+}
@@ -0,0 +1,446 @@
+package database
+
+import (
+	"context"
+	"sort"
+
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+)
+
+// Search result types
+const (
+	SearchFounder          = "founder"
+	SearchInvestor         = "investor"
+	SearchGrant            = "grant"
+	SearchGrantApplication = "grant_application"
+	SearchDeal             = "deal"
+	SearchMeeting          = "meeting"
+	SearchTask             = "task"
+)
+
+// SearchTypes lists every entity type the search endpoint can return
+var SearchTypes = []string{
+	SearchFounder, SearchInvestor, SearchGrant, SearchGrantApplication,
+	SearchDeal, SearchMeeting, SearchTask,
+}
+
+// searchIndexes declares the text index fields (and weights) per collection
+var searchIndexes = map[string]bson.D{
+	"founders": {
+		{Key: "startup_name", Value: 10},
+		{Key: "industry", Value: 3},
+		{Key: "mission_statement", Value: 2},
+		{Key: "location", Value: 1},
+		{Key: "leadership_team", Value: 1},
+	},
+	"investors": {
+		{Key: "investor_type", Value: 3},
+		{Key: "thesis", Value: 2},
+		{Key: "preferred_industries", Value: 2},
+		{Key: "preferred_regions", Value: 1},
+	},
+	"grants": {
+		{Key: "name", Value: 10},
+		{Key: "category", Value: 3},
+		{Key: "description", Value: 2},
+		{Key: "region", Value: 1},
+	},
+	"applications": {
+		{Key: "startup_name", Value: 10},
+		{Key: "description", Value: 2},
+	},
+	"meetings": {
+		{Key: "title", Value: 5},
+		{Key: "notes", Value: 1},
+	},
+	"tasks": {
+		{Key: "title", Value: 5},
+	},
+}
+
+// dealSearchIndex declares the text index fields of deal_flow. Deals are
+// also found by their startup's fields, and tasks kept on a deal are found
+// through it rather than as task results.
+var dealSearchIndex = bson.D{
+	{Key: "tasks.title", Value: 5},
+	{Key: "meetings.title", Value: 2},
+	{Key: "notes.content", Value: 2},
+	{Key: "documents.name", Value: 2},
+}
+
+// SearchScope identifies the caller so results can be limited to what they may see
+type SearchScope struct {
+	UserID    primitive.ObjectID
+	FounderID primitive.ObjectID // founder profile ID, zero if the caller is not a founder
+	Roles     []string
+}
+
+// Has reports whether the caller holds role
+func (s SearchScope) Has(role string) bool {
+	for _, r := range s.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchQuery is a free-text search across entity types
+type SearchQuery struct {
+	Text  string
+	Types []string // empty means all types
+	Limit int      // maximum results per type
+	Scope SearchScope
+}
+
+// SearchResult is a tagged union: Type names which one of the entity fields is set
+type SearchResult struct {
+	Type             string                  `json:"type"`
+	ID               primitive.ObjectID      `json:"id"`
+	Title            string                  `json:"title"`
+	Score            float64                 `json:"score"`
+	Founder          *model.Founder          `json:"founder,omitempty"`
+	Investor         *model.Investor         `json:"investor,omitempty"`
+	Grant            *model.Grant            `json:"grant,omitempty"`
+	GrantApplication *model.GrantApplication `json:"grant_application,omitempty"`
+	Deal             *model.DealFlow         `json:"deal,omitempty"`
+	Meeting          *model.Meeting          `json:"meeting,omitempty"`
+	Task             *model.Task             `json:"task,omitempty"`
+}
+
+type SearchService interface {
+	Search(ctx context.Context, q SearchQuery) ([]SearchResult, error)
+	EnsureSearchIndexes(ctx context.Context) error
+}
+
+type searchService struct {
+	db *mongo.Database
+}
+
+func NewSearchService(db *mongo.Database) SearchService {
+	return &searchService{db: db}
+}
+
+// EnsureSearchIndexes creates the text indexes backing search, except the
+// one on deal_flow, which a later migration adds with textIndex
+func (s *searchService) EnsureSearchIndexes(ctx context.Context) error {
+	for collection, keys := range searchIndexes {
+		if _, err := s.db.Collection(collection).Indexes().CreateOne(ctx, textIndex(keys)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// textIndex builds the search_text index over the weighted fields
+func textIndex(keys bson.D) mongo.IndexModel {
+	fields := bson.D{}
+	for _, k := range keys {
+		fields = append(fields, bson.E{Key: k.Key, Value: "text"})
+	}
+	return mongo.IndexModel{
+		Keys: fields,
+		Options: options.Index().
+			SetName("search_text").
+			SetWeights(keys),
+	}
+}
+
+// Search runs a text query over every requested collection, scopes each one
+// to the caller and merges the hits ordered by relevance.
+func (s *searchService) Search(ctx context.Context, q SearchQuery) ([]SearchResult, error) {
+	q.Limit = SearchLimit(q.Limit)
+	wanted := map[string]bool{}
+	for _, t := range q.Types {
+		wanted[t] = true
+	}
+	include := func(t string) bool { return len(wanted) == 0 || wanted[t] }
+
+	results := []SearchResult{}
+
+	if include(SearchFounder) {
+		docs, err := textSearch[model.Founder](ctx, s.db.Collection("founders"), q, bson.M{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range docs {
+			results = append(results, SearchResult{
+				Type: SearchFounder, ID: docs[i].Doc.ID, Title: docs[i].Doc.StartupName,
+				Score: docs[i].Score, Founder: &docs[i].Doc,
+			})
+		}
+	}
+
+	if include(SearchInvestor) {
+		filter := bson.M{}
+		if !q.Scope.Has("admin") && !q.Scope.Has("founder") {
+			filter["user_id"] = q.Scope.UserID
+		}
+		docs, err := textSearch[model.Investor](ctx, s.db.Collection("investors"), q, filter)
+		if err != nil {
+			return nil, err
+		}
+		for i := range docs {
+			results = append(results, SearchResult{
+				Type: SearchInvestor, ID: docs[i].Doc.ID, Title: docs[i].Doc.InvestorType,
+				Score: docs[i].Score, Investor: &docs[i].Doc,
+			})
+		}
+	}
+
+	if include(SearchGrant) {
+		docs, err := textSearch[model.Grant](ctx, s.db.Collection("grants"), q, bson.M{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range docs {
+			results = append(results, SearchResult{
+				Type: SearchGrant, ID: docs[i].Doc.ID, Title: docs[i].Doc.Name,
+				Score: docs[i].Score, Grant: &docs[i].Doc,
+			})
+		}
+	}
+
+	if include(SearchGrantApplication) {
+		filter := bson.M{}
+		if !q.Scope.Has("admin") {
+			filter["founder_id"] = q.Scope.UserID
+		}
+		docs, err := textSearch[model.GrantApplication](ctx, s.db.Collection("applications"), q, filter)
+		if err != nil {
+			return nil, err
+		}
+		for i := range docs {
+			results = append(results, SearchResult{
+				Type: SearchGrantApplication, ID: docs[i].Doc.ID, Title: docs[i].Doc.StartupName,
+				Score: docs[i].Score, GrantApplication: &docs[i].Doc,
+			})
+		}
+	}
+
+	if include(SearchDeal) {
+		deals, err := s.searchDeals(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, deals...)
+	}
+
+	if include(SearchMeeting) {
+		docs, err := textSearch[model.Meeting](ctx, s.db.Collection("meetings"), q, ParticipantFilter(q.Scope))
+		if err != nil {
+			return nil, err
+		}
+		for i := range docs {
+			results = append(results, SearchResult{
+				Type: SearchMeeting, ID: docs[i].Doc.ID, Title: docs[i].Doc.Title,
+				Score: docs[i].Score, Meeting: &docs[i].Doc,
+			})
+		}
+	}
+
+	if include(SearchTask) {
+		filter := bson.M{}
+		if !q.Scope.Has("admin") {
+			filter["created_by"] = q.Scope.UserID
+		}
+		docs, err := textSearch[model.Task](ctx, s.db.Collection("tasks"), q, filter)
+		if err != nil {
+			return nil, err
+		}
+		for i := range docs {
+			results = append(results, SearchResult{
+				Type: SearchTask, ID: docs[i].Doc.ID, Title: docs[i].Doc.Title,
+				Score: docs[i].Score, Task: &docs[i].Doc,
+			})
+		}
+	}
+
+	SortByRelevance(results)
+	return results, nil
+}
+
+// searchDeals returns the caller's deals matching on their own fields or
+// on their startup's, each scored by the better of the two. The startups
+// searched are those of the caller's deals, so other founders ranking higher
+// do not crowd them out.
+func (s *searchService) searchDeals(ctx context.Context, q SearchQuery) ([]SearchResult, error) {
+	deals := s.db.Collection("deal_flow")
+	scope := ParticipantFilter(q.Scope)
+	best := map[primitive.ObjectID]SearchResult{}
+	keep := func(r SearchResult) {
+		if prev, ok := best[r.ID]; !ok || r.Score > prev.Score {
+			best[r.ID] = r
+		}
+	}
+
+	hits, err := textSearch[model.DealFlow](ctx, deals, q, scope)
+	if err != nil {
+		return nil, err
+	}
+	for i := range hits {
+		keep(SearchResult{Type: SearchDeal, ID: hits[i].Doc.ID, Score: hits[i].Score, Deal: &hits[i].Doc})
+	}
+
+	startupIDs, err := deals.Distinct(ctx, "founder_id", scope)
+	if err != nil {
+		return nil, err
+	}
+	if len(startupIDs) > 0 {
+		founders, err := textSearch[model.Founder](ctx, s.db.Collection("founders"), q, bson.M{"_id": bson.M{"$in": startupIDs}})
+		if err != nil {
+			return nil, err
+		}
+		scores := map[primitive.ObjectID]float64{}
+		ids := bson.A{}
+		for _, f := range founders {
+			scores[f.Doc.ID] = f.Score
+			ids = append(ids, f.Doc.ID)
+		}
+		if len(ids) > 0 {
+			cursor, err := deals.Find(ctx, bson.M{"$and": bson.A{bson.M{"founder_id": bson.M{"$in": ids}}, scope}})
+			if err != nil {
+				return nil, err
+			}
+			var matched []model.DealFlow
+			if err := cursor.All(ctx, &matched); err != nil {
+				return nil, err
+			}
+			for i := range matched {
+				keep(SearchResult{Type: SearchDeal, ID: matched[i].ID, Score: scores[matched[i].StartupID], Deal: &matched[i]})
+			}
+		}
+	}
+
+	results := make([]SearchResult, 0, len(best))
+	for _, r := range best {
+		results = append(results, r)
+	}
+	if err := s.titleDeals(ctx, results); err != nil {
+		return nil, err
+	}
+	SortByRelevance(results)
+	if len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+	return results, nil
+}
+
+// titleDeals titles deal results with their startup's name
+func (s *searchService) titleDeals(ctx context.Context, results []SearchResult) error {
+	ids := bson.A{}
+	for _, r := range results {
+		ids = append(ids, r.Deal.StartupID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	cursor, err := s.db.Collection("founders").Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, options.Find().SetProjection(bson.M{"startup_name": 1}))
+	if err != nil {
+		return err
+	}
+	var founders []model.Founder
+	if err := cursor.All(ctx, &founders); err != nil {
+		return err
+	}
+	names := map[primitive.ObjectID]string{}
+	for _, f := range founders {
+		names[f.ID] = f.StartupName
+	}
+	for i := range results {
+		results[i].Title = names[results[i].Deal.StartupID]
+	}
+	return nil
+}
+
+// SearchLimit applies the default and maximum per-type result limits
+func SearchLimit(limit int) int {
+	if limit <= 0 {
+		return defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		return maxSearchLimit
+	}
+	return limit
+}
+
+// SearchWeights returns the text index fields and weights for collection
+func SearchWeights(collection string) bson.D {
+	if collection == "deal_flow" {
+		return dealSearchIndex
+	}
+	return searchIndexes[collection]
+}
+
+// ParticipantFilter limits deals and meetings to those the caller takes part
+// in, by the same rule as the meeting list. Deals name the founder by their
+// profile, meetings by their user.
+func ParticipantFilter(scope SearchScope) bson.M {
+	if scope.Has("admin") {
+		return bson.M{}
+	}
+	ids := []primitive.ObjectID{scope.UserID}
+	if !scope.FounderID.IsZero() {
+		ids = append(ids, scope.FounderID)
+	}
+	return attendedBy(ids)
+}
+
+// scored wraps a decoded document with its text search score
+type scored[T any] struct {
+	Doc   T
+	Score float64
+}
+
+// textSearch runs $text against collection, restricted by filter, best matches first
+func textSearch[T any](ctx context.Context, collection *mongo.Collection, q SearchQuery, filter bson.M) ([]scored[T], error) {
+	match := bson.M{"$text": bson.M{"$search": q.Text}}
+	if len(filter) > 0 {
+		match = bson.M{"$and": bson.A{match, filter}}
+	}
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}}).
+		SetLimit(int64(q.Limit))
+
+	cursor, err := collection.Find(ctx, match, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []scored[T]
+	for cursor.Next(ctx) {
+		var item scored[T]
+		if err := cursor.Decode(&item.Doc); err != nil {
+			return nil, err
+		}
+		item.Score, _ = cursor.Current.Lookup("score").DoubleOK()
+		docs = append(docs, item)
+	}
+	return docs, cursor.Err()
+}
+
+// SortByRelevance orders results by descending score, then type and title for stable output
+func SortByRelevance(results []SearchResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].Type != results[j].Type {
+			return results[i].Type < results[j].Type
+		}
+		return results[i].Title < results[j].Title
+	})
+}
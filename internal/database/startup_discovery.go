@@ -0,0 +1,272 @@
+package database
+
+import (
+	"context"
+
+	"DBackend/internal/query"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	defaultStartupPageSize = 20
+	maxStartupPageSize     = 100
+)
+
+// startupSortFields whitelists the founder fields discovery results may be sorted by
+var startupSortFields = map[string]bool{
+	"created_at":     true,
+	"fund_required":  true,
+	"total_invested": true,
+	"startup_name":   true,
+}
+
+// startupFacetFields maps facet names to the founder fields they group on
+var startupFacetFields = map[string]string{
+	"industry":      "industry",
+	"funding_stage": "funding_stage",
+	"location":      "location",
+	"team_size":     "team_size",
+}
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = query.ErrInvalidCursor
+
+// IntRange is an inclusive numeric range; nil bounds are open
+type IntRange struct {
+	Min *int
+	Max *int
+}
+
+// StartupQuery describes a faceted startup discovery request
+type StartupQuery struct {
+	Industries    []string
+	FundingStages []string
+	Locations     []string
+	TeamSizes     []string
+	FundRequired  IntRange
+	TotalInvested IntRange
+	SortField     string
+	SortDesc      bool
+	Limit         int
+	Cursor        string
+}
+
+// FacetCount is the number of startups sharing a facet value
+type FacetCount struct {
+	Value string `bson:"_id" json:"value"`
+	Count int    `bson:"count" json:"count"`
+}
+
+// StartupPage is one page of discovery results plus facet counts
+type StartupPage struct {
+	Founders   []model.Founder         `json:"founders"`
+	Facets     map[string][]FacetCount `json:"facets"`
+	Total      int                     `json:"total"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+}
+
+// DiscoverStartups runs a filtered, sorted and cursor-paginated query over the
+// founders collection and returns counts per facet. Each facet counts the
+// startups matching every filter but its own, so selecting an industry still
+// shows how many startups the other industries have.
+func (s *founderRepository) DiscoverStartups(ctx context.Context, q StartupQuery) (*StartupPage, error) {
+	params, err := StartupParams(q)
+	if err != nil {
+		return nil, err
+	}
+
+	facets, total, err := s.startupFacets(ctx, params.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := s.founderCollection.Find(ctx, params.Match(nil), params.FindOptions())
+	if err != nil {
+		return nil, err
+	}
+	founders, err := query.Collect[model.Founder](ctx, cursor, params, int64(total))
+	if err != nil {
+		return nil, err
+	}
+
+	return &StartupPage{
+		Founders:   founders.Items,
+		Facets:     facets,
+		Total:      total,
+		NextCursor: founders.NextCursor,
+	}, nil
+}
+
+// startupFacets counts the startups per facet value in a single
+// aggregation. The filters on no facet narrow the input; each facet then
+// applies the selections of the others.
+func (s *founderRepository) startupFacets(ctx context.Context, filter bson.M) (map[string][]FacetCount, int, error) {
+	base := filter
+	for name := range startupFacetFields {
+		base = StartupFacetFilter(base, name)
+	}
+	facetStages := bson.D{
+		{Key: "total", Value: bson.A{
+			bson.D{{Key: "$match", Value: filter}},
+			bson.D{{Key: "$count", Value: "count"}},
+		}},
+	}
+	for name, field := range startupFacetFields {
+		facetStages = append(facetStages, bson.E{Key: name, Value: bson.A{
+			bson.D{{Key: "$match", Value: StartupFacetFilter(filter, name)}},
+			bson.D{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: "$" + field},
+				{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+			}}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		}})
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: base}},
+		{{Key: "$facet", Value: facetStages}},
+	}
+
+	cursor, err := s.founderCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Total        []struct{ Count int } `bson:"total"`
+		Industry     []FacetCount          `bson:"industry"`
+		FundingStage []FacetCount          `bson:"funding_stage"`
+		Location     []FacetCount          `bson:"location"`
+		TeamSize     []FacetCount          `bson:"team_size"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, 0, err
+	}
+
+	facets := map[string][]FacetCount{
+		"industry":      {},
+		"funding_stage": {},
+		"location":      {},
+		"team_size":     {},
+	}
+	total := 0
+	if len(results) > 0 {
+		r := results[0]
+		if len(r.Total) > 0 {
+			total = r.Total[0].Count
+		}
+		facets["industry"] = nonNilFacets(r.Industry)
+		facets["funding_stage"] = nonNilFacets(r.FundingStage)
+		facets["location"] = nonNilFacets(r.Location)
+		facets["team_size"] = nonNilFacets(r.TeamSize)
+	}
+
+	return facets, total, nil
+}
+
+// EnsureStartupIndexes creates the founders indexes backing discovery filters and sorts
+func (s *founderRepository) EnsureStartupIndexes(ctx context.Context) error {
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "industry", Value: 1}}},
+		{Keys: bson.D{{Key: "funding_stage", Value: 1}}},
+		{Keys: bson.D{{Key: "location", Value: 1}}},
+		{Keys: bson.D{{Key: "team_size", Value: 1}}},
+	}
+	for field := range startupSortFields {
+		models = append(models, mongo.IndexModel{
+			Keys: bson.D{{Key: field, Value: 1}, {Key: "_id", Value: 1}},
+		})
+	}
+
+	_, err := s.founderCollection.Indexes().CreateMany(ctx, models)
+	return err
+}
+
+// StartupParams translates a discovery query into list parameters over the
+// founders collection
+func StartupParams(q StartupQuery) (query.Params, error) {
+	q = normalizeStartupQuery(q)
+	params := query.Params{
+		Filter:   startupFilter(q),
+		SortPath: q.SortField,
+		SortDesc: q.SortDesc,
+		Limit:    q.Limit,
+	}
+	if q.Cursor != "" {
+		after, err := query.DecodeCursor(q.Cursor)
+		if err != nil {
+			return query.Params{}, err
+		}
+		params.After = &after
+	}
+	return params, nil
+}
+
+// normalizeStartupQuery applies default sorting and clamps the page size
+func normalizeStartupQuery(q StartupQuery) StartupQuery {
+	if !startupSortFields[q.SortField] {
+		q.SortField = "created_at"
+		q.SortDesc = true
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultStartupPageSize
+	}
+	if q.Limit > maxStartupPageSize {
+		q.Limit = maxStartupPageSize
+	}
+	return q
+}
+
+// startupFilter translates the query's facet selections and ranges into a founders filter
+func startupFilter(q StartupQuery) bson.M {
+	filter := bson.M{}
+	addIn := func(field string, values []string) {
+		if len(values) > 0 {
+			filter[field] = bson.M{"$in": values}
+		}
+	}
+	addIn("industry", q.Industries)
+	addIn("funding_stage", q.FundingStages)
+	addIn("location", q.Locations)
+	addIn("team_size", q.TeamSizes)
+
+	addRange := func(field string, r IntRange) {
+		bounds := bson.M{}
+		if r.Min != nil {
+			bounds["$gte"] = *r.Min
+		}
+		if r.Max != nil {
+			bounds["$lte"] = *r.Max
+		}
+		if len(bounds) > 0 {
+			filter[field] = bounds
+		}
+	}
+	addRange("fund_required", q.FundRequired)
+	addRange("total_invested", q.TotalInvested)
+
+	return filter
+}
+
+// StartupFacetFilter returns filter without the selection on facet, the
+// startups that facet's counts are taken over
+func StartupFacetFilter(filter bson.M, facet string) bson.M {
+	others := bson.M{}
+	for field, cond := range filter {
+		if field != startupFacetFields[facet] {
+			others[field] = cond
+		}
+	}
+	return others
+}
+
+func nonNilFacets(counts []FacetCount) []FacetCount {
+	if counts == nil {
+		return []FacetCount{}
+	}
+	return counts
+}
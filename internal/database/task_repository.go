@@ -0,0 +1,262 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"DBackend/internal/query"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TaskRepository stores the tasks embedded in deal flow entries, and
+// standalone tasks that belong to no deal in their own collection
+type TaskRepository interface {
+	// AddTask adds task to the deal, or stores it on its own when dealID is
+	// zero. A task without an ID is given one.
+	AddTask(ctx context.Context, dealID primitive.ObjectID, task model.Task) (*mongo.UpdateResult, error)
+	// GetAllTasks returns a page of the tasks userID may see, as with
+	// GetDueTasks but due or not, or of every task when userID is zero
+	GetAllTasks(ctx context.Context, userID primitive.ObjectID, params query.Params) (query.Page[model.Task], error)
+	GetTaskByID(ctx context.Context, id primitive.ObjectID) (model.Task, error)
+	GetTasksByUser(ctx context.Context, userID primitive.ObjectID) ([]model.Task, error)
+	// GetDueTasks lists the tasks with a due date that are in the user's deals
+	// or that the user created or was assigned
+	GetDueTasks(ctx context.Context, userID primitive.ObjectID) ([]model.Task, error)
+	// GetTasksDueBetween lists the open tasks, in deals or standalone, due
+	// from from up to to
+	GetTasksDueBetween(ctx context.Context, from, to time.Time) ([]model.Task, error)
+	UpdateTask(ctx context.Context, id primitive.ObjectID, updates model.Task) error
+	// UpdateTaskStatus sets completion on a task; a zero dealID matches the task in any deal
+	UpdateTaskStatus(ctx context.Context, dealID primitive.ObjectID, taskID primitive.ObjectID, completed bool) (*mongo.UpdateResult, error)
+	AssignTask(ctx context.Context, taskID primitive.ObjectID, userID primitive.ObjectID) error
+	DeleteTask(ctx context.Context, id primitive.ObjectID) error
+}
+
+type taskRepository struct {
+	dealFlowCollection *mongo.Collection
+	taskCollection     *mongo.Collection
+}
+
+// NewTaskRepository returns the MongoDB task repository
+func NewTaskRepository(db *mongo.Database) TaskRepository {
+	return &taskRepository{dealFlowCollection: db.Collection("deal_flow"), taskCollection: db.Collection("tasks")}
+}
+
+// AddTask adds a task to a deal flow, or to the tasks collection when it has no deal
+func (s *taskRepository) AddTask(ctx context.Context, dealID primitive.ObjectID, task model.Task) (*mongo.UpdateResult, error) {
+	if task.ID.IsZero() {
+		task.ID = primitive.NewObjectID()
+	}
+	if dealID.IsZero() {
+		if _, err := s.taskCollection.InsertOne(ctx, task); err != nil {
+			return nil, err
+		}
+		return &mongo.UpdateResult{UpsertedCount: 1, UpsertedID: task.ID}, nil
+	}
+	update := bson.M{"$push": bson.M{"tasks": task}}
+	return s.dealFlowCollection.UpdateOne(ctx, bson.M{"_id": dealID}, update)
+}
+
+// GetAllTasks retrieves a page of the tasks userID may see across all deal
+// flows and the standalone tasks
+func (s *taskRepository) GetAllTasks(ctx context.Context, userID primitive.ObjectID, params query.Params) (query.Page[model.Task], error) {
+	unwound := s.visibleTasks(userID)
+
+	total, err := countAggregate(ctx, s.dealFlowCollection, append(unwound, bson.D{{Key: "$match", Value: params.CountFilter(nil)}}))
+	if err != nil {
+		return query.Page[model.Task]{}, err
+	}
+
+	cursor, err := s.dealFlowCollection.Aggregate(ctx, append(unwound, params.Stages(nil)...))
+	if err != nil {
+		return query.Page[model.Task]{}, err
+	}
+	return query.Collect[model.Task](ctx, cursor, params, total)
+}
+
+// GetTaskByID retrieves a specific task by ID
+func (s *taskRepository) GetTaskByID(ctx context.Context, id primitive.ObjectID) (model.Task, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"tasks._id": id}}},
+		{{Key: "$unwind", Value: "$tasks"}},
+		{{Key: "$match", Value: bson.M{"tasks._id": id}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$tasks"}}},
+	}
+
+	cursor, err := s.dealFlowCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return model.Task{}, err
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []model.Task
+	if err = cursor.All(ctx, &tasks); err != nil {
+		return model.Task{}, err
+	}
+
+	if len(tasks) > 0 {
+		return tasks[0], nil
+	}
+
+	var task model.Task
+	if err := s.taskCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&task); err != nil {
+		return model.Task{}, NotFound(err, ErrTaskNotFound)
+	}
+	return task, nil
+}
+
+// GetTasksByUser retrieves all tasks for a specific user
+func (s *taskRepository) GetTasksByUser(ctx context.Context, userID primitive.ObjectID) ([]model.Task, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"tasks.created_by": userID}}},
+		{{Key: "$unwind", Value: "$tasks"}},
+		{{Key: "$match", Value: bson.M{"tasks.created_by": userID}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$tasks"}}},
+		{{Key: "$unionWith", Value: bson.M{"coll": s.taskCollection.Name(), "pipeline": bson.A{
+			bson.M{"$match": bson.M{"created_by": userID}},
+		}}}},
+	}
+	return s.aggregate(ctx, pipeline)
+}
+
+// GetDueTasks retrieves the tasks with a due date in the user's deals, plus
+// the ones anywhere that the user created or was assigned
+func (s *taskRepository) GetDueTasks(ctx context.Context, userID primitive.ObjectID) ([]model.Task, error) {
+	pipeline := append(s.visibleTasks(userID), bson.D{{Key: "$match", Value: bson.M{"due_date": bson.M{"$gt": time.Time{}}}}})
+	return s.aggregate(ctx, pipeline)
+}
+
+// visibleTasks returns the stages that list the tasks in userID's deals and
+// the ones anywhere that userID created or was assigned, deal tasks first
+// and then standalone ones. Every task is listed when userID is zero.
+func (s *taskRepository) visibleTasks(userID primitive.ObjectID) mongo.Pipeline {
+	if userID.IsZero() {
+		return mongo.Pipeline{
+			{{Key: "$unwind", Value: "$tasks"}},
+			{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$tasks"}}},
+			{{Key: "$unionWith", Value: bson.M{"coll": s.taskCollection.Name()}}},
+		}
+	}
+	inDeal := bson.M{"$or": bson.A{
+		bson.M{"investor_id": userID},
+		bson.M{"founder_id": userID},
+		bson.M{"tasks.created_by": userID},
+		bson.M{"tasks.assigned_to": userID},
+	}}
+	mine := bson.M{"$or": bson.A{bson.M{"created_by": userID}, bson.M{"assigned_to": userID}}}
+	return mongo.Pipeline{
+		{{Key: "$match", Value: inDeal}},
+		{{Key: "$unwind", Value: "$tasks"}},
+		{{Key: "$match", Value: inDeal}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$tasks"}}},
+		{{Key: "$unionWith", Value: bson.M{"coll": s.taskCollection.Name(), "pipeline": bson.A{
+			bson.M{"$match": mine},
+		}}}},
+	}
+}
+
+// GetTasksDueBetween retrieves the incomplete tasks due in [from, to) across
+// all deal flows and the standalone tasks
+func (s *taskRepository) GetTasksDueBetween(ctx context.Context, from, to time.Time) ([]model.Task, error) {
+	due := bson.M{"$gte": from, "$lt": to}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"tasks": bson.M{"$elemMatch": bson.M{"due_date": due, "completed": false}}}}},
+		{{Key: "$unwind", Value: "$tasks"}},
+		{{Key: "$match", Value: bson.M{"tasks.due_date": due, "tasks.completed": false}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$tasks"}}},
+		{{Key: "$unionWith", Value: bson.M{"coll": s.taskCollection.Name(), "pipeline": bson.A{
+			bson.M{"$match": bson.M{"due_date": due, "completed": false}},
+		}}}},
+	}
+	return s.aggregate(ctx, pipeline)
+}
+
+// aggregate runs pipeline over deal_flow and decodes the resulting tasks
+func (s *taskRepository) aggregate(ctx context.Context, pipeline mongo.Pipeline) ([]model.Task, error) {
+	cursor, err := s.dealFlowCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []model.Task
+	if err = cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// UpdateTask updates an existing task
+func (s *taskRepository) UpdateTask(ctx context.Context, id primitive.ObjectID, updates model.Task) error {
+	updates.UpdatedAt = time.Now()
+
+	result, err := s.setTask(ctx, bson.M{"tasks._id": id}, id, bson.M{
+		"title":      updates.Title,
+		"completed":  updates.Completed,
+		"due_date":   updates.DueDate,
+		"priority":   updates.Priority,
+		"updated_at": updates.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
+// UpdateTaskStatus updates the completion status of a task in a deal flow,
+// or of a standalone task when dealID is zero
+func (s *taskRepository) UpdateTaskStatus(ctx context.Context, dealID primitive.ObjectID, taskID primitive.ObjectID, completed bool) (*mongo.UpdateResult, error) {
+	fields := bson.M{"completed": completed, "updated_at": time.Now()}
+	if !dealID.IsZero() {
+		return s.dealFlowCollection.UpdateOne(ctx, bson.M{"_id": dealID, "tasks._id": taskID}, bson.M{"$set": embedded(fields)})
+	}
+	return s.setTask(ctx, bson.M{"tasks._id": taskID}, taskID, fields)
+}
+
+// AssignTask assigns a task to a user
+func (s *taskRepository) AssignTask(ctx context.Context, taskID primitive.ObjectID, userID primitive.ObjectID) error {
+	_, err := s.setTask(ctx, bson.M{"tasks._id": taskID}, taskID, bson.M{
+		"assigned_to": userID,
+		"updated_at":  time.Now(),
+	})
+	return err
+}
+
+// DeleteTask deletes a task
+func (s *taskRepository) DeleteTask(ctx context.Context, id primitive.ObjectID) error {
+	filter := bson.M{}
+	update := bson.M{"$pull": bson.M{"tasks": bson.M{"_id": id}}}
+
+	if _, err := s.dealFlowCollection.UpdateMany(ctx, filter, update); err != nil {
+		return err
+	}
+	_, err := s.taskCollection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// setTask sets fields on the deal task matched by dealFilter, or on the
+// standalone task with id when no deal holds it
+func (s *taskRepository) setTask(ctx context.Context, dealFilter bson.M, id primitive.ObjectID, fields bson.M) (*mongo.UpdateResult, error) {
+	result, err := s.dealFlowCollection.UpdateOne(ctx, dealFilter, bson.M{"$set": embedded(fields)})
+	if err != nil || result.MatchedCount > 0 {
+		return result, err
+	}
+	return s.taskCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": fields})
+}
+
+// embedded prefixes fields with the positional path of the matched deal task
+func embedded(fields bson.M) bson.M {
+	set := bson.M{}
+	for k, v := range fields {
+		set["tasks.$."+k] = v
+	}
+	return set
+}
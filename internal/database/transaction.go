@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// transactionSupport remembers whether the server can run transactions,
+// which only replica sets and sharded clusters can
+type transactionSupport struct {
+	mu        sync.Mutex
+	checked   bool
+	supported bool
+}
+
+// Transaction runs fn in a MongoDB transaction. Unlike
+// mongo.Session.WithTransaction it never retries fn, which may have side
+// effects outside the database. On a standalone server fn runs without a
+// transaction, so writes made before a failure are kept; run a single-node
+// replica set to get atomic writes in development.
+func (s *service) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	supported, err := s.supportsTransactions(ctx)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return fn(ctx)
+	}
+
+	session, err := s.db.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(context.WithoutCancel(ctx))
+	return mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
+		if err := session.StartTransaction(); err != nil {
+			return err
+		}
+		if err := fn(sc); err != nil {
+			if abortErr := session.AbortTransaction(context.WithoutCancel(sc)); abortErr != nil {
+				slog.WarnContext(ctx, "aborting transaction", "error", abortErr)
+			}
+			return err
+		}
+		return session.CommitTransaction(sc)
+	})
+}
+
+func (s *service) supportsTransactions(ctx context.Context) (bool, error) {
+	t := &s.transactions
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.checked {
+		return t.supported, nil
+	}
+
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := s.db.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, err
+	}
+	t.checked = true
+	t.supported = hello.SetName != "" || hello.Msg == "isdbgrid"
+	if !t.supported {
+		slog.WarnContext(ctx, "MongoDB is a standalone server; multi-document writes, including outbox events, are not atomic; run a replica set")
+	}
+	return t.supported, nil
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// UserRepository stores accounts, their role profiles and revoked tokens
+type UserRepository interface {
+	// FindByEmail returns nil and no error when no user has the email
+	FindByEmail(ctx context.Context, email string) (*model.User, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.User, error)
+	CreateUser(ctx context.Context, user model.User) (*mongo.InsertOneResult, error)
+	UpdateRoles(ctx context.Context, email string, roles []string) (*mongo.UpdateResult, error)
+	// SetTimezone sets the IANA zone meeting times are shown to the user in
+	SetTimezone(ctx context.Context, id primitive.ObjectID, timezone string) error
+	// CreateRoleData creates the empty founder, investor or admin profile for a new role
+	CreateRoleData(ctx context.Context, userID primitive.ObjectID, role string) error
+	GetUserCount(ctx context.Context) (int64, error)
+	BlacklistToken(ctx context.Context, token string) error
+	IsTokenBlacklisted(ctx context.Context, token string) (bool, error)
+	// DeleteExpiredTokens removes blacklisted tokens that expired before now
+	// and returns how many it removed
+	DeleteExpiredTokens(ctx context.Context, now time.Time) (int64, error)
+}
+
+type userRepository struct {
+	userCollection      *mongo.Collection
+	founderCollection   *mongo.Collection
+	investorCollection  *mongo.Collection
+	adminCollection     *mongo.Collection
+	blacklistCollection *mongo.Collection
+}
+
+// NewUserRepository returns the MongoDB user repository
+func NewUserRepository(db *mongo.Database) UserRepository {
+	return &userRepository{
+		userCollection:      db.Collection("users"),
+		founderCollection:   db.Collection("founders"),
+		investorCollection:  db.Collection("investors"),
+		adminCollection:     db.Collection("admins"),
+		blacklistCollection: db.Collection("blacklist_tokens"),
+	}
+}
+
+// BlacklistToken stores a JWT token in the blacklist collection
+func (s *userRepository) BlacklistToken(ctx context.Context, token string) error {
+	_, err := s.blacklistCollection.InsertOne(ctx, bson.M{
+		"token":     token,
+		"expiredAt": time.Now().Add(24 * time.Hour), // Set expiry for 24h
+	})
+
+	return err
+}
+
+// IsTokenBlacklisted checks if a token is blacklisted
+func (s *userRepository) IsTokenBlacklisted(ctx context.Context, token string) (bool, error) {
+	count, err := s.blacklistCollection.CountDocuments(ctx, bson.M{"token": token})
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// DeleteExpiredTokens sweeps the blacklist. The TTL index does the same, but
+// only about once a minute and not at all on deployments that lack it.
+func (s *userRepository) DeleteExpiredTokens(ctx context.Context, now time.Time) (int64, error) {
+	result, err := s.blacklistCollection.DeleteMany(ctx, bson.M{"expiredAt": bson.M{"$lt": now}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// Find user by email
+func (s *userRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	var user model.User
+	err := s.userCollection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByID retrieves a user by ID
+func (s *userRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.User, error) {
+	var user model.User
+	if err := s.userCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&user); err != nil {
+		return nil, NotFound(err, ErrUserNotFound)
+	}
+	return &user, nil
+}
+
+// Update user roles
+func (s *userRepository) UpdateRoles(ctx context.Context, email string, roles []string) (*mongo.UpdateResult, error) {
+	filter := bson.M{"email": email}
+	update := bson.M{"$set": bson.M{"roles": roles}}
+	return s.userCollection.UpdateOne(ctx, filter, update)
+}
+
+// SetTimezone sets the user's timezone
+func (s *userRepository) SetTimezone(ctx context.Context, id primitive.ObjectID, timezone string) error {
+	update := bson.M{"$set": bson.M{"timezone": timezone, "updated_at": time.Now()}}
+	result, err := s.userCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// Create a new user
+func (s *userRepository) CreateUser(ctx context.Context, user model.User) (*mongo.InsertOneResult, error) {
+	return s.userCollection.InsertOne(ctx, user)
+}
+
+// Create role-specific data
+func (s *userRepository) CreateRoleData(ctx context.Context, userID primitive.ObjectID, role string) error {
+	switch role {
+	case "founder":
+		_, err := s.founderCollection.InsertOne(ctx, model.Founder{UserID: userID})
+		return err
+	case "investor":
+		_, err := s.investorCollection.InsertOne(ctx, model.Investor{UserID: userID})
+		return err
+	case "admin":
+		_, err := s.adminCollection.InsertOne(ctx, model.Admin{UserID: userID})
+		return err
+	default:
+		return ErrInvalidRole
+	}
+}
+
+func (s *userRepository) GetUserCount(ctx context.Context) (int64, error) {
+	count, err := s.userCollection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,185 @@
+// Package events delivers domain events from the outbox to the subscribers
+// in this process. Repositories publish an event in the outbox together with
+// the write it records. Every API replica runs a Bus, which takes a lease on
+// each due event with a conditional update, hands it to the subscribers of
+// its type and stores which of them handled it. Failed deliveries are retried
+// with exponential backoff without calling the subscribers that succeeded
+// again; once the retries run out the event is marked failed until an admin
+// retries it.
+//
+// Delivery is at least once: a replica that stops after a subscriber handled
+// an event but before that was stored, or that outlives its lease, leaves
+// the event to be delivered again. Subscribers must be idempotent, for
+// instance by keying what they write by the event's ID.
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/database"
+	"DBackend/internal/lease"
+	"DBackend/internal/metrics"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Handler handles an event delivered to a subscriber. It should return soon
+// after ctx is done.
+type Handler func(ctx context.Context, event model.Event) error
+
+type subscriber struct {
+	name   string
+	handle Handler
+}
+
+// Bus delivers the events in the outbox to the subscribers of their type
+type Bus struct {
+	db          database.Service
+	cfg         config.Events
+	owner       string
+	subscribers map[string][]subscriber
+}
+
+// New creates a bus that identifies itself to the other replicas by host
+// name, process ID and a random suffix
+func New(db database.Service, cfg config.Events) *Bus {
+	return &Bus{
+		db:          db,
+		cfg:         cfg,
+		owner:       lease.Owner(),
+		subscribers: map[string][]subscriber{},
+	}
+}
+
+// Owner returns the name the bus takes leases under
+func (b *Bus) Owner() string {
+	return b.owner
+}
+
+// Subscribe adds a subscriber to the events of eventType. The name records
+// that it handled an event, so it must stay the same across releases.
+// Subscribers are added before Run is called.
+func (b *Bus) Subscribe(eventType, name string, handle Handler) error {
+	for _, s := range b.subscribers[eventType] {
+		if s.name == name {
+			return fmt.Errorf("%s already subscribes to %s events", name, eventType)
+		}
+	}
+	b.subscribers[eventType] = append(b.subscribers[eventType], subscriber{name: name, handle: handle})
+	return nil
+}
+
+// On subscribes handle to the events of type E, decoding each event's
+// payload for it. handle is given the event's ID to key its writes by.
+func On[E model.DomainEvent](b *Bus, name string, handle func(ctx context.Context, id primitive.ObjectID, e E) error) error {
+	var zero E
+	return b.Subscribe(zero.EventType(), name, func(ctx context.Context, event model.Event) error {
+		var e E
+		if err := event.Decode(&e); err != nil {
+			return fmt.Errorf("decoding %s event: %w", event.Type, err)
+		}
+		return handle(ctx, event.ID, e)
+	})
+}
+
+// Run delivers the due events every poll interval until ctx is done
+func (b *Bus) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := b.Deliver(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "delivering events failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Deliver hands every event due now to its subscribers, one event at a
+// time, and returns how many events it took. Only failing to reach the
+// outbox is an error; failed deliveries are stored for a retry.
+func (b *Bus) Deliver(ctx context.Context) (int, error) {
+	taken := 0
+	for ctx.Err() == nil {
+		now := time.Now()
+		until := now.Add(b.cfg.Lease)
+		event, err := b.db.Outbox().ClaimEvent(ctx, b.owner, now, until)
+		if err != nil {
+			return taken, fmt.Errorf("claiming event: %w", err)
+		}
+		if event == nil {
+			break
+		}
+		taken++
+		b.deliver(ctx, *event, until)
+	}
+	return taken, nil
+}
+
+// deliver hands a claimed event to the subscribers that have not handled it
+// yet, before the lease runs out, then stores the outcome and releases it
+func (b *Bus) deliver(ctx context.Context, event model.Event, until time.Time) {
+	leaseCtx, cancel := context.WithDeadline(ctx, until)
+	defer cancel()
+
+	handled := map[string]bool{}
+	for _, name := range event.Delivered {
+		handled[name] = true
+	}
+	var failures []string
+	for _, s := range b.subscribers[event.Type] {
+		if handled[s.name] {
+			continue
+		}
+		callCtx, done := context.WithTimeout(leaseCtx, b.cfg.Timeout)
+		// a panic is turned into an error so one subscriber cannot take the API down
+		err := lease.Call(callCtx, func(ctx context.Context) error { return s.handle(ctx, event) })
+		done()
+		if err != nil {
+			metrics.EventDeliveries.WithLabelValues(event.Type, s.name, "failed").Inc()
+			slog.WarnContext(ctx, "event subscriber failed", "event_id", event.ID.Hex(), "event", event.Type, "subscriber", s.name, "attempt", event.Attempt+1, "error", err)
+			failures = append(failures, fmt.Sprintf("%s: %v", s.name, err))
+			continue
+		}
+		metrics.EventDeliveries.WithLabelValues(event.Type, s.name, "delivered").Inc()
+		event.Delivered = append(event.Delivered, s.name)
+	}
+
+	now := time.Now()
+	switch {
+	case len(failures) == 0:
+		event.Status, event.DeliveredAt, event.LastError = model.EventDelivered, now, ""
+	case ctx.Err() != nil:
+		// shutting down: leave the rest due so another replica delivers it now
+		event.LastError = strings.Join(failures, "; ")
+	default:
+		event.Attempt++
+		event.LastError = strings.Join(failures, "; ")
+		if event.Attempt > b.cfg.Retries {
+			event.Status = model.EventFailed
+			metrics.EventsFailed.WithLabelValues(event.Type).Inc()
+			slog.ErrorContext(ctx, "giving up on event", "event_id", event.ID.Hex(), "event", event.Type, "attempts", event.Attempt, "error", event.LastError)
+		} else {
+			event.NextAttempt = now.Add(lease.Backoff(b.cfg.Backoff, event.Attempt))
+		}
+	}
+
+	storeCtx, done := lease.StoreContext(ctx)
+	defer done()
+	err := b.db.Outbox().ReleaseEvent(storeCtx, event, b.owner)
+	if errors.Is(err, database.ErrEventLeaseLost) {
+		slog.WarnContext(ctx, "event lease ran out before delivery was stored; it will be delivered again", "event_id", event.ID.Hex(), "event", event.Type)
+	} else if err != nil {
+		slog.ErrorContext(ctx, "storing event delivery failed", "event_id", event.ID.Hex(), "event", event.Type, "error", err)
+	}
+}
@@ -0,0 +1,231 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/database/memory"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func testConfig() config.Events {
+	return config.Events{
+		Enabled:      true,
+		PollInterval: 10 * time.Millisecond,
+		Lease:        time.Second,
+		Timeout:      100 * time.Millisecond,
+		Retries:      2,
+		Backoff:      20 * time.Millisecond,
+	}
+}
+
+func publish(t *testing.T, store *memory.Store, e model.DomainEvent) {
+	t.Helper()
+	if err := store.Outbox().Publish(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func only(t *testing.T, store *memory.Store) model.Event {
+	t.Helper()
+	list, err := store.Outbox().ListEvents(context.Background(), "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("outbox holds %d events, want 1", len(list))
+	}
+	return list[0]
+}
+
+// eventually polls cond until it holds or a second has passed
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSubscribeRejectsDuplicateNames(t *testing.T) {
+	b := New(memory.New(), testConfig())
+	noop := func(context.Context, model.Event) error { return nil }
+	if err := b.Subscribe(model.EventDealAdded, "timeline", noop); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Subscribe(model.EventDealStageChanged, "timeline", noop); err != nil {
+		t.Errorf("a name was refused for another event type: %v", err)
+	}
+	if err := b.Subscribe(model.EventDealAdded, "timeline", noop); err == nil {
+		t.Error("a subscriber was added twice")
+	}
+}
+
+func TestDeliverHandsEventsToTheirSubscribers(t *testing.T) {
+	store := memory.New()
+	b := New(store, testConfig())
+	deal := primitive.NewObjectID()
+	var got []model.DealStageChanged
+	var ids []primitive.ObjectID
+	err := On(b, "timeline", func(ctx context.Context, id primitive.ObjectID, e model.DealStageChanged) error {
+		got, ids = append(got, e), append(ids, id)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var others atomic.Int32
+	if err := On(b, "timeline", func(context.Context, primitive.ObjectID, model.DealAdded) error { others.Add(1); return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	publish(t, store, model.DealStageChanged{DealID: deal, From: "screening", To: "negotiation"})
+	n, err := b.Deliver(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("Deliver() = %d, %v; want the one event", n, err)
+	}
+	if len(got) != 1 || got[0].DealID != deal || got[0].From != "screening" || got[0].To != "negotiation" {
+		t.Errorf("subscriber got %+v", got)
+	}
+	if others.Load() != 0 {
+		t.Error("a subscriber to another type was called")
+	}
+	event := only(t, store)
+	if ids[0] != event.ID {
+		t.Errorf("subscriber was given ID %v, want the event's %v", ids[0], event.ID)
+	}
+	if event.Status != model.EventDelivered || event.DeliveredAt.IsZero() || event.LeaseOwner != "" || len(event.Delivered) != 1 {
+		t.Errorf("event after delivery = %+v", event)
+	}
+	if n, _ := b.Deliver(context.Background()); n != 0 || len(got) != 1 {
+		t.Errorf("a delivered event was delivered again")
+	}
+}
+
+func TestFailedSubscribersAreRetriedAlone(t *testing.T) {
+	store := memory.New()
+	b := New(store, testConfig())
+	var timeline, notifications atomic.Int32
+	b.Subscribe(model.EventDealAdded, "timeline", func(context.Context, model.Event) error {
+		timeline.Add(1)
+		return nil
+	})
+	b.Subscribe(model.EventDealAdded, "notifications", func(context.Context, model.Event) error {
+		if notifications.Add(1) == 2 {
+			panic("boom")
+		}
+		return errors.New("mail server down")
+	})
+	publish(t, store, model.DealAdded{DealID: primitive.NewObjectID()})
+
+	ctx := context.Background()
+	b.Deliver(ctx)
+	event := only(t, store)
+	if event.Status != model.EventPending || event.Attempt != 1 || event.LastError != "notifications: mail server down" {
+		t.Fatalf("event after a failure = %+v", event)
+	}
+	if wait := event.NextAttempt.Sub(time.Now()); wait <= 0 || wait > 20*time.Millisecond {
+		t.Errorf("retry is due in %v, want within the backoff", wait)
+	}
+	if n, _ := b.Deliver(ctx); n != 0 {
+		t.Error("a retry was delivered before its backoff")
+	}
+
+	// the first attempt and two retries, then the event is given up on
+	eventually(t, "the retries", func() bool {
+		b.Deliver(ctx)
+		return only(t, store).Status == model.EventFailed
+	})
+	event = only(t, store)
+	if notifications.Load() != 3 || timeline.Load() != 1 {
+		t.Errorf("notifications called %d times and timeline %d, want 3 and 1", notifications.Load(), timeline.Load())
+	}
+	if event.Attempt != 3 || len(event.Delivered) != 1 || event.Delivered[0] != "timeline" {
+		t.Errorf("failed event = %+v", event)
+	}
+	if n, _ := b.Deliver(ctx); n != 0 {
+		t.Error("a failed event was delivered without being retried")
+	}
+
+	if err := store.Outbox().RetryEvent(ctx, event.ID, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Outbox().RetryEvent(ctx, event.ID, time.Now()); err == nil {
+		t.Error("a pending event was retried")
+	}
+	b.Deliver(ctx)
+	if event := only(t, store); event.Attempt != 1 || notifications.Load() != 4 || timeline.Load() != 1 {
+		t.Errorf("after a manual retry: event %+v, notifications %d, timeline %d", event, notifications.Load(), timeline.Load())
+	}
+}
+
+func TestEachEventIsTakenByOneReplica(t *testing.T) {
+	store := memory.New()
+	var mu sync.Mutex
+	seen := map[primitive.ObjectID]int{}
+	buses := []*Bus{New(store, testConfig()), New(store, testConfig())}
+	if buses[0].Owner() == buses[1].Owner() {
+		t.Fatal("replicas share an owner name")
+	}
+	for _, b := range buses {
+		On(b, "timeline", func(ctx context.Context, id primitive.ObjectID, e model.MeetingScheduled) error {
+			mu.Lock()
+			defer mu.Unlock()
+			seen[id]++
+			return nil
+		})
+	}
+	for range 20 {
+		publish(t, store, model.MeetingScheduled{MeetingID: primitive.NewObjectID()})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for _, b := range buses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Run(ctx)
+		}()
+	}
+	eventually(t, "every event", func() bool {
+		list, _ := store.Outbox().ListEvents(context.Background(), model.EventDelivered, 100)
+		return len(list) == 20
+	})
+	cancel()
+	wg.Wait()
+
+	for id, n := range seen {
+		if n != 1 {
+			t.Errorf("event %v was handled %d times", id.Hex(), n)
+		}
+	}
+}
+
+func TestShutdownLeavesEventsDue(t *testing.T) {
+	store := memory.New()
+	b := New(store, testConfig())
+	ctx, cancel := context.WithCancel(context.Background())
+	b.Subscribe(model.EventGrantApplicationSubmitted, "notifications", func(ctx context.Context, event model.Event) error {
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	publish(t, store, model.GrantApplicationSubmitted{ApplicationID: primitive.NewObjectID()})
+	b.Deliver(ctx)
+
+	event := only(t, store)
+	if event.Status != model.EventPending || event.Attempt != 0 || event.LeaseOwner != "" || event.NextAttempt.After(time.Now()) {
+		t.Errorf("event after shutdown = %+v, want it due again without using a retry", event)
+	}
+}
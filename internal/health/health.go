@@ -0,0 +1,134 @@
+// Package health runs dependency probes for the liveness and readiness
+// endpoints. Probes never terminate the process; failures are reported as
+// per-dependency status instead.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Status is the state of a dependency or of the service as a whole
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+const defaultTimeout = 2 * time.Second
+
+// Check describes one dependency probe
+type Check struct {
+	Name string
+	// Critical dependencies make the service not ready when down; others only degrade it
+	Critical bool
+	// Timeout bounds the probe; defaults to two seconds
+	Timeout time.Duration
+	// SlowAfter marks a successful probe as degraded when it takes longer
+	SlowAfter time.Duration
+	Probe     func(ctx context.Context) error
+}
+
+// Result is the outcome of one probe
+type Result struct {
+	Name      string  `json:"name"`
+	Status    Status  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the aggregated readiness of the service
+type Report struct {
+	Status    Status    `json:"status"`
+	CheckedAt time.Time `json:"checked_at"`
+	Checks    []Result  `json:"checks"`
+}
+
+// Checker runs a fixed set of checks
+type Checker struct {
+	checks []Check
+}
+
+// NewChecker returns a checker for checks
+func NewChecker(checks ...Check) *Checker {
+	return &Checker{checks: checks}
+}
+
+// Run probes every dependency concurrently and aggregates the results
+func (c *Checker) Run(ctx context.Context) Report {
+	results := make([]Result, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	return Report{Status: Aggregate(results), CheckedAt: time.Now().UTC(), Checks: results}
+}
+
+// Aggregate derives the overall status: down if a critical dependency is
+// down, degraded if anything else is not up
+func Aggregate(results []Result) Status {
+	status := StatusUp
+	for _, r := range results {
+		switch {
+		case r.Status == StatusDown && r.Critical:
+			return StatusDown
+		case r.Status != StatusUp:
+			status = StatusDegraded
+		}
+	}
+	return status
+}
+
+func run(ctx context.Context, check Check) (result Result) {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result = Result{Name: check.Name, Critical: check.Critical}
+	start := time.Now()
+	defer func() { result.LatencyMS = float64(time.Since(start).Microseconds()) / 1000 }()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("probe panicked: %v", r)
+			}
+		}()
+		done <- check.Probe(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			result.Status = StatusDown
+			result.Error = err.Error()
+			return result
+		}
+	case <-ctx.Done():
+		result.Status = StatusDown
+		result.Error = "timed out after " + timeout.String()
+		return result
+	}
+
+	result.Status = StatusUp
+	if check.SlowAfter > 0 && time.Since(start) > check.SlowAfter {
+		result.Status = StatusDegraded
+	}
+	return result
+}
@@ -0,0 +1,50 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunReportsPerDependencyStatus(t *testing.T) {
+	checker := NewChecker(
+		Check{Name: "ok", Critical: true, Probe: func(context.Context) error { return nil }},
+		Check{Name: "failing", Probe: func(context.Context) error { return errors.New("boom") }},
+		Check{Name: "slow", SlowAfter: time.Millisecond, Probe: func(context.Context) error {
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		}},
+		Check{Name: "hung", Timeout: 10 * time.Millisecond, Probe: func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		}},
+		Check{Name: "panics", Probe: func(context.Context) error { panic("nil map") }},
+	)
+
+	report := checker.Run(context.Background())
+
+	want := map[string]Status{
+		"ok": StatusUp, "failing": StatusDown, "slow": StatusDegraded,
+		"hung": StatusDown, "panics": StatusDown,
+	}
+	for _, r := range report.Checks {
+		if r.Status != want[r.Name] {
+			t.Errorf("%s: status = %s, want %s (error %q)", r.Name, r.Status, want[r.Name], r.Error)
+		}
+	}
+	if report.Status != StatusDegraded {
+		t.Errorf("overall status = %s, want degraded when only non-critical checks fail", report.Status)
+	}
+}
+
+func TestCriticalFailureMeansDown(t *testing.T) {
+	results := []Result{
+		{Name: "mongodb", Critical: true, Status: StatusDown},
+		{Name: "ml", Status: StatusUp},
+	}
+	if got := Aggregate(results); got != StatusDown {
+		t.Errorf("Aggregate() = %s, want down", got)
+	}
+}
@@ -0,0 +1,62 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// HTTP probes a service by requesting the root of target's host. Any response
+// below 500 means the service is reachable.
+func HTTP(client *http.Client, target string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		u, err := url.Parse(target)
+		if err != nil {
+			return err
+		}
+		root := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, root.String(), nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 500 {
+			return fmt.Errorf("responded with status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// Files probes that every path exists and is readable
+func Files(paths ...string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		for _, path := range paths {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			f.Close()
+		}
+		return nil
+	}
+}
+
+// WritableDir probes that files can be created in dir
+func WritableDir(dir string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		f, err := os.CreateTemp(dir, ".healthcheck-*")
+		if err != nil {
+			return err
+		}
+		name := f.Name()
+		f.Close()
+		return os.Remove(name)
+	}
+}
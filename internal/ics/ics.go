@@ -0,0 +1,238 @@
+// Package ics renders iCalendar documents (RFC 5545) for meeting invites and
+// calendar feeds. It writes only what the API needs: events with an
+// organizer and attendees, recurring in a timezone or not, and to-dos with a
+// due date, with text escaped and long lines folded.
+package ics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ProdID identifies this API as the producer of the documents it renders
+const ProdID = "-//DBackend//Meetings//EN"
+
+// Calendar is a VCALENDAR object
+type Calendar struct {
+	// Method is REQUEST for an invite and empty for a stored calendar or feed
+	Method string
+	// Name is shown by clients subscribing to a feed
+	Name   string
+	Events []Event
+	Todos  []Todo
+}
+
+// Event is a VEVENT
+type Event struct {
+	UID       string
+	Sequence  int
+	Stamp     time.Time
+	Start     time.Time
+	End       time.Time
+	Summary   string
+	Details   string
+	Location  string
+	URL       string
+	Status    string // TENTATIVE, CONFIRMED or CANCELLED; omitted when empty
+	Organizer *Person
+	Attendees []Person
+	// Timezone is the IANA zone the event's times are written in, with a
+	// VTIMEZONE describing it; UTC when empty
+	Timezone string
+	// RRule is the RRULE value of a recurring event, such as FREQ=WEEKLY
+	RRule string
+	// ExDates are the starts of cancelled occurrences
+	ExDates []time.Time
+	// RecurrenceID is set on an edited occurrence of a recurring event, which
+	// shares its UID, to the start it has in the series
+	RecurrenceID time.Time
+}
+
+// Todo is a VTODO
+type Todo struct {
+	UID      string
+	Stamp    time.Time
+	Due      time.Time
+	Summary  string
+	Details  string
+	Status   string // NEEDS-ACTION, COMPLETED, IN-PROCESS or CANCELLED; omitted when empty
+	Priority int    // 1 is the highest and 9 the lowest; 0 leaves it undefined
+}
+
+// Person is an organizer or attendee, addressed by email
+type Person struct {
+	Name  string
+	Email string
+}
+
+// Bytes renders the calendar
+func (c Calendar) Bytes() []byte {
+	var buf bytes.Buffer
+	c.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+// WriteTo renders the calendar to w
+func (c Calendar) WriteTo(w io.Writer) (int64, error) {
+	l := &lineWriter{w: w}
+	l.line("BEGIN:VCALENDAR")
+	l.line("VERSION:2.0")
+	l.line("PRODID:" + ProdID)
+	l.line("CALSCALE:GREGORIAN")
+	if c.Method != "" {
+		l.line("METHOD:" + c.Method)
+	}
+	if c.Name != "" {
+		l.line("X-WR-CALNAME:" + Escape(c.Name))
+	}
+	for _, tz := range c.timezones() {
+		tz.write(l)
+	}
+	for _, e := range c.Events {
+		e.write(l)
+	}
+	for _, t := range c.Todos {
+		t.write(l)
+	}
+	l.line("END:VCALENDAR")
+	return l.n, l.err
+}
+
+func (e Event) write(l *lineWriter) {
+	l.line("BEGIN:VEVENT")
+	l.line("UID:" + Escape(e.UID))
+	l.line(fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+	l.line("DTSTAMP:" + FormatTime(e.Stamp))
+	if !e.RecurrenceID.IsZero() {
+		l.line(e.dateTime("RECURRENCE-ID", e.RecurrenceID))
+	}
+	l.line(e.dateTime("DTSTART", e.Start))
+	l.line(e.dateTime("DTEND", e.End))
+	for _, line := range RecurrenceLines(e) {
+		l.line(line)
+	}
+	l.line("SUMMARY:" + Escape(e.Summary))
+	if e.Details != "" {
+		l.line("DESCRIPTION:" + Escape(e.Details))
+	}
+	if e.Location != "" {
+		l.line("LOCATION:" + Escape(e.Location))
+	}
+	if e.URL != "" {
+		l.line("URL:" + e.URL)
+	}
+	if e.Status != "" {
+		l.line("STATUS:" + e.Status)
+	}
+	if e.Organizer != nil {
+		l.line("ORGANIZER" + e.Organizer.params() + ":mailto:" + e.Organizer.Email)
+	}
+	for _, a := range e.Attendees {
+		l.line("ATTENDEE" + a.params() + ";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:" + a.Email)
+	}
+	l.line("END:VEVENT")
+}
+
+// RecurrenceLines returns the RRULE and EXDATE properties of a recurring
+// event, unfolded, as APIs that take them on their own expect
+func RecurrenceLines(e Event) []string {
+	if e.RRule == "" {
+		return nil
+	}
+	lines := []string{"RRULE:" + e.RRule}
+	for _, ex := range e.ExDates {
+		lines = append(lines, e.dateTime("EXDATE", ex))
+	}
+	return lines
+}
+
+// dateTime writes the property as a local time in the event's timezone, or
+// in UTC when it has none
+func (e Event) dateTime(name string, t time.Time) string {
+	if loc := location(e.Timezone); loc != nil {
+		return name + ";TZID=" + e.Timezone + ":" + t.In(loc).Format("20060102T150405")
+	}
+	return name + ":" + FormatTime(t)
+}
+
+func (t Todo) write(l *lineWriter) {
+	l.line("BEGIN:VTODO")
+	l.line("UID:" + Escape(t.UID))
+	l.line("DTSTAMP:" + FormatTime(t.Stamp))
+	l.line("DUE:" + FormatTime(t.Due))
+	l.line("SUMMARY:" + Escape(t.Summary))
+	if t.Details != "" {
+		l.line("DESCRIPTION:" + Escape(t.Details))
+	}
+	if t.Status != "" {
+		l.line("STATUS:" + t.Status)
+	}
+	if t.Priority > 0 {
+		l.line(fmt.Sprintf("PRIORITY:%d", t.Priority))
+	}
+	l.line("END:VTODO")
+}
+
+func (p Person) params() string {
+	if p.Name == "" {
+		return ""
+	}
+	// parameter values cannot contain quotes or control characters
+	name := strings.Map(func(r rune) rune {
+		if r == '"' || r < ' ' {
+			return -1
+		}
+		return r
+	}, p.Name)
+	return `;CN="` + name + `"`
+}
+
+// FormatTime formats t as a UTC DATE-TIME
+func FormatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// Escape escapes a TEXT value
+func Escape(s string) string {
+	return textEscaper.Replace(s)
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// maxLine is the longest content line allowed, in octets, before folding
+const maxLine = 75
+
+// lineWriter writes content lines ended by CRLF, folding long lines onto
+// continuation lines that start with a space without splitting characters
+type lineWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (l *lineWriter) line(s string) {
+	limit := maxLine
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		l.write(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = maxLine - 1 // the leading space counts
+	}
+	l.write(s + "\r\n")
+}
+
+func (l *lineWriter) write(s string) {
+	if l.err != nil {
+		return
+	}
+	n, err := io.WriteString(l.w, s)
+	l.n += int64(n)
+	l.err = err
+}
@@ -0,0 +1,113 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendarRendersInvite(t *testing.T) {
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+	cal := Calendar{Method: "REQUEST", Events: []Event{{
+		UID:       "abc@dbackend",
+		Stamp:     start,
+		Start:     start,
+		End:       start.Add(time.Hour),
+		Summary:   "Intro; seed, round",
+		Details:   "Agenda:\nTeam\\traction",
+		URL:       "https://meet.example.com/room",
+		Organizer: &Person{Name: `Ada "The Countess" Lovelace`, Email: "ada@example.com"},
+		Attendees: []Person{{Email: "grace@example.com"}},
+	}}}
+	got := string(cal.Bytes())
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"METHOD:REQUEST\r\n",
+		"DTSTART:20250301T080000Z\r\n",
+		"DTEND:20250301T090000Z\r\n",
+		`SUMMARY:Intro\; seed\, round` + "\r\n",
+		`DESCRIPTION:Agenda:\nTeam\\traction` + "\r\n",
+		`ORGANIZER;CN="Ada The Countess Lovelace":mailto:ada@example.com` + "\r\n",
+		"ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:grace@\r\n example.com\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("calendar does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestLongLinesFoldWithoutSplittingCharacters(t *testing.T) {
+	cal := Calendar{Events: []Event{{Summary: strings.Repeat("é", 100)}}}
+	for _, line := range strings.Split(strings.TrimSuffix(string(cal.Bytes()), "\r\n"), "\r\n") {
+		if len(line) > maxLine {
+			t.Errorf("line of %d octets: %q", len(line), line)
+		}
+		if strings.ContainsRune(line, '\uFFFD') {
+			t.Errorf("line splits a character: %q", line)
+		}
+	}
+	if got := string(cal.Bytes()); !strings.Contains(strings.ReplaceAll(got, "\r\n ", ""), "SUMMARY:"+strings.Repeat("é", 100)) {
+		t.Errorf("unfolded summary was changed:\n%s", got)
+	}
+}
+
+func TestCalendarRendersTodos(t *testing.T) {
+	due := time.Date(2025, 3, 7, 17, 0, 0, 0, time.UTC)
+	cal := Calendar{Name: "Ada's deals", Todos: []Todo{
+		{UID: "t1@dbackend", Stamp: due, Due: due, Summary: "Send term sheet", Status: "NEEDS-ACTION", Priority: 1},
+		{UID: "t2@dbackend", Stamp: due, Due: due, Summary: "Call references"},
+	}}
+	got := string(cal.Bytes())
+
+	for _, want := range []string{
+		"X-WR-CALNAME:Ada's deals\r\n",
+		"BEGIN:VTODO\r\nUID:t1@dbackend\r\nDTSTAMP:20250307T170000Z\r\nDUE:20250307T170000Z\r\nSUMMARY:Send term sheet\r\nSTATUS:NEEDS-ACTION\r\nPRIORITY:1\r\nEND:VTODO\r\n",
+		"SUMMARY:Call references\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("calendar does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestCalendarRendersRecurringEventInTimezone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, berlin)
+	moved := time.Date(2026, 4, 6, 9, 0, 0, 0, berlin)
+	cal := Calendar{Events: []Event{
+		{UID: "board@dbackend", Start: start, End: start.Add(time.Hour), Summary: "Board meeting", Timezone: "Europe/Berlin",
+			RRule: "FREQ=MONTHLY;BYDAY=1MO", ExDates: []time.Time{time.Date(2026, 5, 4, 9, 0, 0, 0, berlin)}},
+		{UID: "board@dbackend", RecurrenceID: moved, Start: moved.Add(2 * time.Hour), End: moved.Add(3 * time.Hour), Summary: "Board meeting", Timezone: "Europe/Berlin"},
+	}}
+	got := string(cal.Bytes())
+
+	for _, want := range []string{
+		"BEGIN:VTIMEZONE\r\nTZID:Europe/Berlin\r\n",
+		// the change to summer time on 29 March 2026, at 02:00 local time
+		"BEGIN:DAYLIGHT\r\nDTSTART:20260329T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\nTZNAME:CEST\r\nEND:DAYLIGHT\r\n",
+		"DTSTART;TZID=Europe/Berlin:20260302T090000\r\n",
+		"RRULE:FREQ=MONTHLY;BYDAY=1MO\r\n",
+		"EXDATE;TZID=Europe/Berlin:20260504T090000\r\n",
+		"RECURRENCE-ID;TZID=Europe/Berlin:20260406T090000\r\nDTSTART;TZID=Europe/Berlin:20260406T110000\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("calendar does not contain %q:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "BEGIN:VTIMEZONE"); n != 1 {
+		t.Errorf("%d VTIMEZONEs, want one shared by both events", n)
+	}
+}
+
+func TestFormatOffset(t *testing.T) {
+	for seconds, want := range map[int]string{0: "+0000", 19800: "+0530", -12600: "-0330", -1050: "-001730"} {
+		if got := formatOffset(seconds); got != want {
+			t.Errorf("formatOffset(%d) = %q, want %q", seconds, got, want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package ics
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// recurringSpan is how far past its first start a recurring event's
+// VTIMEZONE reaches. Clients that know the zone by its IANA name use their
+// own rules beyond it; the rest repeat the last observance.
+const recurringSpan = 5 * 365 * 24 * time.Hour
+
+// timezone is a VTIMEZONE covering the events that use it
+type timezone struct {
+	name     string
+	loc      *time.Location
+	from, to time.Time
+}
+
+// location loads an event's timezone; nil for UTC or an unknown zone, whose
+// times are written in UTC instead
+func location(name string) *time.Location {
+	if name == "" || name == "UTC" {
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil
+	}
+	return loc
+}
+
+// timezones returns a VTIMEZONE per zone the events use, spanning their times
+func (c Calendar) timezones() []timezone {
+	zones := map[string]*timezone{}
+	for _, e := range c.Events {
+		loc := location(e.Timezone)
+		if loc == nil {
+			continue
+		}
+		from, to := e.Start, e.End
+		if !e.RecurrenceID.IsZero() && e.RecurrenceID.Before(from) {
+			from = e.RecurrenceID
+		}
+		if e.RRule != "" {
+			to = from.Add(recurringSpan)
+		}
+		tz, ok := zones[e.Timezone]
+		if !ok {
+			zones[e.Timezone] = &timezone{name: e.Timezone, loc: loc, from: from, to: to}
+			continue
+		}
+		if from.Before(tz.from) {
+			tz.from = from
+		}
+		if to.After(tz.to) {
+			tz.to = to
+		}
+	}
+	out := make([]timezone, 0, len(zones))
+	for _, tz := range zones {
+		out = append(out, *tz)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
+
+// write renders the observance in effect at from and one per offset change
+// until to, from Go's zone data
+func (tz timezone) write(l *lineWriter) {
+	l.line("BEGIN:VTIMEZONE")
+	l.line("TZID:" + tz.name)
+	t := tz.from.In(tz.loc)
+	start, end := t.ZoneBounds()
+	observance(l, t, start)
+	for !end.IsZero() && !end.After(tz.to) {
+		t = end
+		_, end = t.ZoneBounds()
+		observance(l, t, t)
+	}
+	l.line("END:VTIMEZONE")
+}
+
+// observance writes the STANDARD or DAYLIGHT component in effect at t, which
+// began at start; a zero start means the offset has always applied
+func observance(l *lineWriter, t, start time.Time) {
+	name, offset := t.Zone()
+	kind := "STANDARD"
+	if t.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	from, dtstart := offset, "19700101T000000"
+	if !start.IsZero() {
+		_, from = start.Add(-time.Second).Zone()
+		// DTSTART is the local time the change happens at, before it
+		dtstart = start.UTC().Add(time.Duration(from) * time.Second).Format("20060102T150405")
+	}
+	l.line("BEGIN:" + kind)
+	l.line("DTSTART:" + dtstart)
+	l.line("TZOFFSETFROM:" + formatOffset(from))
+	l.line("TZOFFSETTO:" + formatOffset(offset))
+	l.line("TZNAME:" + Escape(name))
+	l.line("END:" + kind)
+}
+
+// formatOffset formats seconds east of UTC as a UTC-OFFSET, e.g. +0530
+func formatOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign, seconds = '-', -seconds
+	}
+	s := fmt.Sprintf("%c%02d%02d", sign, seconds/3600, seconds/60%60)
+	if seconds%60 != 0 {
+		s += fmt.Sprintf("%02d", seconds%60)
+	}
+	return s
+}
@@ -0,0 +1,270 @@
+// Package jobs runs background jobs on cron schedules. Every API replica runs
+// a Scheduler, and the jobs collection decides which one runs each job: a
+// replica runs a due job only after taking its lease with a conditional
+// update, renews the lease while the job runs and gives it up when the run
+// ends. A replica that dies mid-run stops renewing, so once the lease lapses
+// another replica picks the job up again. Failed runs are retried with
+// exponential backoff, and every attempt is kept in the run history.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/cron"
+	"DBackend/internal/database"
+	"DBackend/internal/lease"
+	"DBackend/internal/metrics"
+	"DBackend/model"
+)
+
+// Run statuses
+const (
+	StatusSucceeded   = "succeeded"
+	StatusFailed      = "failed"
+	StatusInterrupted = "interrupted" // the replica shut down or lost the lease mid-run
+)
+
+// errLeaseLost cancels a run whose lease another replica took over
+var errLeaseLost = errors.New("lease lost")
+
+// Func is the work of a job. It should return soon after ctx is done.
+type Func func(ctx context.Context) error
+
+type job struct {
+	name     string
+	schedule cron.Schedule
+	run      Func
+	stored   bool // registered in the jobs collection
+}
+
+// Scheduler runs the registered jobs when they are due and this replica
+// holds their lease
+type Scheduler struct {
+	db    database.Service
+	cfg   config.Jobs
+	owner string
+	jobs  []*job
+
+	mu      sync.Mutex
+	running map[string]bool
+	wg      sync.WaitGroup
+}
+
+// New creates a scheduler that identifies itself to the other replicas by
+// host name, process ID and a random suffix
+func New(db database.Service, cfg config.Jobs) *Scheduler {
+	return &Scheduler{
+		db:      db,
+		cfg:     cfg,
+		owner:   lease.Owner(),
+		running: map[string]bool{},
+	}
+}
+
+// Owner returns the name the scheduler takes leases under
+func (s *Scheduler) Owner() string {
+	return s.owner
+}
+
+// Register adds a job running run on the cron schedule spec. Jobs are
+// registered before Run is called.
+func (s *Scheduler) Register(name, spec string, run Func) error {
+	schedule, err := cron.Parse(spec)
+	if err != nil {
+		return err
+	}
+	if schedule.Next(time.Now()).IsZero() {
+		return fmt.Errorf("job %s: schedule %q never fires", name, spec)
+	}
+	for _, j := range s.jobs {
+		if j.name == name {
+			return fmt.Errorf("job %s is already registered", name)
+		}
+	}
+	s.jobs = append(s.jobs, &job{name: name, schedule: schedule, run: run})
+	return nil
+}
+
+// Run looks for due jobs every poll interval until ctx is done, then waits
+// for the runs in progress, which see ctx done too, to record how they ended
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		s.poll(ctx)
+		select {
+		case <-ctx.Done():
+			s.wg.Wait()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll registers the jobs not stored yet and starts the due ones it can
+// take the lease on
+func (s *Scheduler) poll(ctx context.Context) {
+	for _, j := range s.jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		now := time.Now()
+		if !j.stored {
+			if err := s.db.Jobs().RegisterJob(ctx, j.name, j.schedule.String(), j.schedule.Next(now)); err != nil {
+				slog.ErrorContext(ctx, "registering job failed", "job", j.name, "error", err)
+				continue
+			}
+			j.stored = true
+		}
+		if s.isRunning(j.name) {
+			continue
+		}
+		state, claimed, err := s.db.Jobs().ClaimJob(ctx, j.name, s.owner, now, now.Add(s.cfg.Lease))
+		if err != nil {
+			slog.ErrorContext(ctx, "claiming job failed", "job", j.name, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		s.setRunning(j.name, true)
+		s.wg.Add(1)
+		go s.execute(ctx, j, *state)
+	}
+}
+
+// execute runs a claimed job, then records the run and releases the lease
+// with when the job is next due
+func (s *Scheduler) execute(ctx context.Context, j *job, state model.Job) {
+	defer s.wg.Done()
+	defer s.setRunning(j.name, false)
+
+	started := time.Now()
+	runCtx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	lost := make(chan bool, 1)
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		lost <- s.renew(runCtx, j.name, cancel)
+	}()
+
+	// a panic is turned into an error so one job cannot take the API down
+	err := lease.Call(runCtx, j.run)
+	cancel()
+	<-renewed
+	finished := time.Now()
+
+	run := model.JobRun{
+		Job:          j.name,
+		Owner:        s.owner,
+		Attempt:      state.Attempt + 1,
+		ScheduledFor: state.NextRun,
+		StartedAt:    started,
+		FinishedAt:   finished,
+	}
+	leaseLost := <-lost
+	switch {
+	case leaseLost:
+		run.Status, run.Error = StatusInterrupted, errLeaseLost.Error()
+	case err == nil:
+		run.Status = StatusSucceeded
+		state.Attempt = 0
+		state.NextRun = j.schedule.Next(finished)
+	case ctx.Err() != nil:
+		// shutting down: leave the job due so another replica runs it now
+		run.Status, run.Error = StatusInterrupted, err.Error()
+	default:
+		run.Status, run.Error = StatusFailed, err.Error()
+		state.Attempt, state.NextRun = s.retry(j, state.Attempt+1, finished)
+	}
+	state.LastRun, state.LastStatus, state.LastError = started, run.Status, run.Error
+
+	metrics.JobRuns.WithLabelValues(j.name, run.Status).Inc()
+	metrics.JobDuration.WithLabelValues(j.name).Observe(finished.Sub(started).Seconds())
+	logRun(ctx, run)
+
+	storeCtx, done := lease.StoreContext(ctx)
+	defer done()
+	if err := s.db.Jobs().RecordJobRun(storeCtx, run); err != nil {
+		slog.ErrorContext(ctx, "recording job run failed", "job", j.name, "error", err)
+	}
+	if leaseLost {
+		return
+	}
+	if err := s.db.Jobs().ReleaseJob(storeCtx, state, s.owner); err != nil {
+		slog.ErrorContext(ctx, "releasing job failed", "job", j.name, "error", err)
+	}
+}
+
+// retry returns the failed attempts so far and when the job runs next: after
+// the backoff while retries are left, at its next scheduled time once they
+// run out, or sooner if the schedule comes round first
+func (s *Scheduler) retry(j *job, attempt int, now time.Time) (int, time.Time) {
+	next := j.schedule.Next(now)
+	if attempt > s.cfg.Retries {
+		return 0, next
+	}
+	if retry := now.Add(lease.Backoff(s.cfg.Backoff, attempt)); retry.Before(next) {
+		next = retry
+	}
+	return attempt, next
+}
+
+// renew extends the lease every third of its length until ctx is done. It
+// cancels the run and returns true once another replica holds the lease.
+func (s *Scheduler) renew(ctx context.Context, name string, cancel context.CancelFunc) bool {
+	ticker := time.NewTicker(s.cfg.Lease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+		held, err := s.db.Jobs().RenewLease(ctx, name, s.owner, time.Now().Add(s.cfg.Lease))
+		if err != nil {
+			// the lease still has two thirds to run; try again next tick
+			slog.WarnContext(ctx, "renewing job lease failed", "job", name, "error", err)
+			continue
+		}
+		if !held {
+			cancel()
+			return true
+		}
+	}
+}
+
+func (s *Scheduler) isRunning(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running[name]
+}
+
+func (s *Scheduler) setRunning(name string, running bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if running {
+		s.running[name] = true
+	} else {
+		delete(s.running, name)
+	}
+}
+
+func logRun(ctx context.Context, run model.JobRun) {
+	attrs := []any{"job", run.Job, "attempt", run.Attempt, "duration", run.FinishedAt.Sub(run.StartedAt)}
+	switch run.Status {
+	case StatusSucceeded:
+		slog.InfoContext(ctx, "job succeeded", attrs...)
+	case StatusFailed:
+		slog.ErrorContext(ctx, "job failed", append(attrs, "error", run.Error)...)
+	default:
+		slog.WarnContext(ctx, "job interrupted", append(attrs, "error", run.Error)...)
+	}
+}
@@ -0,0 +1,282 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/database/memory"
+	"DBackend/model"
+)
+
+func testConfig() config.Jobs {
+	return config.Jobs{
+		Enabled:      true,
+		PollInterval: 10 * time.Millisecond,
+		Lease:        300 * time.Millisecond,
+		Timeout:      time.Second,
+		Retries:      2,
+		Backoff:      20 * time.Millisecond,
+	}
+}
+
+// start runs the schedulers until the returned stop is called
+func start(t *testing.T, schedulers ...*Scheduler) (stop func()) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for _, s := range schedulers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Run(ctx)
+		}()
+	}
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			cancel()
+			wg.Wait()
+		})
+	}
+	t.Cleanup(stop)
+	return stop
+}
+
+// eventually polls cond until it holds or a second has passed
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func runs(t *testing.T, store *memory.Store, name string) []model.JobRun {
+	t.Helper()
+	list, err := store.Jobs().GetJobRuns(context.Background(), name, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return list
+}
+
+func jobState(t *testing.T, store *memory.Store, name string) model.Job {
+	t.Helper()
+	list, err := store.Jobs().ListJobs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, j := range list {
+		if j.Name == name {
+			return j
+		}
+	}
+	t.Fatalf("job %s is not registered", name)
+	return model.Job{}
+}
+
+func TestRegisterRejectsBadSchedules(t *testing.T) {
+	s := New(memory.New(), testConfig())
+	noop := func(context.Context) error { return nil }
+	if err := s.Register("a", "every minute", noop); err == nil {
+		t.Error("an invalid expression was accepted")
+	}
+	if err := s.Register("a", "0 0 30 2 *", noop); err == nil {
+		t.Error("a schedule that never fires was accepted")
+	}
+	if err := s.Register("a", "@hourly", noop); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Register("a", "@daily", noop); err == nil {
+		t.Error("a job was registered twice")
+	}
+}
+
+func TestOneReplicaRunsEachJob(t *testing.T) {
+	store := memory.New()
+	var calls, concurrent, overlap atomic.Int32
+	work := func(ctx context.Context) error {
+		if concurrent.Add(1) > 1 {
+			overlap.Store(1)
+		}
+		defer concurrent.Add(-1)
+		calls.Add(1)
+		time.Sleep(30 * time.Millisecond)
+		return nil
+	}
+
+	a, b := New(store, testConfig()), New(store, testConfig())
+	if a.Owner() == b.Owner() {
+		t.Fatal("replicas share an owner name")
+	}
+	for _, s := range []*Scheduler{a, b} {
+		if err := s.Register("matching", "@daily", work); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start(t, a, b)
+	eventually(t, "registration", func() bool {
+		jobs, _ := store.Jobs().ListJobs(context.Background())
+		return len(jobs) == 1
+	})
+	if calls.Load() != 0 {
+		t.Fatal("a job ran before it was due")
+	}
+
+	if err := store.Jobs().TriggerJob(context.Background(), "matching", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the run", func() bool { return len(runs(t, store, "matching")) == 1 })
+	time.Sleep(50 * time.Millisecond)
+
+	if calls.Load() != 1 || overlap.Load() != 0 {
+		t.Errorf("the job ran %d times (overlapping: %v), want once", calls.Load(), overlap.Load() == 1)
+	}
+	run := runs(t, store, "matching")[0]
+	if run.Status != StatusSucceeded || run.Attempt != 1 {
+		t.Errorf("run = %+v", run)
+	}
+	job := jobState(t, store, "matching")
+	if job.LeaseOwner != "" || job.LastStatus != StatusSucceeded || job.NextRun.Before(time.Now()) {
+		t.Errorf("job after the run = %+v", job)
+	}
+	if job.NextRun.Hour() != 0 || job.NextRun.Minute() != 0 {
+		t.Errorf("next run %v is off the daily schedule", job.NextRun)
+	}
+}
+
+func TestFailedRunsAreRetriedWithBackoff(t *testing.T) {
+	store := memory.New()
+	var calls atomic.Int32
+	s := New(store, testConfig())
+	if err := s.Register("valuations", "@daily", func(ctx context.Context) error {
+		if calls.Add(1) == 2 {
+			panic("boom")
+		}
+		return errors.New("database unavailable")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	start(t, s)
+	eventually(t, "registration", func() bool {
+		jobs, _ := store.Jobs().ListJobs(context.Background())
+		return len(jobs) == 1
+	})
+	if err := store.Jobs().TriggerJob(context.Background(), "valuations", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first attempt and two retries, then the job waits for its schedule
+	eventually(t, "the retries", func() bool { return len(runs(t, store, "valuations")) == 3 })
+	time.Sleep(100 * time.Millisecond)
+	history := runs(t, store, "valuations")
+	if len(history) != 3 {
+		t.Fatalf("ran %d times, want 3", len(history))
+	}
+	for i, run := range history {
+		if want := 3 - i; run.Attempt != want || run.Status != StatusFailed {
+			t.Errorf("run %d = attempt %d %s, want attempt %d failed", i, run.Attempt, run.Status, want)
+		}
+	}
+	if history[1].Error != "panic: boom" {
+		t.Errorf("panicking attempt recorded %q", history[1].Error)
+	}
+	if gap := history[0].StartedAt.Sub(history[1].FinishedAt); gap < 40*time.Millisecond {
+		t.Errorf("second retry came %v after the first, want the doubled backoff", gap)
+	}
+
+	job := jobState(t, store, "valuations")
+	if job.Attempt != 0 || job.LastStatus != StatusFailed || job.LastError != "database unavailable" {
+		t.Errorf("job after the retries = %+v", job)
+	}
+	if time.Until(job.NextRun) < time.Minute {
+		t.Errorf("job is due again at %v, want its next scheduled run", job.NextRun)
+	}
+}
+
+func TestShutdownInterruptsRunsAndReleasesLeases(t *testing.T) {
+	store := memory.New()
+	started := make(chan struct{})
+	s := New(store, testConfig())
+	if err := s.Register("reminders", "* * * * *", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}); err != nil {
+		t.Fatal(err)
+	}
+	stop := start(t, s)
+	eventually(t, "registration", func() bool {
+		jobs, _ := store.Jobs().ListJobs(context.Background())
+		return len(jobs) == 1
+	})
+	due := time.Now()
+	if err := store.Jobs().TriggerJob(context.Background(), "reminders", due); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	stop()
+
+	history := runs(t, store, "reminders")
+	if len(history) != 1 || history[0].Status != StatusInterrupted {
+		t.Fatalf("runs = %+v, want one interrupted", history)
+	}
+	job := jobState(t, store, "reminders")
+	if job.LeaseOwner != "" || !job.LeaseUntil.IsZero() {
+		t.Errorf("lease still held: %+v", job)
+	}
+	if job.NextRun.After(due) {
+		t.Errorf("interrupted job moved to %v; another replica should run it at once", job.NextRun)
+	}
+
+	// another replica takes it straight away
+	_, claimed, err := store.Jobs().ClaimJob(context.Background(), "reminders", "other", time.Now(), time.Now().Add(time.Minute))
+	if err != nil || !claimed {
+		t.Errorf("claim after shutdown = %v, %v", claimed, err)
+	}
+}
+
+func TestLostLeaseCancelsTheRun(t *testing.T) {
+	store := memory.New()
+	started := make(chan struct{})
+	cfg := testConfig()
+	cfg.Lease = 60 * time.Millisecond
+	s := New(store, cfg)
+	if err := s.Register("deadlines", "@hourly", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}); err != nil {
+		t.Fatal(err)
+	}
+	start(t, s)
+	eventually(t, "registration", func() bool {
+		jobs, _ := store.Jobs().ListJobs(context.Background())
+		return len(jobs) == 1
+	})
+	if err := store.Jobs().TriggerJob(context.Background(), "deadlines", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	// another replica takes over, as if this one had stalled past its lease
+	now := time.Now().Add(cfg.Lease)
+	if _, claimed, err := store.Jobs().ClaimJob(context.Background(), "deadlines", "other", now, now.Add(time.Hour)); err != nil || !claimed {
+		t.Fatalf("takeover = %v, %v", claimed, err)
+	}
+	eventually(t, "the run to stop", func() bool { return len(runs(t, store, "deadlines")) == 1 })
+	if run := runs(t, store, "deadlines")[0]; run.Status != StatusInterrupted || run.Error != "lease lost" {
+		t.Errorf("run = %+v", run)
+	}
+	if job := jobState(t, store, "deadlines"); job.LeaseOwner != "other" {
+		t.Errorf("the replica that lost the lease released it: %+v", job)
+	}
+}
@@ -0,0 +1,57 @@
+// Package lease holds what the job scheduler and the event bus share to run
+// work that replicas take turns on under a lease: the name a replica takes
+// leases under, the backoff before a retry, and running the work so that a
+// panic cannot take the API down.
+package lease
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+)
+
+// MaxBackoff caps the wait before a retry
+const MaxBackoff = time.Hour
+
+// StoreTimeout bounds recording the outcome of work, which still happens
+// while the replica shuts down
+const StoreTimeout = 10 * time.Second
+
+// Owner returns a name for this process to take leases under: its host name
+// and process ID, and a random suffix that tells apart processes sharing
+// both, such as containers that all run as PID 1
+func Owner() string {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%s:%d:%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// Backoff returns the wait before retrying after the given failed attempt:
+// base, doubled for every attempt after the first, up to MaxBackoff
+func Backoff(base time.Duration, attempt int) time.Duration {
+	backoff := base << (attempt - 1)
+	if backoff <= 0 || backoff > MaxBackoff {
+		backoff = MaxBackoff
+	}
+	return backoff
+}
+
+// StoreContext returns the context to record the outcome of work done under
+// ctx in. It is not cancelled with ctx, but ends after StoreTimeout.
+func StoreContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), StoreTimeout)
+}
+
+// Call runs fn, turning a panic into an error
+func Call(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
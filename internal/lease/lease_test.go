@@ -0,0 +1,33 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 4: 8 * time.Minute, 7: MaxBackoff, 64: MaxBackoff} {
+		if got := Backoff(time.Minute, attempt); got != want {
+			t.Errorf("Backoff(1m, %d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestCallRecoversPanics(t *testing.T) {
+	err := Call(context.Background(), func(context.Context) error { panic("boom") })
+	if err == nil || err.Error() != "panic: boom" {
+		t.Errorf("Call() = %v, want the panic as an error", err)
+	}
+	failed := errors.New("failed")
+	if err := Call(context.Background(), func(context.Context) error { return failed }); err != failed {
+		t.Errorf("Call() = %v, want %v", err, failed)
+	}
+}
+
+func TestOwnersDiffer(t *testing.T) {
+	if a, b := Owner(), Owner(); a == b {
+		t.Errorf("Owner() returned %q twice", a)
+	}
+}
@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"DBackend/internal/config"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func record(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log line %q is not JSON: %v", buf, err)
+	}
+	buf.Reset()
+	return entry
+}
+
+func TestLoggerAddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(config.Log{Level: "info", Format: "json"}, &buf)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	logger.With("component", "test").InfoContext(ctx, "hello")
+	if entry := record(t, &buf); entry["request_id"] != "req-1" || entry["component"] != "test" {
+		t.Errorf("entry = %v, want request_id req-1 and component", entry)
+	}
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1}, SpanID: trace.SpanID{2}, TraceFlags: trace.FlagsSampled,
+	})
+	logger.InfoContext(trace.ContextWithSpanContext(ctx, sc), "traced")
+	if entry := record(t, &buf); entry["trace_id"] != sc.TraceID().String() || entry["span_id"] != sc.SpanID().String() {
+		t.Errorf("entry = %v, want trace_id and span_id", entry)
+	}
+
+	logger.Info("no context")
+	if entry := record(t, &buf); entry["request_id"] != nil || entry["trace_id"] != nil {
+		t.Errorf("entry = %v, want no request_id or trace", entry)
+	}
+}
+
+func TestLoggerRedactsCredentials(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(config.Log{Level: "info", Format: "json"}, &buf)
+
+	logger.Info("login",
+		"email", "ada@example.com",
+		"password", "hunter2",
+		slog.Group("headers", "Authorization", "Bearer abc", "X-Refresh-Token", "xyz"),
+		"note", "Bearer def",
+	)
+	entry := record(t, &buf)
+	headers, _ := entry["headers"].(map[string]interface{})
+	if entry["email"] != "ada@example.com" || entry["password"] != Redacted || entry["note"] != Redacted ||
+		headers["Authorization"] != Redacted || headers["X-Refresh-Token"] != Redacted {
+		t.Errorf("entry = %v, want credentials redacted", entry)
+	}
+}
+
+func TestLoggerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(config.Log{Level: "warn", Format: "text"}, &buf)
+	logger.Info("hidden")
+	if buf.Len() != 0 {
+		t.Errorf("info was logged at warn level: %q", buf.String())
+	}
+	logger.Warn("shown")
+	if !bytes.Contains(buf.Bytes(), []byte("msg=shown")) {
+		t.Errorf("text output = %q, want msg=shown", buf.String())
+	}
+}
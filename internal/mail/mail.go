@@ -0,0 +1,107 @@
+// Package mail sends plain-text email through an SMTP server. Without a
+// configured server messages are only logged, so development setups need no
+// mail relay.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"DBackend/internal/config"
+)
+
+// Message is a plain-text email to one recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers messages
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns an SMTP sender for cfg, or one that logs messages when no SMTP
+// host is configured
+func New(cfg config.Mail) Sender {
+	if cfg.SMTPHost == "" {
+		return logSender{}
+	}
+	return &smtpSender{cfg: cfg, send: smtp.SendMail}
+}
+
+type logSender struct{}
+
+func (logSender) Send(ctx context.Context, msg Message) error {
+	slog.InfoContext(ctx, "email not sent, no SMTP host configured", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
+
+type smtpSender struct {
+	cfg config.Mail
+	// send is smtp.SendMail, replaced in tests
+	send func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func (s *smtpSender) Send(ctx context.Context, msg Message) error {
+	from, err := envelope(s.cfg.From)
+	if err != nil {
+		return err
+	}
+	to, err := envelope(msg.To)
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.SMTPHost)
+	}
+	addr := net.JoinHostPort(s.cfg.SMTPHost, strconv.Itoa(s.cfg.SMTPPort))
+	if err := s.send(addr, auth, from, []string{to}, render(s.cfg.From, msg, time.Now())); err != nil {
+		return fmt.Errorf("mail: sending to %s: %w", to, err)
+	}
+	return nil
+}
+
+// envelope returns the bare address of an address that may carry a name
+func envelope(address string) (string, error) {
+	addr, err := netmail.ParseAddress(address)
+	if err != nil {
+		return "", fmt.Errorf("mail: %q is not an email address: %w", address, err)
+	}
+	return addr.Address, nil
+}
+
+// render writes msg as an RFC 5322 message with CRLF line endings
+func render(from string, msg Message, date time.Time) []byte {
+	var b bytes.Buffer
+	header := func(name, value string) {
+		b.WriteString(name + ": " + value + "\r\n")
+	}
+	header("From", headerValue(from))
+	header("To", headerValue(msg.To))
+	header("Subject", mime.QEncoding.Encode("utf-8", headerValue(msg.Subject)))
+	header("Date", date.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	b.WriteString("\r\n")
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return b.Bytes()
+}
+
+// headerValue keeps a value on one header line, so user input cannot add
+// headers of its own
+func headerValue(v string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(v)
+}
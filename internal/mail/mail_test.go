@@ -0,0 +1,65 @@
+package mail
+
+import (
+	"context"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"DBackend/internal/config"
+)
+
+func TestRenderKeepsHeadersOnOneLine(t *testing.T) {
+	date := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	got := string(render("DBackend <no-reply@example.com>", Message{
+		To:      "ada@example.com",
+		Subject: "Reminder: Intro\r\nBcc: eve@example.com",
+		Body:    "See you\ntomorrow",
+	}, date))
+
+	for _, want := range []string{
+		"From: DBackend <no-reply@example.com>\r\n",
+		"To: ada@example.com\r\n",
+		"Subject: Reminder: Intro  Bcc: eve@example.com\r\n",
+		"Date: Mon, 02 Mar 2026 09:00:00 +0000\r\n",
+		"Content-Type: text/plain; charset=utf-8\r\n\r\nSee you\r\ntomorrow",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("message does not contain %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "\r\nBcc:") {
+		t.Errorf("subject added a header:\n%s", got)
+	}
+
+	if got := string(render("a@example.com", Message{Subject: "Café"}, date)); !strings.Contains(got, "Subject: =?utf-8?q?Caf=C3=A9?=\r\n") {
+		t.Errorf("non-ASCII subject is not encoded:\n%s", got)
+	}
+}
+
+func TestSMTPSender(t *testing.T) {
+	var addr, from string
+	var to []string
+	var auth smtp.Auth
+	s := &smtpSender{
+		cfg: config.Mail{SMTPHost: "smtp.example.com", SMTPPort: 587, Username: "api", Password: "secret", From: "DBackend <no-reply@example.com>"},
+		send: func(a string, au smtp.Auth, f string, t []string, _ []byte) error {
+			addr, auth, from, to = a, au, f, t
+			return nil
+		},
+	}
+	if err := s.Send(context.Background(), Message{To: "Ada <ada@example.com>", Subject: "Hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if addr != "smtp.example.com:587" || from != "no-reply@example.com" || len(to) != 1 || to[0] != "ada@example.com" || auth == nil {
+		t.Errorf("sent via %s as %s to %v (auth %v)", addr, from, to, auth)
+	}
+
+	if err := s.Send(context.Background(), Message{To: "not an address"}); err == nil {
+		t.Error("a malformed recipient was accepted")
+	}
+	if _, ok := New(config.Mail{}).(logSender); !ok {
+		t.Error("New without a host does not log messages")
+	}
+}
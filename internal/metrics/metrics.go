@@ -0,0 +1,140 @@
+// Package metrics defines the Prometheus metrics the API exports: HTTP
+// traffic per route, MongoDB command latencies, ML matcher calls, background
+// job runs, outbox deliveries and domain events such as registrations and investments. Metrics live in their own
+// registry, served by Handler, so tests and tools can read them without
+// touching the global default registry.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every metric below plus Go runtime and process metrics
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// HTTP metrics, labelled by route template rather than path so IDs do not
+// create new series
+var (
+	HTTPRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method and route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+// MongoDB and ML matcher metrics
+var (
+	MongoDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mongodb_command_duration_seconds",
+		Help:    "MongoDB command latency by command name and outcome.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"command", "outcome"})
+
+	MLRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "ml_match_requests_total",
+		Help: "Calls to the ML matcher by outcome (success, error or bad_status).",
+	}, []string{"outcome"})
+
+	MLDuration = factory.NewHistogram(prometheus.HistogramOpts{
+		Name:    "ml_match_request_duration_seconds",
+		Help:    "ML matcher call latency.",
+		Buckets: prometheus.DefBuckets,
+	})
+)
+
+// Rate limiting metrics
+var (
+	RateLimited = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "rate_limited_requests_total",
+		Help: "Requests rejected by a rate limit, by route group and scope (ip or account).",
+	}, []string{"group", "scope"})
+
+	LoginLockouts = factory.NewCounter(prometheus.CounterOpts{
+		Name: "login_lockouts_total",
+		Help: "Failed logins that locked an account.",
+	})
+)
+
+// Background job metrics
+var (
+	JobRuns = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "job_runs_total",
+		Help: "Background job runs by job and status (succeeded, failed or interrupted).",
+	}, []string{"job", "status"})
+
+	JobDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "job_run_duration_seconds",
+		Help:    "Background job run time by job.",
+		Buckets: []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300, 600},
+	}, []string{"job"})
+)
+
+// Outbox metrics
+var (
+	EventDeliveries = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "event_deliveries_total",
+		Help: "Domain events handed to a subscriber, by event type, subscriber and outcome (delivered or failed).",
+	}, []string{"event", "subscriber", "outcome"})
+
+	EventsFailed = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "events_failed_total",
+		Help: "Domain events given up on after their retries ran out, by event type.",
+	}, []string{"event"})
+)
+
+// Domain metrics
+var (
+	Registrations = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "registrations_total",
+		Help: "Roles registered, including roles added to existing users.",
+	}, []string{"role"})
+
+	DealsCreated = factory.NewCounter(prometheus.CounterOpts{
+		Name: "deals_created_total",
+		Help: "Startups added to an investor's deal flow.",
+	})
+
+	StageTransitions = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "deal_stage_transitions_total",
+		Help: "Deals moved into a pipeline stage, by target stage.",
+	}, []string{"stage"})
+
+	Investments = factory.NewCounter(prometheus.CounterOpts{
+		Name: "investments_recorded_total",
+		Help: "Investments recorded against deals.",
+	})
+
+	InvestmentAmount = factory.NewCounter(prometheus.CounterOpts{
+		Name: "investments_recorded_amount_total",
+		Help: "Sum of recorded investment amounts.",
+	})
+
+	GrantApplications = factory.NewCounter(prometheus.CounterOpts{
+		Name: "grant_applications_submitted_total",
+		Help: "Grant applications submitted.",
+	})
+)
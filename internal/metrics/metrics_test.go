@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandlerExposesMetrics(t *testing.T) {
+	HTTPRequests.WithLabelValues("GET", "/api/v1/health", "200").Inc()
+	DealsCreated.Inc()
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	for _, want := range []string{
+		`http_requests_total{method="GET",route="/api/v1/health",status="200"}`,
+		"deals_created_total",
+		"go_goroutines",
+		"process_cpu_seconds_total",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics output is missing %s", want)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+// Package migrate applies versioned schema changes (indexes and data
+// backfills) to MongoDB and records them in the schema_migrations collection.
+//
+// Replicas that start together would otherwise run the same data migrations
+// at once, so a runner holds a lock document in schema_migrations while it
+// applies or rolls back migrations, and the others wait for it. The lock is a
+// lease renewed while the runner works: one whose process dies lapses after
+// LockTTL and another runner takes over.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"DBackend/internal/lease"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection is where applied migrations are recorded
+const Collection = "schema_migrations"
+
+// LockTTL is how long the migration lock lasts without being renewed
+const LockTTL = time.Minute
+
+// lockID is the _id of the lock document, which records are told apart from
+// by their numeric _id
+const lockID = "lock"
+
+// lockPoll is how often a runner waiting for the lock tries to take it
+const lockPoll = time.Second
+
+// Migration is a single versioned change. Down may be nil for irreversible migrations.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+	Down        func(ctx context.Context, db *mongo.Database) error
+}
+
+// Record is the schema_migrations document for an applied migration
+type Record struct {
+	Version     int       `bson:"_id" json:"version"`
+	Description string    `bson:"description" json:"description"`
+	AppliedAt   time.Time `bson:"applied_at" json:"applied_at"`
+}
+
+// Status reports whether a known migration has been applied
+type Status struct {
+	Version     int        `json:"version"`
+	Description string     `json:"description"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+}
+
+// Runner applies migrations to a database
+type Runner struct {
+	db         *mongo.Database
+	migrations []Migration
+	owner      string // the name the runner holds the lock under
+}
+
+// New returns a runner for migrations, which must have unique positive versions
+func New(db *mongo.Database, migrations []Migration) (*Runner, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	for i, m := range sorted {
+		if m.Version <= 0 || m.Up == nil {
+			return nil, fmt.Errorf("migration %d: version must be positive and Up must be set", m.Version)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("migration %d: duplicate version", m.Version)
+		}
+	}
+	return &Runner{db: db, migrations: sorted, owner: lease.Owner()}, nil
+}
+
+// Up applies every pending migration in version order and returns the
+// versions applied. It waits for any other runner to finish first.
+func (r *Runner) Up(ctx context.Context) (done []int, err error) {
+	ctx, unlock, err := r.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range r.migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err := m.Up(ctx, r.db); err != nil {
+			return done, fmt.Errorf("migration %d (%s): %w", m.Version, m.Description, err)
+		}
+		record := Record{Version: m.Version, Description: m.Description, AppliedAt: time.Now()}
+		if _, err := r.db.Collection(Collection).InsertOne(ctx, record); err != nil {
+			return done, fmt.Errorf("recording migration %d: %w", m.Version, err)
+		}
+		done = append(done, m.Version)
+	}
+	return done, nil
+}
+
+// Down rolls back the most recently applied steps migrations and returns
+// the versions reverted. It waits for any other runner to finish first.
+func (r *Runner) Down(ctx context.Context, steps int) (done []int, err error) {
+	ctx, unlock, err := r.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(r.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		m := r.migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if m.Down == nil {
+			return done, fmt.Errorf("migration %d (%s) cannot be rolled back", m.Version, m.Description)
+		}
+		if err := m.Down(ctx, r.db); err != nil {
+			return done, fmt.Errorf("rolling back migration %d (%s): %w", m.Version, m.Description, err)
+		}
+		if _, err := r.db.Collection(Collection).DeleteOne(ctx, bson.M{"_id": m.Version}); err != nil {
+			return done, fmt.Errorf("unrecording migration %d: %w", m.Version, err)
+		}
+		done = append(done, m.Version)
+	}
+	return done, nil
+}
+
+// Status lists every known migration and when it was applied
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(r.migrations))
+	for _, m := range r.migrations {
+		status := Status{Version: m.Version, Description: m.Description}
+		if record, ok := applied[m.Version]; ok {
+			at := record.AppliedAt
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// lock waits until it holds the migration lock, then renews it until unlock
+// is called. The returned context is cancelled if the lock is lost.
+func (r *Runner) lock(ctx context.Context) (context.Context, func(), error) {
+	locks := r.db.Collection(Collection)
+	for waiting := false; ; waiting = true {
+		now := time.Now()
+		_, err := locks.UpdateOne(ctx,
+			bson.M{"_id": lockID, "$or": bson.A{bson.M{"owner": r.owner}, bson.M{"expires_at": bson.M{"$lte": now}}}},
+			bson.M{"$set": bson.M{"owner": r.owner, "expires_at": now.Add(LockTTL)}},
+			options.Update().SetUpsert(true),
+		)
+		if err == nil {
+			break
+		}
+		// another runner holds the lock, so the upsert collided with it
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, nil, fmt.Errorf("taking the migration lock: %w", err)
+		}
+		if !waiting {
+			slog.InfoContext(ctx, "waiting for another instance to finish migrating")
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(lockPoll):
+		}
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(LockTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-lockCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			result, err := locks.UpdateOne(lockCtx,
+				bson.M{"_id": lockID, "owner": r.owner},
+				bson.M{"$set": bson.M{"expires_at": time.Now().Add(LockTTL)}},
+			)
+			if err != nil {
+				// the lock still has two thirds to run; try again next tick
+				slog.WarnContext(lockCtx, "renewing the migration lock failed", "error", err)
+				continue
+			}
+			if result.MatchedCount == 0 {
+				slog.ErrorContext(lockCtx, "lost the migration lock to another instance")
+				cancel()
+				return
+			}
+		}
+	}()
+
+	unlock := func() {
+		cancel()
+		wg.Wait()
+		storeCtx, done := lease.StoreContext(ctx)
+		defer done()
+		if _, err := locks.DeleteOne(storeCtx, bson.M{"_id": lockID, "owner": r.owner}); err != nil {
+			slog.WarnContext(ctx, "releasing the migration lock failed; it lapses on its own", "error", err)
+		}
+	}
+	return lockCtx, unlock, nil
+}
+
+func (r *Runner) applied(ctx context.Context) (map[int]Record, error) {
+	cursor, err := r.db.Collection(Collection).Find(ctx, bson.M{"_id": bson.M{"$type": "number"}})
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]Record, len(records))
+	for _, rec := range records {
+		applied[rec.Version] = rec
+	}
+	return applied, nil
+}
+
+// CreateIndexes returns an Up/Down step that creates models on collection
+func CreateIndexes(collection string, models ...mongo.IndexModel) func(context.Context, *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(collection).Indexes().CreateMany(ctx, models)
+		return err
+	}
+}
+
+// DropIndexes returns an Up/Down step that drops the named indexes, ignoring ones already gone
+func DropIndexes(collection string, names ...string) func(context.Context, *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		for _, name := range names {
+			if _, err := db.Collection(collection).Indexes().DropOne(ctx, name); err != nil && !isIndexNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Steps runs several Up/Down steps in order
+func Steps(steps ...func(context.Context, *mongo.Database) error) func(context.Context, *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		for _, step := range steps {
+			if err := step(ctx, db); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Index is shorthand for an ascending index with an explicit name
+func Index(name string, fields ...string) mongo.IndexModel {
+	keys := bson.D{}
+	for _, f := range fields {
+		keys = append(keys, bson.E{Key: f, Value: 1})
+	}
+	return mongo.IndexModel{Keys: keys, Options: options.Index().SetName(name)}
+}
+
+// isIndexNotFound reports IndexNotFound (27) and NamespaceNotFound (26) errors
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && (cmdErr.Code == 27 || cmdErr.Code == 26)
+}
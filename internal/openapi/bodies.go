@@ -0,0 +1,414 @@
+package openapi
+
+import (
+	"time"
+
+	"DBackend/internal/database"
+	"DBackend/internal/health"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// The types below describe request and response bodies that handlers build
+// from anonymous structs and fiber.Map literals. They exist only to be
+// reflected into schemas, so keep them in step with the handlers.
+
+// Message acknowledges a write that returns nothing else
+type Message struct {
+	Message string `json:"message"`
+}
+
+// Object is a free-form JSON object
+type Object map[string]interface{}
+
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password"`
+}
+
+type LoginResponse struct {
+	Token string    `json:"token"`
+	User  LoginUser `json:"user"`
+}
+
+type LoginUser struct {
+	ID        primitive.ObjectID `json:"id"`
+	Email     string             `json:"email"`
+	Roles     []string           `json:"roles"`
+	FirstName string             `json:"firstName"`
+	LastName  string             `json:"lastName"`
+}
+
+type MeResponse struct {
+	UserID string   `json:"user_id"`
+	Roles  []string `json:"roles"`
+}
+
+type RegisterRequest struct {
+	FirstName  string `json:"first_name"`
+	SecondName string `json:"second_name"`
+	Role       string `json:"role" validate:"required,role"`
+	Email      string `json:"email" validate:"required,email"`
+	Password   string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type UserCount struct {
+	Count int64 `json:"count"`
+}
+
+type Liveness struct {
+	Status health.Status `json:"status"`
+}
+
+type FounderProfile struct {
+	ID                primitive.ObjectID `json:"id"`
+	StartupName       string             `json:"startup_name"`
+	MissionStatement  string             `json:"mission_statement"`
+	Industry          string             `json:"industry"`
+	FundingStage      string             `json:"funding_stage"`
+	FundingAllocation string             `json:"funding_allocation"`
+	BussinessModel    string             `json:"bussiness_model"`
+	RevenueStreams    string             `json:"revenue_streams"`
+	Traction          string             `json:"traction"`
+	ScalingPotential  string             `json:"scaling_potential"`
+	FundRequired      int                `json:"fund_required"`
+	Competition       string             `json:"competition"`
+	LeadershipTeam    string             `json:"leadership_team"`
+	TeamSize          string             `json:"team_size"`
+	Location          string             `json:"location"`
+	StartupWebsite    string             `json:"startup_website"`
+	PithDeck          string             `json:"pith_deck"`
+}
+
+type FounderProfileUpdated struct {
+	Message   string        `json:"message"`
+	PitchDeck model.Founder `json:"pitch_deck"`
+}
+
+type InvestorProfile struct {
+	TotalInvested         float64  `json:"total_invested"`
+	InvestorType          string   `json:"investor_type"`
+	Thesis                string   `json:"thesis"`
+	PreferredFundingStage string   `json:"preferred_funding_stage"`
+	InvestmentRange       string   `json:"investment_range"`
+	InvestmentFrequency   string   `json:"investment_frequency"`
+	RiskTolerance         string   `json:"risk_tolerance"`
+	ExitStrategy          string   `json:"exit_strategy"`
+	PreferredIndustries   []string `json:"preferred_industries"`
+	PreferredRegions      []string `json:"preferred_regions"`
+}
+
+type FounderProfiles struct {
+	FounderProfiles []Object `json:"founder_profiles"`
+	Total           int      `json:"total"`
+	NextCursor      string   `json:"next_cursor,omitempty"`
+}
+
+type ReadStatusRequest struct {
+	ReadStatus bool `json:"read_status"`
+}
+
+type MeetingList struct {
+	Meetings []model.Meeting `json:"meetings"`
+}
+
+type MeetingEnvelope struct {
+	Meeting model.Meeting `json:"meeting"`
+}
+
+type MeetingScheduled struct {
+	Message   string            `json:"message"`
+	Meeting   model.Meeting     `json:"meeting"`
+	Conflicts []MeetingConflict `json:"conflicts,omitempty"`
+}
+
+// MeetingConflict is an overlap reported with on_conflict=warn, and in the
+// details of a meeting_overlap problem
+type MeetingConflict struct {
+	ParticipantID primitive.ObjectID `json:"participant_id"`
+	MeetingID     primitive.ObjectID `json:"meeting_id"`
+	Title         string             `json:"title"`
+	StartTime     time.Time          `json:"start_time"`
+	EndTime       time.Time          `json:"end_time"`
+	Timezone      string             `json:"timezone,omitempty"`
+	LocalStart    string             `json:"local_start,omitempty"`
+}
+
+type MeetingUpdated struct {
+	Message   string            `json:"message"`
+	Conflicts []MeetingConflict `json:"conflicts,omitempty"`
+}
+
+type MeetingAdded struct {
+	Message       string            `json:"message"`
+	ModifiedCount int64             `json:"modifiedCount"`
+	Conflicts     []MeetingConflict `json:"conflicts,omitempty"`
+}
+
+type MeetingOccurrence struct {
+	MeetingID     primitive.ObjectID `json:"meeting_id"`
+	OriginalStart time.Time          `json:"original_start"`
+	StartTime     time.Time          `json:"start_time"`
+	EndTime       time.Time          `json:"end_time"`
+	Title         string             `json:"title"`
+	Notes         string             `json:"notes,omitempty"`
+	Edited        bool               `json:"edited"`
+}
+
+type MeetingOccurrences struct {
+	Timezone    string              `json:"timezone,omitempty"`
+	Occurrences []MeetingOccurrence `json:"occurrences"`
+}
+
+type RSVPRequest struct {
+	Status  string `json:"status" validate:"required,oneof=accepted declined tentative"`
+	Comment string `json:"comment,omitempty" validate:"max=500"`
+}
+
+type RSVPRecorded struct {
+	Message string     `json:"message"`
+	RSVP    model.RSVP `json:"rsvp"`
+}
+
+type MeetingParticipant struct {
+	UserID      primitive.ObjectID `json:"user_id"`
+	Name        string             `json:"name"`
+	Organizer   bool               `json:"organizer"`
+	Status      string             `json:"status" validate:"oneof=accepted declined tentative pending"`
+	Comment     string             `json:"comment,omitempty"`
+	RespondedAt *time.Time         `json:"responded_at,omitempty"`
+}
+
+type MeetingParticipants struct {
+	Participants []MeetingParticipant `json:"participants"`
+}
+
+type OutcomeRecorded struct {
+	Message string               `json:"message"`
+	Outcome model.MeetingOutcome `json:"outcome"`
+}
+
+type DealActivity struct {
+	Activities []model.RecentActivity `json:"activities"`
+}
+
+type TimezoneRequest struct {
+	Timezone string `json:"timezone"`
+}
+
+type NoteList struct {
+	Notes []model.MeetingNote `json:"notes"`
+}
+
+type NoteEnvelope struct {
+	Note model.MeetingNote `json:"note"`
+}
+
+type NoteSaved struct {
+	Message string            `json:"message"`
+	Note    model.MeetingNote `json:"note"`
+}
+
+type CalendarAuthURL struct {
+	AuthURL string `json:"auth_url"`
+}
+
+type CalendarStatus struct {
+	Connected  bool                      `json:"connected"`
+	Connection *model.CalendarConnection `json:"connection,omitempty"`
+}
+
+type CalendarConnected struct {
+	Message    string                   `json:"message"`
+	Connection model.CalendarConnection `json:"connection"`
+}
+
+type CalendarFeedURL struct {
+	URL       string `json:"url"`
+	WebcalURL string `json:"webcal_url"`
+}
+
+type CalendarFeedStatus struct {
+	Enabled bool                `json:"enabled"`
+	Feed    *model.CalendarFeed `json:"feed,omitempty"`
+}
+
+type AvailabilityResponse struct {
+	Availability model.Availability `json:"availability"`
+}
+
+type BookingLinkCreated struct {
+	Link model.BookingLink `json:"link"`
+	URL  string            `json:"url"`
+}
+
+type BookingLinkList struct {
+	Links []model.BookingLink `json:"links"`
+}
+
+type BookingSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type BookingSlots struct {
+	Title    string        `json:"title"`
+	Timezone string        `json:"timezone"`
+	Slots    []BookingSlot `json:"slots"`
+}
+
+type BookingRequest struct {
+	StartTime time.Time `json:"start_time"`
+	Notes     string    `json:"notes"`
+}
+
+type UserIDRequest struct {
+	UserID primitive.ObjectID `json:"user_id"`
+}
+
+type MatchProbability struct {
+	MatchProbability float64 `json:"match_probability"`
+}
+
+type AddDealRequest struct {
+	UserID           primitive.ObjectID `json:"UserID"`
+	Name             string             `json:"Name,omitempty"`
+	Email            string             `json:"Email,omitempty"`
+	Avatar           string             `json:"Avatar,omitempty"`
+	StartupName      string             `json:"StartupName,omitempty"`
+	Industry         string             `json:"Industry,omitempty"`
+	FundingStage     string             `json:"FundingStage"`
+	Location         string             `json:"Location,omitempty"`
+	FundRequired     int                `json:"FundRequired,omitempty"`
+	MissionStatement string             `json:"MissionStatement,omitempty"`
+	BusinessModel    string             `json:"BusinessModel,omitempty"`
+	RevenueStreams   string             `json:"RevenueStreams,omitempty"`
+	Traction         string             `json:"Traction,omitempty"`
+	ScalingPotential string             `json:"ScalingPotential,omitempty"`
+	Competition      string             `json:"Competition,omitempty"`
+	LeadershipTeam   string             `json:"LeadershipTeam,omitempty"`
+	TeamSize         string             `json:"TeamSize,omitempty"`
+	StartupWebsite   string             `json:"StartupWebsite,omitempty"`
+	PitchDeck        string             `json:"PitchDeck,omitempty"`
+	FundAllocation   string             `json:"FundAllocation,omitempty"`
+	Founded          string             `json:"Founded,omitempty"`
+	MatchScore       float64            `json:"MatchScore,omitempty"`
+	Tags             []string           `json:"Tags,omitempty"`
+	Bookmarked       bool               `json:"Bookmarked,omitempty"`
+}
+
+type Created struct {
+	Message string             `json:"message"`
+	ID      primitive.ObjectID `json:"id"`
+}
+
+type Modified struct {
+	Message       string `json:"message"`
+	ModifiedCount int64  `json:"modifiedCount"`
+}
+
+type Deleted struct {
+	Message      string `json:"message"`
+	DeletedCount int64  `json:"deletedCount"`
+}
+
+type CompletedRequest struct {
+	Completed bool `json:"completed"`
+}
+
+type InvestRequest struct {
+	InvestmentAmount float64 `json:"investmentAmount"`
+}
+
+type InvestmentRecorded struct {
+	Message    string           `json:"message"`
+	Investment model.Investment `json:"investment"`
+}
+
+type DealStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=active paused completed cancelled"`
+}
+
+type DealStageRequest struct {
+	Stage string `json:"stage" validate:"required,oneof=screening dueDiligence negotiation closed"`
+}
+
+// DealUpdateRequest sets only the fields it contains
+type DealUpdateRequest struct {
+	Stage      string  `json:"stage,omitempty" validate:"oneof=screening dueDiligence negotiation closed"`
+	Status     string  `json:"status,omitempty" validate:"oneof=active paused completed cancelled"`
+	Priority   string  `json:"priority,omitempty" validate:"oneof=low medium high"`
+	MatchScore float64 `json:"match_score,omitempty" validate:"min=0,max=100"`
+}
+
+type DealStageUpdated struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Deal    struct {
+		ID    primitive.ObjectID `json:"_id"`
+		Stage string             `json:"stage"`
+	} `json:"deal"`
+}
+
+type GrantList struct {
+	Grants []model.Grant `json:"grants"`
+}
+
+type ApplicationSubmitted struct {
+	Message       string             `json:"message"`
+	ApplicationID primitive.ObjectID `json:"applicationId"`
+}
+
+type GrantApplicationReview struct {
+	Status  string `json:"status" validate:"required,oneof=Pending Approved Rejected"`
+	Remarks string `json:"remarks"`
+}
+
+type TaskEnvelope struct {
+	Task model.Task `json:"task"`
+}
+
+type TaskCreated struct {
+	Message string     `json:"message"`
+	Task    model.Task `json:"task"`
+}
+
+type TaskList struct {
+	Tasks []model.Task `json:"tasks"`
+}
+
+type TaskStatusUpdated struct {
+	Message string `json:"message"`
+	Task    struct {
+		ID        primitive.ObjectID `json:"id"`
+		Completed bool               `json:"completed"`
+	} `json:"task"`
+}
+
+type SearchResponse struct {
+	Query   string                  `json:"query"`
+	Results []database.SearchResult `json:"results"`
+}
+
+type JobList struct {
+	Jobs []model.Job `json:"jobs"`
+}
+
+type JobRunList struct {
+	Runs []model.JobRun `json:"runs"`
+}
+
+type JobTriggered struct {
+	Message string `json:"message"`
+	Job     string `json:"job"`
+}
+
+type EventList struct {
+	Events []model.Event `json:"events"`
+}
+
+type EventRetried struct {
+	Message string             `json:"message"`
+	ID      primitive.ObjectID `json:"id"`
+}
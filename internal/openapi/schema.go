@@ -0,0 +1,175 @@
+// Package openapi builds OpenAPI 3 documents from the Go types the API
+// serves, so response schemas follow the code instead of drifting from it.
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"DBackend/internal/validate"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Schema is the subset of the OpenAPI schema object the generator emits
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	objectIDType = reflect.TypeOf(primitive.ObjectID{})
+)
+
+// objectID describes a primitive.ObjectID, which marshals as a hex string
+func objectID() *Schema {
+	return &Schema{Type: "string", Pattern: "^[0-9a-f]{24}$"}
+}
+
+// Registry collects the component schemas of named struct types
+type Registry struct {
+	schemas map[string]*Schema
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{schemas: map[string]*Schema{}}
+}
+
+// Schemas returns every component schema registered so far, keyed by type name
+func (r *Registry) Schemas() map[string]*Schema {
+	return r.schemas
+}
+
+// Ref registers the type of v and returns a reference to its component schema
+func (r *Registry) Ref(v interface{}) *Schema {
+	return r.schemaOf(reflect.TypeOf(v))
+}
+
+func (r *Registry) schemaOf(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case objectIDType:
+		return objectID()
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		// OpenAPI 3.1 spells nullable as a union with the null type
+		return &Schema{OneOf: []*Schema{r.schemaOf(t.Elem()), {Type: "null"}}}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.object(t)
+		}
+		name := componentName(t)
+		if _, ok := r.schemas[name]; !ok {
+			// Reserve the name first so self-referencing types terminate
+			r.schemas[name] = &Schema{}
+			*r.schemas[name] = *r.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	// interface{} and anything else accepts any JSON value
+	return &Schema{}
+}
+
+// componentName names a struct's schema, turning an instantiated generic
+// such as query.Page[DBackend/model.Task] into PageTask
+func componentName(t reflect.Type) string {
+	base, args, generic := strings.Cut(t.Name(), "[")
+	if !generic {
+		return base
+	}
+	for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+		if i := strings.LastIndex(arg, "."); i >= 0 {
+			arg = arg[i+1:]
+		}
+		base += strings.Trim(arg, "[]*")
+	}
+	return base
+}
+
+// object describes a struct the way encoding/json marshals it
+func (r *Registry) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			embedded := r.object(f.Type)
+			for k, v := range embedded.Properties {
+				s.Properties[k] = v
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = constrain(r.schemaOf(f.Type), f.Tag.Get("validate"))
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s
+}
+
+// constrain documents the validate rules of a string field that OpenAPI can
+// express: oneof and role become an enum, email and url a format
+func constrain(s *Schema, rules string) *Schema {
+	target := s
+	if len(s.OneOf) > 0 {
+		target = s.OneOf[0]
+	}
+	if rules == "" || target.Type != "string" {
+		return s
+	}
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "oneof":
+			target.Enum = strings.Fields(param)
+		case "role":
+			target.Enum = validate.Roles
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		}
+	}
+	return s
+}
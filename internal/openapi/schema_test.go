@@ -0,0 +1,99 @@
+package openapi
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type box[T any] struct {
+	Item T `json:"item"`
+}
+
+type node struct {
+	ID       primitive.ObjectID `json:"id"`
+	Name     string             `json:"name"`
+	Tags     []string           `json:"tags,omitempty"`
+	Seen     *time.Time         `json:"seen"`
+	Children []node             `json:"children"`
+	Hidden   string             `json:"-"`
+	secret   string
+}
+
+func TestRegistryRef(t *testing.T) {
+	r := NewRegistry()
+	ref := r.Ref(node{})
+	if ref.Ref != "#/components/schemas/node" {
+		t.Fatalf("Ref() = %+v, want a component reference", ref)
+	}
+
+	s := r.Schemas()["node"]
+	if s == nil {
+		t.Fatal("node schema was not registered")
+	}
+	var names []string
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	if len(names) != 5 {
+		t.Errorf("properties = %v, want id, name, tags, seen, children", names)
+	}
+	if want := []string{"id", "name", "seen", "children"}; !reflect.DeepEqual(s.Required, want) {
+		t.Errorf("required = %v, want %v", s.Required, want)
+	}
+	if p := s.Properties["seen"]; len(p.OneOf) != 2 || p.OneOf[0].Format != "date-time" || p.OneOf[1].Type != "null" {
+		t.Errorf("seen = %+v, want a nullable date-time", p)
+	}
+	if p := s.Properties["children"]; p.Type != "array" || p.Items.Ref != ref.Ref {
+		t.Errorf("children = %+v, want an array of node references", p)
+	}
+}
+
+func TestRegistryValidateRules(t *testing.T) {
+	type signup struct {
+		Email string  `json:"email" validate:"required,email"`
+		Role  string  `json:"role" validate:"required,role"`
+		Size  *string `json:"size" validate:"oneof=s m l"`
+		Age   int     `json:"age" validate:"min=18"`
+	}
+	r := NewRegistry()
+	r.Ref(signup{})
+	s := r.Schemas()["signup"]
+	if p := s.Properties["email"]; p.Format != "email" {
+		t.Errorf("email = %+v, want format email", p)
+	}
+	if p := s.Properties["role"]; !reflect.DeepEqual(p.Enum, []string{"founder", "investor"}) {
+		t.Errorf("role = %+v, want the registrable roles", p)
+	}
+	if p := s.Properties["size"]; !reflect.DeepEqual(p.OneOf[0].Enum, []string{"s", "m", "l"}) {
+		t.Errorf("size = %+v, want a nullable enum", p)
+	}
+	if p := s.Properties["age"]; p.Type != "integer" || p.Enum != nil {
+		t.Errorf("age = %+v, want a plain integer", p)
+	}
+}
+
+func TestRegistryGenericName(t *testing.T) {
+	r := NewRegistry()
+	if ref := r.Ref(box[node]{}); ref.Ref != "#/components/schemas/boxnode" {
+		t.Errorf("Ref() = %+v, want boxnode", ref)
+	}
+}
+
+func TestSpecDocumentIsCurrent(t *testing.T) {
+	want, err := json.MarshalIndent(Spec(), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("../../docs/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want)+"\n" {
+		t.Error("docs/openapi.json is stale; run make openapi")
+	}
+}
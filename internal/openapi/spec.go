@@ -0,0 +1,369 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"DBackend/internal/database"
+	"DBackend/internal/health"
+	"DBackend/internal/query"
+	"DBackend/internal/server/middleware"
+	"DBackend/model"
+)
+
+// BasePath is the prefix every route in the document is mounted under
+const BasePath = "/api/v1"
+
+// op describes one route for Spec
+type op struct {
+	id      string
+	summary string
+	public  bool
+	role    string       // role RequireRole enforces, if any
+	query   []*Parameter // query string and header parameters, and path parameters that are not ObjectIDs
+	body    interface{}  // JSON request body
+	form    *Schema      // multipart/form-data request body
+	status  string       // success status, "200" when empty
+	resp    interface{}  // success body, Message when nil
+	media   string       // media type of a success body that is not JSON
+	cached  bool         // answers 304 when If-None-Match matches the ETag
+	// conflict describes when the route answers 409, if it can
+	conflict string
+}
+
+type builder struct {
+	reg   *Registry
+	paths map[string]*PathItem
+}
+
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// add registers an operation on path, which is relative to BasePath and uses
+// OpenAPI {param} syntax
+func (b *builder) add(method, path, tag string, o op) {
+	operation := &Operation{
+		OperationID: o.id,
+		Summary:     o.summary,
+		Tags:        []string{tag},
+		Parameters:  o.query,
+		Responses:   map[string]*Response{"500": ProblemJSON("Unexpected server error", b.reg.Ref(middleware.Problem{}))},
+	}
+
+	for _, m := range pathParam.FindAllStringSubmatch(path, -1) {
+		if hasParam(o.query, m[1], "path") {
+			continue
+		}
+		operation.Parameters = append(operation.Parameters, &Parameter{
+			Name:     m[1],
+			In:       "path",
+			Required: true,
+			Schema:   objectID(),
+		})
+	}
+	if len(operation.Parameters) > 0 || o.body != nil || o.form != nil {
+		operation.Responses["400"] = ProblemJSON("Invalid parameters or body", b.reg.Ref(middleware.Problem{}))
+	}
+	if strings.Contains(path, "{") {
+		operation.Responses["404"] = ProblemJSON("Not found", b.reg.Ref(middleware.Problem{}))
+	}
+	if !o.public {
+		operation.Security = []map[string][]string{{"bearerAuth": {}}}
+		operation.Responses["401"] = ProblemJSON("Missing, invalid or revoked token", b.reg.Ref(middleware.Problem{}))
+	}
+	if o.conflict != "" {
+		operation.Responses["409"] = ProblemJSON(o.conflict, b.reg.Ref(middleware.Problem{}))
+	}
+	if method != "GET" {
+		operation.Responses["429"] = ProblemJSON("Rate limited or account locked; retry after Retry-After seconds", b.reg.Ref(middleware.Problem{}))
+	}
+	if o.role != "" {
+		operation.Description = fmt.Sprintf("Requires the %s role.", o.role)
+		operation.Responses["403"] = ProblemJSON("Caller lacks the "+o.role+" role", b.reg.Ref(middleware.Problem{}))
+	}
+
+	switch {
+	case o.body != nil:
+		operation.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			"application/json": {Schema: b.reg.Ref(o.body)},
+		}}
+	case o.form != nil:
+		operation.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			"multipart/form-data": {Schema: o.form},
+		}}
+	}
+
+	status, resp := o.status, o.resp
+	if status == "" {
+		status = "200"
+	}
+	if resp == nil {
+		resp = Message{}
+	}
+	operation.Responses[status] = JSON("Success", b.reg.Ref(resp))
+	if o.media != "" {
+		operation.Responses[status] = &Response{Description: "Success", Content: map[string]MediaType{
+			o.media: {Schema: &Schema{Type: "string"}},
+		}}
+	}
+
+	if o.cached {
+		operation.Responses["304"] = &Response{Description: "Unchanged since the ETag in If-None-Match"}
+	}
+
+	item := b.paths[path]
+	if item == nil {
+		item = &PathItem{}
+		b.paths[path] = item
+	}
+	item.set(method, operation)
+}
+
+func hasParam(params []*Parameter, name, in string) bool {
+	for _, p := range params {
+		if p.Name == name && p.In == in {
+			return true
+		}
+	}
+	return false
+}
+
+// Spec describes every route the API registers. TestSpecCoversRoutes in the
+// routes package fails when a registered route is missing here.
+func Spec() *Document {
+	b := &builder{reg: NewRegistry(), paths: map[string]*PathItem{}}
+	onConflict := []*Parameter{{Name: "on_conflict", In: "query", Description: "reject (default) refuses a meeting overlapping one a participant is in; warn schedules it and lists the overlaps", Schema: &Schema{Type: "string", Enum: []string{"reject", "warn"}}}}
+
+	// health
+	b.add("GET", "/health", "health", op{id: "getHealth", summary: "Readiness report (alias of /health/ready)", public: true, resp: health.Report{}})
+	b.add("GET", "/health/live", "health", op{id: "getLiveness", summary: "Liveness probe; checks no dependencies", public: true, resp: Liveness{}})
+	b.add("GET", "/health/ready", "health", op{id: "getReadiness", summary: "Readiness probe; 503 when a critical dependency is down", public: true, resp: health.Report{}})
+
+	// docs
+	b.add("GET", "/openapi.json", "docs", op{id: "getOpenAPI", summary: "This OpenAPI document", public: true, resp: Object{}})
+	b.add("GET", "/docs", "docs", op{id: "getDocs", summary: "Interactive API documentation (HTML)", public: true, resp: Object{}})
+
+	// user and auth
+	b.add("POST", "/user/register", "auth", op{id: "register", summary: "Register a user, or add a role to an existing one", public: true, body: RegisterRequest{}})
+	b.add("GET", "/user/count", "auth", op{id: "countUsers", summary: "Number of registered users", public: true, resp: UserCount{}})
+	b.add("POST", "/auth/login", "auth", op{id: "login", summary: "Exchange credentials for a JWT", public: true, body: LoginRequest{}, resp: LoginResponse{}})
+	b.add("POST", "/auth/logout", "auth", op{id: "logout", summary: "Revoke the bearer token, if one is sent", public: true})
+	b.add("GET", "/get/me", "auth", op{id: "me", summary: "ID and roles of the token's user", resp: MeResponse{}})
+	b.add("PUT", "/get/me/timezone", "auth", op{id: "setMyTimezone", summary: "Set the IANA timezone the user's meetings default to", body: TimezoneRequest{}, resp: TimezoneRequest{}})
+
+	// founder
+	b.add("GET", "/founder", "founder", op{id: "getFounderUser", summary: "Account details of the signed-in user", resp: model.User{}})
+	b.add("GET", "/founder/profile", "founder", op{id: "getFounderProfile", summary: "Startup profile of the signed-in founder", role: "founder", resp: FounderProfile{}})
+	b.add("PUT", "/founder/profile", "founder", op{id: "updateFounderProfile", summary: "Update the startup profile and optionally upload a pitch deck", role: "founder", form: &Schema{
+		Type:     "object",
+		Required: []string{"data"},
+		Properties: map[string]*Schema{
+			"data":       {Type: "string", Description: "JSON-encoded founder profile fields"},
+			"pitch_deck": {Type: "string", Format: "binary"},
+		},
+	}, resp: FounderProfileUpdated{}})
+	b.add("GET", "/founder/dashboard", "founder", op{id: "getFounderDashboard", summary: "Fundraising progress and investor engagement", role: "founder", resp: model.FounderDashboard{}})
+	b.add("GET", "/founder/notifications", "founder", op{id: "listFounderNotifications", summary: "Notifications for the signed-in founder", role: "founder", query: listParams(database.NotificationListSpec), resp: query.Page[model.Notification]{}})
+	b.add("PUT", "/founder/notifications/{notificationID}", "founder", op{id: "updateFounderNotification", summary: "Update a notification", role: "founder", body: model.Notification{}})
+	b.add("DELETE", "/founder/notification/{notificationID}", "founder", op{id: "deleteFounderNotification", summary: "Delete a notification", role: "founder"})
+
+	// investor
+	b.add("GET", "/investor", "investor", op{id: "getInvestorUser", summary: "Account details of the signed-in user", resp: model.User{}})
+	b.add("GET", "/investor/profile", "investor", op{id: "getInvestorProfile", summary: "Profile of the signed-in investor", role: "investor", resp: InvestorProfile{}})
+	b.add("PATCH", "/investor/profile", "investor", op{id: "updateInvestorProfile", summary: "Update the investor profile", role: "investor", body: InvestorProfile{}})
+	b.add("GET", "/investor/startups", "investor", op{id: "discoverStartups", summary: "Filter, sort and page through startups with facet counts", query: startupParams(), resp: database.StartupPage{}})
+	b.add("GET", "/investor/founderProfile", "investor", op{id: "listFounderProfiles", summary: "Founder profiles with match data, using the startup filters", query: startupParams(), resp: FounderProfiles{}})
+	b.add("POST", "/investor/investor/{id}/meeting", "investor", op{id: "addInvestorDealMeeting", summary: "Schedule a meeting on a deal and create its calendar event", query: onConflict, body: model.Meeting{}, resp: MeetingAdded{}, conflict: "A participant already has a meeting at that time, or the organizer has not connected a calendar or revoked its access"})
+	b.add("GET", "/investor/investor/{id}/meetings", "investor", op{id: "listInvestorMeetings", summary: "Meetings of an investor", resp: MeetingList{}})
+	b.add("GET", "/investor/notifications", "investor", op{id: "listInvestorNotifications", summary: "Notifications for the signed-in investor", role: "investor", query: listParams(database.NotificationListSpec), resp: query.Page[model.Notification]{}})
+	b.add("PUT", "/investor/notifications/{notificationID}", "investor", op{id: "updateInvestorNotification", summary: "Mark a notification read or unread", role: "investor", body: ReadStatusRequest{}})
+	b.add("DELETE", "/investor/notification/{notificationID}", "investor", op{id: "deleteInvestorNotification", summary: "Delete a notification", role: "investor"})
+	b.add("GET", "/investor/dashboard", "investor", op{id: "getInvestorDashboard", summary: "Portfolio, pipeline and recent activity", role: "investor", resp: model.InvestorDashboard{}})
+	b.add("GET", "/investor/portfolio/performance", "investor", op{id: "getPortfolioPerformance", summary: "Portfolio value history and return metrics", role: "investor", query: []*Parameter{
+		{Name: "period", In: "query", Schema: &Schema{Type: "string", Enum: []string{"1m", "3m", "6m", "1y", "all"}}},
+	}, resp: model.PortfolioPerformance{}})
+
+	// match
+	b.add("GET", "/match/data/{userID}", "match", op{id: "matchFounder", summary: "Score the signed-in investor against a founder and record the match", status: "201", resp: MatchProbability{}})
+
+	// deal flow
+	b.add("GET", "/dealflow", "dealflow", op{id: "listDeals", summary: "Deals in the signed-in investor's pipeline", query: listParams(database.DealFlowListSpec), resp: query.Page[Object]{}})
+	b.add("POST", "/dealflow", "dealflow", op{id: "addDeal", summary: "Add a startup to the pipeline and notify its founder", body: AddDealRequest{}, resp: Created{}, conflict: "The startup is already in the pipeline"})
+	b.add("GET", "/dealflow/{id}", "dealflow", op{id: "getDeal", summary: "A deal", resp: model.DealFlow{}})
+	b.add("GET", "/dealflow/{id}/activity", "dealflow", op{id: "listDealActivity", summary: "A deal's activity timeline, newest first, for its investor and founder", resp: DealActivity{}})
+	b.add("PUT", "/dealflow/{id}", "dealflow", op{id: "updateDeal", summary: "Change a deal's stage, status, priority or match score", body: DealUpdateRequest{}, resp: Modified{}})
+	b.add("DELETE", "/dealflow/{id}", "dealflow", op{id: "deleteDeal", summary: "Remove a deal from the pipeline", resp: Deleted{}})
+	b.add("POST", "/dealflow/{id}/invest", "dealflow", op{id: "investInDeal", summary: "Record an investment and reduce the amount still required", role: "investor", body: InvestRequest{}, resp: InvestmentRecorded{}})
+	b.add("POST", "/dealflow/{id}/meetings", "dealflow", op{id: "addDealMeeting", summary: "Schedule a meeting on a deal and create its calendar event", query: onConflict, body: model.Meeting{}, resp: MeetingAdded{}, conflict: "A participant already has a meeting at that time, or the organizer has not connected a calendar or revoked its access"})
+	b.add("POST", "/dealflow/{id}/documents", "dealflow", op{id: "addDealDocument", summary: "Attach a document to a deal", body: model.Document{}, resp: Modified{}})
+	b.add("POST", "/dealflow/{id}/tasks", "dealflow", op{id: "addDealTask", summary: "Add a task to a deal", body: model.Task{}, resp: Modified{}})
+	b.add("PATCH", "/dealflow/{id}/tasks/{taskID}", "dealflow", op{id: "updateDealTask", summary: "Complete or reopen a deal task", body: CompletedRequest{}, resp: Modified{}})
+	b.add("PATCH", "/dealflow/{id}/stage", "dealflow", op{id: "updateDealStage", summary: "Move a deal to another stage", body: DealStageRequest{}, resp: DealStageUpdated{}})
+	b.add("PATCH", "/dealflow/{id}/status", "dealflow", op{id: "updateDealStatus", summary: "Change a deal's status", body: DealStatusRequest{}, resp: Object{}})
+
+	// grants
+	b.add("GET", "/grants", "grants", op{id: "listGrants", summary: "Open grants", public: true, query: []*Parameter{
+		{Name: "category", In: "query", Schema: &Schema{Type: "string"}},
+		{Name: "region", In: "query", Schema: &Schema{Type: "string"}},
+	}, resp: GrantList{}})
+	b.add("GET", "/grants/{id}", "grants", op{id: "getGrant", summary: "A grant", public: true, resp: model.Grant{}})
+	b.add("POST", "/grants", "grants", op{id: "createGrant", summary: "Create a grant", body: model.Grant{}, status: "201", resp: Created{}})
+	b.add("PUT", "/grants/{id}", "grants", op{id: "updateGrant", summary: "Update a grant", body: model.Grant{}})
+	b.add("DELETE", "/grants/{id}", "grants", op{id: "deleteGrant", summary: "Delete a grant"})
+	b.add("POST", "/grants/apply", "grants", op{id: "applyForGrant", summary: "Apply for a grant as the signed-in founder", body: model.GrantApplication{}, status: "201", resp: ApplicationSubmitted{}})
+	b.add("GET", "/grants/applications", "grants", op{id: "listGrantApplications", summary: "All applications for admins, otherwise the caller's own", query: listParams(database.GrantApplicationListSpec), resp: query.Page[model.GrantApplication]{}})
+	b.add("GET", "/grants/applications/{id}", "grants", op{id: "getGrantApplication", summary: "A grant application", resp: model.GrantApplication{}})
+	b.add("PUT", "/grants/applications/{id}", "grants", op{id: "reviewGrantApplication", summary: "Set an application's status and remarks", body: GrantApplicationReview{}})
+
+	// tasks
+	b.add("GET", "/tasks", "tasks", op{id: "listTasks", summary: "Tasks in the caller's deals or created by or assigned to them, or every task for admins", query: listParams(database.TaskListSpec), resp: query.Page[model.Task]{}})
+	b.add("POST", "/tasks", "tasks", op{id: "createTask", summary: "Create a task", body: model.Task{}, status: "201", resp: TaskCreated{}})
+	b.add("GET", "/tasks/{id}", "tasks", op{id: "getTask", summary: "A task", resp: TaskEnvelope{}})
+	b.add("PUT", "/tasks/{id}", "tasks", op{id: "updateTask", summary: "Update a task", body: model.Task{}})
+	b.add("DELETE", "/tasks/{id}", "tasks", op{id: "deleteTask", summary: "Delete a task"})
+	b.add("GET", "/tasks/user/{userId}", "tasks", op{id: "listUserTasks", summary: "Tasks assigned to a user", resp: TaskList{}})
+	b.add("PATCH", "/tasks/{id}/status", "tasks", op{id: "updateTaskStatus", summary: "Complete or reopen a task", body: CompletedRequest{}, resp: TaskStatusUpdated{}})
+	b.add("POST", "/tasks/{id}/assign", "tasks", op{id: "assignTask", summary: "Assign a task to a user", body: UserIDRequest{}})
+
+	// meetings
+	overlap := "A participant already has a meeting at that time; pass on_conflict=warn to schedule it anyway"
+	b.add("GET", "/meetings", "meetings", op{id: "listMeetings", summary: "Meetings the caller takes part in, or every meeting for admins", query: listParams(database.MeetingListSpec), resp: query.Page[model.Meeting]{}})
+	b.add("POST", "/meetings", "meetings", op{id: "scheduleMeeting", summary: "Schedule a meeting, optionally recurring, and create its calendar event", query: onConflict, body: model.Meeting{}, status: "201", resp: MeetingScheduled{}, conflict: "A participant already has a meeting at that time, or the organizer has not connected a calendar or revoked its access"})
+	b.add("GET", "/meetings/user", "meetings", op{id: "listMyMeetings", summary: "Meetings of the signed-in user", resp: MeetingList{}})
+	b.add("GET", "/meetings/{id}", "meetings", op{id: "getMeeting", summary: "A meeting", resp: MeetingEnvelope{}})
+	b.add("GET", "/meetings/{id}/invite.ics", "meetings", op{id: "getMeetingInvite", summary: "The meeting as an RFC 5545 invite", media: "text/calendar"})
+	b.add("PUT", "/meetings/{id}", "meetings", op{id: "updateMeeting", summary: "Update a meeting as its organizer; a new rule or start drops the edited and cancelled occurrences", query: onConflict, body: model.Meeting{}, resp: MeetingUpdated{}, conflict: overlap})
+	b.add("GET", "/meetings/{id}/occurrences", "meetings", op{id: "listMeetingOccurrences", summary: "Occurrences of a meeting the caller takes part in, with edits applied and cancelled ones left out", query: []*Parameter{
+		{Name: "from", In: "query", Description: "Start of the span, RFC 3339; now by default", Schema: &Schema{Type: "string", Format: "date-time"}},
+		{Name: "to", In: "query", Description: "End of the span, RFC 3339; 90 days after from by default and at most 366", Schema: &Schema{Type: "string", Format: "date-time"}},
+	}, resp: MeetingOccurrences{}})
+	b.add("PUT", "/meetings/{id}/occurrences", "meetings", op{id: "updateMeetingOccurrence", summary: "Edit one occurrence of a recurring meeting as its organizer, named by its original start", query: onConflict, body: model.OccurrenceOverride{}, resp: MeetingUpdated{}, conflict: overlap})
+	b.add("DELETE", "/meetings/{id}/occurrences", "meetings", op{id: "cancelMeetingOccurrence", summary: "Cancel one occurrence of a recurring meeting as its organizer", query: []*Parameter{
+		{Name: "start", In: "query", Required: true, Description: "Original start of the occurrence, RFC 3339", Schema: &Schema{Type: "string", Format: "date-time"}},
+	}})
+	b.add("DELETE", "/meetings/{id}", "meetings", op{id: "cancelMeeting", summary: "Cancel a meeting as its organizer"})
+	b.add("GET", "/meetings/{id}/notes", "meetings", op{id: "listMeetingNotes", summary: "Shared notes of a meeting and the signed-in participant's private ones, without their history", resp: NoteList{}})
+	b.add("POST", "/meetings/{id}/notes", "meetings", op{id: "createMeetingNote", summary: "Add a private or shared note with sections and action items", body: model.MeetingNote{}, status: "201", resp: NoteSaved{}})
+	b.add("GET", "/meetings/{id}/notes/{noteId}", "meetings", op{id: "getMeetingNote", summary: "A note with its edit history", resp: NoteEnvelope{}})
+	b.add("PUT", "/meetings/{id}/notes/{noteId}", "meetings", op{id: "updateMeetingNote", summary: "Edit a note, keeping the version it replaces; only the author can change its visibility", body: model.MeetingNote{}, resp: NoteSaved{}, conflict: "The note was edited since the version sent"})
+	b.add("DELETE", "/meetings/{id}/notes/{noteId}", "meetings", op{id: "deleteMeetingNote", summary: "Delete a note as its author"})
+	b.add("POST", "/meetings/{id}/notes/{noteId}/action-items/{itemId}/task", "meetings", op{id: "createActionItemTask", summary: "Turn an action item into a task on the meeting's deal; completing the task marks the item done", status: "201", resp: TaskCreated{}, conflict: "The action item already has a task"})
+	b.add("POST", "/meetings/{id}/participants", "meetings", op{id: "addMeetingParticipant", summary: "Invite a user to a meeting", query: onConflict, body: UserIDRequest{}, resp: MeetingUpdated{}, conflict: "The user already has a meeting at that time; pass on_conflict=warn to add them anyway"})
+	b.add("DELETE", "/meetings/{id}/participants/{userId}", "meetings", op{id: "removeMeetingParticipant", summary: "Remove a user from a meeting"})
+	b.add("GET", "/meetings/{id}/participants", "meetings", op{id: "listMeetingParticipants", summary: "Everyone taking part in a meeting with their answer, pending until they reply", resp: MeetingParticipants{}})
+	b.add("PUT", "/meetings/{id}/rsvp", "meetings", op{id: "respondToMeeting", summary: "Accept, decline or tentatively accept a meeting as one of its participants, notifying the organizer", body: RSVPRequest{}, resp: RSVPRecorded{}})
+	b.add("POST", "/meetings/{id}/outcome", "meetings", op{id: "recordMeetingOutcome", summary: "Record attendance and the outcome of a meeting that has ended, adding it to the deal's timeline", body: model.MeetingOutcome{}, resp: OutcomeRecorded{}, conflict: "The meeting, or the named occurrence of it, has not ended yet"})
+
+	// calendar
+	b.add("GET", "/calendar/google", "calendar", op{id: "getGoogleCalendar", summary: "Whether the signed-in user has connected a Google calendar", resp: CalendarStatus{}})
+	b.add("DELETE", "/calendar/google", "calendar", op{id: "disconnectGoogleCalendar", summary: "Forget the signed-in user's Google calendar"})
+	b.add("GET", "/calendar/google/connect", "calendar", op{id: "connectGoogleCalendar", summary: "Google consent page to send the user's browser to; sets the nonce cookie the callback checks, so call it with credentials", resp: CalendarAuthURL{}})
+	b.add("GET", "/calendar/google/callback", "calendar", op{id: "googleCalendarCallback", summary: "Google's OAuth redirect; stores the user's refresh token encrypted", public: true, query: []*Parameter{
+		{Name: "code", In: "query", Schema: &Schema{Type: "string"}},
+		{Name: "state", In: "query", Description: "Signed state from the consent page URL", Schema: &Schema{Type: "string"}},
+		{Name: "error", In: "query", Description: "Set by Google when the user declined", Schema: &Schema{Type: "string"}},
+	}, resp: CalendarConnected{}})
+	b.add("POST", "/calendar/feed", "calendar", op{id: "createCalendarFeed", summary: "Create the signed-in user's calendar feed, or rotate its secret URL", status: "201", resp: CalendarFeedURL{}})
+	b.add("GET", "/calendar/feed", "calendar", op{id: "getCalendarFeed", summary: "Whether the signed-in user has a calendar feed", resp: CalendarFeedStatus{}})
+	b.add("DELETE", "/calendar/feed", "calendar", op{id: "deleteCalendarFeed", summary: "Turn the signed-in user's calendar feed off"})
+	b.add("GET", "/calendar/{token}.ics", "calendar", op{id: "getCalendarFeedICS", summary: "A user's meetings and due tasks as an iCalendar feed to subscribe to", public: true, query: []*Parameter{
+		{Name: "token", In: "path", Required: true, Description: "Secret from the feed URL", Schema: &Schema{Type: "string"}},
+		{Name: "If-None-Match", In: "header", Description: "ETag of the feed the client has", Schema: &Schema{Type: "string"}},
+	}, media: "text/calendar", cached: true})
+
+	// booking
+	slug := []*Parameter{{Name: "slug", In: "path", Required: true, Description: "Slug of the booking link", Schema: &Schema{Type: "string"}}}
+	b.add("PUT", "/booking/availability", "booking", op{id: "setAvailability", summary: "Replace the weekly hours the investor takes booked meetings in", role: "investor", body: model.Availability{}, resp: AvailabilityResponse{}})
+	b.add("GET", "/booking/availability", "booking", op{id: "getAvailability", summary: "The investor's availability", role: "investor", resp: AvailabilityResponse{}})
+	b.add("POST", "/booking/links", "booking", op{id: "createBookingLink", summary: "Create a booking link founders in the investor's deal flow can book through", role: "investor", body: model.BookingLink{}, status: "201", resp: BookingLinkCreated{}})
+	b.add("GET", "/booking/links", "booking", op{id: "listBookingLinks", summary: "The investor's booking links, newest first", role: "investor", resp: BookingLinkList{}})
+	b.add("DELETE", "/booking/links/{id}", "booking", op{id: "deleteBookingLink", summary: "Remove a booking link; meetings booked through it are kept", role: "investor"})
+	b.add("GET", "/booking/{slug}/slots", "booking", op{id: "getBookingSlots", summary: "Free slots of a booking link, for founders in the investor's deal flow", query: slug, resp: BookingSlots{}})
+	b.add("POST", "/booking/{slug}", "booking", op{id: "bookSlot", summary: "Book a free slot: creates the meeting, its calendar event and the investor's notification, cancelling the event if the booking is not saved", query: slug, body: BookingRequest{}, status: "201", resp: MeetingScheduled{}, conflict: "The slot is no longer free"})
+
+	// search
+	b.add("GET", "/search", "search", op{id: "search", summary: "Search founders, investors, grants, deals and meetings visible to the caller. Deals match on their startup or on their own tasks, meetings, notes and documents; task results come from standalone tasks only", query: []*Parameter{
+		{Name: "q", In: "query", Required: true, Schema: &Schema{Type: "string"}},
+		{Name: "types", In: "query", Description: "Comma-separated entity types to search", Schema: &Schema{Type: "string"}},
+		{Name: "limit", In: "query", Schema: &Schema{Type: "integer", Format: "int32"}},
+	}, resp: SearchResponse{}})
+
+	// jobs
+	jobName := &Parameter{Name: "name", In: "path", Required: true, Description: "Job name: reminders, matching, valuations, deadlines or token_cleanup", Schema: &Schema{Type: "string"}}
+	b.add("GET", "/jobs", "jobs", op{id: "listJobs", summary: "Background jobs with their schedule, lease and last run", role: "admin", resp: JobList{}})
+	b.add("GET", "/jobs/{name}/runs", "jobs", op{id: "listJobRuns", summary: "Latest runs of a job, newest first; runs are kept for 30 days", role: "admin", query: []*Parameter{
+		jobName,
+		{Name: "limit", In: "query", Description: "At most 100, 20 by default", Schema: &Schema{Type: "integer", Format: "int32"}},
+	}, resp: JobRunList{}})
+	b.add("POST", "/jobs/{name}/run", "jobs", op{id: "triggerJob", summary: "Make a job due now, so the next replica to poll runs it", role: "admin", query: []*Parameter{jobName}, status: "202", resp: JobTriggered{}})
+
+	// events
+	b.add("GET", "/events", "events", op{id: "listEvents", summary: "Latest domain events in the outbox with their delivery status, newest first; delivered events are kept for 7 days", role: "admin", query: []*Parameter{
+		{Name: "status", In: "query", Description: "pending, delivered or failed", Schema: &Schema{Type: "string"}},
+		{Name: "limit", In: "query", Description: "At most 100, 20 by default", Schema: &Schema{Type: "integer", Format: "int32"}},
+	}, resp: EventList{}})
+	b.add("POST", "/events/{id}/retry", "events", op{id: "retryEvent", summary: "Deliver a failed event again to the subscribers that have not handled it", role: "admin", status: "202", resp: EventRetried{}})
+
+	return &Document{
+		OpenAPI: "3.1.0",
+		Info: Info{
+			Title:       "fundMe API",
+			Version:     "1.0.0",
+			Description: "Matchmaking, deal flow and grant management for founders and investors.",
+		},
+		Servers: []Server{{URL: BasePath}},
+		Tags: []Tag{
+			{Name: "health"}, {Name: "docs"}, {Name: "auth"}, {Name: "founder"}, {Name: "investor"},
+			{Name: "match"}, {Name: "dealflow"}, {Name: "grants"}, {Name: "tasks"}, {Name: "meetings"}, {Name: "calendar"}, {Name: "booking"}, {Name: "search"}, {Name: "jobs"}, {Name: "events"},
+		},
+		Paths: b.paths,
+		Components: Components{
+			Schemas: b.reg.Schemas(),
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+}
+
+// listParams documents the ?filter, ?sort, ?limit and ?cursor parameters of
+// a list endpoint, naming the fields spec allows
+func listParams(spec query.Spec) []*Parameter {
+	var fields, sortable []string
+	for name, f := range spec.Fields {
+		fields = append(fields, name)
+		if f.Sortable {
+			sortable = append(sortable, name)
+		}
+	}
+	sort.Strings(fields)
+	sort.Strings(sortable)
+
+	return []*Parameter{
+		{Name: "filter", In: "query", Schema: &Schema{Type: "string"},
+			Description: "Comma-separated field:op:value conditions on " + strings.Join(fields, ", ")},
+		{Name: "sort", In: "query", Schema: &Schema{Type: "string"},
+			Description: "Field to sort by, prefixed with - for descending: " + strings.Join(sortable, ", ")},
+		{Name: "limit", In: "query", Schema: &Schema{Type: "integer", Format: "int32"}},
+		{Name: "cursor", In: "query", Description: "next_cursor from the previous page", Schema: &Schema{Type: "string"}},
+	}
+}
+
+// startupParams documents the faceted startup discovery filters
+func startupParams() []*Parameter {
+	list := func(name string) *Parameter {
+		return &Parameter{Name: name, In: "query", Description: "Comma-separated values", Schema: &Schema{Type: "string"}}
+	}
+	number := func(name string) *Parameter {
+		return &Parameter{Name: name, In: "query", Schema: &Schema{Type: "integer", Format: "int32"}}
+	}
+	return []*Parameter{
+		list("industry"), list("funding_stage"), list("location"), list("team_size"),
+		number("fund_required_min"), number("fund_required_max"),
+		number("total_invested_min"), number("total_invested_max"),
+		number("limit"),
+		{Name: "sort", In: "query", Description: "Field to sort by, prefixed with - for descending", Schema: &Schema{Type: "string"}},
+		{Name: "cursor", In: "query", Schema: &Schema{Type: "string"}},
+	}
+}
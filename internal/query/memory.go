@@ -0,0 +1,308 @@
+package query
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Slice pages docs in memory with the same semantics as FindOptions and
+// Collect, so repository fakes can share list behavior with MongoDB.
+func Slice[T any](docs []bson.M, base bson.M, p Params) (Page[T], error) {
+	countFilter, match := p.CountFilter(base), p.Match(base)
+
+	var total int64
+	var hits []bson.M
+	for _, doc := range docs {
+		if Matches(doc, countFilter) {
+			total++
+		}
+		if Matches(doc, match) {
+			hits = append(hits, doc)
+		}
+	}
+
+	keys := p.Sort()
+	sort.SliceStable(hits, func(i, j int) bool {
+		for _, key := range keys {
+			c := Compare(Lookup(hits[i], key.Key), Lookup(hits[j], key.Key))
+			if c != 0 {
+				return (c < 0) == (key.Value.(int) > 0)
+			}
+		}
+		return false
+	})
+	if len(hits) > p.Limit+1 {
+		hits = hits[:p.Limit+1]
+	}
+
+	raw := make([]bson.Raw, 0, len(hits))
+	for _, doc := range hits {
+		b, err := bson.Marshal(doc)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		raw = append(raw, b)
+	}
+	return FromRaw[T](raw, p, total)
+}
+
+// Matches reports whether doc satisfies filter. It supports the operators
+// Params produces ($eq, $ne, $gt, $gte, $lt, $lte, $in, $nin, $regex, $and
+// and $or); array fields match when any element does, as in MongoDB.
+func Matches(doc bson.M, filter bson.M) bool {
+	for key, cond := range filter {
+		switch key {
+		case "$and":
+			for _, sub := range toSlice(cond) {
+				if f, ok := sub.(bson.M); ok && !Matches(doc, f) {
+					return false
+				}
+			}
+		case "$or":
+			matched := false
+			for _, sub := range toSlice(cond) {
+				if f, ok := sub.(bson.M); ok && Matches(doc, f) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		default:
+			if !matchField(Lookup(doc, key), cond) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func matchField(value, cond interface{}) bool {
+	ops, ok := cond.(bson.M)
+	if !ok || !isOperatorDoc(ops) {
+		return equals(value, cond)
+	}
+	for op, operand := range ops {
+		var ok bool
+		switch op {
+		case "$eq":
+			ok = equals(value, operand)
+		case "$ne":
+			ok = !equals(value, operand)
+		case "$in":
+			ok = in(value, operand)
+		case "$nin":
+			ok = !in(value, operand)
+		case "$gt", "$gte", "$lt", "$lte":
+			ok = anyElement(value, func(v interface{}) bool {
+				c, comparable := compare(v, operand)
+				if !comparable {
+					return false
+				}
+				switch op {
+				case "$gt":
+					return c > 0
+				case "$gte":
+					return c >= 0
+				case "$lt":
+					return c < 0
+				default:
+					return c <= 0
+				}
+			})
+		case "$regex":
+			ok = matchRegex(value, operand)
+		default:
+			return false
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func isOperatorDoc(m bson.M) bool {
+	for k := range m {
+		if !strings.HasPrefix(k, "$") {
+			return false
+		}
+	}
+	return len(m) > 0
+}
+
+func equals(value, operand interface{}) bool {
+	if value == nil || operand == nil {
+		return value == nil && operand == nil
+	}
+	return anyElement(value, func(v interface{}) bool {
+		c, ok := compare(v, operand)
+		return ok && c == 0
+	})
+}
+
+func in(value, operand interface{}) bool {
+	for _, candidate := range toSlice(operand) {
+		if equals(value, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchRegex(value, operand interface{}) bool {
+	var pattern string
+	switch r := operand.(type) {
+	case primitive.Regex:
+		pattern = r.Pattern
+		if strings.Contains(r.Options, "i") {
+			pattern = "(?i)" + pattern
+		}
+	case string:
+		pattern = r
+	default:
+		return false
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false
+	}
+	return anyElement(value, func(v interface{}) bool {
+		s, ok := v.(string)
+		return ok && re.MatchString(s)
+	})
+}
+
+// anyElement applies fn to value, or to each element when value is an array
+func anyElement(value interface{}, fn func(interface{}) bool) bool {
+	if arr, ok := value.(bson.A); ok {
+		for _, v := range arr {
+			if fn(v) {
+				return true
+			}
+		}
+		return false
+	}
+	return fn(value)
+}
+
+func toSlice(v interface{}) []interface{} {
+	switch s := v.(type) {
+	case bson.A:
+		return s
+	case []interface{}:
+		return s
+	case nil:
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []interface{}{v}
+	}
+	out := make([]interface{}, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out
+}
+
+// Lookup resolves a dotted path such as "startup.user_id" in doc
+func Lookup(doc bson.M, path string) interface{} {
+	var current interface{} = doc
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(bson.M)
+		if !ok {
+			return nil
+		}
+		current = m[part]
+	}
+	return current
+}
+
+// Compare orders two BSON values, sorting by type first (null, numbers,
+// strings, ObjectIDs, booleans, dates) and then by value
+func Compare(a, b interface{}) int {
+	if c, ok := compare(a, b); ok {
+		return c
+	}
+	ra, rb := rank(a), rank(b)
+	switch {
+	case ra < rb:
+		return -1
+	case ra > rb:
+		return 1
+	}
+	return 0
+}
+
+func compare(a, b interface{}) (int, bool) {
+	ka, va := normalize(a)
+	kb, vb := normalize(b)
+	if ka != kb || ka == unknownKind {
+		return 0, false
+	}
+	switch x := va.(type) {
+	case nil:
+		return 0, true
+	case float64:
+		return cmp(x < vb.(float64), x > vb.(float64)), true
+	case int64:
+		return cmp(x < vb.(int64), x > vb.(int64)), true
+	case string:
+		return strings.Compare(x, vb.(string)), true
+	case bool:
+		return cmp(!x && vb.(bool), x && !vb.(bool)), true
+	}
+	return 0, false
+}
+
+func cmp(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+func rank(v interface{}) int {
+	k, _ := normalize(v)
+	return k
+}
+
+// normalize maps equivalent BSON representations onto one comparable form
+func normalize(v interface{}) (int, interface{}) {
+	switch x := v.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return 1, float64(x)
+	case int32:
+		return 1, float64(x)
+	case int64:
+		return 1, float64(x)
+	case float64:
+		return 1, x
+	case string:
+		return 2, x
+	case primitive.ObjectID:
+		return 3, x.Hex()
+	case bool:
+		return 4, x
+	case time.Time:
+		return 5, x.UnixMilli()
+	case primitive.DateTime:
+		return 5, int64(x)
+	}
+	return unknownKind, nil
+}
+
+const unknownKind = 6
@@ -0,0 +1,118 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMatches(t *testing.T) {
+	owner := primitive.NewObjectID()
+	doc := bson.M{
+		"status":      "active",
+		"match_score": int32(80),
+		"tags":        bson.A{"fintech", "seed"},
+		"startup":     bson.M{"user_id": owner},
+		"created_at":  primitive.NewDateTimeFromTime(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)),
+	}
+	cases := map[string]struct {
+		filter bson.M
+		want   bool
+	}{
+		"equal":        {bson.M{"status": "active"}, true},
+		"not equal":    {bson.M{"status": bson.M{"$ne": "active"}}, false},
+		"number range": {bson.M{"match_score": bson.M{"$gte": 70.0, "$lt": 90}}, true},
+		"array member": {bson.M{"tags": "seed"}, true},
+		"in":           {bson.M{"status": bson.M{"$in": bson.A{"closed", "active"}}}, true},
+		"nin":          {bson.M{"tags": bson.M{"$nin": bson.A{"seed"}}}, false},
+		"dotted path":  {bson.M{"startup.user_id": owner}, true},
+		"regex":        {bson.M{"status": bson.M{"$regex": primitive.Regex{Pattern: "ACT", Options: "i"}}}, true},
+		"time":         {bson.M{"created_at": bson.M{"$gt": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}}, true},
+		"or":           {bson.M{"$or": bson.A{bson.M{"status": "closed"}, bson.M{"tags": "fintech"}}}, true},
+		"and":          {bson.M{"$and": bson.A{bson.M{"status": "active"}, bson.M{"tags": "series-a"}}}, false},
+		"missing":      {bson.M{"region": "EU"}, false},
+	}
+	for name, tc := range cases {
+		if got := Matches(doc, tc.filter); got != tc.want {
+			t.Errorf("%s: Matches() = %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestSlicePagesLikeCollect(t *testing.T) {
+	var docs []bson.M
+	for i := 0; i < 5; i++ {
+		docs = append(docs, bson.M{"_id": primitive.NewObjectID(), "owner": "a", "match_score": int32(i)})
+	}
+	docs = append(docs, bson.M{"_id": primitive.NewObjectID(), "owner": "b", "match_score": int32(9)})
+
+	p := Params{SortPath: "match_score", SortDesc: true, Limit: 2}
+	page, err := Slice[bson.M](docs, bson.M{"owner": "a"}, p)
+	if err != nil {
+		t.Fatalf("Slice() error = %v", err)
+	}
+	if page.Total != 5 || len(page.Items) != 2 || page.NextCursor == "" {
+		t.Fatalf("page = %d of %d, next=%q; want 2 of 5 with a cursor", len(page.Items), page.Total, page.NextCursor)
+	}
+	if page.Items[0]["match_score"] != int32(4) {
+		t.Errorf("first item score = %v, want 4", page.Items[0]["match_score"])
+	}
+
+	after, err := DecodeCursor(page.NextCursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	p.After = &after
+	next, err := Slice[bson.M](docs, bson.M{"owner": "a"}, p)
+	if err != nil {
+		t.Fatalf("Slice() error = %v", err)
+	}
+	if len(next.Items) != 2 || next.Items[0]["match_score"] != int32(2) {
+		t.Errorf("second page = %v, want scores 2 and 1", next.Items)
+	}
+}
+
+func TestSlicePagesThroughNulls(t *testing.T) {
+	due := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	docs := []bson.M{
+		{"_id": primitive.NewObjectID(), "due_date": due},
+		{"_id": primitive.NewObjectID(), "due_date": nil},
+		{"_id": primitive.NewObjectID(), "due_date": due.Add(time.Hour)},
+		{"_id": primitive.NewObjectID()},
+		{"_id": primitive.NewObjectID(), "due_date": due},
+		{"_id": primitive.NewObjectID(), "due_date": nil},
+	}
+	for _, desc := range []bool{false, true} {
+		p := Params{SortPath: "due_date", SortDesc: desc, Limit: 2}
+		seen := map[primitive.ObjectID]bool{}
+		for pages := 0; ; pages++ {
+			if pages > len(docs) {
+				t.Fatalf("desc=%v: paging did not end", desc)
+			}
+			page, err := Slice[bson.M](docs, nil, p)
+			if err != nil {
+				t.Fatalf("Slice() error = %v", err)
+			}
+			for _, item := range page.Items {
+				id := item["_id"].(primitive.ObjectID)
+				if seen[id] {
+					t.Errorf("desc=%v: %v listed twice", desc, item)
+				}
+				seen[id] = true
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			after, err := DecodeCursor(page.NextCursor)
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+			p.After = &after
+		}
+		if len(seen) != len(docs) {
+			t.Errorf("desc=%v: paged through %d of %d documents", desc, len(seen), len(docs))
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package query
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Page is the standard envelope returned by list endpoints
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int64  `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Collect drains a cursor opened with FindOptions or Stages into a page,
+// using the extra document (if any) to produce next_cursor.
+func Collect[T any](ctx context.Context, cur *mongo.Cursor, p Params, total int64) (Page[T], error) {
+	defer cur.Close(ctx)
+
+	var raw []bson.Raw
+	if err := cur.All(ctx, &raw); err != nil {
+		return Page[T]{}, err
+	}
+	return FromRaw[T](raw, p, total)
+}
+
+// FromRaw decodes up to p.Limit documents into a page
+func FromRaw[T any](raw []bson.Raw, p Params, total int64) (Page[T], error) {
+	page := Page[T]{Items: make([]T, 0, len(raw)), Total: total}
+	for i, doc := range raw {
+		if i == p.Limit {
+			next, err := EncodeCursor(CursorAfter(raw[i-1], p.SortPath))
+			if err != nil {
+				return Page[T]{}, err
+			}
+			page.NextCursor = next
+			break
+		}
+		var item T
+		if err := bson.Unmarshal(doc, &item); err != nil {
+			return Page[T]{}, err
+		}
+		page.Items = append(page.Items, item)
+	}
+	return page, nil
+}
@@ -0,0 +1,351 @@
+// Package query parses list endpoint parameters (?filter, ?sort, ?limit and
+// ?cursor) into whitelisted MongoDB filters and builds paginated responses.
+//
+// Filters are comma-separated conditions of the form field:op:value, where op
+// is one of eq, ne, gt, gte, lt, lte, in, nin or contains. The op may be
+// omitted for equality, and in/nin take |-separated values:
+//
+//	?filter=status:eq:active,priority:in:high|medium&sort=-created_at&limit=20
+//
+// Only fields declared in a Spec can be filtered or sorted on, and values are
+// converted to the declared type, so user input never becomes a BSON key.
+package query
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// FieldType controls how filter values are converted before querying
+type FieldType int
+
+const (
+	String FieldType = iota
+	Number
+	Bool
+	Time
+	ObjectID
+)
+
+// Field declares a queryable field and the BSON path it maps to
+type Field struct {
+	Path     string
+	Type     FieldType
+	Sortable bool
+}
+
+// Spec whitelists the fields of a collection endpoint
+type Spec struct {
+	Fields       map[string]Field
+	DefaultSort  string
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// Params is the parsed, validated form of a list request
+type Params struct {
+	Filter   bson.M
+	SortPath string
+	SortDesc bool
+	Limit    int
+	After    *Cursor
+}
+
+// Cursor is the keyset position of the last item on a page
+type Cursor struct {
+	Value interface{}        `bson:"v"`
+	ID    primitive.ObjectID `bson:"id"`
+}
+
+// Error describes an invalid query parameter
+type Error struct {
+	Param   string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Param, e.Message)
+}
+
+var operators = map[string]string{
+	"eq":       "$eq",
+	"ne":       "$ne",
+	"gt":       "$gt",
+	"gte":      "$gte",
+	"lt":       "$lt",
+	"lte":      "$lte",
+	"in":       "$in",
+	"nin":      "$nin",
+	"contains": "$regex",
+}
+
+// Parse reads filter, sort, limit and cursor using get (typically a request's
+// query string lookup) and validates them against spec.
+func Parse(get func(key string) string, spec Spec) (Params, error) {
+	p := Params{Filter: bson.M{}}
+
+	if raw := get("filter"); raw != "" {
+		if err := parseFilter(raw, spec, p.Filter); err != nil {
+			return p, err
+		}
+	}
+
+	sortBy := get("sort")
+	if sortBy == "" {
+		sortBy = spec.DefaultSort
+	}
+	if sortBy != "" {
+		desc := strings.HasPrefix(sortBy, "-")
+		field, ok := spec.Fields[strings.TrimPrefix(sortBy, "-")]
+		if !ok || !field.Sortable {
+			return p, &Error{Param: "sort", Message: fmt.Sprintf("cannot sort by %q", strings.TrimPrefix(sortBy, "-"))}
+		}
+		p.SortPath = field.Path
+		p.SortDesc = desc
+	} else {
+		p.SortPath = "_id"
+		p.SortDesc = true
+	}
+
+	p.Limit = spec.DefaultLimit
+	if p.Limit <= 0 {
+		p.Limit = defaultLimit
+	}
+	if raw := get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return p, &Error{Param: "limit", Message: "must be a positive integer"}
+		}
+		p.Limit = limit
+	}
+	max := spec.MaxLimit
+	if max <= 0 {
+		max = maxLimit
+	}
+	if p.Limit > max {
+		p.Limit = max
+	}
+
+	if raw := get("cursor"); raw != "" {
+		after, err := DecodeCursor(raw)
+		if err != nil {
+			return p, err
+		}
+		p.After = &after
+	}
+
+	return p, nil
+}
+
+func parseFilter(raw string, spec Spec, filter bson.M) error {
+	for _, cond := range strings.Split(raw, ",") {
+		cond = strings.TrimSpace(cond)
+		if cond == "" {
+			continue
+		}
+
+		parts := strings.SplitN(cond, ":", 3)
+		name, op, value := parts[0], "eq", ""
+		switch len(parts) {
+		case 2:
+			value = parts[1]
+		case 3:
+			op, value = parts[1], parts[2]
+		default:
+			return &Error{Param: "filter", Message: fmt.Sprintf("%q is not field:op:value", cond)}
+		}
+
+		field, ok := spec.Fields[name]
+		if !ok {
+			return &Error{Param: "filter", Message: fmt.Sprintf("cannot filter on %q", name)}
+		}
+		mongoOp, ok := operators[op]
+		if !ok {
+			return &Error{Param: "filter", Message: fmt.Sprintf("unknown operator %q", op)}
+		}
+
+		var operand interface{}
+		switch op {
+		case "in", "nin":
+			values := bson.A{}
+			for _, v := range strings.Split(value, "|") {
+				converted, err := convert(field.Type, v)
+				if err != nil {
+					return &Error{Param: "filter", Message: fmt.Sprintf("%s: %v", name, err)}
+				}
+				values = append(values, converted)
+			}
+			operand = values
+		case "contains":
+			if field.Type != String {
+				return &Error{Param: "filter", Message: fmt.Sprintf("contains is only supported on text fields, not %q", name)}
+			}
+			operand = primitive.Regex{Pattern: regexp.QuoteMeta(value), Options: "i"}
+		default:
+			converted, err := convert(field.Type, value)
+			if err != nil {
+				return &Error{Param: "filter", Message: fmt.Sprintf("%s: %v", name, err)}
+			}
+			operand = converted
+		}
+
+		conds, _ := filter[field.Path].(bson.M)
+		if conds == nil {
+			conds = bson.M{}
+		}
+		conds[mongoOp] = operand
+		filter[field.Path] = conds
+	}
+	return nil
+}
+
+func convert(t FieldType, value string) (interface{}, error) {
+	switch t {
+	case Number:
+		return strconv.ParseFloat(value, 64)
+	case Bool:
+		return strconv.ParseBool(value)
+	case Time:
+		if ts, err := time.Parse(time.RFC3339, value); err == nil {
+			return ts, nil
+		}
+		return time.Parse("2006-01-02", value)
+	case ObjectID:
+		return primitive.ObjectIDFromHex(value)
+	default:
+		return value, nil
+	}
+}
+
+// CountFilter combines base with the user's filter, ignoring the cursor, for totals
+func (p Params) CountFilter(base bson.M) bson.M {
+	return and(base, p.Filter)
+}
+
+// Match combines base, the user's filter and the keyset position of the cursor
+func (p Params) Match(base bson.M) bson.M {
+	if p.After == nil {
+		return p.CountFilter(base)
+	}
+	op := "$gt"
+	if p.SortDesc {
+		op = "$lt"
+	}
+	keyset := bson.M{"_id": bson.M{op: p.After.ID}}
+	if p.SortPath != "_id" {
+		keyset = p.keyset(op)
+	}
+	return and(base, p.Filter, keyset)
+}
+
+// keyset matches the documents that sort after the cursor. Null and missing
+// values sort before all others, but range operators never match them, so
+// they are matched on their own.
+func (p Params) keyset(op string) bson.M {
+	tie := bson.M{p.SortPath: p.After.Value, "_id": bson.M{op: p.After.ID}}
+	if p.After.Value == nil {
+		if p.SortDesc {
+			return tie
+		}
+		return bson.M{"$or": bson.A{tie, bson.M{p.SortPath: bson.M{"$ne": nil}}}}
+	}
+	after := bson.A{bson.M{p.SortPath: bson.M{op: p.After.Value}}, tie}
+	if p.SortDesc {
+		after = append(after, bson.M{p.SortPath: nil})
+	}
+	return bson.M{"$or": after}
+}
+
+// Sort returns the sort document, always tie-broken on _id
+func (p Params) Sort() bson.D {
+	order := 1
+	if p.SortDesc {
+		order = -1
+	}
+	if p.SortPath == "_id" {
+		return bson.D{{Key: "_id", Value: order}}
+	}
+	return bson.D{{Key: p.SortPath, Value: order}, {Key: "_id", Value: order}}
+}
+
+// FindOptions sorts and fetches one extra document to detect a next page
+func (p Params) FindOptions() *options.FindOptions {
+	return options.Find().SetSort(p.Sort()).SetLimit(int64(p.Limit + 1))
+}
+
+// Stages returns the $match, $sort and $limit aggregation stages for a page
+func (p Params) Stages(base bson.M) []bson.D {
+	return []bson.D{
+		{{Key: "$match", Value: p.Match(base)}},
+		{{Key: "$sort", Value: p.Sort()}},
+		{{Key: "$limit", Value: p.Limit + 1}},
+	}
+}
+
+func and(filters ...bson.M) bson.M {
+	var nonEmpty bson.A
+	for _, f := range filters {
+		if len(f) > 0 {
+			nonEmpty = append(nonEmpty, f)
+		}
+	}
+	switch len(nonEmpty) {
+	case 0:
+		return bson.M{}
+	case 1:
+		return nonEmpty[0].(bson.M)
+	default:
+		return bson.M{"$and": nonEmpty}
+	}
+}
+
+// CursorAfter builds the cursor pointing past doc for the given sort path
+func CursorAfter(doc bson.Raw, sortPath string) Cursor {
+	var c Cursor
+	c.ID, _ = doc.Lookup("_id").ObjectIDOK()
+	if sortPath != "_id" {
+		if err := doc.Lookup(strings.Split(sortPath, ".")...).Unmarshal(&c.Value); err != nil {
+			c.Value = nil
+		}
+	}
+	return c
+}
+
+// EncodeCursor serializes a cursor into an opaque URL-safe string
+func EncodeCursor(c Cursor) (string, error) {
+	b, err := bson.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := bson.Unmarshal(b, &c); err != nil || c.ID.IsZero() {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
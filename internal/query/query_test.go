@@ -0,0 +1,103 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var testSpec = Spec{
+	DefaultSort: "-created_at",
+	Fields: map[string]Field{
+		"status":     {Path: "status", Type: String, Sortable: true},
+		"score":      {Path: "match_score", Type: Number, Sortable: true},
+		"read":       {Path: "read_status", Type: Bool},
+		"created_at": {Path: "created_at", Type: Time, Sortable: true},
+	},
+}
+
+func getter(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func TestParse(t *testing.T) {
+	p, err := Parse(getter(map[string]string{
+		"filter": "status:in:active|pending,score:gte:70,read:false",
+		"sort":   "score",
+		"limit":  "500",
+	}), testSpec)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if p.SortPath != "match_score" || p.SortDesc {
+		t.Errorf("sort = %s desc=%v, want match_score asc", p.SortPath, p.SortDesc)
+	}
+	if p.Limit != maxLimit {
+		t.Errorf("limit = %d, want %d", p.Limit, maxLimit)
+	}
+	if got := p.Filter["match_score"].(bson.M)["$gte"]; got != 70.0 {
+		t.Errorf("match_score $gte = %v, want 70", got)
+	}
+	if got := p.Filter["read_status"].(bson.M)["$eq"]; got != false {
+		t.Errorf("read_status $eq = %v, want false", got)
+	}
+	if got := p.Filter["status"].(bson.M)["$in"].(bson.A); len(got) != 2 {
+		t.Errorf("status $in = %v, want 2 values", got)
+	}
+}
+
+func TestParseRejectsInvalidInput(t *testing.T) {
+	cases := map[string]map[string]string{
+		"unknown field":    {"filter": "password:eq:x"},
+		"unknown operator": {"filter": "status:where:x"},
+		"bad number":       {"filter": "score:gt:high"},
+		"contains number":  {"filter": "score:contains:1"},
+		"unsortable field": {"sort": "read"},
+		"bad limit":        {"limit": "-1"},
+		"bad cursor":       {"cursor": "not-a-cursor"},
+	}
+	for name, values := range cases {
+		if _, err := Parse(getter(values), testSpec); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	doc, _ := bson.Marshal(bson.M{"_id": id, "created_at": created})
+
+	encoded, err := EncodeCursor(CursorAfter(doc, "created_at"))
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+	p, err := Parse(getter(map[string]string{"cursor": encoded}), testSpec)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if p.After == nil || p.After.ID != id {
+		t.Fatalf("cursor id = %v, want %v", p.After, id)
+	}
+	if _, ok := p.Match(nil)["$or"]; !ok {
+		t.Errorf("Match() = %v, want keyset $or", p.Match(nil))
+	}
+}
+
+func TestFromRawSetsNextCursor(t *testing.T) {
+	p := Params{SortPath: "_id", SortDesc: true, Limit: 2}
+	var raw []bson.Raw
+	for i := 0; i < 3; i++ {
+		doc, _ := bson.Marshal(bson.M{"_id": primitive.NewObjectID()})
+		raw = append(raw, doc)
+	}
+	page, err := FromRaw[bson.M](raw, p, 3)
+	if err != nil {
+		t.Fatalf("FromRaw() error = %v", err)
+	}
+	if len(page.Items) != 2 || page.NextCursor == "" {
+		t.Errorf("page = %d items, next=%q; want 2 items and a cursor", len(page.Items), page.NextCursor)
+	}
+}
@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"DBackend/internal/config"
+)
+
+var _ Store = (*MemoryStore)(nil)
+
+// sweepInterval is how often MemoryStore drops state that has expired
+const sweepInterval = time.Minute
+
+// MemoryStore keeps limits in process. Each API instance counts on its own,
+// so use the MongoDB store when running more than one.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lockouts  map[string]*lockout
+	nextSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	expires time.Time // when the bucket is full again and can be forgotten
+}
+
+type lockout struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+	expires     time.Time
+}
+
+// NewMemoryStore returns an empty in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}, lockouts: map[string]*lockout{}}
+}
+
+// Take implements Store
+func (s *MemoryStore) Take(_ context.Context, key string, b config.Bucket, now time.Time) (Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	st, ok := s.buckets[key]
+	if !ok {
+		st = &bucket{tokens: float64(b.Requests), updated: now}
+		s.buckets[key] = st
+	}
+	st.tokens = Refill(st.tokens, now.Sub(st.updated), b)
+	allowed := st.tokens >= 1
+	if allowed {
+		st.tokens--
+	}
+	st.updated = now
+	st.expires = now.Add(b.Period)
+	return NewDecision(allowed, st.tokens, b), nil
+}
+
+// Fail implements Store. Failures are forgotten once Window passes after the
+// last failure or the end of the lock, whichever is later.
+func (s *MemoryStore) Fail(_ context.Context, key string, l config.Lockout, now time.Time) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	st, ok := s.lockouts[key]
+	if !ok || now.Sub(latest(st.lastFailure, st.lockedUntil)) > l.Window {
+		st = &lockout{}
+		s.lockouts[key] = st
+	}
+	st.failures++
+	st.lastFailure = now
+	if d := LockDuration(st.failures, l); d > 0 {
+		st.lockedUntil = latest(st.lockedUntil, now.Add(d))
+	}
+	st.expires = latest(st.lockedUntil, now).Add(l.Window)
+	return st.lockedUntil, nil
+}
+
+// LockedUntil implements Store
+func (s *MemoryStore) LockedUntil(_ context.Context, key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st, ok := s.lockouts[key]; ok {
+		return st.lockedUntil, nil
+	}
+	return time.Time{}, nil
+}
+
+// Reset implements Store
+func (s *MemoryStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.lockouts, key)
+	return nil
+}
+
+// sweep drops expired state at most once per sweepInterval; s.mu must be held
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(sweepInterval)
+	for key, st := range s.buckets {
+		if now.After(st.expires) {
+			delete(s.buckets, key)
+		}
+	}
+	for key, st := range s.lockouts {
+		if now.After(st.expires) {
+			delete(s.lockouts, key)
+		}
+	}
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
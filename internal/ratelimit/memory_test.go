@@ -0,0 +1,14 @@
+package ratelimit_test
+
+import (
+	"testing"
+
+	"DBackend/internal/ratelimit"
+	"DBackend/internal/ratelimit/storetest"
+)
+
+func TestMemoryStoreContract(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) ratelimit.Store {
+		return ratelimit.NewMemoryStore()
+	})
+}
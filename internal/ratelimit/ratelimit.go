@@ -0,0 +1,202 @@
+// Package ratelimit throttles clients with token buckets and locks accounts
+// after repeated failed logins. Every route group has a bucket per client IP
+// and one per account, sized by config.RateLimit:
+//
+//	if wait, ok := limiter.Allow(ctx, ratelimit.Login, c.IP(), email); !ok {
+//		return apperror.TooManyRequests("rate_limited", "Too many requests", wait)
+//	}
+//
+// State lives in a Store. MemoryStore keeps it in process; the database
+// package provides a MongoDB store that several API instances can share.
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/metrics"
+)
+
+// Group names a set of routes sharing one config.RateLimitRule
+type Group string
+
+const (
+	Login    Group = "login"
+	Register Group = "register"
+	Writes   Group = "writes"
+)
+
+// Decision is the outcome of taking a token from a bucket
+type Decision struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until a token is available, when not allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps bucket and lockout state by key. Implementations must be safe
+// for concurrent use and update each key atomically.
+type Store interface {
+	// Take removes one token from the bucket at key, which refills at
+	// b.Requests per b.Period and holds at most b.Requests
+	Take(ctx context.Context, key string, b config.Bucket, now time.Time) (Decision, error)
+	// Fail records a failed attempt on key and returns when key is locked
+	// until, which is in the past when it is not locked
+	Fail(ctx context.Context, key string, l config.Lockout, now time.Time) (time.Time, error)
+	// LockedUntil returns when key's lock ends, the zero time if it has none
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+	// Reset forgets the failures recorded on key
+	Reset(ctx context.Context, key string) error
+}
+
+// NewDecision describes a bucket left with tokens after a Take
+func NewDecision(allowed bool, tokens float64, b config.Bucket) Decision {
+	d := Decision{Allowed: allowed, Remaining: int(math.Max(tokens, 0))}
+	if !allowed {
+		d.RetryAfter = time.Duration((1 - tokens) / float64(b.Requests) * float64(b.Period))
+	}
+	return d
+}
+
+// Refill returns the tokens in a bucket that held tokens elapsed ago
+func Refill(tokens float64, elapsed time.Duration, b config.Bucket) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(b.Requests), tokens+float64(b.Requests)*elapsed.Seconds()/b.Period.Seconds())
+}
+
+// LockDuration returns how long an account with failures failed logins is
+// locked: l.Base at l.Threshold, doubling with each further failure up to l.Max
+func LockDuration(failures int, l config.Lockout) time.Duration {
+	if l.Threshold <= 0 || failures < l.Threshold {
+		return 0
+	}
+	d := l.Base
+	for i := l.Threshold; i < failures && d < l.Max; i++ {
+		d *= 2
+	}
+	if d > l.Max {
+		d = l.Max
+	}
+	return d
+}
+
+// Limiter applies the configured limits over a Store. Store failures are
+// logged and let requests through, so an outage of a shared store does not
+// take the API down with it.
+type Limiter struct {
+	cfg   config.RateLimit
+	store Store
+	now   func() time.Time
+}
+
+// New returns a limiter for cfg keeping its state in store
+func New(cfg config.RateLimit, store Store) *Limiter {
+	return &Limiter{cfg: cfg, store: store, now: time.Now}
+}
+
+func (l *Limiter) rule(group Group) config.RateLimitRule {
+	switch group {
+	case Login:
+		return l.cfg.Login
+	case Register:
+		return l.cfg.Register
+	default:
+		return l.cfg.Writes
+	}
+}
+
+// Allow takes a token from the group's bucket for ip and, when account is
+// not empty, from the account's. It returns false and how long to wait when
+// either bucket is empty.
+func (l *Limiter) Allow(ctx context.Context, group Group, ip, account string) (time.Duration, bool) {
+	if !l.cfg.Enabled {
+		return 0, true
+	}
+	rule := l.rule(group)
+	if wait, ok := l.take(ctx, group, "ip", ip, rule.PerIP); !ok {
+		return wait, false
+	}
+	if account == "" {
+		return 0, true
+	}
+	return l.take(ctx, group, "account", account, rule.PerAccount)
+}
+
+func (l *Limiter) take(ctx context.Context, group Group, scope, id string, b config.Bucket) (time.Duration, bool) {
+	if b.Requests == 0 {
+		return 0, true
+	}
+	d, err := l.store.Take(ctx, string(group)+":"+scope+":"+id, b, l.now())
+	if err != nil {
+		slog.WarnContext(ctx, "rate limit store failed", "group", group, "error", err)
+		return 0, true
+	}
+	if !d.Allowed {
+		metrics.RateLimited.WithLabelValues(string(group), scope).Inc()
+	}
+	return d.RetryAfter, d.Allowed
+}
+
+// Locked returns how much longer account is locked out of logging in, zero
+// when it is not
+func (l *Limiter) Locked(ctx context.Context, account string) time.Duration {
+	if !l.lockoutEnabled() {
+		return 0
+	}
+	until, err := l.store.LockedUntil(ctx, lockoutKey(account))
+	if err != nil {
+		slog.WarnContext(ctx, "rate limit store failed", "error", err)
+		return 0
+	}
+	return positive(until.Sub(l.now()))
+}
+
+// LoginFailed records a failed login on account and returns how long the
+// account is now locked, zero when it is not
+func (l *Limiter) LoginFailed(ctx context.Context, account string) time.Duration {
+	if !l.lockoutEnabled() {
+		return 0
+	}
+	now := l.now()
+	until, err := l.store.Fail(ctx, lockoutKey(account), l.cfg.Lockout, now)
+	if err != nil {
+		slog.WarnContext(ctx, "rate limit store failed", "error", err)
+		return 0
+	}
+	wait := positive(until.Sub(now))
+	if wait > 0 {
+		metrics.LoginLockouts.Inc()
+		slog.WarnContext(ctx, "account locked after failed logins", "locked_for", wait.String())
+	}
+	return wait
+}
+
+// LoginSucceeded clears the failed logins recorded on account
+func (l *Limiter) LoginSucceeded(ctx context.Context, account string) {
+	if !l.lockoutEnabled() {
+		return
+	}
+	if err := l.store.Reset(ctx, lockoutKey(account)); err != nil {
+		slog.WarnContext(ctx, "rate limit store failed", "error", err)
+	}
+}
+
+func (l *Limiter) lockoutEnabled() bool {
+	return l.cfg.Enabled && l.cfg.Lockout.Threshold > 0
+}
+
+func lockoutKey(account string) string {
+	return "lockout:" + account
+}
+
+func positive(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
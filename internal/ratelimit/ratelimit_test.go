@@ -0,0 +1,150 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"DBackend/internal/config"
+)
+
+// clock is a settable time source for the limiter
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newLimiter(cfg config.RateLimit) (*Limiter, *clock) {
+	clk := &clock{now: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)}
+	l := New(cfg, NewMemoryStore())
+	l.now = func() time.Time { return clk.now }
+	return l, clk
+}
+
+func TestBucketRefillsAtConfiguredRate(t *testing.T) {
+	l, clk := newLimiter(config.RateLimit{
+		Enabled: true,
+		Writes:  config.RateLimitRule{PerIP: config.Bucket{Requests: 2, Period: time.Minute}},
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, ok := l.Allow(ctx, Writes, "10.0.0.1", ""); !ok {
+			t.Fatalf("request %d was limited", i+1)
+		}
+	}
+	wait, ok := l.Allow(ctx, Writes, "10.0.0.1", "")
+	if ok || wait != 30*time.Second {
+		t.Fatalf("third request: ok = %v, wait = %v, want limited for 30s", ok, wait)
+	}
+	if _, ok := l.Allow(ctx, Writes, "10.0.0.2", ""); !ok {
+		t.Errorf("another IP was limited")
+	}
+
+	clk.advance(30 * time.Second)
+	if _, ok := l.Allow(ctx, Writes, "10.0.0.1", ""); !ok {
+		t.Errorf("request after refill was limited")
+	}
+}
+
+func TestAccountBucketSpansIPs(t *testing.T) {
+	l, _ := newLimiter(config.RateLimit{
+		Enabled: true,
+		Login: config.RateLimitRule{
+			PerIP:      config.Bucket{Requests: 10, Period: time.Minute},
+			PerAccount: config.Bucket{Requests: 2, Period: time.Minute},
+		},
+	})
+	ctx := context.Background()
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+		if _, ok := l.Allow(ctx, Login, ip, "ada@example.com"); !ok {
+			t.Fatalf("login from %s was limited", ip)
+		}
+	}
+	if _, ok := l.Allow(ctx, Login, "10.0.0.3", "ada@example.com"); ok {
+		t.Errorf("third login for the account was allowed from a new IP")
+	}
+	if _, ok := l.Allow(ctx, Login, "10.0.0.3", "grace@example.com"); !ok {
+		t.Errorf("another account was limited")
+	}
+}
+
+func TestLockoutIsProgressive(t *testing.T) {
+	l, clk := newLimiter(config.RateLimit{
+		Enabled: true,
+		Lockout: config.Lockout{Threshold: 3, Window: 15 * time.Minute, Base: time.Minute, Max: 4 * time.Minute},
+	})
+	ctx := context.Background()
+	const account = "ada@example.com"
+
+	for i := 0; i < 2; i++ {
+		if wait := l.LoginFailed(ctx, account); wait != 0 {
+			t.Fatalf("failure %d locked the account for %v", i+1, wait)
+		}
+	}
+	for _, want := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 4 * time.Minute} {
+		if wait := l.LoginFailed(ctx, account); wait != want {
+			t.Fatalf("lock = %v, want %v", wait, want)
+		}
+		if locked := l.Locked(ctx, account); locked != want {
+			t.Fatalf("Locked() = %v, want %v", locked, want)
+		}
+		clk.advance(want)
+		if locked := l.Locked(ctx, account); locked != 0 {
+			t.Fatalf("Locked() after the lock = %v, want 0", locked)
+		}
+	}
+
+	l.LoginSucceeded(ctx, account)
+	if wait := l.LoginFailed(ctx, account); wait != 0 {
+		t.Errorf("failure after a successful login locked the account for %v", wait)
+	}
+}
+
+func TestLockoutForgetsFailuresAfterWindow(t *testing.T) {
+	l, clk := newLimiter(config.RateLimit{
+		Enabled: true,
+		Lockout: config.Lockout{Threshold: 2, Window: 10 * time.Minute, Base: time.Minute, Max: time.Hour},
+	})
+	ctx := context.Background()
+	l.LoginFailed(ctx, "ada@example.com")
+	clk.advance(11 * time.Minute)
+	if wait := l.LoginFailed(ctx, "ada@example.com"); wait != 0 {
+		t.Errorf("failures outside the window locked the account for %v", wait)
+	}
+}
+
+func TestDisabledLimiterAllowsEverything(t *testing.T) {
+	l, _ := newLimiter(config.RateLimit{
+		Writes:  config.RateLimitRule{PerIP: config.Bucket{Requests: 1, Period: time.Hour}},
+		Lockout: config.Lockout{Threshold: 1, Window: time.Hour, Base: time.Hour, Max: time.Hour},
+	})
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, ok := l.Allow(ctx, Writes, "10.0.0.1", ""); !ok {
+			t.Fatalf("disabled limiter limited request %d", i+1)
+		}
+	}
+	if wait := l.LoginFailed(ctx, "ada@example.com"); wait != 0 {
+		t.Errorf("disabled limiter locked an account for %v", wait)
+	}
+}
+
+func TestMemoryStoreSweepsExpiredState(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+	b := config.Bucket{Requests: 1, Period: time.Second}
+	if _, err := s.Take(ctx, "old", b, now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Take(ctx, "new", b, now.Add(2*sweepInterval)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.buckets["old"]; ok || len(s.buckets) != 1 {
+		t.Errorf("buckets = %v, want only the new one", s.buckets)
+	}
+}
@@ -0,0 +1,92 @@
+// Package storetest pins the behavior every ratelimit.Store must share. Each
+// implementation runs the same suite from its own tests so the MongoDB store
+// and the in-memory store cannot drift apart.
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/ratelimit"
+)
+
+// start is a fixed instant with millisecond precision, which MongoDB keeps
+var start = time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// Run executes the store contract against stores built by newStore
+func Run(t *testing.T, newStore func(t *testing.T) ratelimit.Store) {
+	t.Run("Take", func(t *testing.T) {
+		s := newStore(t)
+		ctx := context.Background()
+		b := config.Bucket{Requests: 2, Period: time.Minute}
+
+		for i, want := range []int{1, 0} {
+			d, err := s.Take(ctx, "ip", b, start)
+			if err != nil {
+				t.Fatalf("Take() error = %v", err)
+			}
+			if !d.Allowed || d.Remaining != want {
+				t.Fatalf("Take() %d = %+v, want allowed with %d left", i+1, d, want)
+			}
+		}
+		d, err := s.Take(ctx, "ip", b, start.Add(15*time.Second))
+		if err != nil {
+			t.Fatalf("Take() error = %v", err)
+		}
+		if d.Allowed || d.RetryAfter != 15*time.Second {
+			t.Errorf("Take() on an empty bucket = %+v, want denied for 15s", d)
+		}
+		if d, _ := s.Take(ctx, "other", b, start); !d.Allowed {
+			t.Errorf("Take() on another key = %+v, want allowed", d)
+		}
+		if d, _ := s.Take(ctx, "ip", b, start.Add(time.Minute)); !d.Allowed {
+			t.Errorf("Take() after refill = %+v, want allowed", d)
+		}
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		s := newStore(t)
+		ctx := context.Background()
+		l := config.Lockout{Threshold: 2, Window: 10 * time.Minute, Base: time.Minute, Max: time.Hour}
+
+		if until, err := s.LockedUntil(ctx, "ada"); err != nil || !until.IsZero() {
+			t.Fatalf("LockedUntil() of a new key = %v, %v; want zero", until, err)
+		}
+		if until, err := s.Fail(ctx, "ada", l, start); err != nil || until.After(start) {
+			t.Fatalf("first Fail() = %v, %v; want no lock", until, err)
+		}
+		until, err := s.Fail(ctx, "ada", l, start.Add(time.Second))
+		if err != nil || !until.Equal(start.Add(time.Second+time.Minute)) {
+			t.Fatalf("second Fail() = %v, %v; want locked for a minute", until, err)
+		}
+		if got, _ := s.LockedUntil(ctx, "ada"); !got.Equal(until) {
+			t.Errorf("LockedUntil() = %v, want %v", got, until)
+		}
+		next, err := s.Fail(ctx, "ada", l, until)
+		if err != nil || !next.Equal(until.Add(2*time.Minute)) {
+			t.Errorf("third Fail() = %v, %v; want locked for two minutes", next, err)
+		}
+
+		if err := s.Reset(ctx, "ada"); err != nil {
+			t.Fatalf("Reset() error = %v", err)
+		}
+		if got, _ := s.LockedUntil(ctx, "ada"); !got.IsZero() {
+			t.Errorf("LockedUntil() after Reset() = %v, want zero", got)
+		}
+	})
+
+	t.Run("FailForgetsOldFailures", func(t *testing.T) {
+		s := newStore(t)
+		ctx := context.Background()
+		l := config.Lockout{Threshold: 2, Window: 10 * time.Minute, Base: time.Minute, Max: time.Hour}
+
+		if _, err := s.Fail(ctx, "ada", l, start); err != nil {
+			t.Fatalf("Fail() error = %v", err)
+		}
+		if until, err := s.Fail(ctx, "ada", l, start.Add(11*time.Minute)); err != nil || until.After(start.Add(11*time.Minute)) {
+			t.Errorf("Fail() after the window = %v, %v; want no lock", until, err)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/events"
+	"DBackend/internal/jobs"
+	"DBackend/internal/mail"
+	"DBackend/internal/server/services"
+)
+
+// RunJobs runs the background jobs until ctx is done, then waits for the
+// runs in progress to stop. It returns at once when jobs are disabled. A job
+// whose schedule is off is not registered, and neither are reminders while
+// they are disabled.
+func (s *FiberServer) RunJobs(ctx context.Context) {
+	if !s.cfg.Jobs.Enabled {
+		return
+	}
+	sender := mail.New(s.cfg.Mail)
+	run := map[string]jobs.Func{
+		"reminders":  services.NewReminders(s.db, sender, s.cfg.Reminders).Run,
+		"matching":   services.NewMatcher(s.db, s.cfg.ML).Run,
+		"valuations": services.NewValuations(s.db).Run,
+		"deadlines":  services.NewDeadlines(s.db, sender).Run,
+		"token_cleanup": func(ctx context.Context) error {
+			removed, err := s.db.Users().DeleteExpiredTokens(ctx, time.Now())
+			if removed > 0 {
+				slog.InfoContext(ctx, "removed expired blacklisted tokens", "count", removed)
+			}
+			return err
+		},
+	}
+
+	scheduler := jobs.New(s.db, s.cfg.Jobs)
+	for name, spec := range s.cfg.Jobs.Schedules.Each() {
+		if spec == config.JobsOff || name == "reminders" && !s.cfg.Reminders.Enabled {
+			continue
+		}
+		if err := scheduler.Register(name, spec, run[name]); err != nil {
+			slog.ErrorContext(ctx, "registering job failed", "job", name, "error", err)
+		}
+	}
+	scheduler.Run(ctx)
+}
+
+// RunEvents delivers domain events from the outbox to the API's subscribers
+// until ctx is done, finishing the delivery in progress. It returns at once
+// when delivery is disabled, leaving the events to other replicas.
+func (s *FiberServer) RunEvents(ctx context.Context) {
+	if !s.cfg.Events.Enabled {
+		return
+	}
+	bus := events.New(s.db, s.cfg.Events)
+	if err := services.Subscribe(bus, s.db); err != nil {
+		slog.ErrorContext(ctx, "subscribing to events failed", "error", err)
+		return
+	}
+	bus.Run(ctx)
+}
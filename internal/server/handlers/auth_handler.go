@@ -1,7 +1,12 @@
 package handlers
 
 import (
+	"strings"
+	"time"
+
+	"DBackend/internal/apperror"
 	"DBackend/internal/database"
+	"DBackend/internal/ratelimit"
 	"DBackend/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -9,33 +14,44 @@ import (
 )
 
 type AuthHandler struct {
-	db database.Service
+	db      database.Service
+	limiter *ratelimit.Limiter
 }
 
-func NewAuthHandler(db database.Service) *AuthHandler {
-	return &AuthHandler{db: db}
+func NewAuthHandler(db database.Service, limiter *ratelimit.Limiter) *AuthHandler {
+	return &AuthHandler{db: db, limiter: limiter}
 }
 
-// LoginHandler handles user login
+// LoginHandler handles user login. Repeated failures lock the account for
+// progressively longer; a locked account is refused before its password is
+// checked, so guessing cannot continue during the lock.
 func (h *AuthHandler) LoginHandler(c *fiber.Ctx) error {
 	data := new(struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+		Email    string `json:"email" validate:"required,email"`
+		Password string `json:"password" validate:"required"`
 	})
-	if err := c.BodyParser(data); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request"})
+	if err := parseBody(c, data); err != nil {
+		return err
 	}
-	user, err := h.db.User().FindByEmail(c.Context(), data.Email)
-	if err != nil || user == nil {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid credentials"})
+	account := strings.ToLower(strings.TrimSpace(data.Email))
+	if wait := h.limiter.Locked(c.UserContext(), account); wait > 0 {
+		return accountLocked(wait)
 	}
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(data.Password)); err != nil {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid credentials"})
+	user, err := h.db.Users().FindByEmail(c.UserContext(), account)
+	if err == nil && user != nil {
+		err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(data.Password))
+	}
+	if err != nil || user == nil {
+		if wait := h.limiter.LoginFailed(c.UserContext(), account); wait > 0 {
+			return accountLocked(wait)
+		}
+		return apperror.Unauthorized("invalid_credentials", "Invalid credentials")
 	}
+	h.limiter.LoginSucceeded(c.UserContext(), account)
 	id := user.ID.Hex()
 	token, err := utils.GenerateJWT(id, user.Roles)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to generate token"})
+		return apperror.Wrap(err, "Failed to generate token")
 	}
 
 	// Return comprehensive user details along with token
@@ -51,6 +67,10 @@ func (h *AuthHandler) LoginHandler(c *fiber.Ctx) error {
 	})
 }
 
+func accountLocked(wait time.Duration) error {
+	return apperror.TooManyRequests("account_locked", "Too many failed logins, try again later", wait)
+}
+
 // LogoutHandler logs out the user by expiring the JWT cookie and invalidating the token
 func (h *AuthHandler) LogoutHandler(c *fiber.Ctx) error {
 	token := c.Get("Authorization")
@@ -61,9 +81,9 @@ func (h *AuthHandler) LogoutHandler(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{"message": "Logged out successfully"})
 	}
 	// Optional: Store the token in a blacklist (if implementing token revocation)
-	err := h.db.User().BlacklistToken(c.Context(), token)
+	err := h.db.Users().BlacklistToken(c.UserContext(), token)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to blacklist token"})
+		return apperror.Wrap(err, "Failed to blacklist token")
 	}
 	return c.JSON(fiber.Map{"message": "Logged out successfully"})
 }
@@ -72,11 +92,30 @@ func (h *AuthHandler) LogoutHandler(c *fiber.Ctx) error {
 func (h *AuthHandler) MeHandler(c *fiber.Ctx) error {
 	user_id, ok := c.Locals("user_id").(string)
 	if !ok {
-		return c.Status(500).JSON(fiber.Map{"error": "Internal Server Error"})
+		return apperror.Internal("Internal Server Error", nil)
 	}
 	roles, ok := c.Locals("roles").([]string)
 	if !ok {
-		return c.Status(500).JSON(fiber.Map{"error": "Internal Server Error"})
+		return apperror.Internal("Internal Server Error", nil)
 	}
 	return c.JSON(fiber.Map{"user_id": user_id, "roles": roles})
 }
+
+// SetTimezoneHandler sets the IANA zone the user's meetings default to and
+// overlaps are reported in
+func (h *AuthHandler) SetTimezoneHandler(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	var data struct {
+		Timezone string `json:"timezone" validate:"required,timezone"`
+	}
+	if err := parseBody(c, &data); err != nil {
+		return err
+	}
+	if err := h.db.Users().SetTimezone(c.UserContext(), userID, data.Timezone); err != nil {
+		return apperror.Wrap(err, "Failed to set timezone")
+	}
+	return c.JSON(fiber.Map{"timezone": data.Timezone})
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"DBackend/internal/apperror"
+	"DBackend/internal/config"
+	"DBackend/internal/database"
+	"DBackend/internal/server/services"
+	"DBackend/model"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BookingHandler lets investors publish when they take meetings and founders
+// in their deal flow book one of the free slots
+type BookingHandler struct {
+	db       database.Service
+	calendar config.Calendar
+	provider services.CalendarProvider
+}
+
+// NewBookingHandler creates a new instance of BookingHandler
+func NewBookingHandler(db database.Service, calendar config.Calendar) *BookingHandler {
+	return &BookingHandler{db: db, calendar: calendar, provider: services.NewCalendarProvider(calendar, db)}
+}
+
+// BookingRequest picks a slot to book
+type BookingRequest struct {
+	StartTime time.Time `json:"start_time" validate:"required,future"`
+	Notes     string    `json:"notes" validate:"max=2000"`
+}
+
+// SetAvailabilityHandler replaces the investor's availability
+func (h *BookingHandler) SetAvailabilityHandler(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	var availability model.Availability
+	if err := parseBody(c, &availability); err != nil {
+		return err
+	}
+	availability.InvestorID = userID
+	if err := h.db.Bookings().SaveAvailability(c.UserContext(), availability); err != nil {
+		return apperror.Wrap(err, "Failed to save availability")
+	}
+	saved, err := h.db.Bookings().GetAvailability(c.UserContext(), userID)
+	if err != nil {
+		return apperror.Wrap(err, "Failed to retrieve availability")
+	}
+	return c.JSON(fiber.Map{"availability": saved})
+}
+
+// GetAvailabilityHandler returns the investor's availability
+func (h *BookingHandler) GetAvailabilityHandler(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	availability, err := h.db.Bookings().GetAvailability(c.UserContext(), userID)
+	if err != nil {
+		return apperror.Wrap(err, "Failed to retrieve availability")
+	}
+	return c.JSON(fiber.Map{"availability": availability})
+}
+
+// CreateBookingLinkHandler creates a booking link with a random slug
+func (h *BookingHandler) CreateBookingLinkHandler(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	var link model.BookingLink
+	if err := parseBody(c, &link); err != nil {
+		return err
+	}
+	slug, err := services.NewBookingSlug()
+	if err != nil {
+		return apperror.Wrap(err, "Failed to create booking link")
+	}
+	link.ID = primitive.NewObjectID()
+	link.InvestorID = userID
+	link.Slug = slug
+	if _, err := h.db.Bookings().CreateBookingLink(c.UserContext(), link); err != nil {
+		return apperror.Wrap(err, "Failed to create booking link")
+	}
+	created, err := h.db.Bookings().GetBookingLink(c.UserContext(), slug)
+	if err != nil {
+		return apperror.Wrap(err, "Failed to retrieve booking link")
+	}
+
+	// founders book at /booking/<slug>, next to this route
+	base := c.BaseURL() + strings.TrimSuffix(strings.TrimSuffix(c.Path(), "/"), "/links")
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"link": created, "url": base + "/" + slug})
+}
+
+// ListBookingLinksHandler returns the investor's booking links
+func (h *BookingHandler) ListBookingLinksHandler(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	links, err := h.db.Bookings().ListBookingLinks(c.UserContext(), userID)
+	if err != nil {
+		return apperror.Wrap(err, "Failed to retrieve booking links")
+	}
+	return c.JSON(fiber.Map{"links": links})
+}
+
+// DeleteBookingLinkHandler removes one of the investor's booking links.
+// Meetings already booked through it are kept.
+func (h *BookingHandler) DeleteBookingLinkHandler(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apperror.Validation("invalid_booking_link_id", "Invalid booking link ID")
+	}
+	if err := h.db.Bookings().DeleteBookingLink(c.UserContext(), userID, id); err != nil {
+		return apperror.Wrap(err, "Failed to delete booking link")
+	}
+	return c.JSON(fiber.Map{"message": "Booking link deleted"})
+}
+
+// GetSlotsHandler returns the free slots of a booking link to a founder in
+// the investor's deal flow
+func (h *BookingHandler) GetSlotsHandler(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	link, availability, err := h.bookable(c.UserContext(), c.Params("slug"), userID)
+	if err != nil {
+		return err
+	}
+	slots, err := h.freeSlots(c.UserContext(), link, availability, userID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"title": link.Title, "timezone": availability.Timezone, "slots": slots})
+}
+
+// BookHandler books a free slot of a booking link. The meeting and the
+// investor's notification are saved in one transaction. The calendar event
+// is created before it, so the meeting is saved with its link, and cancelled
+// again if saving fails.
+func (h *BookingHandler) BookHandler(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	var req BookingRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+	ctx := c.UserContext()
+	link, availability, err := h.bookable(ctx, c.Params("slug"), userID)
+	if err != nil {
+		return err
+	}
+	slots, err := h.freeSlots(ctx, link, availability, userID)
+	if err != nil {
+		return err
+	}
+	var slot *services.Slot
+	for i := range slots {
+		if slots[i].Start.Equal(req.StartTime) {
+			slot = &slots[i]
+			break
+		}
+	}
+	if slot == nil {
+		return database.ErrSlotTaken
+	}
+
+	now := time.Now()
+	meeting := model.Meeting{
+		ID:            primitive.NewObjectID(),
+		InvestorID:    link.InvestorID,
+		FounderID:     userID,
+		Title:         link.Title,
+		StartTime:     slot.Start,
+		EndTime:       slot.End,
+		Notes:         req.Notes,
+		BookingLinkID: link.ID,
+		Timezone:      availability.Timezone,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := scheduleOnCalendar(ctx, h.db, h.calendar, h.provider, link.InvestorID, &meeting); err != nil {
+		return err
+	}
+	err = h.db.Transaction(ctx, func(ctx context.Context) error {
+		// The slot was free above, so a booking racing this one is the usual
+		// way the insert fails; the unique index on booked slots keeps a
+		// second meeting out
+		if _, err := h.db.Meetings().CreateMeeting(ctx, meeting); err != nil {
+			return apperror.Wrap(err, "Failed to create meeting")
+		}
+		// The investor reads the time in the zone they publish slots in
+		loc, err := time.LoadLocation(availability.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+		founder := "A founder"
+		if user, err := h.db.Users().FindByID(ctx, userID); err == nil && person(user).Name != "" {
+			founder = person(user).Name
+		}
+		_, err = h.db.Notifications().CreateNotification(ctx, model.Notification{
+			FounderID:        link.InvestorID,
+			NotificationType: "meeting",
+			Title:            "Meeting booked",
+			Message:          fmt.Sprintf("%s booked %q on %s", founder, link.Title, slot.Start.In(loc).Format(time.RFC1123)),
+		})
+		if err != nil {
+			return apperror.Wrap(err, "Failed to notify investor")
+		}
+		return nil
+	})
+	if err != nil {
+		// The event is not part of the transaction, so it is taken back here,
+		// even if the founder has gone
+		if cancelErr := cancelOnCalendar(context.WithoutCancel(ctx), h.db, h.calendar, meeting); cancelErr != nil {
+			slog.ErrorContext(ctx, "calendar event of an unsaved booking left behind", "calendar_provider", meeting.CalendarProvider, "calendar_event_id", meeting.CalendarEventID, "error", cancelErr)
+		}
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Meeting booked", "meeting": meeting})
+}
+
+// bookable looks up the booking link named slug and its investor's
+// availability, and checks founderID is in the investor's deal flow
+func (h *BookingHandler) bookable(ctx context.Context, slug string, founderID primitive.ObjectID) (*model.BookingLink, *model.Availability, error) {
+	link, err := h.db.Bookings().GetBookingLink(ctx, slug)
+	if err != nil {
+		return nil, nil, apperror.Wrap(err, "Failed to retrieve booking link")
+	}
+	_, err = h.db.Deals().FindDeal(ctx, link.InvestorID, founderID)
+	if errors.Is(err, database.ErrDealNotFound) {
+		return nil, nil, apperror.Forbidden("not_in_deal_flow", "Only founders in the investor's deal flow can book")
+	}
+	if err != nil {
+		return nil, nil, apperror.Wrap(err, "Failed to retrieve deal")
+	}
+	availability, err := h.db.Bookings().GetAvailability(ctx, link.InvestorID)
+	if err != nil {
+		return nil, nil, apperror.Wrap(err, "Failed to retrieve availability")
+	}
+	return link, availability, nil
+}
+
+// freeSlots returns the slots of availability in which neither the investor
+// nor the founder has a meeting and the investor's own calendar is free
+func (h *BookingHandler) freeSlots(ctx context.Context, link *model.BookingLink, availability *model.Availability, founderID primitive.ObjectID) ([]services.Slot, error) {
+	from, to := services.BookingWindow(*availability, time.Now())
+	meetings, err := h.db.Meetings().GetBusyMeetings(ctx, []primitive.ObjectID{link.InvestorID, founderID}, from, to)
+	if err != nil {
+		return nil, apperror.Wrap(err, "Failed to retrieve meetings")
+	}
+	external, err := h.provider.BusyTimes(ctx, link.InvestorID, from, to)
+	if err != nil {
+		return nil, apperror.Wrap(err, "Failed to retrieve calendar busy times")
+	}
+	slots, err := services.FreeSlots(*availability, from, to, append(services.MeetingBusy(meetings, from, to), external...))
+	if err != nil {
+		return nil, apperror.Wrap(err, "Failed to compute free slots")
+	}
+	return slots, nil
+}
@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"DBackend/internal/apperror"
+	"DBackend/internal/config"
+	"DBackend/internal/database"
+	"DBackend/internal/ics"
+	"DBackend/internal/server/services"
+	"DBackend/model"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// oauthNonceCookie holds the nonce binding a Google consent to the browser
+// that started it
+const oauthNonceCookie = "calendar_oauth_nonce"
+
+// CalendarHandler lets users connect their own Google calendar and subscribe
+// to a feed of their meetings and tasks
+type CalendarHandler struct {
+	db       database.Service
+	calendar config.Calendar
+}
+
+// NewCalendarHandler creates a new instance of CalendarHandler
+func NewCalendarHandler(db database.Service, calendar config.Calendar) *CalendarHandler {
+	return &CalendarHandler{db: db, calendar: calendar}
+}
+
+// ConnectGoogleHandler returns the Google consent page the client should send
+// the user's browser to, and sets a nonce cookie the callback must see
+// again. The client calls it with credentials so the browser keeps the
+// cookie.
+func (h *CalendarHandler) ConnectGoogleHandler(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	oauth, err := services.NewGoogleOAuth(h.calendar)
+	if err != nil {
+		return apperror.Wrap(err, "Failed to initialize Google Calendar service")
+	}
+	nonce, err := services.NewOAuthNonce()
+	if err != nil {
+		return apperror.Wrap(err, "Failed to start calendar authorization")
+	}
+	h.setNonceCookie(c, nonce, time.Now().Add(services.OAuthStateTTL))
+	return c.JSON(fiber.Map{"auth_url": oauth.AuthCodeURL(userID, nonce)})
+}
+
+// setNonceCookie scopes the nonce cookie to the callback. Lax lets it
+// through on Google's top-level redirect back to the API.
+func (h *CalendarHandler) setNonceCookie(c *fiber.Ctx, value string, expires time.Time) {
+	cookie := &fiber.Cookie{
+		Name:     oauthNonceCookie,
+		Value:    value,
+		Expires:  expires,
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	}
+	if callback, err := url.Parse(h.calendar.RedirectURL); err == nil {
+		cookie.Path, cookie.Secure = callback.Path, callback.Scheme == "https"
+	}
+	c.Cookie(cookie)
+}
+
+// GoogleCallbackHandler is where Google redirects the browser after consent.
+// It carries no JWT; the signed state identifies the user, and only counts
+// with the nonce cookie of the browser that asked for it. The cookie is
+// cleared, so each consent connects once. With a return URL
+// configured the browser is sent back to the frontend with ?calendar= set to
+// connected or an error code, otherwise the outcome is returned as JSON.
+func (h *CalendarHandler) GoogleCallbackHandler(c *fiber.Ctx) error {
+	nonce := c.Cookies(oauthNonceCookie)
+	h.setNonceCookie(c, "", time.Unix(0, 0))
+	conn, err := h.connectGoogle(c, nonce)
+	if h.calendar.ReturnURL == "" {
+		if err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"message": "Calendar connected", "connection": conn})
+	}
+
+	outcome := "connected"
+	if err != nil {
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) || appErr.Kind == apperror.KindInternal {
+			slog.ErrorContext(c.UserContext(), "connecting calendar failed", "error", err)
+			outcome = "internal"
+		} else {
+			outcome = appErr.Code
+		}
+	}
+	return c.Redirect(withQuery(h.calendar.ReturnURL, "calendar", outcome), fiber.StatusFound)
+}
+
+func (h *CalendarHandler) connectGoogle(c *fiber.Ctx, nonce string) (*model.CalendarConnection, error) {
+	if c.Query("error") != "" {
+		return nil, apperror.Validation("oauth_denied", "Calendar access was not granted")
+	}
+	if c.Query("code") == "" || c.Query("state") == "" {
+		return nil, apperror.Validation("code_and_state_are_required", "code and state are required")
+	}
+	oauth, err := services.NewGoogleOAuth(h.calendar)
+	if err != nil {
+		return nil, apperror.Wrap(err, "Failed to initialize Google Calendar service")
+	}
+	conn, err := oauth.Connect(c.UserContext(), c.Query("state"), nonce, c.Query("code"))
+	if err != nil {
+		return nil, apperror.Wrap(err, "Failed to connect calendar")
+	}
+	if err := h.db.Calendars().SaveConnection(c.UserContext(), conn); err != nil {
+		return nil, apperror.Wrap(err, "Failed to save calendar connection")
+	}
+	return h.db.Calendars().GetConnection(c.UserContext(), conn.UserID, conn.Provider)
+}
+
+// GetGoogleConnectionHandler reports whether the user has connected a Google
+// calendar
+func (h *CalendarHandler) GetGoogleConnectionHandler(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	conn, err := h.db.Calendars().GetConnection(c.UserContext(), userID, model.CalendarGoogle)
+	if errors.Is(err, database.ErrCalendarNotConnected) {
+		return c.JSON(fiber.Map{"connected": false})
+	}
+	if err != nil {
+		return apperror.Wrap(err, "Failed to retrieve calendar connection")
+	}
+	return c.JSON(fiber.Map{"connected": true, "connection": conn})
+}
+
+// DisconnectGoogleHandler forgets the user's Google calendar. Access can also
+// be revoked from the Google account's security settings.
+func (h *CalendarHandler) DisconnectGoogleHandler(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	if err := h.db.Calendars().DeleteConnection(c.UserContext(), userID, model.CalendarGoogle); err != nil {
+		return apperror.Wrap(err, "Failed to disconnect calendar")
+	}
+	return c.JSON(fiber.Map{"message": "Calendar disconnected"})
+}
+
+// CreateFeedHandler creates the user's calendar feed, or rotates its token
+// when it exists so the previous URL stops working. The URL is only returned
+// here; it cannot be read back later.
+func (h *CalendarHandler) CreateFeedHandler(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	token, err := services.NewFeedToken()
+	if err != nil {
+		return apperror.Wrap(err, "Failed to create calendar feed")
+	}
+	if err := h.db.Calendars().SaveFeed(c.UserContext(), userID, services.FeedTokenHash(token)); err != nil {
+		return apperror.Wrap(err, "Failed to save calendar feed")
+	}
+
+	// the feed is served next to this route, as /calendar/<token>.ics
+	base := c.BaseURL() + strings.TrimSuffix(strings.TrimSuffix(c.Path(), "/"), "/feed")
+	feedURL := base + "/" + token + ".ics"
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"url":        feedURL,
+		"webcal_url": "webcal" + strings.TrimPrefix(strings.TrimPrefix(feedURL, "https"), "http"),
+	})
+}
+
+// GetFeedHandler reports whether the user has a calendar feed
+func (h *CalendarHandler) GetFeedHandler(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	feed, err := h.db.Calendars().GetFeed(c.UserContext(), userID)
+	if errors.Is(err, database.ErrCalendarFeedNotFound) {
+		return c.JSON(fiber.Map{"enabled": false})
+	}
+	if err != nil {
+		return apperror.Wrap(err, "Failed to retrieve calendar feed")
+	}
+	return c.JSON(fiber.Map{"enabled": true, "feed": feed})
+}
+
+// DeleteFeedHandler turns the user's calendar feed off
+func (h *CalendarHandler) DeleteFeedHandler(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	if err := h.db.Calendars().DeleteFeed(c.UserContext(), userID); err != nil {
+		return apperror.Wrap(err, "Failed to delete calendar feed")
+	}
+	return c.JSON(fiber.Map{"message": "Calendar feed deleted"})
+}
+
+// FeedHandler serves a calendar feed to subscribing calendar apps, which
+// cannot send a JWT; the secret token in the URL names the user instead. The
+// ETag is a hash of the body, so polling clients get 304 until it changes.
+func (h *CalendarHandler) FeedHandler(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	feed, err := h.db.Calendars().GetFeedByToken(ctx, services.FeedTokenHash(c.Params("token")))
+	if err != nil {
+		return apperror.Wrap(err, "Failed to retrieve calendar feed")
+	}
+	meetings, err := h.db.Meetings().GetMeetings(ctx, feed.UserID)
+	if err != nil {
+		return apperror.Wrap(err, "Failed to retrieve meetings")
+	}
+	tasks, err := h.db.Tasks().GetDueTasks(ctx, feed.UserID)
+	if err != nil {
+		return apperror.Wrap(err, "Failed to retrieve tasks")
+	}
+
+	body := services.Feed(h.calendar, meetings, tasks).Bytes()
+	c.Set(fiber.HeaderETag, fmt.Sprintf(`"%x"`, sha256.Sum256(body)))
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	if c.Fresh() {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	return c.Send(body)
+}
+
+// scheduleOnCalendar creates the calendar event of meeting, organized by
+// organizer, with the configured provider and records on meeting where it
+// went and the link to join it
+func scheduleOnCalendar(ctx context.Context, db database.Service, calendar config.Calendar, provider services.CalendarProvider, organizer primitive.ObjectID, meeting *model.Meeting) error {
+	scheduled, err := provider.CreateEvent(ctx, meetingEvent(ctx, db, calendar, organizer, *meeting))
+	if err != nil {
+		return apperror.Wrap(err, "Failed to create calendar event")
+	}
+	meeting.CalendarProvider = scheduled.Provider
+	meeting.CalendarEventID = scheduled.EventID
+	meeting.MeetingURL = scheduled.MeetingURL
+	meeting.GoogleMeetURL = scheduled.GoogleMeetURL
+	return nil
+}
+
+// updateOnCalendar brings the calendar event of meeting up to date with it,
+// through the provider that created the event
+func updateOnCalendar(ctx context.Context, db database.Service, calendar config.Calendar, meeting model.Meeting) error {
+	if meeting.CalendarEventID == "" {
+		return nil
+	}
+	err := meetingProvider(db, calendar, meeting).UpdateEvent(ctx, meeting.CalendarEventID, meetingEvent(ctx, db, calendar, meeting.InvestorID, meeting))
+	if err != nil {
+		return apperror.Wrap(err, "Failed to update calendar event")
+	}
+	return nil
+}
+
+// cancelOnCalendar removes the calendar event of meeting, through the
+// provider that created the event
+func cancelOnCalendar(ctx context.Context, db database.Service, calendar config.Calendar, meeting model.Meeting) error {
+	if meeting.CalendarEventID == "" {
+		return nil
+	}
+	err := meetingProvider(db, calendar, meeting).CancelEvent(ctx, meeting.CalendarEventID, meetingEvent(ctx, db, calendar, meeting.InvestorID, meeting))
+	if err != nil {
+		return apperror.Wrap(err, "Failed to cancel calendar event")
+	}
+	return nil
+}
+
+// meetingProvider returns the provider that created the event of meeting,
+// which need not be the one configured now
+func meetingProvider(db database.Service, calendar config.Calendar, meeting model.Meeting) services.CalendarProvider {
+	calendar.Provider = meeting.CalendarProvider
+	return services.NewCalendarProvider(calendar, db)
+}
+
+// meetingEvent describes meeting to calendar providers. Everyone taking part
+// other than the organizer is invited; users who cannot be found are left out.
+func meetingEvent(ctx context.Context, db database.Service, calendar config.Calendar, organizer primitive.ObjectID, meeting model.Meeting) services.CalendarEvent {
+	event := services.CalendarEvent{
+		MeetingID:   meeting.ID,
+		Title:       meeting.Title,
+		Description: meeting.Notes,
+		Start:       meeting.StartTime,
+		End:         meeting.EndTime,
+		OrganizerID: organizer,
+		MeetingURL:  meeting.MeetingURL,
+		Timezone:    meeting.Timezone,
+		Recurrence:  meeting.Recurrence,
+		Exceptions:  meeting.Exceptions,
+		Overrides:   meeting.Overrides,
+	}
+	if event.MeetingURL == "" {
+		event.MeetingURL = services.MeetingLink(calendar, meeting.ID)
+	}
+	if user, err := db.Users().FindByID(ctx, organizer); err == nil {
+		event.Organizer = person(user)
+	}
+
+	seen := map[primitive.ObjectID]bool{organizer: true}
+	for _, id := range append([]primitive.ObjectID{meeting.InvestorID, meeting.FounderID}, meeting.Participants...) {
+		if id.IsZero() || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if user, err := db.Users().FindByID(ctx, id); err == nil {
+			event.Attendees = append(event.Attendees, person(user))
+		}
+	}
+	return event
+}
+
+func person(user *model.User) ics.Person {
+	return ics.Person{Name: strings.TrimSpace(user.FirstName + " " + user.SecondName), Email: user.Email}
+}
+
+// withQuery returns rawURL with key set to value in its query string
+func withQuery(rawURL, key, value string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	q := u.Query()
+	q.Set(key, value)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
-	"fmt"
+	"context"
 	"time"
 
+	"DBackend/internal/apperror"
+	"DBackend/internal/config"
 	"DBackend/internal/database"
+	"DBackend/internal/metrics"
 	"DBackend/internal/server/services"
 	"DBackend/model"
 	"DBackend/utils"
@@ -12,28 +15,31 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // DealFlowHandler struct to handle deal flow requests
 type DealFlowHandler struct {
-	db database.Service
+	db       database.Service
+	calendar config.Calendar
+	provider services.CalendarProvider
 }
 
 // NewDealFlowHandler creates a new instance of DealFlowHandler
-func NewDealFlowHandler(db database.Service) *DealFlowHandler {
-	return &DealFlowHandler{db: db}
+func NewDealFlowHandler(db database.Service, calendar config.Calendar) *DealFlowHandler {
+	return &DealFlowHandler{db: db, calendar: calendar, provider: services.NewCalendarProvider(calendar, db)}
 }
 
 // AddDealFlowHandler - Add a startup to deal flow
 func (h *DealFlowHandler) AddDealFlowHandler(c *fiber.Ctx) error {
 	deal := new(struct {
-		UserID           string   `json:"UserID"`
+		UserID           string   `json:"UserID" validate:"required,objectid"`
 		Name             string   `json:"Name"`
 		Email            string   `json:"Email"`
 		Avatar           string   `json:"Avatar"`
 		StartupName      string   `json:"StartupName"`
 		Industry         string   `json:"Industry"`
-		FundingStage     string   `json:"FundingStage"`
+		FundingStage     string   `json:"FundingStage" validate:"required"`
 		Location         string   `json:"Location"`
 		FundRequired     int      `json:"FundRequired"`
 		MissionStatement string   `json:"MissionStatement"`
@@ -48,41 +54,33 @@ func (h *DealFlowHandler) AddDealFlowHandler(c *fiber.Ctx) error {
 		PitchDeck        string   `json:"PitchDeck"`
 		FundAllocation   string   `json:"FundAllocation"`
 		Founded          string   `json:"Founded"`
-		MatchScore       float64  `json:"MatchScore"`
+		MatchScore       float64  `json:"MatchScore" validate:"min=0,max=100"`
 		Tags             []string `json:"Tags"`
 		Bookmarked       bool     `json:"Bookmarked"`
 	})
 
 	tokenStr, ok := c.Locals("token").(string)
 	if !ok || tokenStr == "" {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	claims, err := utils.ValidateJWT(tokenStr)
 	if err != nil {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Convert claims.UserID to primitive.ObjectID
 	investorID, err := primitive.ObjectIDFromHex(claims.UserID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Validation("invalid_user_id", "Invalid user ID")
 	}
 
-	// Parse request body
-	if err := c.BodyParser(&deal); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
-	}
-
-	// Validate required fields
-	if deal.UserID == "" || deal.FundingStage == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "StartupID, InvestorID, Stage, and Status are required"})
+	// Parse and validate request body
+	if err := parseBody(c, deal); err != nil {
+		return err
 	}
 
-	startUpId, err := primitive.ObjectIDFromHex(deal.UserID)
-	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid Startup ID"})
-	}
+	startUpId, _ := primitive.ObjectIDFromHex(deal.UserID)
 	// Create a new DealFlow instance
 	newDeal := model.DealFlow{
 		ID:         primitive.NewObjectID(),
@@ -96,65 +94,103 @@ func (h *DealFlowHandler) AddDealFlowHandler(c *fiber.Ctx) error {
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
-	existingDeal, err := h.db.User().GetDealFlowByStartupID(c.Context(), startUpId)
-	if err == nil && existingDeal.ID != primitive.NilObjectID {
-		return c.Status(400).JSON(fiber.Map{"error": "Deal already exists"})
-	}
-
-	// Insert into database
-	insertResult, err := h.db.User().AddStartupToDealFlow(c.Context(), newDeal)
+	// Insert into database, together with the DealAdded event
+	var insertResult *mongo.InsertOneResult
+	err = h.db.Transaction(c.UserContext(), func(ctx context.Context) error {
+		insertResult, err = h.db.Deals().AddStartupToDealFlow(ctx, newDeal)
+		return err
+	})
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to add deal to deal flow"})
+		return apperror.Wrap(err, "Failed to add deal to deal flow")
 	}
+	metrics.DealsCreated.Inc()
 
 	return c.JSON(fiber.Map{"message": "Deal added successfully", "id": insertResult.InsertedID})
 } // GetDealFlowByIDHandler - Retrieve a specific deal flow entry
 func (h *DealFlowHandler) GetDealFlowByIDHandler(c *fiber.Ctx) error {
 	id, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid ID"})
+		return apperror.Validation("invalid_id", "Invalid ID")
 	}
 
-	deal, err := h.db.User().GetDealFlowByID(c.Context(), id)
+	deal, err := h.db.Deals().GetDealFlowByID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "Deal not found"})
+		return apperror.Wrap(err, "Failed to retrieve deal")
 	}
 
 	return c.JSON(deal)
 }
 
+// GetDealActivityHandler returns the activity timeline of a deal, newest
+// first, to its investor or founder
+func (h *DealFlowHandler) GetDealActivityHandler(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apperror.Validation("invalid_deal_id", "Invalid deal ID")
+	}
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+
+	deal, err := h.db.Deals().GetDealFlowByID(c.UserContext(), id)
+	if err != nil {
+		return apperror.Wrap(err, "Failed to retrieve deal")
+	}
+	founder := deal.StartupID == userID
+	if profile, err := h.db.Founders().GetFounderByUserID(c.UserContext(), userID); err == nil && profile.ID == deal.StartupID {
+		founder = true
+	}
+	if userID != deal.InvestorID && !founder {
+		return apperror.Forbidden("not_in_deal", "Only the deal's investor and founder can see its activity")
+	}
+
+	activities, err := h.db.Deals().ListActivities(c.UserContext(), id)
+	if err != nil {
+		return apperror.Wrap(err, "Failed to retrieve deal activity")
+	}
+	return c.JSON(fiber.Map{"activities": activities})
+}
+
 // ListAllDealFlowHandler - Retrieve all deal flow entries with founder details
 func (h *DealFlowHandler) ListAllDealFlowHandler(c *fiber.Ctx) error {
 	tokenStr, ok := c.Locals("token").(string)
 	if !ok || tokenStr == "" {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	claims, err := utils.ValidateJWT(tokenStr)
 	if err != nil {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Convert claims.UserID to primitive.ObjectID
 	investorID, err := primitive.ObjectIDFromHex(claims.UserID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Validation("invalid_user_id", "Invalid user ID")
+	}
+
+	params, err := parseListQuery(c, database.DealFlowListSpec)
+	if err != nil {
+		return err
 	}
 
-	deals, err := h.db.User().ListDealsByInvestorID(c.Context(), investorID)
+	page, err := h.db.Deals().ListDealsByInvestorID(c.UserContext(), investorID, params)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch deal flow entries"})
+		return apperror.Wrap(err, "Failed to fetch deal flow entries")
 	}
 
 	// Enrich deals with founder information and ensure required fields exist
+	deals := page.Items
 	for i, deal := range deals {
-		founder, err := h.db.User().FindByID(c.Context(), "users", deal["startup"].(bson.M)["user_id"].(primitive.ObjectID))
-		if err == nil {
-			founderUser, ok := founder.(*model.User)
-			if ok {
+		// Deals whose founder profile is missing have no startup to enrich from
+		startup, _ := deal["startup"].(bson.M)
+		if founderID, ok := startup["user_id"].(primitive.ObjectID); ok {
+			founderUser, err := h.db.Users().FindByID(c.UserContext(), founderID)
+			if err == nil {
 				deals[i]["founder_name"] = founderUser.FirstName + " " + founderUser.SecondName
+				deals[i]["founder_email"] = founderUser.Email
 			}
-			deals[i]["founder_email"] = founderUser.Email
 		}
 
 		// Ensure documents field exists (even if empty)
@@ -171,41 +207,81 @@ func (h *DealFlowHandler) ListAllDealFlowHandler(c *fiber.Ctx) error {
 		}
 	}
 
-	return c.JSON(deals)
+	return c.JSON(page)
 }
 
 // UpdateDealFlowHandler - Update deal flow entry (stage, status, match score)
 func (h *DealFlowHandler) UpdateDealFlowHandler(c *fiber.Ctx) error {
 	id, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid ID"})
+		return apperror.Validation("invalid_id", "Invalid ID")
 	}
 
-	fmt.Println(id)
-	var updateFields map[string]interface{}
-	if err := c.BodyParser(&updateFields); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	var data struct {
+		Stage      *string  `json:"stage" validate:"oneof=screening dueDiligence negotiation closed"`
+		Status     *string  `json:"status" validate:"oneof=active paused completed cancelled"`
+		Priority   *string  `json:"priority" validate:"oneof=low medium high"`
+		MatchScore *float64 `json:"match_score" validate:"min=0,max=100"`
+	}
+	if err := parseBody(c, &data); err != nil {
+		return err
+	}
+
+	// Only the fields present in the body are updated. The stage goes
+	// through UpdateDealStage, as on PATCH /:id/stage, so the move publishes
+	// its DealStageChanged event.
+	updateFields := bson.M{}
+	if data.Status != nil {
+		updateFields["status"] = *data.Status
+	}
+	if data.Priority != nil {
+		updateFields["priority"] = *data.Priority
+	}
+	if data.MatchScore != nil {
+		updateFields["match_score"] = *data.MatchScore
+	}
+	if len(updateFields) == 0 && data.Stage == nil {
+		return apperror.Validation("missing_fields", "No fields to update")
 	}
 
-	// Update database
-	updateResult, err := h.db.User().UpdateDealFlow(c.Context(), id, updateFields)
+	var modified int64
+	err = h.db.Transaction(c.UserContext(), func(ctx context.Context) error {
+		if data.Stage != nil {
+			result, err := h.db.Deals().UpdateDealStage(ctx, id, *data.Stage)
+			if err != nil {
+				return err
+			}
+			modified = result.ModifiedCount
+		}
+		if len(updateFields) > 0 {
+			result, err := h.db.Deals().UpdateDealFlow(ctx, id, updateFields)
+			if err != nil {
+				return err
+			}
+			modified = max(modified, result.ModifiedCount)
+		}
+		return nil
+	})
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to update deal flow"})
+		return apperror.Wrap(err, "Failed to update deal flow")
+	}
+	if data.Stage != nil {
+		metrics.StageTransitions.WithLabelValues(*data.Stage).Inc()
 	}
 
-	return c.JSON(fiber.Map{"message": "Deal flow updated successfully", "modifiedCount": updateResult.ModifiedCount})
+	return c.JSON(fiber.Map{"message": "Deal flow updated successfully", "modifiedCount": modified})
 }
 
 // DeleteDealFlowHandler - Remove startup from deal flow
 func (h *DealFlowHandler) DeleteDealFlowHandler(c *fiber.Ctx) error {
 	id, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid ID"})
+		return apperror.Validation("invalid_id", "Invalid ID")
 	}
 
-	deleteResult, err := h.db.User().DeleteDealFlow(c.Context(), id)
+	deleteResult, err := h.db.Deals().DeleteDealFlow(c.UserContext(), id)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete deal flow entry"})
+		return apperror.Wrap(err, "Failed to delete deal flow entry")
 	}
 
 	return c.JSON(fiber.Map{"message": "Deal flow entry deleted", "deletedCount": deleteResult.DeletedCount})
@@ -215,62 +291,63 @@ func (h *DealFlowHandler) DeleteDealFlowHandler(c *fiber.Ctx) error {
 func (h *DealFlowHandler) AddMeetingHandler(c *fiber.Ctx) error {
 	id, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid deal flow ID"})
+		return apperror.Validation("invalid_deal_flow_id", "Invalid deal flow ID")
+	}
+
+	warn, err := warnOnConflict(c)
+	if err != nil {
+		return err
 	}
 
 	var meeting model.Meeting
-	if err := c.BodyParser(&meeting); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	if err := parseBody(c, &meeting); err != nil {
+		return err
 	}
 
 	meeting.ID = primitive.NewObjectID()
 
-	// Create Google Calendar event
-	calendarService, err := services.NewGoogleCalendarService()
+	// Create the calendar event, organized by the current user
+	organizer, err := currentUserID(c)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to initialize Google Calendar service"})
+		return err
 	}
-
-	event, err := calendarService.CreateEvent(
-		meeting.Title,
-		"", // Location
-		meeting.Notes,
-		meeting.StartTime.Format(time.RFC3339),
-		meeting.EndTime.Format(time.RFC3339),
-		"UTC",
-		[]string{}, // Attendees
-	)
+	conflicts, err := planMeeting(c.UserContext(), h.db, organizer, &meeting, warn)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to create Google Calendar event"})
+		return err
+	}
+	if err := scheduleOnCalendar(c.UserContext(), h.db, h.calendar, h.provider, organizer, &meeting); err != nil {
+		return err
 	}
 
-	meeting.GoogleMeetURL = event.HangoutLink
-
-	updateResult, err := h.db.DealFlow().AddMeeting(c.Context(), id, meeting)
+	updateResult, err := h.db.Deals().AddMeeting(c.UserContext(), id, meeting)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to add meeting"})
+		return apperror.Wrap(err, "Failed to add meeting")
 	}
 
-	return c.JSON(fiber.Map{"message": "Meeting added successfully", "modifiedCount": updateResult.ModifiedCount})
+	response := fiber.Map{"message": "Meeting added successfully", "modifiedCount": updateResult.ModifiedCount}
+	if len(conflicts) > 0 {
+		response["conflicts"] = conflicts
+	}
+	return c.JSON(response)
 }
 
 // AddDocumentHandler - Add a document to a deal flow entry
 func (h *DealFlowHandler) AddDocumentHandler(c *fiber.Ctx) error {
 	id, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid deal flow ID"})
+		return apperror.Validation("invalid_deal_flow_id", "Invalid deal flow ID")
 	}
 
 	var document model.Document
-	if err := c.BodyParser(&document); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	if err := parseBody(c, &document); err != nil {
+		return err
 	}
 
 	document.Date = time.Now()
 
-	updateResult, err := h.db.User().AddDocument(c.Context(), id, document)
+	updateResult, err := h.db.Deals().AddDocument(c.UserContext(), id, document)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to add document"})
+		return apperror.Wrap(err, "Failed to add document")
 	}
 
 	return c.JSON(fiber.Map{"message": "Document added successfully", "modifiedCount": updateResult.ModifiedCount})
@@ -280,20 +357,20 @@ func (h *DealFlowHandler) AddDocumentHandler(c *fiber.Ctx) error {
 func (h *DealFlowHandler) AddTaskHandler(c *fiber.Ctx) error {
 	id, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid deal flow ID"})
+		return apperror.Validation("invalid_deal_flow_id", "Invalid deal flow ID")
 	}
 
 	var task model.Task
-	if err := c.BodyParser(&task); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	if err := parseBody(c, &task); err != nil {
+		return err
 	}
 
 	task.ID = primitive.NewObjectID()
 	task.Completed = false // Default to incomplete
 
-	updateResult, err := h.db.User().AddTask(c.Context(), id, task)
+	updateResult, err := h.db.Tasks().AddTask(c.UserContext(), id, task)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to add task"})
+		return apperror.Wrap(err, "Failed to add task")
 	}
 
 	return c.JSON(fiber.Map{"message": "Task added successfully", "modifiedCount": updateResult.ModifiedCount})
@@ -303,26 +380,35 @@ func (h *DealFlowHandler) AddTaskHandler(c *fiber.Ctx) error {
 func (h *DealFlowHandler) UpdateTaskStatusHandler(c *fiber.Ctx) error {
 	dealID, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid deal flow ID"})
+		return apperror.Validation("invalid_deal_flow_id", "Invalid deal flow ID")
 	}
 
 	taskID, err := primitive.ObjectIDFromHex(c.Params("taskID"))
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid task ID"})
+		return apperror.Validation("invalid_task_id", "Invalid task ID")
 	}
 
 	var updateData struct {
 		Completed bool `json:"completed"`
 	}
-	if err := c.BodyParser(&updateData); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	if err := parseBody(c, &updateData); err != nil {
+		return err
 	}
 
-	// Implement the database update
-	// This assumes you have a method to update a task's status
-	updateResult, err := h.db.User().UpdateTaskStatus(c.Context(), dealID, taskID, updateData.Completed)
+	// Update the task, and the action item it was made from
+	var updateResult *mongo.UpdateResult
+	err = h.db.Transaction(c.UserContext(), func(ctx context.Context) error {
+		updateResult, err = h.db.Tasks().UpdateTaskStatus(ctx, dealID, taskID, updateData.Completed)
+		if err != nil {
+			return apperror.Wrap(err, "Failed to update task status")
+		}
+		if updateResult.MatchedCount == 0 {
+			return nil
+		}
+		return completeActionItem(ctx, h.db, taskID, updateData.Completed)
+	})
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to update task status"})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -336,45 +422,40 @@ func (h *DealFlowHandler) InvestInStartupHandler(c *fiber.Ctx) error {
 	// Get the deal ID from the URL parameters
 	id, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid deal flow ID"})
+		return apperror.Validation("invalid_deal_flow_id", "Invalid deal flow ID")
 	}
 
 	// Parse the request body
 	var request struct {
-		InvestmentAmount float64 `json:"investmentAmount"`
-	}
-
-	if err := c.BodyParser(&request); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		InvestmentAmount float64 `json:"investmentAmount" validate:"required,amount"`
 	}
 
-	// Validate the investment amount
-	if request.InvestmentAmount <= 0 {
-		return c.Status(400).JSON(fiber.Map{"error": "Investment amount must be greater than zero"})
+	if err := parseBody(c, &request); err != nil {
+		return err
 	}
 
 	// Get token from context
 	tokenStr, ok := c.Locals("token").(string)
 	if !ok || tokenStr == "" {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Validate token and extract claims
 	claims, err := utils.ValidateJWT(tokenStr)
 	if err != nil {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Convert claims.UserID to primitive.ObjectID
 	investorID, err := primitive.ObjectIDFromHex(claims.UserID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Validation("invalid_user_id", "Invalid user ID")
 	}
 
 	// Get the deal from the database
-	deal, err := h.db.DealFlow().GetDealFlowByID(c.Context(), id)
+	deal, err := h.db.Deals().GetDealFlowByID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "Deal not found"})
+		return apperror.Wrap(err, "Failed to retrieve deal")
 	}
 
 	// Create an investment record
@@ -389,29 +470,34 @@ func (h *DealFlowHandler) InvestInStartupHandler(c *fiber.Ctx) error {
 		UpdatedAt:      time.Now(),
 	}
 
-	// Save the investment record
-	_, err = h.db.Investment().CreateInvestment(c.Context(), investment)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to record investment"})
-	}
+	// Save the investment record with its InvestmentRecorded event, and the
+	// totals it changes, all or nothing
+	err = h.db.Transaction(c.UserContext(), func(ctx context.Context) error {
+		if _, err := h.db.Investments().CreateInvestment(ctx, investment); err != nil {
+			return apperror.Wrap(err, "Failed to record investment")
+		}
 
-	// Update startup's invested amount
-	_, err = h.db.User().UpdateStartupInvestment(c.Context(), deal.StartupID, request.InvestmentAmount)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to update startup investment"})
-	}
+		// Update startup's invested amount
+		if _, err := h.db.Founders().UpdateStartupInvestment(ctx, deal.StartupID, request.InvestmentAmount); err != nil {
+			return apperror.Wrap(err, "Failed to update startup investment")
+		}
 
-	// Update investor's total investment and portfolio
-	_, err = h.db.User().UpdateInvestorPortfolio(c.Context(), investorID, deal.StartupID, request.InvestmentAmount)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to update investor investment"})
-	}
+		// Update investor's total investment and portfolio
+		if _, err := h.db.Investors().UpdateInvestorPortfolio(ctx, investorID, deal.StartupID, request.InvestmentAmount); err != nil {
+			return apperror.Wrap(err, "Failed to update investor investment")
+		}
 
-	// Update the deal's fund required amount
-	_, err = h.db.User().UpdateDealFundRequired(c.Context(), id, -request.InvestmentAmount)
+		// Update the deal's fund required amount
+		if _, err := h.db.Deals().UpdateDealFundRequired(ctx, id, -request.InvestmentAmount); err != nil {
+			return apperror.Wrap(err, "Failed to update deal fund required")
+		}
+		return nil
+	})
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to update deal fund required"})
+		return err
 	}
+	metrics.Investments.Inc()
+	metrics.InvestmentAmount.Add(request.InvestmentAmount)
 
 	return c.JSON(fiber.Map{
 		"message":    "Investment recorded successfully",
@@ -423,33 +509,27 @@ func (h *DealFlowHandler) InvestInStartupHandler(c *fiber.Ctx) error {
 func (h *DealFlowHandler) UpdateDealStatusHandler(c *fiber.Ctx) error {
 	dealID := c.Params("id")
 	if dealID == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "Deal ID is required"})
+		return apperror.Validation("missing_deal_id", "Deal ID is required")
 	}
 
 	// Parse request body
 	data := struct {
-		Status string `json:"status"`
+		Status string `json:"status" validate:"required,oneof=active paused completed cancelled"`
 	}{}
-	if err := c.BodyParser(&data); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
-	}
-
-	// Validate status
-	validStatuses := map[string]bool{"active": true, "paused": true, "completed": true, "cancelled": true}
-	if !validStatuses[data.Status] {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid status"})
+	if err := parseBody(c, &data); err != nil {
+		return err
 	}
 
 	// Convert ID string to ObjectID
 	objID, err := primitive.ObjectIDFromHex(dealID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid deal ID format"})
+		return apperror.Validation("invalid_deal_id", "Invalid deal ID format")
 	}
 
 	// Update deal status
-	result, err := h.db.DealFlow().UpdateDealFlow(c.Context(), objID, bson.M{"status": data.Status})
+	result, err := h.db.Deals().UpdateDealStatus(c.UserContext(), objID, data.Status)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to update deal status"})
+		return apperror.Wrap(err, "Failed to update deal status")
 	}
 
 	return c.JSON(fiber.Map{"message": "Deal status updated successfully", "result": result})
@@ -459,34 +539,32 @@ func (h *DealFlowHandler) UpdateDealStatusHandler(c *fiber.Ctx) error {
 func (h *DealFlowHandler) UpdateDealStageHandler(c *fiber.Ctx) error {
 	dealID := c.Params("id")
 	if dealID == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "Deal ID is required"})
+		return apperror.Validation("missing_deal_id", "Deal ID is required")
 	}
 
 	// Parse request body
 	data := struct {
-		Stage string `json:"stage"`
+		Stage string `json:"stage" validate:"required,oneof=screening dueDiligence negotiation closed"`
 	}{}
-	if err := c.BodyParser(&data); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
-	}
-
-	// Validate stage
-	validStages := map[string]bool{"screening": true, "dueDiligence": true, "negotiation": true, "closed": true}
-	if !validStages[data.Stage] {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid stage"})
+	if err := parseBody(c, &data); err != nil {
+		return err
 	}
 
 	// Convert dealID to ObjectID
 	objID, err := primitive.ObjectIDFromHex(dealID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid deal ID"})
+		return apperror.Validation("invalid_deal_id", "Invalid deal ID")
 	}
 
-	// Update deal stage
-	_, err = h.db.DealFlow().UpdateDealStage(c.Context(), objID, data.Stage)
+	// Update deal stage, together with the DealStageChanged event
+	err = h.db.Transaction(c.UserContext(), func(ctx context.Context) error {
+		_, err := h.db.Deals().UpdateDealStage(ctx, objID, data.Stage)
+		return err
+	})
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to update deal stage"})
+		return apperror.Wrap(err, "Failed to update deal stage")
 	}
+	metrics.StageTransitions.WithLabelValues(data.Stage).Inc()
 
 	return c.JSON(fiber.Map{
 		"success": true,
@@ -502,25 +580,21 @@ func (h *DealFlowHandler) UpdateDealStageHandler(c *fiber.Ctx) error {
 func (h *DealFlowHandler) AddNoteHandler(c *fiber.Ctx) error {
 	dealID := c.Params("id")
 	if dealID == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "Deal ID is required"})
+		return apperror.Validation("missing_deal_id", "Deal ID is required")
 	}
 
 	// Parse request body
 	data := struct {
-		Content string `json:"content"`
+		Content string `json:"content" validate:"required,max=5000"`
 	}{}
-	if err := c.BodyParser(&data); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
-	}
-
-	if data.Content == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "Note content is required"})
+	if err := parseBody(c, &data); err != nil {
+		return err
 	}
 
 	// Convert dealID to ObjectID
 	objID, err := primitive.ObjectIDFromHex(dealID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid deal ID"})
+		return apperror.Validation("invalid_deal_id", "Invalid deal ID")
 	}
 
 	// Create note
@@ -531,9 +605,9 @@ func (h *DealFlowHandler) AddNoteHandler(c *fiber.Ctx) error {
 	}
 
 	// Add note to deal
-	err = h.db.DealFlow().AddNote(c.Context(), objID, note)
+	err = h.db.Deals().AddNote(c.UserContext(), objID, note)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to add note"})
+		return apperror.Wrap(err, "Failed to add note")
 	}
 
 	return c.JSON(fiber.Map{
@@ -568,7 +642,7 @@ func (h *DealFlowHandler) AddNoteHandler(c *fiber.Ctx) error {
 // 	}
 
 // 	// Update task status
-// 	_, err = h.db.User().UpdateTaskStatus(c.Context(), objID, objID, data.Completed)
+// 	_, err = h.db.Tasks().UpdateTaskStatus(c.UserContext(), objID, objID, data.Completed)
 // 	if err != nil {
 // 		return c.Status(500).JSON(fiber.Map{"error": "Failed to update task status"})
 // 	}
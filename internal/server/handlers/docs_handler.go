@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	_ "embed"
+	"encoding/json"
+
+	"DBackend/internal/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:embed docs.html
+var docsPage []byte
+
+// OpenAPISpec serves the OpenAPI document, encoded once at startup
+func OpenAPISpec() fiber.Handler {
+	spec, err := json.Marshal(openapi.Spec())
+	if err != nil {
+		panic(err)
+	}
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+		return c.Send(spec)
+	}
+}
+
+// Docs serves the interactive API documentation, which loads openapi.json
+// from the same directory
+func Docs(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Send(docsPage)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"DBackend/internal/apperror"
+	"DBackend/internal/database"
+	"DBackend/model"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Page sizes of the outbox listing
+const (
+	defaultEvents = 20
+	maxEvents     = 100
+)
+
+// ListEvents returns the latest events in the outbox, newest first, with
+// their delivery status. ?status narrows them to pending, delivered or
+// failed events.
+func ListEvents(db database.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		status := c.Query("status")
+		switch status {
+		case "", model.EventPending, model.EventDelivered, model.EventFailed:
+		default:
+			return apperror.Validation("invalid_status", "status must be pending, delivered or failed")
+		}
+		limit := defaultEvents
+		if raw := c.Query("limit"); raw != "" {
+			var err error
+			if limit, err = strconv.Atoi(raw); err != nil || limit <= 0 {
+				return apperror.Validation("invalid_limit", "limit must be a positive integer")
+			}
+			limit = min(limit, maxEvents)
+		}
+		events, err := db.Outbox().ListEvents(c.UserContext(), status, limit)
+		if err != nil {
+			return apperror.Wrap(err, "Failed to retrieve events")
+		}
+		return c.JSON(fiber.Map{"events": events})
+	}
+}
+
+// RetryEvent makes a failed event due again with a fresh set of retries.
+// Subscribers that already handled it are not called again.
+func RetryEvent(db database.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := primitive.ObjectIDFromHex(c.Params("id"))
+		if err != nil {
+			return apperror.Validation("invalid_event_id", "Invalid event ID")
+		}
+		if err := db.Outbox().RetryEvent(c.UserContext(), id, time.Now()); err != nil {
+			return apperror.Wrap(err, "Failed to retry event")
+		}
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"message": "Event will be delivered at the next poll",
+			"id":      id,
+		})
+	}
+}
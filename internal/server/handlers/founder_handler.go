@@ -1,13 +1,19 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
+	"DBackend/internal/apperror"
+	"DBackend/internal/config"
 	"DBackend/internal/database"
+	"DBackend/internal/metrics"
+	"DBackend/internal/validate"
 	"DBackend/model"
 	"DBackend/utils"
 
@@ -18,17 +24,18 @@ import (
 )
 
 type FounderHandler struct {
-	db database.Service
+	db      database.Service
+	storage config.Storage
 }
 
-func NewFounderHandler(db database.Service) *FounderHandler {
-	return &FounderHandler{db: db}
+func NewFounderHandler(db database.Service, storage config.Storage) *FounderHandler {
+	return &FounderHandler{db: db, storage: storage}
 }
 
 func (h *FounderHandler) UpdateFounderHandler(c *fiber.Ctx) error {
 	// Parse JSON data from form-data
 	data := new(struct {
-		StartupName       string `json:"startup_name"`
+		StartupName       string `json:"startup_name" validate:"required,max=200"`
 		MissionStatement  string `json:"mission_statement"`
 		Industry          string `json:"industry"`
 		FundingStage      string `json:"funding_stage"`
@@ -37,61 +44,58 @@ func (h *FounderHandler) UpdateFounderHandler(c *fiber.Ctx) error {
 		RevenueStreams    string `json:"revenue_streams"`
 		Traction          string `json:"traction"`
 		ScalingPotential  string `json:"scaling_potential"`
-		TotalInvested     int    `json:"total_invested"`
-		FundRequired      string `json:"fund_required"`
+		TotalInvested     int    `json:"total_invested" validate:"min=0"`
+		FundRequired      string `json:"fund_required" validate:"required"`
 		Competition       string `json:"competition"`
 		LeadershipTeam    string `json:"leadership_team"`
 		TeamSize          string `json:"team_size"`
 		Location          string `json:"location"`
-		StartupWebsite    string `json:"startup_website"`
+		StartupWebsite    string `json:"startup_website" validate:"url"`
 	})
 
 	// Extract JSON data from form field "data"
 	jsonData := c.FormValue("data")
 	if jsonData == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "Missing JSON data"})
+		return apperror.Validation("invalid_request_body", "Missing JSON data")
 	}
 
 	// Unmarshal JSON into struct
 	if err := json.Unmarshal([]byte(jsonData), data); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON format"})
+		return apperror.Validation("invalid_request_body", "Invalid JSON format")
+	}
+	if err := validate.Struct(data); err != nil {
+		return err
 	}
 
 	// Get the token from the context
 	userToken := c.Locals("token")
 	tokenStr, ok := userToken.(string)
 	if !ok || tokenStr == "" {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Validate token and extract claims
 	claims, err := utils.ValidateJWT(tokenStr)
 	if err != nil {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Convert claims.UserID to primitive.ObjectID
 	id, err := primitive.ObjectIDFromHex(claims.UserID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Validation("invalid_user_id", "Invalid user ID")
 	}
 
 	// Find the user based on the ID in the claims
-	userInterface, err := h.db.User().FindByID(c.Context(), "founders", id)
+	user, err := h.db.Founders().GetFounderByUserID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "User not found"})
-	}
-
-	// Assert correct type for the user object
-	user, ok := userInterface.(*model.Founder)
-	if !ok {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to parse user data"})
+		return apperror.Wrap(err, "Failed to retrieve user")
 	}
 
 	// Convert FundRequired from string to int
 	fundRequired, err := strconv.Atoi(data.FundRequired)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid fund required value"})
+		return apperror.Validation("invalid_fund_required", "Invalid fund required value")
 	}
 
 	// Handle pitch deck file upload
@@ -103,12 +107,12 @@ func (h *FounderHandler) UpdateFounderHandler(c *fiber.Ctx) error {
 
 		// Ensure the folder exists
 		if err := os.MkdirAll(folderPath, os.ModePerm); err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to create storage directory"})
+			return apperror.Wrap(err, "Failed to create storage directory")
 		}
 
 		// Save the file
 		if err := c.SaveFile(file, filePath); err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to save pitch deck file"})
+			return apperror.Wrap(err, "Failed to save pitch deck file")
 		}
 
 		// Update the pitch deck file name in the user struct
@@ -117,7 +121,7 @@ func (h *FounderHandler) UpdateFounderHandler(c *fiber.Ctx) error {
 
 	// Add validation for required fields
 	if data.StartupName == "" || data.Industry == "" || data.FundingStage == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "Missing required fields"})
+		return apperror.Validation("missing_fields", "Missing required fields")
 	}
 
 	// Ensure FundRequired is never nil by providing a default
@@ -149,8 +153,8 @@ func (h *FounderHandler) UpdateFounderHandler(c *fiber.Ctx) error {
 	user.StartupWebsite = data.StartupWebsite
 
 	// Save the updated data to MongoDB
-	if _, err := h.db.User().UpdateFounder(c.Context(), id, *user); err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to update profile"})
+	if _, err := h.db.Founders().UpdateFounder(c.UserContext(), id, *user); err != nil {
+		return apperror.Wrap(err, "Failed to update profile")
 	}
 	datad := fiber.Map{"message": "Founder profile updated successfully", "pitch_deck": *user}
 	return c.JSON(datad)
@@ -161,31 +165,25 @@ func (h *FounderHandler) GetFounderDetailsHandler(c *fiber.Ctx) error {
 	userToken := c.Locals("token")
 	tokenStr, ok := userToken.(string)
 	if !ok || tokenStr == "" {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Validate token and extract claims
 	claims, err := utils.ValidateJWT(tokenStr)
 	if err != nil {
-		return c.Status(402).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Convert claims.UserID to primitive.ObjectID
 	id, err := primitive.ObjectIDFromHex(claims.UserID)
 	if err != nil {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Unauthorized("invalid_user_id", "Invalid user ID")
 	}
 
 	// Find the founder in the database
-	userInterface, err := h.db.User().FindByID(c.Context(), "founders", id)
+	founder, err := h.db.Founders().GetFounderByUserID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(405).JSON(fiber.Map{"error": "Founder not found"})
-	}
-
-	// Assert correct type for the founder object
-	founder, ok := userInterface.(*model.Founder)
-	if !ok {
-		return c.Status(501).JSON(fiber.Map{"error": "Failed to parse founder data"})
+		return apperror.Wrap(err, "Failed to retrieve founder")
 	}
 
 	return c.JSON(fiber.Map{
@@ -215,31 +213,36 @@ func (h *FounderHandler) GetAllNotificationsHandler(c *fiber.Ctx) error {
 	userToken := c.Locals("token")
 	tokenStr, ok := userToken.(string)
 	if !ok || tokenStr == "" {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Validate token and extract claims
 	claims, err := utils.ValidateJWT(tokenStr)
 	if err != nil {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Convert claims.UserID to primitive.ObjectID
 	founderID, err := primitive.ObjectIDFromHex(claims.UserID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Validation("invalid_user_id", "Invalid user ID")
 	}
 	// get the founder where user_id = founderID
-	founder, err := h.db.User().GetFounderByUserID(c.Context(), founderID)
+	founder, err := h.db.Founders().GetFounderByUserID(c.UserContext(), founderID)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve founder"})
+		return apperror.Wrap(err, "Failed to retrieve founder")
 	}
 
 	founderID = founder.ID
 
-	notifications, err := h.db.User().GetAllNotificationsByFounder(c.Context(), founderID)
+	params, err := parseListQuery(c, database.NotificationListSpec)
+	if err != nil {
+		return err
+	}
+
+	notifications, err := h.db.Notifications().GetAllNotificationsByFounder(c.UserContext(), founderID, params)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve notifications"})
+		return apperror.Wrap(err, "Failed to retrieve notifications")
 	}
 
 	return c.JSON(notifications)
@@ -249,12 +252,12 @@ func (h *FounderHandler) GetAllNotificationsHandler(c *fiber.Ctx) error {
 func (h *FounderHandler) UpdateNotificationHandler(c *fiber.Ctx) error {
 	notificationID, err := primitive.ObjectIDFromHex(c.Params("notificationID"))
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid notification ID"})
+		return apperror.Validation("invalid_notification_id", "Invalid notification ID")
 	}
 
 	var updateData model.Notification
-	if err := c.BodyParser(&updateData); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	if err := parseBody(c, &updateData); err != nil {
+		return err
 	}
 
 	update := bson.M{
@@ -264,9 +267,9 @@ func (h *FounderHandler) UpdateNotificationHandler(c *fiber.Ctx) error {
 		"read_status": updateData.ReadStatus,
 	}
 
-	err = h.db.User().UpdateNotification(c.Context(), notificationID, update)
+	err = h.db.Notifications().UpdateNotification(c.UserContext(), notificationID, update)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to update notification"})
+		return apperror.Wrap(err, "Failed to update notification")
 	}
 
 	return c.JSON(fiber.Map{"message": "Notification updated successfully"})
@@ -275,14 +278,13 @@ func (h *FounderHandler) UpdateNotificationHandler(c *fiber.Ctx) error {
 // DeleteNotificationHandler deletes a specific notification for the authenticated founder.
 func (h *FounderHandler) DeleteNotificationHandler(c *fiber.Ctx) error {
 	notificationID, err := primitive.ObjectIDFromHex(c.Params("notificationID"))
-	fmt.Println(notificationID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid notification ID"})
+		return apperror.Validation("invalid_notification_id", "Invalid notification ID")
 	}
 
-	_, err = h.db.User().DeleteNotification(c.Context(), notificationID)
+	_, err = h.db.Notifications().DeleteNotification(c.UserContext(), notificationID)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete notification"})
+		return apperror.Wrap(err, "Failed to delete notification")
 	}
 
 	return c.JSON(fiber.Map{"message": "Notification deleted successfully"})
@@ -292,30 +294,30 @@ func (h *FounderHandler) DeleteNotificationHandler(c *fiber.Ctx) error {
 func (h *FounderHandler) GetFounderDashboardHandler(c *fiber.Ctx) error {
 	userID, ok := c.Locals("user_id").(string)
 	if !ok {
-		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+		return apperror.Unauthorized("unauthorized", "Unauthorized")
 	}
 
 	// Get founder data from database
 	founderID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Validation("invalid_user_id", "Invalid user ID")
 	}
 
 	// Get fundraising summary
-	fundraisingSummary, err := h.db.Founder().GetFundraisingSummary(c.Context(), founderID)
+	fundraisingSummary, err := h.db.Founders().GetFundraisingSummary(c.UserContext(), founderID)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to get fundraising summary"})
+		return apperror.Wrap(err, "Failed to get fundraising summary")
 	}
 
 	// Get investor engagement
-	investorEngagement, err := h.db.Founder().GetInvestorEngagement(c.Context(), founderID)
+	investorEngagement, err := h.db.Founders().GetInvestorEngagement(c.UserContext(), founderID)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to get investor engagement"})
+		return apperror.Wrap(err, "Failed to get investor engagement")
 	}
 
-	return c.JSON(fiber.Map{
-		"fundraisingSummary": fundraisingSummary,
-		"investorEngagement": investorEngagement,
+	return c.JSON(model.FounderDashboard{
+		FundraisingSummary: *fundraisingSummary,
+		InvestorEngagement: *investorEngagement,
 	})
 }
 
@@ -326,9 +328,9 @@ func (h *FounderHandler) GetGrantsHandler(c *fiber.Ctx) error {
 	region := c.Query("region")
 
 	// Get grants from database
-	grants, err := h.db.Founder().GetGrants(c.Context(), category, region)
+	grants, err := h.db.Grants().GetGrants(c.UserContext(), category, region)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to get grants"})
+		return apperror.Wrap(err, "Failed to get grants")
 	}
 
 	return c.JSON(fiber.Map{
@@ -340,71 +342,76 @@ func (h *FounderHandler) GetGrantsHandler(c *fiber.Ctx) error {
 func (h *FounderHandler) SubmitGrantApplicationHandler(c *fiber.Ctx) error {
 	userID, ok := c.Locals("user_id").(string)
 	if !ok {
-		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+		return apperror.Unauthorized("unauthorized", "Unauthorized")
 	}
 
 	// Fiber doesn't have ParseMultipartForm, it automatically parses the form
 	// when you call FormValue or FormFile
 
 	// Get form data
-	grantIDStr := c.FormValue("grantId")
-	startupName := c.FormValue("startupName")
-	contactEmail := c.FormValue("contactEmail")
-	contactPhone := c.FormValue("contactPhone")
-	description := c.FormValue("description")
-	website := c.FormValue("website")
-	teamSize := c.FormValue("teamSize")
-	previousFunding := c.FormValue("previousFunding")
-
-	// Validate required fields
-	if grantIDStr == "" || startupName == "" || contactEmail == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "Missing required fields"})
-	}
-
-	// Convert grantID to int
-	grantID, err := strconv.Atoi(grantIDStr)
-	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid grant ID"})
+	form := new(struct {
+		GrantID         string `form:"grantId" validate:"required,objectid"`
+		StartupName     string `form:"startupName" validate:"required,max=200"`
+		ContactEmail    string `form:"contactEmail" validate:"required,email"`
+		ContactPhone    string `form:"contactPhone"`
+		Description     string `form:"description" validate:"max=5000"`
+		Website         string `form:"website" validate:"url"`
+		TeamSize        string `form:"teamSize"`
+		PreviousFunding string `form:"previousFunding"`
+	})
+	if err := parseBody(c, form); err != nil {
+		return err
+	}
+
+	grantID, _ := primitive.ObjectIDFromHex(form.GrantID)
+	if _, err := h.db.Grants().GetGrantByID(c.UserContext(), grantID); err != nil {
+		return apperror.Wrap(err, "Failed to retrieve grant")
 	}
 
 	// Get pitch deck file
 	file, err := c.FormFile("pitchDeck")
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Pitch deck is required"})
+		return apperror.Validation("missing_pitch_deck", "Pitch deck is required")
 	}
 
 	// Save file
 	filename := fmt.Sprintf("%s-%s-%s", userID, time.Now().Format("20060102150405"), file.Filename)
-	if err := c.SaveFile(file, "./uploads/"+filename); err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to save file"})
+	pitchDeckPath := filepath.Join(h.storage.UploadDir, filename)
+	if err := c.SaveFile(file, pitchDeckPath); err != nil {
+		return apperror.Wrap(err, "Failed to save file")
 	}
 
 	// Create application
 	founderID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Validation("invalid_user_id", "Invalid user ID")
 	}
 
 	application := model.GrantApplication{
 		ID:              primitive.NewObjectID(),
 		FounderID:       founderID,
 		GrantID:         grantID,
-		StartupName:     startupName,
-		ContactEmail:    contactEmail,
-		ContactPhone:    contactPhone,
-		Description:     description,
-		Website:         website,
-		TeamSize:        teamSize,
-		PreviousFunding: previousFunding,
-		PitchDeckPath:   "./uploads/" + filename,
+		StartupName:     form.StartupName,
+		ContactEmail:    form.ContactEmail,
+		ContactPhone:    form.ContactPhone,
+		Description:     form.Description,
+		Website:         form.Website,
+		TeamSize:        form.TeamSize,
+		PreviousFunding: form.PreviousFunding,
+		PitchDeckPath:   pitchDeckPath,
 		CreatedAt:       time.Now(),
 	}
 
-	// Save application to database
-	appID, err := h.db.Founder().SubmitGrantApplication(c.Context(), application)
+	// Save application to database, together with its GrantApplicationSubmitted event
+	var appID primitive.ObjectID
+	err = h.db.Transaction(c.UserContext(), func(ctx context.Context) error {
+		appID, err = h.db.Grants().SubmitGrantApplication(ctx, application)
+		return err
+	})
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to submit application"})
+		return apperror.Wrap(err, "Failed to submit application")
 	}
+	metrics.GrantApplications.Inc()
 
 	return c.JSON(fiber.Map{
 		"success":       true,
@@ -420,9 +427,9 @@ func (h *FounderHandler) GetInvestorsHandler(c *fiber.Ctx) error {
 	stage := c.Query("stage")
 
 	// Get investors from database
-	investors, err := h.db.Founder().GetInvestors(c.Context(), industry, stage)
+	investors, err := h.db.Investors().GetInvestors(c.UserContext(), industry, stage)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to get investors"})
+		return apperror.Wrap(err, "Failed to get investors")
 	}
 
 	return c.JSON(fiber.Map{
@@ -436,33 +443,28 @@ func (h *FounderHandler) SubmitInvestorApplicationHandler(c *fiber.Ctx) error {
 	userToken := c.Locals("token")
 	tokenStr, ok := userToken.(string)
 	if !ok || tokenStr == "" {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Validate token and extract claims
 	claims, err := utils.ValidateJWT(tokenStr)
 	if err != nil {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Get user ID from claims
 	userID := claims.UserID
 
-	// Parse request body
+	// Parse and validate request body
 	data := new(model.InvestorApplication)
-	if err := c.BodyParser(data); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
-	}
-
-	// Validate required fields
-	if data.InvestorID.IsZero() || data.FundingAmount == 0 {
-		return c.Status(400).JSON(fiber.Map{"error": "Missing required fields"})
+	if err := parseBody(c, data); err != nil {
+		return err
 	}
 
 	// Convert IDs to ObjectID
 	founderID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Validation("invalid_user_id", "Invalid user ID")
 	}
 
 	// No conversion needed as data.InvestorID is already primitive.ObjectID
@@ -479,9 +481,9 @@ func (h *FounderHandler) SubmitInvestorApplicationHandler(c *fiber.Ctx) error {
 	}
 
 	// Save application to database
-	appID, err := h.db.Founder().SubmitInvestorApplication(c.Context(), application)
+	appID, err := h.db.Founders().SubmitInvestorApplication(c.UserContext(), application)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to submit application"})
+		return apperror.Wrap(err, "Failed to submit application")
 	}
 
 	return c.JSON(fiber.Map{
@@ -1,7 +1,12 @@
 package handlers
 
 import (
+    "context"
+
+    "DBackend/internal/apperror"
     "DBackend/internal/database"
+    "DBackend/internal/metrics"
+    "DBackend/internal/query"
     "DBackend/model"
     "time"
 
@@ -13,17 +18,17 @@ import (
 func CreateGrant(db database.Service) fiber.Handler {
     return func(c *fiber.Ctx) error {
         var grant model.Grant
-        if err := c.BodyParser(&grant); err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+        if err := parseBody(c, &grant); err != nil {
+            return err
         }
 
         grant.ID = primitive.NewObjectID()
         grant.CreatedAt = time.Now()
         grant.UpdatedAt = time.Now()
 
-        result, err := db.Founder().CreateGrant(c.Context(), grant)
+        result, err := db.Grants().CreateGrant(c.UserContext(), grant)
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to create grant"})
+            return apperror.Wrap(err, "Failed to create grant")
         }
 
         return c.Status(201).JSON(fiber.Map{
@@ -38,12 +43,12 @@ func GetGrantByID(db database.Service) fiber.Handler {
     return func(c *fiber.Ctx) error {
         id, err := primitive.ObjectIDFromHex(c.Params("id"))
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid grant ID"})
+            return apperror.Validation("invalid_grant_id", "Invalid grant ID")
         }
 
-        grant, err := db.Founder().GetGrantByID(c.Context(), id)
+        grant, err := db.Grants().GetGrantByID(c.UserContext(), id)
         if err != nil {
-            return c.Status(404).JSON(fiber.Map{"error": "Grant not found"})
+            return apperror.Wrap(err, "Failed to retrieve grant")
         }
 
         return c.JSON(grant)
@@ -55,19 +60,19 @@ func UpdateGrant(db database.Service) fiber.Handler {
     return func(c *fiber.Ctx) error {
         id, err := primitive.ObjectIDFromHex(c.Params("id"))
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid grant ID"})
+            return apperror.Validation("invalid_grant_id", "Invalid grant ID")
         }
 
         var updates model.Grant
-        if err := c.BodyParser(&updates); err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+        if err := parseBody(c, &updates); err != nil {
+            return err
         }
 
         updates.UpdatedAt = time.Now()
 
-        err = db.Founder().UpdateGrant(c.Context(), id, updates)
+        err = db.Grants().UpdateGrant(c.UserContext(), id, updates)
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to update grant"})
+            return apperror.Wrap(err, "Failed to update grant")
         }
 
         return c.JSON(fiber.Map{"message": "Grant updated successfully"})
@@ -79,12 +84,12 @@ func DeleteGrant(db database.Service) fiber.Handler {
     return func(c *fiber.Ctx) error {
         id, err := primitive.ObjectIDFromHex(c.Params("id"))
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid grant ID"})
+            return apperror.Validation("invalid_grant_id", "Invalid grant ID")
         }
 
-        err = db.Founder().DeleteGrant(c.Context(), id)
+        err = db.Grants().DeleteGrant(c.UserContext(), id)
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to delete grant"})
+            return apperror.Wrap(err, "Failed to delete grant")
         }
 
         return c.JSON(fiber.Map{"message": "Grant deleted successfully"})
@@ -95,8 +100,8 @@ func DeleteGrant(db database.Service) fiber.Handler {
 func ApplyForGrant(db database.Service) fiber.Handler {
     return func(c *fiber.Ctx) error {
         var application model.GrantApplication
-        if err := c.BodyParser(&application); err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+        if err := parseBody(c, &application); err != nil {
+            return err
         }
 
         application.ID = primitive.NewObjectID()
@@ -106,19 +111,27 @@ func ApplyForGrant(db database.Service) fiber.Handler {
         // Get user ID from JWT token
         userID, ok := c.Locals("user_id").(string)
         if !ok {
-            return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+            return apperror.Unauthorized("unauthorized", "Unauthorized")
         }
 
         founderID, err := primitive.ObjectIDFromHex(userID)
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+            return apperror.Validation("invalid_user_id", "Invalid user ID")
         }
         application.FounderID = founderID
 
-        result, err := db.Founder().SubmitGrantApplication(c.Context(), application)
+        // The application and its GrantApplicationSubmitted event are kept
+        // together on a replica set; a standalone server writes them one
+        // after the other, and a failure in between loses the event
+        var result primitive.ObjectID
+        err = db.Transaction(c.UserContext(), func(ctx context.Context) error {
+            result, err = db.Grants().SubmitGrantApplication(ctx, application)
+            return err
+        })
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to submit application"})
+            return apperror.Wrap(err, "Failed to submit application")
         }
+        metrics.GrantApplications.Inc()
 
         return c.Status(201).JSON(fiber.Map{
             "message":       "Application submitted successfully",
@@ -127,40 +140,39 @@ func ApplyForGrant(db database.Service) fiber.Handler {
     }
 }
 
-// GetGrantApplications handles retrieving all grant applications
+// GetGrantApplications handles retrieving a page of grant applications
 func GetGrantApplications(db database.Service) fiber.Handler {
     return func(c *fiber.Ctx) error {
         // Check if user is admin or founder
         userID, ok := c.Locals("user_id").(string)
         if !ok {
-            return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+            return apperror.Unauthorized("unauthorized", "Unauthorized")
         }
 
-        role, ok := c.Locals("user_role").(string)
-        if !ok {
-            return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+        params, err := parseListQuery(c, database.GrantApplicationListSpec)
+        if err != nil {
+            return err
         }
 
-        var applications []model.GrantApplication
-        var err error
+        var applications query.Page[model.GrantApplication]
 
-        if role == "admin" {
+        if hasRole(c, "admin") {
             // Admins can see all applications
-            applications, err = db.Founder().GetAllGrantApplications(c.Context())
+            applications, err = db.Grants().GetAllGrantApplications(c.UserContext(), params)
         } else {
             // Founders can only see their own applications
-            founderID, err := primitive.ObjectIDFromHex(userID)
-            if err != nil {
-                return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+            founderID, idErr := primitive.ObjectIDFromHex(userID)
+            if idErr != nil {
+                return apperror.Validation("invalid_user_id", "Invalid user ID")
             }
-            applications, err = db.Founder().GetFounderGrantApplications(c.Context(), founderID)
+            applications, err = db.Grants().GetFounderGrantApplications(c.UserContext(), founderID, params)
         }
 
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve applications"})
+            return apperror.Wrap(err, "Failed to retrieve applications")
         }
 
-        return c.JSON(fiber.Map{"applications": applications})
+        return c.JSON(applications)
     }
 }
 
@@ -169,34 +181,29 @@ func GetGrantApplicationByID(db database.Service) fiber.Handler {
     return func(c *fiber.Ctx) error {
         id, err := primitive.ObjectIDFromHex(c.Params("id"))
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid application ID"})
+            return apperror.Validation("invalid_application_id", "Invalid application ID")
         }
 
-        application, err := db.Founder().GetGrantApplicationByID(c.Context(), id)
+        application, err := db.Grants().GetGrantApplicationByID(c.UserContext(), id)
         if err != nil {
-            return c.Status(404).JSON(fiber.Map{"error": "Application not found"})
+            return apperror.Wrap(err, "Failed to retrieve application")
         }
 
         // Check if user is authorized to view this application
         userID, ok := c.Locals("user_id").(string)
         if !ok {
-            return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
-        }
-
-        role, ok := c.Locals("user_role").(string)
-        if !ok {
-            return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+            return apperror.Unauthorized("unauthorized", "Unauthorized")
         }
 
         // If not admin, check if application belongs to user
-        if role != "admin" {
+        if !hasRole(c, "admin") {
             founderID, err := primitive.ObjectIDFromHex(userID)
             if err != nil {
-                return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+                return apperror.Validation("invalid_user_id", "Invalid user ID")
             }
 
             if application.FounderID != founderID {
-                return c.Status(403).JSON(fiber.Map{"error": "Forbidden"})
+                return apperror.Forbidden("forbidden", "Forbidden")
             }
         }
 
@@ -209,27 +216,26 @@ func UpdateGrantApplication(db database.Service) fiber.Handler {
     return func(c *fiber.Ctx) error {
         id, err := primitive.ObjectIDFromHex(c.Params("id"))
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid application ID"})
+            return apperror.Validation("invalid_application_id", "Invalid application ID")
         }
 
         var updates struct {
-            Status  string `json:"status"`
-            Remarks string `json:"remarks"`
+            Status  string `json:"status" validate:"required,oneof=Pending Approved Rejected"`
+            Remarks string `json:"remarks" validate:"max=2000"`
         }
 
-        if err := c.BodyParser(&updates); err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+        if err := parseBody(c, &updates); err != nil {
+            return err
         }
 
         // Only admins can update application status
-        role, ok := c.Locals("user_role").(string)
-        if !ok || role != "admin" {
-            return c.Status(403).JSON(fiber.Map{"error": "Forbidden"})
+        if !hasRole(c, "admin") {
+            return apperror.Forbidden("forbidden", "Forbidden")
         }
 
-        err = db.Founder().UpdateGrantApplication(c.Context(), id, updates.Status, updates.Remarks)
+        err = db.Grants().UpdateGrantApplication(c.UserContext(), id, updates.Status, updates.Remarks)
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to update application"})
+            return apperror.Wrap(err, "Failed to update application")
         }
 
         return c.JSON(fiber.Map{"message": "Application updated successfully"})
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"DBackend/internal/health"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Liveness reports that the process is serving requests; it checks no dependencies
+func Liveness(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": health.StatusUp})
+}
+
+// Readiness probes every dependency and returns 503 when a critical one is down
+func Readiness(checker *health.Checker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		report := checker.Run(c.UserContext())
+		if report.Status == health.StatusDown {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(report)
+		}
+		return c.JSON(report)
+	}
+}
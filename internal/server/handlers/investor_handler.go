@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"fmt"
-	"time"
+	"strconv"
+	"strings"
 
+	"DBackend/internal/apperror"
+	"DBackend/internal/config"
 	"DBackend/internal/database"
 	"DBackend/internal/server/services"
 	"DBackend/model"
@@ -16,12 +19,14 @@ import (
 
 // InvestorHandler struct to handle investor-related operations
 type InvestorHandler struct {
-	db database.Service
+	db       database.Service
+	calendar config.Calendar
+	provider services.CalendarProvider
 }
 
 // NewInvestorHandler initializes an InvestorHandler
-func NewInvestorHandler(db database.Service) *InvestorHandler {
-	return &InvestorHandler{db: db}
+func NewInvestorHandler(db database.Service, calendar config.Calendar) *InvestorHandler {
+	return &InvestorHandler{db: db, calendar: calendar, provider: services.NewCalendarProvider(calendar, db)}
 }
 
 // UpdateInvestorHandler handles updating investor profile
@@ -29,7 +34,7 @@ func (h *InvestorHandler) UpdateInvestorHandler(c *fiber.Ctx) error {
 	// Parse JSON data from form-data
 	data := new(struct {
 		//		InvestmentPortfolio   []string `json:"investment_portfolio"`
-		TotalInvested         float64  `json:"total_invested"`
+		TotalInvested         float64  `json:"total_invested" validate:"min=0"`
 		InvestorType          string   `json:"investor_type"`
 		Thesis                string   `json:"thesis"`
 		PreferredFundingStage string   `json:"preferred_funding_stage"`
@@ -41,40 +46,33 @@ func (h *InvestorHandler) UpdateInvestorHandler(c *fiber.Ctx) error {
 		PreferredRegions      []string `json:"preferred_regions"`
 	})
 
-	if err := c.BodyParser(data); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request"})
+	if err := parseBody(c, data); err != nil {
+		return err
 	}
-	fmt.Println("data", data)
 
 	// Get the token from the context
 	userToken := c.Locals("token")
 	tokenStr, ok := userToken.(string)
 	if !ok || tokenStr == "" {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Validate token and extract claims
 	claims, err := utils.ValidateJWT(tokenStr)
 	if err != nil {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Convert claims.UserID to primitive.ObjectID
 	id, err := primitive.ObjectIDFromHex(claims.UserID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Validation("invalid_user_id", "Invalid user ID")
 	}
 
 	// Find the investor in the database
-	userInterface, err := h.db.User().FindByID(c.Context(), "investors", id)
+	investor, err := h.db.Investors().GetInvestorByUserID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "Investor not found"})
-	}
-
-	// Assert correct type for the investor object
-	investor, ok := userInterface.(*model.Investor)
-	if !ok {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to parse investor data"})
+		return apperror.Wrap(err, "Failed to retrieve investor")
 	}
 
 	// Update the investor's profile with provided data
@@ -91,8 +89,8 @@ func (h *InvestorHandler) UpdateInvestorHandler(c *fiber.Ctx) error {
 	investor.PreferredRegions = data.PreferredRegions
 
 	// Save the updated data to MongoDB
-	if _, err := h.db.User().UpdateInvestor(c.Context(), id, *investor); err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to update investor profile"})
+	if _, err := h.db.Investors().UpdateInvestor(c.UserContext(), id, *investor); err != nil {
+		return apperror.Wrap(err, "Failed to update investor profile")
 	}
 
 	return c.JSON(fiber.Map{"message": "Investor profile updated successfully"})
@@ -103,31 +101,25 @@ func (h *InvestorHandler) GetInvestorDetailsHandler(c *fiber.Ctx) error {
 	// Get the token from the context
 	tokenStr, ok := c.Locals("token").(string)
 	if !ok || tokenStr == "" {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Validate token and extract claims
 	claims, err := utils.ValidateJWT(tokenStr)
 	if err != nil {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Convert claims.UserID to primitive.ObjectID
 	id, err := primitive.ObjectIDFromHex(claims.UserID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Validation("invalid_user_id", "Invalid user ID")
 	}
 
 	// Find the investor in the database
-	userInterface, err := h.db.User().FindByID(c.Context(), "investors", id)
+	investor, err := h.db.Investors().GetInvestorByUserID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "Investor not found"})
-	}
-
-	// Assert correct type for the investor object
-	investor, ok := userInterface.(*model.Investor)
-	if !ok {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to parse investor data"})
+		return apperror.Wrap(err, "Failed to retrieve investor")
 	}
 
 	// Return the investor's profile in the same format as the frontend
@@ -146,35 +138,111 @@ func (h *InvestorHandler) GetInvestorDetailsHandler(c *fiber.Ctx) error {
 	})
 }
 
-// GetStartupDetailsHandler handles retrieving startup details from the founders
+// GetStartupDetailsHandler handles faceted startup discovery over the founders
+// collection with filtering, sorting and cursor pagination
 func (h *InvestorHandler) GetStartupDetailsHandler(c *fiber.Ctx) error {
-	// Get all the founder details and return a JSON response
-	founders, err := h.db.User().GetStartupDetails(c.Context())
+	query, err := parseStartupQuery(c)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve startup details"})
+		return err
 	}
 
-	// Return the founders' details
-	return c.JSON(fiber.Map{"founders": founders})
+	page, err := h.db.Founders().DiscoverStartups(c.UserContext(), query)
+	if err != nil {
+		return apperror.Wrap(queryError(err), "Failed to retrieve startup details")
+	}
+
+	return c.JSON(page)
 }
 
 func (h *InvestorHandler) GetFounderProfilesHandler(c *fiber.Ctx) error {
-	// Get all founder profiles
-	founders, err := h.db.User().GetStartupDetails(c.Context())
+	query, err := parseStartupQuery(c)
+	if err != nil {
+		return err
+	}
+
+	// Get one page of founder profiles
+	page, err := h.db.Founders().DiscoverStartups(c.UserContext(), query)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve founder profiles"})
+		return apperror.Wrap(queryError(err), "Failed to retrieve founder profiles")
 	}
 
-	var founderProfiles []bson.M
-	for _, founder := range founders {
-		profile, err := h.db.User().GetFounderProfileWithMatch(c.Context(), founder.UserID)
+	founderProfiles := []bson.M{}
+	for _, founder := range page.Founders {
+		profile, err := h.db.Founders().GetFounderProfileWithMatch(c.UserContext(), founder.UserID)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve founder profile"})
+			return apperror.Wrap(err, "Failed to retrieve founder profile")
 		}
 		founderProfiles = append(founderProfiles, profile)
 	}
 
-	return c.JSON(fiber.Map{"founder_profiles": founderProfiles})
+	return c.JSON(fiber.Map{
+		"founder_profiles": founderProfiles,
+		"total":            page.Total,
+		"next_cursor":      page.NextCursor,
+	})
+}
+
+// parseStartupQuery reads discovery filters from the query string. Facet
+// filters accept comma-separated values, e.g. ?industry=Fintech,AI/ML
+func parseStartupQuery(c *fiber.Ctx) (database.StartupQuery, error) {
+	query := database.StartupQuery{
+		Industries:    splitQueryList(c.Query("industry")),
+		FundingStages: splitQueryList(c.Query("funding_stage")),
+		Locations:     splitQueryList(c.Query("location")),
+		TeamSizes:     splitQueryList(c.Query("team_size")),
+		Cursor:        c.Query("cursor"),
+	}
+
+	var err error
+	if query.FundRequired.Min, err = parseOptionalInt(c, "fund_required_min"); err != nil {
+		return query, err
+	}
+	if query.FundRequired.Max, err = parseOptionalInt(c, "fund_required_max"); err != nil {
+		return query, err
+	}
+	if query.TotalInvested.Min, err = parseOptionalInt(c, "total_invested_min"); err != nil {
+		return query, err
+	}
+	if query.TotalInvested.Max, err = parseOptionalInt(c, "total_invested_max"); err != nil {
+		return query, err
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil || query.Limit <= 0 {
+			return query, apperror.Validation("invalid_limit", "limit must be a positive integer")
+		}
+	}
+
+	// A leading "-" sorts descending, e.g. ?sort=-fund_required
+	if sortBy := c.Query("sort"); sortBy != "" {
+		query.SortDesc = strings.HasPrefix(sortBy, "-")
+		query.SortField = strings.TrimPrefix(sortBy, "-")
+	}
+
+	return query, nil
+}
+
+func splitQueryList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func parseOptionalInt(c *fiber.Ctx, key string) (*int, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, apperror.Validation("invalid_query", fmt.Sprintf("%s must be an integer", key))
+	}
+	return &value, nil
 }
 
 // convertToObjectIDArray converts an array of string IDs to ObjectIDs
@@ -193,12 +261,12 @@ func convertToObjectIDArray(ids []string) []primitive.ObjectID {
 func (h *InvestorHandler) GetMeetingsHandler(c *fiber.Ctx) error {
 	id, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid investor ID"})
+		return apperror.Validation("invalid_investor_id", "Invalid investor ID")
 	}
 
-	meetings, err := h.db.User().GetMeetings(c.Context(), id)
+	meetings, err := h.db.Meetings().GetMeetings(c.UserContext(), id)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve meetings"})
+		return apperror.Wrap(err, "Failed to retrieve meetings")
 	}
 
 	return c.JSON(fiber.Map{"meetings": meetings})
@@ -208,82 +276,81 @@ func (h *InvestorHandler) GetMeetingsHandler(c *fiber.Ctx) error {
 func (h *InvestorHandler) AddMeetingHandler(c *fiber.Ctx) error {
 	id, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid investor ID"})
+		return apperror.Validation("invalid_investor_id", "Invalid investor ID")
 	}
 
-	var meeting model.Meeting
-	if err := c.BodyParser(&meeting); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	warn, err := warnOnConflict(c)
+	if err != nil {
+		return err
 	}
 
-	fmt.Println("meeting", meeting)
+	var meeting model.Meeting
+	if err := parseBody(c, &meeting); err != nil {
+		return err
+	}
 
 	meeting.ID = primitive.NewObjectID()
 
-	// Create Google Calendar event
-	calendarService, err := services.NewGoogleCalendarService()
+	// Create the calendar event, organized by the current user
+	organizer, err := currentUserID(c)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to initialize Google Calendar service"})
+		return err
 	}
-
-	event, err := calendarService.CreateEvent(
-		meeting.Title,
-		"", // Location
-		meeting.Notes,
-		meeting.StartTime.Format(time.RFC3339),
-		meeting.EndTime.Format(time.RFC3339),
-		"UTC",
-		[]string{}, // Attendees
-	)
+	conflicts, err := planMeeting(c.UserContext(), h.db, organizer, &meeting, warn)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to create Google Calendar event"})
+		return err
+	}
+	if err := scheduleOnCalendar(c.UserContext(), h.db, h.calendar, h.provider, organizer, &meeting); err != nil {
+		return err
 	}
 
-	meeting.GoogleMeetURL = event.HangoutLink
-
-	updateResult, err := h.db.DealFlow().AddMeeting(c.Context(), id, meeting)
+	updateResult, err := h.db.Deals().AddMeeting(c.UserContext(), id, meeting)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to add meeting"})
+		return apperror.Wrap(err, "Failed to add meeting")
 	}
 
-	return c.JSON(fiber.Map{"message": "Meeting added successfully", "modifiedCount": updateResult.ModifiedCount})
+	response := fiber.Map{"message": "Meeting added successfully", "modifiedCount": updateResult.ModifiedCount}
+	if len(conflicts) > 0 {
+		response["conflicts"] = conflicts
+	}
+	return c.JSON(response)
 }
 
 // GetInvestorDashboardHandler returns dashboard summary for investors
 func (h *InvestorHandler) GetInvestorDashboardHandler(c *fiber.Ctx) error {
 	userID, ok := c.Locals("user_id").(string)
 	if !ok {
-		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+		return apperror.Unauthorized("unauthorized", "Unauthorized")
 	}
 
 	// Get investor data from database
 	investorID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Validation("invalid_user_id", "Invalid user ID")
 	}
 
 	// Get portfolio summary
-	portfolioSummary, err := h.db.Investor().GetPortfolioSummary(c.Context(), investorID)
+	portfolioSummary, err := h.db.Investors().GetPortfolioSummary(c.UserContext(), investorID)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to get portfolio summary"})
+		return apperror.Wrap(err, "Failed to get portfolio summary")
 	}
 
 	// Get pipeline summary
-	pipelineSummary, err := h.db.Investor().GetPipelineSummary(c.Context(), investorID)
+	pipelineSummary, err := h.db.Investors().GetPipelineSummary(c.UserContext(), investorID)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to get pipeline summary"})
+		return apperror.Wrap(err, "Failed to get pipeline summary")
 	}
 
 	// Get recent activities
-	recentActivities, err := h.db.Investor().GetRecentActivities(c.Context(), investorID)
+	recentActivities, err := h.db.Investors().GetRecentActivities(c.UserContext(), investorID)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to get recent activities"})
+		return apperror.Wrap(err, "Failed to get recent activities")
 	}
 
-	return c.JSON(fiber.Map{
-		"portfolioSummary": portfolioSummary,
-		"pipelineSummary":  pipelineSummary,
-		"recentActivities": recentActivities,
+	return c.JSON(model.InvestorDashboard{
+		PortfolioSummary: *portfolioSummary,
+		PipelineSummary:  *pipelineSummary,
+		RecentActivities: recentActivities,
 	})
 }
 
@@ -291,7 +358,7 @@ func (h *InvestorHandler) GetInvestorDashboardHandler(c *fiber.Ctx) error {
 func (h *InvestorHandler) GetPortfolioPerformanceHandler(c *fiber.Ctx) error {
 	userID, ok := c.Locals("user_id").(string)
 	if !ok {
-		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+		return apperror.Unauthorized("unauthorized", "Unauthorized")
 	}
 
 	// Get period from query params
@@ -300,30 +367,30 @@ func (h *InvestorHandler) GetPortfolioPerformanceHandler(c *fiber.Ctx) error {
 	// Validate period
 	validPeriods := map[string]bool{"1m": true, "3m": true, "6m": true, "1y": true, "all": true}
 	if !validPeriods[period] {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid period"})
+		return apperror.Validation("invalid_period", "Invalid period")
 	}
 
 	// Get investor data from database
 	investorID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Validation("invalid_user_id", "Invalid user ID")
 	}
 
 	// Get performance data
-	performanceData, err := h.db.Investor().GetPerformanceData(c.Context(), investorID, period)
+	performanceData, err := h.db.Investors().GetPerformanceData(c.UserContext(), investorID, period)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to get performance data"})
+		return apperror.Wrap(err, "Failed to get performance data")
 	}
 
 	// Get metrics
-	metrics, err := h.db.Investor().GetPerformanceMetrics(c.Context(), investorID, period)
+	metrics, err := h.db.Investors().GetPerformanceMetrics(c.UserContext(), investorID, period)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to get performance metrics"})
+		return apperror.Wrap(err, "Failed to get performance metrics")
 	}
 
-	return c.JSON(fiber.Map{
-		"performanceData": performanceData,
-		"metrics":         metrics,
+	return c.JSON(model.PortfolioPerformance{
+		PerformanceData: performanceData,
+		Metrics:         *metrics,
 	})
 }
 
@@ -332,43 +399,48 @@ func (h *InvestorHandler) GetAllNotificationsHandler(c *fiber.Ctx) error {
 	// Get the user ID from the context (set by JWT middleware)
 	userID, ok := c.Locals("user_id").(string)
 	if !ok {
-		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+		return apperror.Unauthorized("unauthorized", "Unauthorized")
 	}
 
 	// Convert string ID to ObjectID
 	investorID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Validation("invalid_user_id", "Invalid user ID")
+	}
+
+	params, err := parseListQuery(c, database.NotificationListSpec)
+	if err != nil {
+		return err
 	}
 
 	// Get notifications for this investor
-	notifications, err := h.db.User().GetAllNotificationsByFounder(c.Context(), investorID)
+	notifications, err := h.db.Notifications().GetAllNotificationsByFounder(c.UserContext(), investorID, params)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve notifications"})
+		return apperror.Wrap(err, "Failed to retrieve notifications")
 	}
 
-	return c.JSON(fiber.Map{"notifications": notifications})
+	return c.JSON(notifications)
 }
 
 // UpdateNotificationHandler updates a specific notification for the authenticated investor
 func (h *InvestorHandler) UpdateNotificationHandler(c *fiber.Ctx) error {
 	notificationID, err := primitive.ObjectIDFromHex(c.Params("notificationID"))
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid notification ID"})
+		return apperror.Validation("invalid_notification_id", "Invalid notification ID")
 	}
 
 	var updateData struct {
 		ReadStatus bool `json:"read_status"`
 	}
-	if err := c.BodyParser(&updateData); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	if err := parseBody(c, &updateData); err != nil {
+		return err
 	}
 
 	// Update the notification
 	updateFields := bson.M{"read_status": updateData.ReadStatus}
-	err = h.db.User().UpdateNotification(c.Context(), notificationID, updateFields)
+	err = h.db.Notifications().UpdateNotification(c.UserContext(), notificationID, updateFields)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to update notification"})
+		return apperror.Wrap(err, "Failed to update notification")
 	}
 
 	return c.JSON(fiber.Map{"message": "Notification updated successfully"})
@@ -378,13 +450,13 @@ func (h *InvestorHandler) UpdateNotificationHandler(c *fiber.Ctx) error {
 func (h *InvestorHandler) DeleteNotificationHandler(c *fiber.Ctx) error {
 	notificationID, err := primitive.ObjectIDFromHex(c.Params("notificationID"))
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid notification ID"})
+		return apperror.Validation("invalid_notification_id", "Invalid notification ID")
 	}
 
 	// Delete the notification
-	_, err = h.db.User().DeleteNotification(c.Context(), notificationID)
+	_, err = h.db.Notifications().DeleteNotification(c.UserContext(), notificationID)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete notification"})
+		return apperror.Wrap(err, "Failed to delete notification")
 	}
 
 	return c.JSON(fiber.Map{"message": "Notification deleted successfully"})
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"DBackend/internal/apperror"
+	"DBackend/internal/database"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Page sizes of the job run history
+const (
+	defaultJobRuns = 20
+	maxJobRuns     = 100
+)
+
+// ListJobs returns every background job with its schedule, lease and last run
+func ListJobs(db database.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		jobs, err := db.Jobs().ListJobs(c.UserContext())
+		if err != nil {
+			return apperror.Wrap(err, "Failed to retrieve jobs")
+		}
+		return c.JSON(fiber.Map{"jobs": jobs})
+	}
+}
+
+// GetJobRuns returns the latest runs of a job, newest first
+func GetJobRuns(db database.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		limit := defaultJobRuns
+		if raw := c.Query("limit"); raw != "" {
+			var err error
+			if limit, err = strconv.Atoi(raw); err != nil || limit <= 0 {
+				return apperror.Validation("invalid_limit", "limit must be a positive integer")
+			}
+			limit = min(limit, maxJobRuns)
+		}
+		runs, err := db.Jobs().GetJobRuns(c.UserContext(), c.Params("name"), limit)
+		if err != nil {
+			return apperror.Wrap(err, "Failed to retrieve job runs")
+		}
+		return c.JSON(fiber.Map{"runs": runs})
+	}
+}
+
+// TriggerJob makes a job due now, so the first replica to poll runs it. A
+// run in progress is not interrupted, and the job is then due on schedule.
+func TriggerJob(db database.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		name := c.Params("name")
+		if err := db.Jobs().TriggerJob(c.UserContext(), name, time.Now()); err != nil {
+			return apperror.Wrap(err, "Failed to trigger job")
+		}
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"message": "Job will run at the next poll",
+			"job":     name,
+		})
+	}
+}
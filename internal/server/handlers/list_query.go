@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+
+	"DBackend/internal/apperror"
+	"DBackend/internal/query"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// parseListQuery reads ?filter, ?sort, ?limit and ?cursor for a list endpoint
+func parseListQuery(c *fiber.Ctx, spec query.Spec) (query.Params, error) {
+	params, err := query.Parse(func(key string) string { return c.Query(key) }, spec)
+	return params, queryError(err)
+}
+
+// queryError reports invalid list parameters and cursors as validation errors
+// and returns any other error unchanged
+func queryError(err error) error {
+	var invalid *query.Error
+	switch {
+	case errors.Is(err, query.ErrInvalidCursor):
+		return apperror.Validation("invalid_cursor", "Invalid cursor")
+	case errors.As(err, &invalid):
+		return apperror.Validation("invalid_query", invalid.Error())
+	}
+	return err
+}
+
+// hasRole reports whether the authenticated user holds role
+func hasRole(c *fiber.Ctx, role string) bool {
+	roles, _ := c.Locals("roles").([]string)
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// currentUserID returns the authenticated user's ID
+func currentUserID(c *fiber.Ctx) (primitive.ObjectID, error) {
+	raw, _ := c.Locals("user_id").(string)
+	userID, err := primitive.ObjectIDFromHex(raw)
+	if err != nil {
+		return primitive.NilObjectID, apperror.Unauthorized("unauthorized", "Unauthorized")
+	}
+	return userID, nil
+}
@@ -1,16 +1,13 @@
 package handlers
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"net/http"
+	"log/slog"
 	"time"
 
-	// import ioutil
-	"io/ioutil"
-
+	"DBackend/internal/apperror"
+	"DBackend/internal/config"
 	"DBackend/internal/database"
+	"DBackend/internal/server/services"
 	"DBackend/model"
 	"DBackend/utils"
 
@@ -21,22 +18,12 @@ import (
 // MatHandler struct to handle Match-related operations
 type MatHandler struct {
 	db database.Service
+	ml config.ML
 }
 
 // NewInvestorHandler initializes an InvestorHandler
-func NewMatHandler(db database.Service) *MatHandler {
-	return &MatHandler{db: db}
-}
-
-// MatchRequest defines the structure of the payload that our FastAPI endpoint expects.
-type MatchRequest struct {
-	Founder  map[string]interface{} `json:"founder"`
-	Investor map[string]interface{} `json:"investor"`
-}
-
-// MatchResponse defines the structure of the response from FastAPI.
-type MatchResponse struct {
-	MatchProbability float64 `json:"match_probability"`
+func NewMatHandler(db database.Service, ml config.ML) *MatHandler {
+	return &MatHandler{db: db, ml: ml}
 }
 
 func (h *MatHandler) MatchHandler(c *fiber.Ctx) error {
@@ -45,79 +32,42 @@ func (h *MatHandler) MatchHandler(c *fiber.Ctx) error {
 
 	tokenStr, ok := c.Locals("token").(string)
 	if !ok || tokenStr == "" {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	claims, err := utils.ValidateJWT(tokenStr)
 	if err != nil {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Unauthorized("invalid_token", "Invalid token")
 	}
 
 	// Convert claims.UserID to primitive.ObjectID
 	investorID, err := primitive.ObjectIDFromHex(claims.UserID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Validation("invalid_user_id", "Invalid user ID")
 	}
-	fmt.Println("founderID", founderID)
-	fmt.Println("investorID", investorID)
 	// Convert IDs to ObjectID
 	fID, err := primitive.ObjectIDFromHex(founderID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid founder ID",
-		})
+		return apperror.Validation("invalid_founder_id", "Invalid founder ID")
 	}
 
 	// Fetch founder and investor details from the database
-	founder, err := h.db.User().FindByID(c.Context(), "founders", fID)
-	fmt.Println(err)
+	founderDetails, err := h.db.Founders().GetFounderByUserID(c.UserContext(), fID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Founder not found",
-		})
+		return apperror.Wrap(err, "Failed to retrieve founder")
 	}
-	investor, err := h.db.User().FindByID(c.Context(), "investors", investorID)
-	fmt.Println(err)
+	investorDetails, err := h.db.Investors().GetInvestorByUserID(c.UserContext(), investorID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Investor not found",
-		})
-	}
-
-	// Type assert founder and investor to their respective models
-	founderDetails, ok := founder.(*model.Founder)
-	if !ok {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Internal server error",
-		})
-	}
-	investorDetails, ok := investor.(*model.Investor)
-	if !ok {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Internal server error",
-		})
+		return apperror.Wrap(err, "Failed to retrieve investor")
 	}
 
 	// Build the payload with default values for empty fields
-	reqPayload := MatchRequest{
-		Founder: map[string]interface{}{
-			"fund_required": getDefaultIfZero(float64(founderDetails.FundRequired), 500000),
-			"industry":      getDefaultIfEmpty(founderDetails.Industry, "Other"),
-			"funding_stage": getDefaultIfEmpty(founderDetails.FundingStage, "Seed"),
-		},
-		Investor: map[string]interface{}{
-			"total_invested":          getDefaultIfZero(investorDetails.TotalInvested, 1000000),
-			"preferred_funding_stage": getDefaultIfEmpty(investorDetails.PreferredFundingStage, "Seed"),
-			"risk_tolerance":          getDefaultIfEmpty(investorDetails.RiskTolerance, "Moderate"),
-		},
-	}
+	reqPayload := services.MatchPayload(*founderDetails, *investorDetails)
 
 	// Call the ML service
-	matchProbability, err := GetMatchProbability(reqPayload)
+	matchProbability, err := services.GetMatchProbability(c.UserContext(), h.ml, reqPayload)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": fmt.Sprintf("Error getting match probability: %v", err),
-		})
+		return apperror.Wrap(err, "Failed to get match probability")
 	}
 
 	match := model.MatchInvestorFounder{
@@ -128,11 +78,12 @@ func (h *MatHandler) MatchHandler(c *fiber.Ctx) error {
 		UpdatedAt:       time.Now(),
 	}
 
-	_, err = h.db.User().AddMatch(c.Context(), match)
+	_, err = h.db.Investors().AddMatch(c.UserContext(), match)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to add match"})
+		return apperror.Wrap(err, "Failed to add match")
 	}
-	fmt.Println(matchProbability)
+	slog.InfoContext(c.UserContext(), "match calculated",
+		"founder_id", fID.Hex(), "investor_id", investorID.Hex(), "probability", matchProbability)
 
 	// Return the match probability
 	return c.Status(201).JSON(fiber.Map{
@@ -140,78 +91,34 @@ func (h *MatHandler) MatchHandler(c *fiber.Ctx) error {
 	})
 }
 
-// GetMatchProbability sends a POST request to the FastAPI endpoint and returns the match probability.
-func GetMatchProbability(req MatchRequest) (float64, error) {
-	url := "http://127.0.0.1:4040/predict/" // FastAPI endpoint URL
-
-	// Marshal the request into JSON.
-	requestBody, err := json.Marshal(req)
-	if err != nil {
-		return 0, fmt.Errorf("failed to marshal request: %v", err)
-	}
-
-	// Send the POST request.
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(requestBody))
-	if err != nil {
-		return 0, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	// Read and parse the response body.
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return 0, fmt.Errorf("failed to read response: %v", err)
-	}
-
-	var matchResp MatchResponse
-	if err := json.Unmarshal(body, &matchResp); err != nil {
-		return 0, fmt.Errorf("failed to unmarshal response: %v", err)
-	}
-
-	return matchResp.MatchProbability, nil
-}
-
 // CalculateMatchHandler calculates and stores match between investor and founder
 func (h *MatHandler) CalculateMatchHandler(c *fiber.Ctx) error {
 	// Parse request
 	var req struct {
-		FounderID  string `json:"founder_id"`
-		InvestorID string `json:"investor_id"`
+		FounderID  string `json:"founder_id" validate:"required,objectid"`
+		InvestorID string `json:"investor_id" validate:"required,objectid"`
 	}
 
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
-	}
-
-	// Validate IDs
-	fID, err := primitive.ObjectIDFromHex(req.FounderID)
-	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid founder ID"})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
-	investorID, err := primitive.ObjectIDFromHex(req.InvestorID)
-	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid investor ID"})
-	}
+	fID, _ := primitive.ObjectIDFromHex(req.FounderID)
+	investorID, _ := primitive.ObjectIDFromHex(req.InvestorID)
 
 	// Get founder and investor details
-	founderDetails, err := h.db.User().GetFounderByUserID(c.Context(), fID)
+	founderDetails, err := h.db.Founders().GetFounderByUserID(c.UserContext(), fID)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "Founder not found"})
+		return apperror.Wrap(err, "Failed to retrieve founder")
 	}
 
-	investorDetails, err := h.db.User().FindByID(c.Context(), "investors", investorID)
+	investorObj, err := h.db.Investors().GetInvestorByUserID(c.UserContext(), investorID)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "Investor not found"})
-	}
-
-	investorObj, ok := investorDetails.(*model.Investor)
-	if !ok {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to parse investor data"})
+		return apperror.Wrap(err, "Failed to retrieve investor")
 	}
 
 	// Build the payload
-	reqPayload := MatchRequest{
+	reqPayload := services.MatchRequest{
 		Founder: map[string]interface{}{
 			"fund_required": founderDetails.FundRequired,
 			"industry":      founderDetails.Industry,
@@ -225,11 +132,9 @@ func (h *MatHandler) CalculateMatchHandler(c *fiber.Ctx) error {
 	}
 
 	// Call the ML service
-	matchProbability, err := GetMatchProbability(reqPayload)
+	matchProbability, err := services.GetMatchProbability(c.UserContext(), h.ml, reqPayload)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": fmt.Sprintf("Error getting match probability: %v", err),
-		})
+		return apperror.Wrap(err, "Failed to get match probability")
 	}
 
 	match := model.MatchInvestorFounder{
@@ -240,29 +145,15 @@ func (h *MatHandler) CalculateMatchHandler(c *fiber.Ctx) error {
 		UpdatedAt:       time.Now(),
 	}
 
-	_, err = h.db.User().AddMatch(c.Context(), match)
+	_, err = h.db.Investors().AddMatch(c.UserContext(), match)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to add match"})
+		return apperror.Wrap(err, "Failed to add match")
 	}
-	fmt.Println(matchProbability)
+	slog.InfoContext(c.UserContext(), "match calculated",
+		"founder_id", fID.Hex(), "investor_id", investorID.Hex(), "probability", matchProbability)
 
 	// Return the match probability
 	return c.Status(201).JSON(fiber.Map{
 		"match_probability": matchProbability,
 	})
 }
-
-// Helper functions to provide defaults for empty values
-func getDefaultIfEmpty(value string, defaultValue string) string {
-	if value == "" {
-		return defaultValue
-	}
-	return value
-}
-
-func getDefaultIfZero(value float64, defaultValue float64) float64 {
-	if value == 0 {
-		return defaultValue
-	}
-	return value
-}
@@ -1,8 +1,14 @@
 package handlers
 
 import (
+    "context"
+    "errors"
+    "fmt"
+    "strings"
     "time"
 
+    "DBackend/internal/apperror"
+    "DBackend/internal/config"
     "DBackend/internal/database"
     "DBackend/internal/server/services"
     "DBackend/model"
@@ -10,14 +16,21 @@ import (
 
     "github.com/gofiber/fiber/v2"
     "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
 )
 
-// ScheduleMeeting handles creating a new meeting
-func ScheduleMeeting(db database.Service) fiber.Handler {
+// ScheduleMeeting handles creating a new meeting. A meeting overlapping one
+// a participant is already in is refused unless on_conflict=warn.
+func ScheduleMeeting(db database.Service, calendar config.Calendar) fiber.Handler {
+    provider := services.NewCalendarProvider(calendar, db)
     return func(c *fiber.Ctx) error {
+        warn, err := warnOnConflict(c)
+        if err != nil {
+            return err
+        }
         var meeting model.Meeting
-        if err := c.BodyParser(&meeting); err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+        if err := parseBody(c, &meeting); err != nil {
+            return err
         }
 
         // Generate new ID for the meeting
@@ -26,67 +39,79 @@ func ScheduleMeeting(db database.Service) fiber.Handler {
         // Get user ID from token
         tokenStr, ok := c.Locals("token").(string)
         if !ok || tokenStr == "" {
-            return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+            return apperror.Unauthorized("invalid_token", "Invalid token")
         }
 
         claims, err := utils.ValidateJWT(tokenStr)
         if err != nil {
-            return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+            return apperror.Unauthorized("invalid_token", "Invalid token")
         }
+        userID, _ := primitive.ObjectIDFromHex(claims.UserID)
 
-        // Create Google Calendar event
-        calendarService, err := services.NewGoogleCalendarService()
-        if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to initialize Google Calendar service"})
+        // Checked before the event is created so a rejected request leaves
+        // nothing on the organizer's calendar
+        if meeting.FounderID.IsZero() {
+            return apperror.Validation("founder_id_is_required", "Founder ID is required")
         }
 
-        event, err := calendarService.CreateEvent(
-            meeting.Title,
-            "", // Location
-            meeting.Notes,
-            meeting.StartTime.Format(time.RFC3339),
-            meeting.EndTime.Format(time.RFC3339),
-            "UTC",
-            []string{}, // Attendees
-        )
+        // Assuming the current user is the investor
+        meeting.InvestorID = userID
+
+        conflicts, err := planMeeting(c.UserContext(), db, userID, &meeting, warn)
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to create Google Calendar event"})
+            return err
         }
 
-        meeting.GoogleMeetURL = event.HangoutLink
-        userID, _ := primitive.ObjectIDFromHex(claims.UserID)
-        
-        // Assuming the current user is the investor
-        meeting.InvestorID = userID
-        
-        // If FounderID is not set in the request, you might need to handle that separately
-        if meeting.FounderID.IsZero() {
-            return c.Status(400).JSON(fiber.Map{"error": "Founder ID is required"})
+        // The calendar event is organized by the current user
+        if err := scheduleOnCalendar(c.UserContext(), db, calendar, provider, userID, &meeting); err != nil {
+            return err
         }
+
         meeting.CreatedAt = time.Now()
         meeting.UpdatedAt = time.Now()
 
-        // Save meeting to database
-        result, err := db.DealFlow().AddMeeting(c.Context(), primitive.NilObjectID, meeting)
+        // Save meeting to database, together with its MeetingScheduled event
+        var result *mongo.InsertOneResult
+        err = db.Transaction(c.UserContext(), func(ctx context.Context) error {
+            result, err = db.Meetings().CreateMeeting(ctx, meeting)
+            return err
+        })
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to create meeting"})
+            return apperror.Wrap(err, "Failed to create meeting")
         }
 
-        return c.Status(201).JSON(fiber.Map{
+        response := fiber.Map{
             "message": "Meeting scheduled successfully",
             "meeting": result,
-        })
+        }
+        if len(conflicts) > 0 {
+            response["conflicts"] = conflicts
+        }
+        return c.Status(201).JSON(response)
     }
 }
 
-// GetAllMeetings returns all meetings
+// GetAllMeetings returns a page of the meetings the caller takes part in;
+// admins see every meeting
 func GetAllMeetings(db database.Service) fiber.Handler {
     return func(c *fiber.Ctx) error {
-        meetings, err := db.User().GetMeetings(c.Context(), primitive.NilObjectID)
+        params, err := parseListQuery(c, database.MeetingListSpec)
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve meetings"})
+            return err
         }
-        return c.JSON(fiber.Map{"meetings": meetings})
+        userID, err := currentUserID(c)
+        if err != nil {
+            return err
+        }
+        if hasRole(c, "admin") {
+            userID = primitive.NilObjectID
+        }
+
+        meetings, err := db.Meetings().ListMeetings(c.UserContext(), userID, params)
+        if err != nil {
+            return apperror.Wrap(err, "Failed to retrieve meetings")
+        }
+        return c.JSON(meetings)
     }
 }
 
@@ -95,60 +120,278 @@ func GetMeetingByID(db database.Service) fiber.Handler {
     return func(c *fiber.Ctx) error {
         id, err := primitive.ObjectIDFromHex(c.Params("id"))
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid meeting ID"})
+            return apperror.Validation("invalid_meeting_id", "Invalid meeting ID")
         }
 
-        var meeting model.Meeting
-        result, err := db.User().FindByID(c.Context(), "meetings", id)
+        meeting, err := db.Meetings().GetMeetingByID(c.UserContext(), id)
         if err != nil {
-            return c.Status(404).JSON(fiber.Map{"error": "Meeting not found"})
-        }
-        
-        meeting, ok := result.(model.Meeting)
-        if !ok {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to parse meeting data"})
+            return apperror.Wrap(err, "Failed to retrieve meeting")
         }
 
         return c.JSON(fiber.Map{"meeting": meeting})
     }
 }
 
-// UpdateMeeting updates an existing meeting
-func UpdateMeeting(db database.Service) fiber.Handler {
+// GetMeetingInvite returns the meeting as an RFC 5545 invite to import into
+// any calendar app, whichever provider created its event
+func GetMeetingInvite(db database.Service, calendar config.Calendar) fiber.Handler {
     return func(c *fiber.Ctx) error {
         id, err := primitive.ObjectIDFromHex(c.Params("id"))
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid meeting ID"})
+            return apperror.Validation("invalid_meeting_id", "Invalid meeting ID")
+        }
+
+        meeting, err := db.Meetings().GetMeetingByID(c.UserContext(), id)
+        if err != nil {
+            return apperror.Wrap(err, "Failed to retrieve meeting")
+        }
+
+        // Meetings are organized by the investor who scheduled them
+        invite := services.Invite(meetingEvent(c.UserContext(), db, calendar, meeting.InvestorID, *meeting), "REQUEST")
+        c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8; method=REQUEST")
+        c.Set(fiber.HeaderContentDisposition, `attachment; filename="meeting-`+meeting.ID.Hex()+`.ics"`)
+        return c.Send(invite.Bytes())
+    }
+}
+
+// UpdateMeeting lets the organizer update a meeting and its calendar event.
+// The timezone and reminders are kept when the update leaves them out;
+// overlaps are handled as when scheduling. Changing when a series recurs or
+// starts drops the cancelled and edited occurrences, which belong to the old
+// dates.
+func UpdateMeeting(db database.Service, calendar config.Calendar) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        id, err := primitive.ObjectIDFromHex(c.Params("id"))
+        if err != nil {
+            return apperror.Validation("invalid_meeting_id", "Invalid meeting ID")
+        }
+        warn, err := warnOnConflict(c)
+        if err != nil {
+            return err
+        }
+
+        userID, err := currentUserID(c)
+        if err != nil {
+            return err
         }
 
         var updates model.Meeting
-        if err := c.BodyParser(&updates); err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+        if err := parseBody(c, &updates); err != nil {
+            return err
+        }
+
+        existing, err := organizerMeeting(c.UserContext(), db, id, userID)
+        if err != nil {
+            return err
+        }
+        if updates.Timezone == "" {
+            updates.Timezone = existing.Timezone
+        }
+        if !bodyHas(c, "reminders") {
+            updates.Reminders = existing.Reminders
+        }
+        planned := *existing
+        planned.Title, planned.Notes = updates.Title, updates.Notes
+        planned.StartTime, planned.EndTime = updates.StartTime, updates.EndTime
+        planned.Timezone, planned.Recurrence = updates.Timezone, updates.Recurrence
+        reschedule := existing.Recurrence != "" && (updates.Recurrence != existing.Recurrence || !updates.StartTime.Equal(existing.StartTime))
+        if reschedule {
+            planned.Exceptions, planned.Overrides = nil, nil
+        }
+        conflicts, err := planMeeting(c.UserContext(), db, existing.InvestorID, &planned, warn)
+        if err != nil {
+            return err
+        }
+        updates.Timezone, updates.SeriesEnd = planned.Timezone, planned.SeriesEnd
+
+        // The calendar goes first: if saving then fails the request can be
+        // retried, updating the event again to the same meeting
+        if err := updateOnCalendar(c.UserContext(), db, calendar, planned); err != nil {
+            return err
         }
 
         updates.UpdatedAt = time.Now()
 
-        err = db.User().UpdateMeeting(c.Context(), id, updates)
+        err = db.Transaction(c.UserContext(), func(ctx context.Context) error {
+            if err := db.Meetings().UpdateMeeting(ctx, id, updates); err != nil {
+                return err
+            }
+            if reschedule {
+                return db.Meetings().UpdateOccurrences(ctx, id, nil, nil, planned.SeriesEnd)
+            }
+            return nil
+        })
+        if err != nil {
+            return apperror.Wrap(err, "Failed to update meeting")
+        }
+
+        response := fiber.Map{"message": "Meeting updated successfully"}
+        if len(conflicts) > 0 {
+            response["conflicts"] = conflicts
+        }
+        return c.JSON(response)
+    }
+}
+
+// GetMeetingOccurrences lists the occurrences of a meeting between from and
+// to, by default the next 90 days, with edits applied and cancelled ones left
+// out. A meeting that does not recur has a single occurrence.
+func GetMeetingOccurrences(db database.Service) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        id, err := primitive.ObjectIDFromHex(c.Params("id"))
+        if err != nil {
+            return apperror.Validation("invalid_meeting_id", "Invalid meeting ID")
+        }
+        from, to, err := occurrenceRange(c)
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to update meeting"})
+            return err
+        }
+        userID, err := currentUserID(c)
+        if err != nil {
+            return err
         }
 
-        return c.JSON(fiber.Map{"message": "Meeting updated successfully"})
+        meeting, err := participantMeeting(c.UserContext(), db, id, userID)
+        if err != nil {
+            return err
+        }
+        occurrences, err := services.Occurrences(*meeting, from, to)
+        if err != nil {
+            return apperror.Wrap(err, "Failed to expand recurrence")
+        }
+
+        return c.JSON(fiber.Map{"timezone": meeting.Timezone, "occurrences": occurrences})
     }
 }
 
-// CancelMeeting deletes a meeting
-func CancelMeeting(db database.Service) fiber.Handler {
+// UpdateMeetingOccurrence lets the organizer edit one occurrence of a
+// recurring meeting, named by its original start, leaving the rest of the
+// series as it is, here and on the calendar
+func UpdateMeetingOccurrence(db database.Service, calendar config.Calendar) fiber.Handler {
     return func(c *fiber.Ctx) error {
         id, err := primitive.ObjectIDFromHex(c.Params("id"))
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid meeting ID"})
+            return apperror.Validation("invalid_meeting_id", "Invalid meeting ID")
+        }
+        warn, err := warnOnConflict(c)
+        if err != nil {
+            return err
         }
 
-        // Use an existing method that can delete a meeting
-        _, err = db.User().DeleteNotification(c.Context(), id)
+        userID, err := currentUserID(c)
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to cancel meeting"})
+            return err
+        }
+
+        var override model.OccurrenceOverride
+        if err := parseBody(c, &override); err != nil {
+            return err
+        }
+
+        meeting, err := recurringOccurrence(c.UserContext(), db, id, userID, override.OriginalStart)
+        if err != nil {
+            return err
+        }
+        services.EditOccurrence(meeting, override)
+
+        // Only the edited occurrence is checked; the rest of the series was
+        // checked when it was scheduled
+        moved := *meeting
+        moved.Recurrence, moved.Exceptions, moved.Overrides = "", nil, nil
+        moved.StartTime, moved.EndTime, moved.SeriesEnd = override.StartTime, override.EndTime, override.EndTime
+        conflicts, err := findConflicts(c.UserContext(), db, moved)
+        if err != nil {
+            return err
+        }
+        if len(conflicts) > 0 && !warn {
+            overlap := apperror.Conflict("meeting_overlap", "A participant already has a meeting at that time")
+            overlap.Details = conflicts
+            return overlap
+        }
+
+        seriesEnd, err := services.SeriesEnd(*meeting)
+        if err != nil {
+            return apperror.Wrap(err, "Failed to expand recurrence")
+        }
+        if err := updateOnCalendar(c.UserContext(), db, calendar, *meeting); err != nil {
+            return err
+        }
+        err = db.Meetings().UpdateOccurrences(c.UserContext(), id, meeting.Exceptions, meeting.Overrides, seriesEnd)
+        if err != nil {
+            return apperror.Wrap(err, "Failed to update occurrence")
+        }
+
+        response := fiber.Map{"message": "Occurrence updated successfully"}
+        if len(conflicts) > 0 {
+            response["conflicts"] = conflicts
+        }
+        return c.JSON(response)
+    }
+}
+
+// CancelMeetingOccurrence lets the organizer cancel the occurrence of a
+// recurring meeting that starts at start in its series, along with any edit
+// made to it, here and on the calendar
+func CancelMeetingOccurrence(db database.Service, calendar config.Calendar) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        id, err := primitive.ObjectIDFromHex(c.Params("id"))
+        if err != nil {
+            return apperror.Validation("invalid_meeting_id", "Invalid meeting ID")
+        }
+        start, err := time.Parse(time.RFC3339, c.Query("start"))
+        if err != nil {
+            return apperror.Invalid([]apperror.FieldError{{Field: "start", Rule: "datetime", Message: "must be an RFC 3339 time"}})
+        }
+        userID, err := currentUserID(c)
+        if err != nil {
+            return err
+        }
+
+        meeting, err := recurringOccurrence(c.UserContext(), db, id, userID, start)
+        if err != nil {
+            return err
+        }
+        services.CancelOccurrence(meeting, start)
+
+        seriesEnd, err := services.SeriesEnd(*meeting)
+        if err != nil {
+            return apperror.Wrap(err, "Failed to expand recurrence")
+        }
+        if err := updateOnCalendar(c.UserContext(), db, calendar, *meeting); err != nil {
+            return err
+        }
+        err = db.Meetings().UpdateOccurrences(c.UserContext(), id, meeting.Exceptions, meeting.Overrides, seriesEnd)
+        if err != nil {
+            return apperror.Wrap(err, "Failed to cancel occurrence")
+        }
+
+        return c.JSON(fiber.Map{"message": "Occurrence cancelled successfully"})
+    }
+}
+
+// CancelMeeting lets the organizer delete a meeting and its calendar event.
+// The event goes first, so a meeting that fails to delete can be cancelled
+// again.
+func CancelMeeting(db database.Service, calendar config.Calendar) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        id, err := primitive.ObjectIDFromHex(c.Params("id"))
+        if err != nil {
+            return apperror.Validation("invalid_meeting_id", "Invalid meeting ID")
+        }
+        userID, err := currentUserID(c)
+        if err != nil {
+            return err
+        }
+        meeting, err := organizerMeeting(c.UserContext(), db, id, userID)
+        if err != nil {
+            return err
+        }
+        if err := cancelOnCalendar(c.UserContext(), db, calendar, *meeting); err != nil {
+            return err
+        }
+
+        if _, err := db.Meetings().DeleteMeeting(c.UserContext(), id); err != nil {
+            return apperror.Wrap(err, "Failed to cancel meeting")
         }
 
         return c.JSON(fiber.Map{"message": "Meeting cancelled successfully"})
@@ -160,116 +403,404 @@ func GetUserMeetings(db database.Service) fiber.Handler {
     return func(c *fiber.Ctx) error {
         tokenStr, ok := c.Locals("token").(string)
         if !ok || tokenStr == "" {
-            return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+            return apperror.Unauthorized("invalid_token", "Invalid token")
         }
 
         claims, err := utils.ValidateJWT(tokenStr)
         if err != nil {
-            return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+            return apperror.Unauthorized("invalid_token", "Invalid token")
         }
 
         userID, err := primitive.ObjectIDFromHex(claims.UserID)
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+            return apperror.Validation("invalid_user_id", "Invalid user ID")
         }
 
-        meetings, err := db.User().GetMeetings(c.Context(), userID)
+        meetings, err := db.Meetings().GetMeetings(c.UserContext(), userID)
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve meetings"})
+            return apperror.Wrap(err, "Failed to retrieve meetings")
         }
 
         return c.JSON(fiber.Map{"meetings": meetings})
     }
 }
 
-// AddMeetingNotes adds notes to a meeting
-func AddMeetingNotes(db database.Service) fiber.Handler {
+// AddMeetingParticipant adds a participant to a meeting. A participant who
+// already has a meeting at that time is refused unless on_conflict=warn.
+func AddMeetingParticipant(db database.Service) fiber.Handler {
     return func(c *fiber.Ctx) error {
         id, err := primitive.ObjectIDFromHex(c.Params("id"))
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid meeting ID"})
+            return apperror.Validation("invalid_meeting_id", "Invalid meeting ID")
+        }
+        warn, err := warnOnConflict(c)
+        if err != nil {
+            return err
         }
 
         var data struct {
-            Notes string `json:"notes"`
+            UserID string `json:"user_id" validate:"required,objectid"`
         }
-        if err := c.BodyParser(&data); err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+        if err := parseBody(c, &data); err != nil {
+            return err
         }
 
-        err = db.User().AddMeetingNotes(c.Context(), id, data.Notes)
+        userID, _ := primitive.ObjectIDFromHex(data.UserID)
+
+        meeting, err := db.Meetings().GetMeetingByID(c.UserContext(), id)
+        if err != nil {
+            return apperror.Wrap(err, "Failed to retrieve meeting")
+        }
+        // Only the new participant's meetings are checked
+        joining := *meeting
+        joining.InvestorID, joining.FounderID, joining.Participants = primitive.NilObjectID, primitive.NilObjectID, []primitive.ObjectID{userID}
+        conflicts, err := findConflicts(c.UserContext(), db, joining)
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to add meeting notes"})
+            return err
+        }
+        if len(conflicts) > 0 && !warn {
+            overlap := apperror.Conflict("meeting_overlap", "The participant already has a meeting at that time")
+            overlap.Details = conflicts
+            return overlap
         }
 
-        return c.JSON(fiber.Map{"message": "Meeting notes added successfully"})
+        err = db.Meetings().AddMeetingParticipant(c.UserContext(), id, userID)
+        if err != nil {
+            return apperror.Wrap(err, "Failed to add participant")
+        }
+
+        response := fiber.Map{"message": "Participant added successfully"}
+        if len(conflicts) > 0 {
+            response["conflicts"] = conflicts
+        }
+        return c.JSON(response)
     }
 }
 
-// GetMeetingNotes retrieves notes for a meeting
-func GetMeetingNotes(db database.Service) fiber.Handler {
+// RemoveMeetingParticipant removes a participant from a meeting
+func RemoveMeetingParticipant(db database.Service) fiber.Handler {
     return func(c *fiber.Ctx) error {
         id, err := primitive.ObjectIDFromHex(c.Params("id"))
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid meeting ID"})
+            return apperror.Validation("invalid_meeting_id", "Invalid meeting ID")
         }
 
-        notes, err := db.User().GetMeetingNotes(c.Context(), id)
+        userID, err := primitive.ObjectIDFromHex(c.Params("userId"))
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve meeting notes"})
+            return apperror.Validation("invalid_user_id", "Invalid user ID")
         }
 
-        return c.JSON(fiber.Map{"notes": notes})
+        err = db.Meetings().RemoveMeetingParticipant(c.UserContext(), id, userID)
+        if err != nil {
+            return apperror.Wrap(err, "Failed to remove participant")
+        }
+
+        return c.JSON(fiber.Map{"message": "Participant removed successfully"})
     }
 }
 
-// AddMeetingParticipant adds a participant to a meeting
-func AddMeetingParticipant(db database.Service) fiber.Handler {
+// RespondToMeeting records the current user's answer to a meeting and lets
+// the organizer know
+func RespondToMeeting(db database.Service) fiber.Handler {
     return func(c *fiber.Ctx) error {
         id, err := primitive.ObjectIDFromHex(c.Params("id"))
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid meeting ID"})
+            return apperror.Validation("invalid_meeting_id", "Invalid meeting ID")
+        }
+        userID, err := currentUserID(c)
+        if err != nil {
+            return err
         }
 
-        var data struct {
-            UserID string `json:"user_id"`
+        var rsvp model.RSVP
+        if err := parseBody(c, &rsvp); err != nil {
+            return err
         }
-        if err := c.BodyParser(&data); err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+
+        meeting, err := participantMeeting(c.UserContext(), db, id, userID)
+        if err != nil {
+            return err
+        }
+        rsvp.UserID = userID
+        rsvp.RespondedAt = time.Now()
+
+        err = db.Transaction(c.UserContext(), func(ctx context.Context) error {
+            if err := db.Meetings().SetRSVP(ctx, id, rsvp); err != nil {
+                return apperror.Wrap(err, "Failed to record response")
+            }
+            if userID == meeting.InvestorID {
+                return nil
+            }
+            name := "A participant"
+            if user, err := db.Users().FindByID(ctx, userID); err == nil && person(user).Name != "" {
+                name = person(user).Name
+            }
+            _, err := db.Notifications().CreateNotification(ctx, model.Notification{
+                FounderID:        meeting.InvestorID,
+                NotificationType: "meeting_rsvp",
+                Title:            "Meeting " + rsvp.Status,
+                Message:          fmt.Sprintf("%s %s %q", name, rsvp.Status, meeting.Title),
+            })
+            if err != nil {
+                return apperror.Wrap(err, "Failed to notify organizer")
+            }
+            return nil
+        })
+        if err != nil {
+            return err
         }
 
-        userID, err := primitive.ObjectIDFromHex(data.UserID)
+        return c.JSON(fiber.Map{"message": "Response recorded", "rsvp": rsvp})
+    }
+}
+
+// MeetingParticipant is a participant of a meeting with their answer
+type MeetingParticipant struct {
+    UserID      primitive.ObjectID `json:"user_id"`
+    Name        string             `json:"name"`
+    Organizer   bool               `json:"organizer"`
+    Status      string             `json:"status"` // accepted, declined, tentative or pending
+    Comment     string             `json:"comment,omitempty"`
+    RespondedAt *time.Time         `json:"responded_at,omitempty"`
+}
+
+// GetMeetingParticipants lists everyone taking part in a meeting with their
+// answer, pending for those who have not answered
+func GetMeetingParticipants(db database.Service) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        id, err := primitive.ObjectIDFromHex(c.Params("id"))
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+            return apperror.Validation("invalid_meeting_id", "Invalid meeting ID")
         }
 
-        err = db.User().AddMeetingParticipant(c.Context(), id, userID)
+        meeting, err := db.Meetings().GetMeetingByID(c.UserContext(), id)
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to add participant"})
+            return apperror.Wrap(err, "Failed to retrieve meeting")
         }
 
-        return c.JSON(fiber.Map{"message": "Participant added successfully"})
+        rsvps := map[primitive.ObjectID]model.RSVP{}
+        for _, rsvp := range meeting.RSVPs {
+            rsvps[rsvp.UserID] = rsvp
+        }
+        participants := []MeetingParticipant{}
+        for _, userID := range services.Participants(*meeting) {
+            p := MeetingParticipant{UserID: userID, Organizer: userID == meeting.InvestorID, Status: "pending"}
+            if user, err := db.Users().FindByID(c.UserContext(), userID); err == nil {
+                p.Name = person(user).Name
+            }
+            if rsvp, ok := rsvps[userID]; ok {
+                respondedAt := rsvp.RespondedAt
+                p.Status, p.Comment, p.RespondedAt = rsvp.Status, rsvp.Comment, &respondedAt
+            }
+            participants = append(participants, p)
+        }
+
+        return c.JSON(fiber.Map{"participants": participants})
     }
 }
 
-// RemoveMeetingParticipant removes a participant from a meeting
-func RemoveMeetingParticipant(db database.Service) fiber.Handler {
+// RecordMeetingOutcome records who attended an occurrence of a meeting that
+// has ended and how it went. The outcome is added to the timeline of the
+// deal between the investor and the founder, when they have one.
+func RecordMeetingOutcome(db database.Service) fiber.Handler {
     return func(c *fiber.Ctx) error {
         id, err := primitive.ObjectIDFromHex(c.Params("id"))
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid meeting ID"})
+            return apperror.Validation("invalid_meeting_id", "Invalid meeting ID")
+        }
+        userID, err := currentUserID(c)
+        if err != nil {
+            return err
         }
 
-        userID, err := primitive.ObjectIDFromHex(c.Params("userId"))
+        var outcome model.MeetingOutcome
+        if err := parseBody(c, &outcome); err != nil {
+            return err
+        }
+
+        meeting, err := participantMeeting(c.UserContext(), db, id, userID)
+        if err != nil {
+            return err
+        }
+        occurrence, err := endedOccurrence(*meeting, outcome.OccurrenceStart)
+        if err != nil {
+            return err
+        }
+        participants := map[primitive.ObjectID]bool{}
+        for _, p := range services.Participants(*meeting) {
+            participants[p] = true
+        }
+        var fields []apperror.FieldError
+        var noShows []primitive.ObjectID
+        for i, a := range outcome.Attendance {
+            if !participants[a.UserID] {
+                fields = append(fields, apperror.FieldError{Field: fmt.Sprintf("attendance[%d].user_id", i), Rule: "participant", Message: "must be a participant of the meeting"})
+            }
+            if a.Status == "no_show" {
+                noShows = append(noShows, a.UserID)
+            }
+        }
+        if len(fields) > 0 {
+            return apperror.Invalid(fields)
+        }
+        outcome.OccurrenceStart = occurrence.OriginalStart
+        outcome.RecordedBy = userID
+        outcome.RecordedAt = time.Now()
+
+        err = db.Transaction(c.UserContext(), func(ctx context.Context) error {
+            if err := db.Meetings().SetOutcome(ctx, id, outcome); err != nil {
+                return apperror.Wrap(err, "Failed to record outcome")
+            }
+            deal, err := meetingDeal(ctx, db, *meeting)
+            if errors.Is(err, database.ErrDealNotFound) {
+                return nil
+            }
+            if err != nil {
+                return apperror.Wrap(err, "Failed to retrieve deal")
+            }
+            err = db.Deals().AddActivity(ctx, model.Activity{
+                InvestorID:  meeting.InvestorID,
+                DealID:      deal.ID,
+                Type:        "meeting_outcome",
+                Description: outcomeDescription(ctx, db, occurrence, outcome.Outcome, noShows),
+                Date:        outcome.RecordedAt,
+            })
+            if err != nil {
+                return apperror.Wrap(err, "Failed to update deal timeline")
+            }
+            return nil
+        })
         if err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+            return err
+        }
+
+        return c.JSON(fiber.Map{"message": "Outcome recorded", "outcome": outcome})
+    }
+}
+
+// participantMeeting returns the meeting id after checking userID takes part
+// in it
+func participantMeeting(ctx context.Context, db database.Service, id, userID primitive.ObjectID) (*model.Meeting, error) {
+    meeting, err := db.Meetings().GetMeetingByID(ctx, id)
+    if err != nil {
+        return nil, apperror.Wrap(err, "Failed to retrieve meeting")
+    }
+    for _, p := range services.Participants(*meeting) {
+        if p == userID {
+            return meeting, nil
+        }
+    }
+    return nil, apperror.Forbidden("not_a_participant", "Only participants of the meeting can do this")
+}
+
+// organizerMeeting returns the meeting id after checking userID organized it
+func organizerMeeting(ctx context.Context, db database.Service, id, userID primitive.ObjectID) (*model.Meeting, error) {
+    meeting, err := db.Meetings().GetMeetingByID(ctx, id)
+    if err != nil {
+        return nil, apperror.Wrap(err, "Failed to retrieve meeting")
+    }
+    if meeting.InvestorID != userID {
+        return nil, apperror.Forbidden("not_the_organizer", "Only the organizer of the meeting can do this")
+    }
+    return meeting, nil
+}
+
+// meetingDeal returns the deal between the meeting's investor and founder
+func meetingDeal(ctx context.Context, db database.Service, m model.Meeting) (*model.DealFlow, error) {
+    return services.DealWith(ctx, db, m.InvestorID, m.FounderID)
+}
+
+// endedOccurrence returns the occurrence of m that originally starts at
+// start, or m itself when it does not recur, after checking it has ended
+func endedOccurrence(m model.Meeting, start time.Time) (services.Occurrence, error) {
+    if m.Recurrence == "" {
+        start = m.StartTime
+    }
+    if start.IsZero() {
+        return services.Occurrence{}, apperror.Invalid([]apperror.FieldError{{Field: "occurrence_start", Rule: "required", Message: "is required for a recurring meeting"}})
+    }
+    occurrences, err := services.Occurrences(m, start.Add(-maxOccurrenceSpan), start.Add(maxOccurrenceSpan))
+    if err != nil {
+        return services.Occurrence{}, apperror.Wrap(err, "Failed to expand recurrence")
+    }
+    for _, o := range occurrences {
+        if !o.OriginalStart.Equal(start) {
+            continue
+        }
+        if o.EndTime.After(time.Now()) {
+            return o, apperror.Conflict("meeting_not_ended", "The outcome of a meeting can only be recorded once it has ended")
         }
+        return o, nil
+    }
+    return services.Occurrence{}, database.ErrOccurrenceNotFound
+}
+
+// outcomeDescription summarizes an outcome for the deal's timeline, naming
+// the participants who did not turn up
+func outcomeDescription(ctx context.Context, db database.Service, o services.Occurrence, outcome string, noShows []primitive.ObjectID) string {
+    description := fmt.Sprintf("Meeting %q on %s: %s outcome", o.Title, o.StartTime.Format("2006-01-02"), outcome)
+    if len(noShows) == 0 {
+        return description
+    }
+    names := make([]string, len(noShows))
+    for i, id := range noShows {
+        names[i] = "a participant"
+        if user, err := db.Users().FindByID(ctx, id); err == nil && person(user).Name != "" {
+            names[i] = person(user).Name
+        }
+    }
+    return description + "; no-show: " + strings.Join(names, ", ")
+}
 
-        err = db.User().RemoveMeetingParticipant(c.Context(), id, userID)
+// occurrenceRange reads the from and to query parameters of an occurrence
+// listing, at most a little over a year apart
+func occurrenceRange(c *fiber.Ctx) (time.Time, time.Time, error) {
+    from, to := time.Now(), time.Time{}
+    var fields []apperror.FieldError
+    if v := c.Query("from"); v != "" {
+        t, err := time.Parse(time.RFC3339, v)
+        if err != nil {
+            fields = append(fields, apperror.FieldError{Field: "from", Rule: "datetime", Message: "must be an RFC 3339 time"})
+        }
+        from = t
+    }
+    to = from.AddDate(0, 0, 90)
+    if v := c.Query("to"); v != "" {
+        t, err := time.Parse(time.RFC3339, v)
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to remove participant"})
+            fields = append(fields, apperror.FieldError{Field: "to", Rule: "datetime", Message: "must be an RFC 3339 time"})
         }
+        to = t
+    }
+    if len(fields) == 0 && (!to.After(from) || to.Sub(from) > maxOccurrenceSpan) {
+        fields = append(fields, apperror.FieldError{Field: "to", Rule: "after", Message: "must be after from and within 366 days of it"})
+    }
+    if len(fields) > 0 {
+        return from, to, apperror.Invalid(fields)
+    }
+    return from, to, nil
+}
 
-        return c.JSON(fiber.Map{"message": "Participant removed successfully"})
+// maxOccurrenceSpan bounds how far apart from and to may be when listing
+// occurrences
+const maxOccurrenceSpan = 366 * 24 * time.Hour
+
+// recurringOccurrence returns the meeting id after checking it recurs and has
+// an occurrence starting at start that has not been cancelled
+func recurringOccurrence(ctx context.Context, db database.Service, id, userID primitive.ObjectID, start time.Time) (*model.Meeting, error) {
+    meeting, err := organizerMeeting(ctx, db, id, userID)
+    if err != nil {
+        return nil, err
+    }
+    if meeting.Recurrence == "" {
+        return nil, database.ErrMeetingNotRecurring
+    }
+    ok, err := services.IsOccurrence(*meeting, start)
+    if err != nil {
+        return nil, apperror.Wrap(err, "Failed to expand recurrence")
+    }
+    if !ok || services.IsCancelled(*meeting, start) {
+        return nil, database.ErrOccurrenceNotFound
     }
+    return meeting, nil
 }
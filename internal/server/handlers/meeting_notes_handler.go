@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"DBackend/internal/apperror"
+	"DBackend/internal/database"
+	"DBackend/internal/server/services"
+	"DBackend/model"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ListMeetingNotes returns the notes of a meeting the current participant
+// can read, without their edit history
+func ListMeetingNotes(db database.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		meeting, userID, err := noteMeeting(c, db)
+		if err != nil {
+			return err
+		}
+		notes, err := db.Meetings().ListNotes(c.UserContext(), meeting.ID, userID)
+		if err != nil {
+			return apperror.Wrap(err, "Failed to retrieve meeting notes")
+		}
+		for i := range notes {
+			notes[i].History = nil
+		}
+		return c.JSON(fiber.Map{"notes": notes})
+	}
+}
+
+// CreateMeetingNote adds a note by the current participant to a meeting
+func CreateMeetingNote(db database.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var note model.MeetingNote
+		if err := parseBody(c, &note); err != nil {
+			return err
+		}
+		meeting, userID, err := noteMeeting(c, db)
+		if err != nil {
+			return err
+		}
+		items, err := actionItems(*meeting, nil, note.ActionItems)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		note.ID = primitive.NewObjectID()
+		note.MeetingID = meeting.ID
+		note.AuthorID, note.EditedBy = userID, userID
+		note.ActionItems = items
+		note.Version = 1
+		note.History = nil
+		note.CreatedAt, note.UpdatedAt = now, now
+		if _, err := db.Meetings().CreateNote(c.UserContext(), note); err != nil {
+			return apperror.Wrap(err, "Failed to add meeting note")
+		}
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Note added", "note": note})
+	}
+}
+
+// GetMeetingNote returns a note with its edit history
+func GetMeetingNote(db database.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		meeting, userID, err := noteMeeting(c, db)
+		if err != nil {
+			return err
+		}
+		note, err := readableNote(c.UserContext(), db, meeting.ID, c.Params("noteId"), userID)
+		if err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"note": note})
+	}
+}
+
+// UpdateMeetingNote replaces the sections and action items of a note,
+// keeping the version it replaces in the note's history. Action items sent
+// back with their id keep the task they were turned into. Only the author
+// can change who sees the note.
+func UpdateMeetingNote(db database.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var edit model.MeetingNote
+		if err := parseBody(c, &edit); err != nil {
+			return err
+		}
+		meeting, userID, err := noteMeeting(c, db)
+		if err != nil {
+			return err
+		}
+		note, err := readableNote(c.UserContext(), db, meeting.ID, c.Params("noteId"), userID)
+		if err != nil {
+			return err
+		}
+		if edit.Version != 0 && edit.Version != note.Version {
+			return database.ErrNoteEdited
+		}
+		if edit.Visibility != note.Visibility && userID != note.AuthorID {
+			return apperror.Forbidden("not_note_author", "Only the note's author can change who sees it")
+		}
+		items, err := actionItems(*meeting, note.ActionItems, edit.ActionItems)
+		if err != nil {
+			return err
+		}
+
+		revision := model.NoteRevision{
+			Version:     note.Version,
+			Visibility:  note.Visibility,
+			Sections:    note.Sections,
+			ActionItems: note.ActionItems,
+			EditedBy:    note.EditedBy,
+			EditedAt:    note.UpdatedAt,
+		}
+		note.Visibility, note.Sections, note.ActionItems, note.EditedBy = edit.Visibility, edit.Sections, items, userID
+		if err := db.Meetings().UpdateNote(c.UserContext(), *note, revision); err != nil {
+			return apperror.Wrap(err, "Failed to update meeting note")
+		}
+		updated, err := db.Meetings().GetNote(c.UserContext(), meeting.ID, note.ID)
+		if err != nil {
+			return apperror.Wrap(err, "Failed to retrieve meeting note")
+		}
+		return c.JSON(fiber.Map{"message": "Note updated", "note": updated})
+	}
+}
+
+// DeleteMeetingNote deletes a note by its author. Tasks made from its action
+// items are kept.
+func DeleteMeetingNote(db database.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		meeting, userID, err := noteMeeting(c, db)
+		if err != nil {
+			return err
+		}
+		note, err := readableNote(c.UserContext(), db, meeting.ID, c.Params("noteId"), userID)
+		if err != nil {
+			return err
+		}
+		if userID != note.AuthorID {
+			return apperror.Forbidden("not_note_author", "Only the note's author can delete it")
+		}
+		if err := db.Meetings().DeleteNote(c.UserContext(), meeting.ID, note.ID); err != nil {
+			return apperror.Wrap(err, "Failed to delete meeting note")
+		}
+		return c.JSON(fiber.Map{"message": "Note deleted"})
+	}
+}
+
+// CreateActionItemTask turns an action item into a task assigned to its
+// assignee and due when it is. The task goes on the deal between the
+// meeting's investor and founder, or stands alone when they have none.
+// Completing or reopening the task marks the action item done or open.
+func CreateActionItemTask(db database.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		meeting, userID, err := noteMeeting(c, db)
+		if err != nil {
+			return err
+		}
+		itemID, err := primitive.ObjectIDFromHex(c.Params("itemId"))
+		if err != nil {
+			return apperror.Validation("invalid_action_item_id", "Invalid action item ID")
+		}
+
+		var task model.Task
+		err = db.Transaction(c.UserContext(), func(ctx context.Context) error {
+			// read inside the transaction so an item becomes a single task
+			note, err := readableNote(ctx, db, meeting.ID, c.Params("noteId"), userID)
+			if err != nil {
+				return err
+			}
+			var item *model.ActionItem
+			for i := range note.ActionItems {
+				if note.ActionItems[i].ID == itemID {
+					item = &note.ActionItems[i]
+				}
+			}
+			if item == nil {
+				return database.ErrActionItemNotFound
+			}
+			if !item.TaskID.IsZero() {
+				return database.ErrActionItemHasTask
+			}
+
+			now := time.Now()
+			task = model.Task{
+				ID:           primitive.NewObjectID(),
+				Title:        item.Description,
+				Completed:    item.Done,
+				DueDate:      item.DueDate,
+				Priority:     "medium",
+				CreatedBy:    userID,
+				AssignedTo:   item.AssigneeID,
+				MeetingID:    meeting.ID,
+				ActionItemID: item.ID,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			}
+			var dealID primitive.ObjectID
+			deal, err := meetingDeal(ctx, db, *meeting)
+			if err == nil {
+				dealID = deal.ID
+			} else if !errors.Is(err, database.ErrDealNotFound) {
+				return apperror.Wrap(err, "Failed to retrieve deal")
+			}
+			if _, err := db.Tasks().AddTask(ctx, dealID, task); err != nil {
+				return apperror.Wrap(err, "Failed to create task")
+			}
+			if err := db.Meetings().LinkActionItem(ctx, note.ID, item.ID, task.ID); err != nil {
+				return apperror.Wrap(err, "Failed to link task")
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Task created", "task": task})
+	}
+}
+
+// completeActionItem marks the action item a task was made from done or
+// open as the task is completed or reopened
+func completeActionItem(ctx context.Context, db database.Service, taskID primitive.ObjectID, completed bool) error {
+	if err := db.Meetings().SetActionItemDone(ctx, taskID, completed); err != nil {
+		return apperror.Wrap(err, "Failed to update action item")
+	}
+	return nil
+}
+
+// noteMeeting returns the meeting named by the id parameter after checking
+// the current user takes part in it
+func noteMeeting(c *fiber.Ctx, db database.Service) (*model.Meeting, primitive.ObjectID, error) {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return nil, primitive.NilObjectID, apperror.Validation("invalid_meeting_id", "Invalid meeting ID")
+	}
+	userID, err := currentUserID(c)
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+	meeting, err := participantMeeting(c.UserContext(), db, id, userID)
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+	return meeting, userID, nil
+}
+
+// readableNote returns the meeting's note with the given hex ID if userID
+// can read it. Others' private notes are reported as not found.
+func readableNote(ctx context.Context, db database.Service, meetingID primitive.ObjectID, noteID string, userID primitive.ObjectID) (*model.MeetingNote, error) {
+	id, err := primitive.ObjectIDFromHex(noteID)
+	if err != nil {
+		return nil, apperror.Validation("invalid_note_id", "Invalid note ID")
+	}
+	note, err := db.Meetings().GetNote(ctx, meetingID, id)
+	if err != nil {
+		return nil, apperror.Wrap(err, "Failed to retrieve meeting note")
+	}
+	if note.Visibility == "private" && note.AuthorID != userID {
+		return nil, database.ErrNoteNotFound
+	}
+	return note, nil
+}
+
+// actionItems checks the action items sent for a note of m and gives new
+// ones an ID. Items sent back with the ID of one in existing keep its task,
+// and its completion once it has a task.
+func actionItems(m model.Meeting, existing, sent []model.ActionItem) ([]model.ActionItem, error) {
+	participants := map[primitive.ObjectID]bool{}
+	for _, p := range services.Participants(m) {
+		participants[p] = true
+	}
+	previous := map[primitive.ObjectID]model.ActionItem{}
+	for _, item := range existing {
+		previous[item.ID] = item
+	}
+
+	var fields []apperror.FieldError
+	items := make([]model.ActionItem, len(sent))
+	for i, item := range sent {
+		if !item.AssigneeID.IsZero() && !participants[item.AssigneeID] {
+			fields = append(fields, apperror.FieldError{Field: fmt.Sprintf("action_items[%d].assignee_id", i), Rule: "participant", Message: "must be a participant of the meeting"})
+		}
+		old, kept := previous[item.ID]
+		delete(previous, item.ID) // an ID sent twice is kept once
+		item.TaskID = primitive.NilObjectID
+		switch {
+		case !kept || item.ID.IsZero():
+			item.ID = primitive.NewObjectID()
+		case !old.TaskID.IsZero():
+			item.TaskID, item.Done = old.TaskID, old.Done
+		}
+		items[i] = item
+	}
+	if len(fields) > 0 {
+		return nil, apperror.Invalid(fields)
+	}
+	return items, nil
+}
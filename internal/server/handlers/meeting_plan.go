@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"DBackend/internal/apperror"
+	"DBackend/internal/database"
+	"DBackend/internal/server/services"
+	"DBackend/model"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// warnOnConflict reads the on_conflict query parameter: reject, the default,
+// refuses a meeting that overlaps one a participant is already in, and warn
+// schedules it and reports the overlaps
+func warnOnConflict(c *fiber.Ctx) (bool, error) {
+	switch c.Query("on_conflict", "reject") {
+	case "reject":
+		return false, nil
+	case "warn":
+		return true, nil
+	}
+	return false, apperror.Invalid([]apperror.FieldError{{Field: "on_conflict", Rule: "oneof", Message: "must be one of: reject warn"}})
+}
+
+// planMeeting completes meeting before it is saved: it defaults the timezone
+// to the organizer's, checks the recurrence rule, records when the series
+// ends, checks its reminders and looks for overlaps with every participant's other meetings.
+// Overlaps are an error unless warn is set, in which case they are returned.
+func planMeeting(ctx context.Context, db database.Service, organizer primitive.ObjectID, meeting *model.Meeting, warn bool) ([]services.Conflict, error) {
+	if meeting.Timezone == "" {
+		if user, err := db.Users().FindByID(ctx, organizer); err == nil {
+			meeting.Timezone = user.Timezone
+		}
+	}
+	seriesEnd, err := services.SeriesEnd(*meeting)
+	if err != nil {
+		return nil, apperror.Invalid([]apperror.FieldError{{Field: "recurrence", Rule: "rrule", Message: err.Error()}})
+	}
+	meeting.SeriesEnd = seriesEnd
+	for _, minutes := range meeting.Reminders {
+		if minutes < 1 || time.Duration(minutes)*time.Minute > services.MaxReminder {
+			return nil, apperror.Invalid([]apperror.FieldError{{Field: "reminders", Rule: "range", Message: fmt.Sprintf("must be between 1 and %d minutes", int(services.MaxReminder/time.Minute))}})
+		}
+	}
+	// answers and outcomes are recorded through their own routes
+	meeting.RSVPs, meeting.Outcomes = nil, nil
+
+	conflicts, err := findConflicts(ctx, db, *meeting)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 && !warn {
+		overlap := apperror.Conflict("meeting_overlap", "A participant already has a meeting at that time")
+		overlap.Details = conflicts
+		return nil, overlap
+	}
+	return conflicts, nil
+}
+
+// findConflicts returns the occurrences of other meetings that overlap m for
+// one of its participants, with their start in that participant's timezone
+func findConflicts(ctx context.Context, db database.Service, m model.Meeting) ([]services.Conflict, error) {
+	participants := services.Participants(m)
+	from, to := services.ConflictWindow(m, m.SeriesEnd)
+	others, err := db.Meetings().GetBusyMeetings(ctx, participants, from, to)
+	if err != nil {
+		return nil, apperror.Wrap(err, "Failed to retrieve meetings")
+	}
+	conflicts, err := services.FindConflicts(m, others, from, to)
+	if err != nil {
+		return nil, apperror.Invalid([]apperror.FieldError{{Field: "recurrence", Rule: "rrule", Message: err.Error()}})
+	}
+
+	zones := map[primitive.ObjectID]string{}
+	for _, c := range conflicts {
+		if _, seen := zones[c.ParticipantID]; seen {
+			continue
+		}
+		zones[c.ParticipantID] = ""
+		if user, err := db.Users().FindByID(ctx, c.ParticipantID); err == nil {
+			zones[c.ParticipantID] = user.Timezone
+		}
+	}
+	services.Localize(conflicts, zones)
+	return conflicts, nil
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"encoding/json"
+
+	"DBackend/internal/apperror"
+	"DBackend/internal/validate"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseBody decodes the request body into out and checks its validate tags
+func parseBody(c *fiber.Ctx, out interface{}) error {
+	if err := c.BodyParser(out); err != nil {
+		return apperror.Validation("invalid_request_body", "Invalid request body")
+	}
+	return validate.Struct(out)
+}
+
+// bodyHas reports whether the JSON request body sets field, even to null
+func bodyHas(c *fiber.Ctx, field string) bool {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(c.Body(), &fields); err != nil {
+		return false
+	}
+	_, ok := fields[field]
+	return ok
+}
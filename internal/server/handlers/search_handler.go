@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"DBackend/internal/apperror"
+	"DBackend/internal/database"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Search handles free-text search across founders, investors, grants, grant
+// applications, deals, meetings and tasks visible to the caller
+func Search(db database.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		text := strings.TrimSpace(c.Query("q"))
+		if text == "" {
+			return apperror.Validation("query_parameter_q_is_required", "Query parameter q is required")
+		}
+
+		rawUserID, _ := c.Locals("user_id").(string)
+		userID, err := primitive.ObjectIDFromHex(rawUserID)
+		if err != nil {
+			return apperror.Unauthorized("unauthorized", "Unauthorized")
+		}
+		roles, _ := c.Locals("roles").([]string)
+
+		scope := database.SearchScope{UserID: userID, Roles: roles}
+		if hasRole(c, "founder") {
+			if founder, err := db.Founders().GetFounderByUserID(c.UserContext(), userID); err == nil {
+				scope.FounderID = founder.ID
+			}
+		}
+
+		types := splitQueryList(c.Query("types"))
+		for _, t := range types {
+			if !isSearchType(t) {
+				return apperror.Validation("unknown_search_type", "Unknown search type: "+t)
+			}
+		}
+
+		limit := 0
+		if raw := c.Query("limit"); raw != "" {
+			if limit, err = strconv.Atoi(raw); err != nil || limit <= 0 {
+				return apperror.Validation("invalid_limit", "limit must be a positive integer")
+			}
+		}
+
+		results, err := db.Search().Search(c.UserContext(), database.SearchQuery{
+			Text:  text,
+			Types: types,
+			Limit: limit,
+			Scope: scope,
+		})
+		if err != nil {
+			return apperror.Wrap(err, "Failed to search")
+		}
+
+		return c.JSON(fiber.Map{"query": text, "results": results})
+	}
+}
+
+func isSearchType(t string) bool {
+	for _, known := range database.SearchTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
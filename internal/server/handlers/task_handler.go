@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"context"
 	"time"
 
+	"DBackend/internal/apperror"
 	"DBackend/internal/database"
 	"DBackend/model"
 	"DBackend/utils"
@@ -15,24 +17,25 @@ import (
 func CreateTask(db database.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var task model.Task
-		if err := c.BodyParser(&task); err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		if err := parseBody(c, &task); err != nil {
+			return err
 		}
 
-		// Generate new ID for the task
+		// Generate new ID for the task; only action items link tasks to meetings
 		task.ID = primitive.NewObjectID()
+		task.MeetingID, task.ActionItemID = primitive.NilObjectID, primitive.NilObjectID
 		task.CreatedAt = time.Now()
 		task.UpdatedAt = time.Now()
 
 		// Get user ID from token
 		tokenStr, ok := c.Locals("token").(string)
 		if !ok || tokenStr == "" {
-			return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+			return apperror.Unauthorized("invalid_token", "Invalid token")
 		}
 
 		claims, err := utils.ValidateJWT(tokenStr)
 		if err != nil {
-			return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+			return apperror.Unauthorized("invalid_token", "Invalid token")
 		}
 
 		userID, _ := primitive.ObjectIDFromHex(claims.UserID)
@@ -40,9 +43,9 @@ func CreateTask(db database.Service) fiber.Handler {
 
 		// Save task to database
 		// Since this is a standalone task, we'll use primitive.NilObjectID for dealflow
-		result, err := db.User().AddTask(c.Context(), primitive.NilObjectID, task)
+		result, err := db.Tasks().AddTask(c.UserContext(), primitive.NilObjectID, task)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to create task"})
+			return apperror.Wrap(err, "Failed to create task")
 		}
 
 		return c.Status(201).JSON(fiber.Map{
@@ -52,14 +55,27 @@ func CreateTask(db database.Service) fiber.Handler {
 	}
 }
 
-// GetAllTasks returns all tasks
+// GetAllTasks returns a page of the tasks in the caller's deals and those
+// they created or were assigned; admins see every task
 func GetAllTasks(db database.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		tasks, err := db.User().GetAllTasks(c.Context())
+		params, err := parseListQuery(c, database.TaskListSpec)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve tasks"})
+			return err
 		}
-		return c.JSON(fiber.Map{"tasks": tasks})
+		userID, err := currentUserID(c)
+		if err != nil {
+			return err
+		}
+		if hasRole(c, "admin") {
+			userID = primitive.NilObjectID
+		}
+
+		tasks, err := db.Tasks().GetAllTasks(c.UserContext(), userID, params)
+		if err != nil {
+			return apperror.Wrap(err, "Failed to retrieve tasks")
+		}
+		return c.JSON(tasks)
 	}
 }
 
@@ -68,12 +84,12 @@ func GetTaskByID(db database.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		id, err := primitive.ObjectIDFromHex(c.Params("id"))
 		if err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid task ID"})
+			return apperror.Validation("invalid_task_id", "Invalid task ID")
 		}
 
-		task, err := db.User().GetTaskByID(c.Context(), id)
+		task, err := db.Tasks().GetTaskByID(c.UserContext(), id)
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Task not found"})
+			return apperror.Wrap(err, "Failed to retrieve task")
 		}
 
 		return c.JSON(fiber.Map{"task": task})
@@ -85,19 +101,24 @@ func UpdateTask(db database.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		id, err := primitive.ObjectIDFromHex(c.Params("id"))
 		if err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid task ID"})
+			return apperror.Validation("invalid_task_id", "Invalid task ID")
 		}
 
 		var updates model.Task
-		if err := c.BodyParser(&updates); err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		if err := parseBody(c, &updates); err != nil {
+			return err
 		}
 
 		updates.UpdatedAt = time.Now()
 
-		err = db.User().UpdateTask(c.Context(), id, updates)
+		err = db.Transaction(c.UserContext(), func(ctx context.Context) error {
+			if err := db.Tasks().UpdateTask(ctx, id, updates); err != nil {
+				return apperror.Wrap(err, "Failed to update task")
+			}
+			return completeActionItem(ctx, db, id, updates.Completed)
+		})
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to update task"})
+			return err
 		}
 
 		return c.JSON(fiber.Map{"message": "Task updated successfully"})
@@ -109,12 +130,12 @@ func DeleteTask(db database.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		id, err := primitive.ObjectIDFromHex(c.Params("id"))
 		if err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid task ID"})
+			return apperror.Validation("invalid_task_id", "Invalid task ID")
 		}
 
-		err = db.User().DeleteTask(c.Context(), id)
+		err = db.Tasks().DeleteTask(c.UserContext(), id)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to delete task"})
+			return apperror.Wrap(err, "Failed to delete task")
 		}
 
 		return c.JSON(fiber.Map{"message": "Task deleted successfully"})
@@ -126,12 +147,12 @@ func GetTasksByUser(db database.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		userID, err := primitive.ObjectIDFromHex(c.Params("userId"))
 		if err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+			return apperror.Validation("invalid_user_id", "Invalid user ID")
 		}
 
-		tasks, err := db.User().GetTasksByUser(c.Context(), userID)
+		tasks, err := db.Tasks().GetTasksByUser(c.UserContext(), userID)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve tasks"})
+			return apperror.Wrap(err, "Failed to retrieve tasks")
 		}
 
 		return c.JSON(fiber.Map{"tasks": tasks})
@@ -143,26 +164,31 @@ func UpdateTaskStatus(db database.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		id, err := primitive.ObjectIDFromHex(c.Params("id"))
 		if err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid task ID"})
+			return apperror.Validation("invalid_task_id", "Invalid task ID")
 		}
 
 		var data struct {
 			Completed bool `json:"completed"`
 		}
-		if err := c.BodyParser(&data); err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		if err := parseBody(c, &data); err != nil {
+			return err
 		}
 
 		// First try to get the task to check if it exists
-		_, err = db.User().GetTaskByID(c.Context(), id)
+		_, err = db.Tasks().GetTaskByID(c.UserContext(), id)
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Task not found"})
+			return apperror.Wrap(err, "Failed to retrieve task")
 		}
 
-		// Update task completion status
-		_, err = db.User().UpdateTaskStatus(c.Context(), primitive.NilObjectID, id, data.Completed)
+		// Update task completion status, and the action item it was made from
+		err = db.Transaction(c.UserContext(), func(ctx context.Context) error {
+			if _, err := db.Tasks().UpdateTaskStatus(ctx, primitive.NilObjectID, id, data.Completed); err != nil {
+				return apperror.Wrap(err, "Failed to update task status")
+			}
+			return completeActionItem(ctx, db, id, data.Completed)
+		})
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to update task status"})
+			return err
 		}
 
 		return c.JSON(fiber.Map{
@@ -180,24 +206,21 @@ func AssignTask(db database.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		taskID, err := primitive.ObjectIDFromHex(c.Params("id"))
 		if err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid task ID"})
+			return apperror.Validation("invalid_task_id", "Invalid task ID")
 		}
 
 		var data struct {
-			UserID string `json:"user_id"`
+			UserID string `json:"user_id" validate:"required,objectid"`
 		}
-		if err := c.BodyParser(&data); err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		if err := parseBody(c, &data); err != nil {
+			return err
 		}
 
-		userID, err := primitive.ObjectIDFromHex(data.UserID)
-		if err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
-		}
+		userID, _ := primitive.ObjectIDFromHex(data.UserID)
 
-		err = db.User().AssignTask(c.Context(), taskID, userID)
+		err = db.Tasks().AssignTask(c.UserContext(), taskID, userID)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to assign task"})
+			return apperror.Wrap(err, "Failed to assign task")
 		}
 
 		return c.JSON(fiber.Map{"message": "Task assigned successfully"})
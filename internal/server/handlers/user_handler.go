@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"strings"
+
+	"DBackend/internal/apperror"
 	"DBackend/internal/database"
+	"DBackend/internal/metrics"
 	"DBackend/model"
 	"DBackend/utils"
 	"github.com/gofiber/fiber/v2"
@@ -21,49 +25,46 @@ func NewUserHandler(db database.Service) *UserHandler {
 // RegisterHandler handles user registration
 func (h *UserHandler) RegisterHandler(c *fiber.Ctx) error {
 	data := new(struct {
-		FirstName  string `json:"first_name"`
-		SecondName string `json:"second_name"`
-		Role       string `json:"role"`
-		Email      string `json:"email"`
-		Password   string `json:"password"`
+		FirstName  string `json:"first_name" validate:"required,max=100"`
+		SecondName string `json:"second_name" validate:"required,max=100"`
+		Role       string `json:"role" validate:"required,role"`
+		Email      string `json:"email" validate:"required,email"`
+		Password   string `json:"password" validate:"required,min=8,max=72"`
 	})
 
-	// Parse request body
-	if err := c.BodyParser(data); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
-	}
-
-	// Validate required fields
-	if data.FirstName == "" || data.SecondName == "" || data.Role == "" || data.Email == "" || data.Password == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "All fields are required"})
+	// Parse and validate request body
+	if err := parseBody(c, data); err != nil {
+		return err
 	}
+	data.Email = strings.ToLower(strings.TrimSpace(data.Email))
 
 	// Check if user already exists
-	existingUser, err := h.db.User().FindByEmail(c.Context(), data.Email)
+	existingUser, err := h.db.Users().FindByEmail(c.UserContext(), data.Email)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+		return apperror.Wrap(err, "Database error")
 	}
 
 	// If user exists, add role if not already assigned
 	if existingUser != nil {
 		for _, role := range existingUser.Roles {
 			if role == data.Role {
-				return c.Status(400).JSON(fiber.Map{"error": "User already has this role"})
+				return apperror.Validation("user_already_has_this_role", "User already has this role")
 			}
 		}
 
 		// Append the new role and update user
 		existingUser.Roles = append(existingUser.Roles, data.Role)
-		_, err := h.db.User().UpdateRoles(c.Context(), existingUser.Email, existingUser.Roles)
+		_, err := h.db.Users().UpdateRoles(c.UserContext(), existingUser.Email, existingUser.Roles)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to update user roles"})
+			return apperror.Wrap(err, "Failed to update user roles")
 		}
 
 		// Insert role-specific data
-		err = h.db.User().CreateRoleData(c.Context(), existingUser.ID, data.Role)
+		err = h.db.Users().CreateRoleData(c.UserContext(), existingUser.ID, data.Role)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to create role data"})
+			return apperror.Wrap(err, "Failed to create role data")
 		}
+		metrics.Registrations.WithLabelValues(data.Role).Inc()
 
 		return c.JSON(fiber.Map{"message": "Role added successfully"})
 	}
@@ -71,7 +72,7 @@ func (h *UserHandler) RegisterHandler(c *fiber.Ctx) error {
 	// Hash password securely
 	hashedPassword, err := utils.HashPassword(data.Password)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to encrypt password"})
+		return apperror.Wrap(err, "Failed to encrypt password")
 	}
 
 	// Create new user
@@ -85,16 +86,17 @@ func (h *UserHandler) RegisterHandler(c *fiber.Ctx) error {
 	}
 
 	// Insert user into the database
-	insertResult, err := h.db.User().CreateUser(c.Context(), user)
+	insertResult, err := h.db.Users().CreateUser(c.UserContext(), user)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to create user"})
+		return apperror.Wrap(err, "Failed to create user")
 	}
 
 	// Insert role-specific data
-	err = h.db.User().CreateRoleData(c.Context(), insertResult.InsertedID.(primitive.ObjectID), data.Role)
+	err = h.db.Users().CreateRoleData(c.UserContext(), insertResult.InsertedID.(primitive.ObjectID), data.Role)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to create role data"})
+		return apperror.Wrap(err, "Failed to create role data")
 	}
+	metrics.Registrations.WithLabelValues(data.Role).Inc()
 
 	return c.JSON(fiber.Map{"message": "User registered successfully"})
 }
@@ -105,28 +107,24 @@ func (h *UserHandler) GetUserDetailsHandler(c *fiber.Ctx) error {
 	userToken := c.Locals("token")
 	tokenStr, ok := userToken.(string)
 	if !ok || tokenStr == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Validation("invalid_token", "Invalid token")
 	}
 	// Validate token and extract claims
 	claims, err := utils.ValidateJWT(tokenStr)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid token"})
+		return apperror.Validation("invalid_token", "Invalid token")
 	}
 	// Convert claims.UserID to primitive.ObjectID
 	id, err := primitive.ObjectIDFromHex(claims.UserID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperror.Validation("invalid_user_id", "Invalid user ID")
 	}
 	// Find the user in the database
-	user, err := h.db.User().FindByID(c.Context(), "users", id)
+	userDetails, err := h.db.Users().FindByID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "User not found"})
-	}
-	// Type assert user to model.User and remove sensitive fields
-	userDetails, ok := user.(*model.User)
-	if !ok {
-		return c.Status(500).JSON(fiber.Map{"error": "Internal server error"})
+		return apperror.Wrap(err, "Failed to retrieve user")
 	}
+	// Remove sensitive fields
 	userDetails.Password = ""
 	userDetails.ID = primitive.NilObjectID
 
@@ -135,9 +133,9 @@ func (h *UserHandler) GetUserDetailsHandler(c *fiber.Ctx) error {
 
 // GetUserCountHandler returns the total count of users
 func (h *UserHandler) GetUserCountHandler(c *fiber.Ctx) error {
-	count, err := h.db.User().GetUserCount(c.Context())
+	count, err := h.db.Users().GetUserCount(c.UserContext())
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to get user count"})
+		return apperror.Wrap(err, "Failed to get user count")
 	}
 	
 	return c.JSON(fiber.Map{"count": count})
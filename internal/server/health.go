@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/database"
+	"DBackend/internal/health"
+)
+
+// newHealthChecker wires the probes for every external dependency
+func newHealthChecker(cfg *config.Config, db database.Service) *health.Checker {
+	return health.NewChecker(
+		health.Check{
+			Name:      "mongodb",
+			Critical:  true,
+			SlowAfter: 500 * time.Millisecond,
+			Probe: func(ctx context.Context) error {
+				return db.Health(ctx)
+			},
+		},
+		// without its indexes the database rejects text search and lets
+		// duplicates in, so the instance is not ready until it is migrated
+		health.Check{
+			Name:     "migrations",
+			Critical: true,
+			Probe: func(ctx context.Context) error {
+				pending, err := db.PendingMigrations(ctx)
+				if err != nil {
+					return err
+				}
+				if len(pending) > 0 {
+					return fmt.Errorf("migrations %v are pending; run go run ./cmd/migrate or set BLUEPRINT_DB_MIGRATE=true", pending)
+				}
+				return nil
+			},
+		},
+		health.Check{
+			Name:      "ml_match_service",
+			SlowAfter: time.Second,
+			Probe:     health.HTTP(&http.Client{Timeout: cfg.ML.Timeout}, cfg.ML.URL),
+		},
+		calendarCheck(cfg.Calendar),
+		health.Check{
+			Name:  "blob_storage",
+			Probe: health.WritableDir(cfg.Storage.UploadDir),
+		},
+	)
+}
+
+// calendarCheck probes what the configured calendar provider depends on; the
+// local provider needs nothing
+func calendarCheck(cfg config.Calendar) health.Check {
+	check := health.Check{Name: "calendar", Probe: func(ctx context.Context) error { return nil }}
+	switch cfg.Provider {
+	case "google":
+		check.Probe = health.Files(cfg.CredentialsFile)
+	case "caldav":
+		check.SlowAfter = time.Second
+		check.Probe = health.HTTP(&http.Client{Timeout: 5 * time.Second}, cfg.CalDAV.URL)
+	}
+	return check
+}
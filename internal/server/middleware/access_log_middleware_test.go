@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"DBackend/internal/apperror"
+	"DBackend/internal/config"
+	"DBackend/internal/logging"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestAccessLogCarriesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(config.Log{Level: "info", Format: "json"}, &buf)
+
+	var seen string
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(RequestIDMiddleware(), AccessLog(logger))
+	app.Get("/deals/:id", func(c *fiber.Ctx) error {
+		seen = logging.RequestID(c.UserContext())
+		return apperror.NotFound("deal_not_found", "Deal not found")
+	})
+
+	for _, tc := range []struct {
+		header string
+		keep   bool
+	}{
+		{"trace-123", true},
+		{"", false},
+		{"bad id\nwith newline", false},
+	} {
+		buf.Reset()
+		req := httptest.NewRequest("GET", "/deals/42?token=leaked-token-value", nil)
+		if tc.header != "" {
+			req.Header.Set(RequestIDHeader, tc.header)
+		}
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		id := resp.Header.Get(RequestIDHeader)
+		if id == "" || seen != id || (tc.keep && id != tc.header) || (!tc.keep && id == tc.header) {
+			t.Errorf("header %q: response ID %q, context ID %q", tc.header, id, seen)
+		}
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("access log %q is not JSON: %v", buf.String(), err)
+		}
+		if entry["msg"] != "request" || entry["level"] != slog.LevelWarn.String() || entry["status"] != 404.0 ||
+			entry["method"] != "GET" || entry["path"] != "/deals/42" || entry["request_id"] != id || entry["latency_ms"] == nil {
+			t.Errorf("access log = %v", entry)
+		}
+		if strings.Contains(buf.String(), "leaked-token-value") {
+			t.Errorf("access log leaks the query string: %s", buf.String())
+		}
+	}
+}
@@ -1,9 +1,10 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"strings"
 
+	"DBackend/internal/apperror"
 	"DBackend/internal/database"
 	"DBackend/utils"
 
@@ -13,30 +14,23 @@ import (
 // JWTMiddleware verifies JWT tokens and checks if they are blacklisted
 func JWTMiddleware(db database.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Get token from Authorization header or cookie
-		token := c.Cookies("jwt")
+		token := requestToken(c)
 		if token == "" {
-			authHeader := c.Get("Authorization")
-			if strings.HasPrefix(authHeader, "Bearer ") {
-				token = utils.ExtractBearerToken(authHeader)
-			}
-		}
-		if token == "" {
-			return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+			return apperror.Unauthorized("unauthorized", "Unauthorized")
 		}
 		// Check if token is blacklisted
-		isBlacklisted, err := db.User().IsTokenBlacklisted(c.Context(), token)
+		isBlacklisted, err := db.Users().IsTokenBlacklisted(c.UserContext(), token)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+			return apperror.Wrap(err, "Database error")
 		}
 		if isBlacklisted {
-			return c.Status(401).JSON(fiber.Map{"error": "Token expired, please log in again"})
+			return apperror.Unauthorized("token_expired", "Token expired, please log in again")
 		}
 		// Validate token
 		claims, err := utils.ValidateJWT(token)
 		if err != nil {
-			log.Printf("JWT validation error: %v", err)
-			return c.Status(401).JSON(fiber.Map{"error": "Invalid or expired token"})
+			slog.DebugContext(c.UserContext(), "rejected token", "error", err)
+			return apperror.Unauthorized("invalid_token", "Invalid or expired token")
 		}
 
 		// Store user details in context
@@ -48,17 +42,28 @@ func JWTMiddleware(db database.Service) fiber.Handler {
 	}
 }
 
+// requestToken returns the token from the jwt cookie or the Authorization header
+func requestToken(c *fiber.Ctx) string {
+	if token := c.Cookies("jwt"); token != "" {
+		return token
+	}
+	if authHeader := c.Get("Authorization"); strings.HasPrefix(authHeader, "Bearer ") {
+		return utils.ExtractBearerToken(authHeader)
+	}
+	return ""
+}
+
 func RequireRole(role string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		roles, ok := c.Locals("roles").([]string)
 		if !ok {
-			return c.Status(403).JSON(fiber.Map{"error": "Forbidden"})
+			return apperror.Forbidden("forbidden", "Forbidden")
 		}
 		for _, r := range roles {
 			if r == role {
 				return c.Next()
 			}
 		}
-		return c.Status(403).JSON(fiber.Map{"error": "access denied"})
+		return apperror.Forbidden("forbidden", "access denied")
 	}
 }
@@ -1,14 +1,18 @@
 package middleware
 
 import (
+	"strings"
+
+	"DBackend/internal/config"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
 // CORSMiddleware configures CORS for the application
-func CORSMiddleware() fiber.Handler {
+func CORSMiddleware(cfg config.CORS) fiber.Handler {
 	return cors.New(cors.Config{
-		AllowOrigins:     "http://localhost:3000", // Change this to specific frontend domains in production
+		AllowOrigins:     strings.Join(cfg.AllowOrigins, ","),
 		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS,PATCH",
 		AllowHeaders:     "Accept, Authorization, Content-Type",
 		AllowCredentials: true, // Enables cookies (for authentication)
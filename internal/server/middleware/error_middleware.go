@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"DBackend/internal/apperror"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ProblemContentType is the media type of error responses (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Problem is the error envelope every failed request returns. Code is stable
+// and machine-readable; Detail is for humans and may change.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Code      string `json:"code"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	// Errors lists each invalid field when Code is validation_failed
+	Errors []apperror.FieldError `json:"errors,omitempty"`
+	// Details is extra context some codes carry, described with the route
+	Details interface{} `json:"details,omitempty"`
+}
+
+// statuses maps each domain error kind to its HTTP status
+var statuses = map[apperror.Kind]int{
+	apperror.KindInternal:     fiber.StatusInternalServerError,
+	apperror.KindValidation:   fiber.StatusBadRequest,
+	apperror.KindUnauthorized: fiber.StatusUnauthorized,
+	apperror.KindForbidden:    fiber.StatusForbidden,
+	apperror.KindNotFound:     fiber.StatusNotFound,
+	apperror.KindConflict:     fiber.StatusConflict,
+	apperror.KindRateLimited:  fiber.StatusTooManyRequests,
+}
+
+// ErrorHandler is the app's fiber.ErrorHandler. It renders domain errors,
+// Fiber errors and unexpected errors as a Problem; the cause of internal
+// errors is logged with the request ID and never sent to the client.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	problem := Problem{
+		Type:      "about:blank",
+		Status:    fiber.StatusInternalServerError,
+		Code:      "internal",
+		Detail:    "Internal server error",
+		Instance:  c.OriginalURL(),
+		RequestID: RequestID(c),
+	}
+
+	var appErr *apperror.Error
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &appErr):
+		problem.Status = statuses[appErr.Kind]
+		problem.Code = appErr.Code
+		problem.Detail = appErr.Message
+		problem.Errors = appErr.Fields
+		problem.Details = appErr.Details
+		if appErr.RetryAfter > 0 {
+			// Whole seconds, rounded up so clients never retry too early
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(appErr.RetryAfter.Seconds()))))
+		}
+	case errors.As(err, &fiberErr):
+		problem.Status = fiberErr.Code
+		problem.Code = statusCode(fiberErr.Code)
+		problem.Detail = fiberErr.Message
+	}
+	if problem.Status >= fiber.StatusInternalServerError {
+		slog.ErrorContext(c.UserContext(), "request failed", "method", c.Method(), "path", c.Path(), "error", err)
+	}
+	problem.Title = http.StatusText(problem.Status)
+
+	return c.Status(problem.Status).JSON(problem, ProblemContentType)
+}
+
+// statusCode derives an error code from a status, e.g. 405 -> method_not_allowed
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"DBackend/internal/metrics"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// unmatchedRoute labels requests answered by root middleware rather than a
+// route, such as the not found handler and CORS preflights
+const unmatchedRoute = "unmatched"
+
+// Metrics counts and times requests per route template. Like AccessLog it runs
+// the error handler itself so the recorded status is the one sent.
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		route := routeTemplate(c)
+		status := strconv.Itoa(c.Response().StatusCode())
+		metrics.HTTPRequests.WithLabelValues(c.Method(), route, status).Inc()
+		metrics.HTTPDuration.WithLabelValues(c.Method(), route).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
+
+// routeTemplate returns the path template of the route that handled c, such
+// as /api/v1/dealflow/:id, so IDs in paths do not multiply labels
+func routeTemplate(c *fiber.Ctx) string {
+	// Root middleware reports "/" as its route whatever the path was
+	route := c.Route().Path
+	if route == "/" && c.Path() != "/" {
+		return unmatchedRoute
+	}
+	return route
+}
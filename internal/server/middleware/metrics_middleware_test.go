@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"DBackend/internal/apperror"
+	"DBackend/internal/metrics"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsLabelRouteTemplates(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(Metrics())
+	app.Get("/things/:id", func(c *fiber.Ctx) error {
+		if c.Params("id") == "missing" {
+			return apperror.NotFound("thing_not_found", "Thing not found")
+		}
+		return c.SendString("ok")
+	})
+	app.Use(func(c *fiber.Ctx) error {
+		return apperror.NotFound("route_not_found", "Route not found")
+	})
+
+	ok := metrics.HTTPRequests.WithLabelValues("GET", "/things/:id", "200")
+	missing := metrics.HTTPRequests.WithLabelValues("GET", "/things/:id", "404")
+	unmatched := metrics.HTTPRequests.WithLabelValues("GET", unmatchedRoute, "404")
+	before := []float64{testutil.ToFloat64(ok), testutil.ToFloat64(missing), testutil.ToFloat64(unmatched)}
+
+	for _, path := range []string{"/things/1", "/things/2", "/things/missing", "/elsewhere/3"} {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil), -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	for i, tc := range []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"200 on the route", testutil.ToFloat64(ok), 2},
+		{"404 on the route", testutil.ToFloat64(missing), 1},
+		{"unmatched", testutil.ToFloat64(unmatched), 1},
+	} {
+		if tc.got-before[i] != tc.want {
+			t.Errorf("%s: counted %v, want %v", tc.name, tc.got-before[i], tc.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"strings"
+
+	"DBackend/internal/apperror"
+	"DBackend/internal/ratelimit"
+	"DBackend/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimit rejects requests over the group's per-IP or per-account limit
+// with 429 and a Retry-After header. account names the caller's account, or
+// returns "" when it is unknown so only the IP limit applies.
+func RateLimit(limiter *ratelimit.Limiter, group ratelimit.Group, account func(*fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if wait, ok := limiter.Allow(c.UserContext(), group, c.IP(), account(c)); !ok {
+			return apperror.TooManyRequests("rate_limited", "Too many requests, try again later", wait)
+		}
+		return c.Next()
+	}
+}
+
+// WriteRateLimit applies the writes limits to POST, PUT, PATCH and DELETE
+// requests, counting per signed-in user. Reads are not limited.
+func WriteRateLimit(limiter *ratelimit.Limiter) fiber.Handler {
+	limit := RateLimit(limiter, ratelimit.Writes, TokenAccount)
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+		return limit(c)
+	}
+}
+
+// EmailAccount names the account by the email field of a JSON or form body
+func EmailAccount(c *fiber.Ctx) string {
+	var body struct {
+		Email string `json:"email" form:"email"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(body.Email))
+}
+
+// TokenAccount names the account by the user of a valid token. The token is
+// checked again, with revocation, by JWTMiddleware on protected routes.
+func TokenAccount(c *fiber.Ctx) string {
+	token := requestToken(c)
+	if token == "" {
+		return ""
+	}
+	claims, err := utils.ValidateJWT(token)
+	if err != nil {
+		return ""
+	}
+	return claims.UserID
+}
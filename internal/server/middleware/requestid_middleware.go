@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"DBackend/internal/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// RequestIDHeader carries the request ID in both directions; a client-supplied
+// value is kept so a request can be traced across services
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the Locals key the request ID is stored under
+const requestIDKey = "requestid"
+
+// maxRequestIDLength bounds client-supplied IDs before they reach the logs
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware assigns every request an ID, echoes it in the response
+// and stores it in the user context, so handlers that pass c.UserContext()
+// on to the database layer get it in every log record
+func RequestIDMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = utils.UUIDv4()
+		}
+		c.Set(RequestIDHeader, id)
+		c.Locals(requestIDKey, id)
+		c.SetUserContext(logging.WithRequestID(c.UserContext(), id))
+		return c.Next()
+	}
+}
+
+// RequestID returns the ID RequestIDMiddleware assigned to the request
+func RequestID(c *fiber.Ctx) string {
+	id, _ := c.Locals(requestIDKey).(string)
+	return id
+}
+
+// validRequestID accepts short IDs of printable ASCII without spaces
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
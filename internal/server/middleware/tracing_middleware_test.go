@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+	return recorder
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTracingContinuesCallerTrace(t *testing.T) {
+	recorder := recordSpans(t)
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(RequestIDMiddleware())
+	app.Use(Tracing())
+	var handlerTrace trace.TraceID
+	app.Get("/things/:id", func(c *fiber.Ctx) error {
+		handlerTrace = trace.SpanContextFromContext(c.UserContext()).TraceID()
+		if c.Params("id") == "broken" {
+			return errors.New("database unreachable")
+		}
+		return c.SendString("ok")
+	})
+
+	const parent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	req := httptest.NewRequest("GET", "/things/1", nil)
+	req.Header.Set("traceparent", parent)
+	req.Header.Set(RequestIDHeader, "req-42")
+	if _, err := app.Test(req, -1); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET /things/:id" || span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("span = %q (%v), want server span GET /things/:id", span.Name(), span.SpanKind())
+	}
+	if got := span.SpanContext().TraceID().String(); got != "0af7651916cd43dd8448eb211c80319c" || handlerTrace.String() != got {
+		t.Errorf("trace = %s, handler saw %s, want the caller's trace", got, handlerTrace)
+	}
+	attrs := attributes(span)
+	if attrs["http.route"].AsString() != "/things/:id" || attrs["http.response.status_code"].AsInt64() != 200 ||
+		attrs["request.id"].AsString() != "req-42" {
+		t.Errorf("attributes = %v", attrs)
+	}
+	if span.Status().Code == codes.Error {
+		t.Errorf("status = %v, want unset for 200", span.Status())
+	}
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/things/broken", nil), -1); err != nil {
+		t.Fatal(err)
+	}
+	failed := recorder.Ended()[1]
+	if failed.Status().Code != codes.Error || len(failed.Events()) == 0 {
+		t.Errorf("failed span status = %v with %d events, want error recorded", failed.Status(), len(failed.Events()))
+	}
+	if attributes(failed)["http.response.status_code"].AsInt64() != 500 {
+		t.Errorf("failed span attributes = %v, want status 500", attributes(failed))
+	}
+}
@@ -0,0 +1,17 @@
+package server
+
+import (
+	"DBackend/internal/config"
+	"DBackend/internal/database"
+	"DBackend/internal/ratelimit"
+)
+
+// newRateLimiter keeps limits in process, or in MongoDB when several
+// instances must share them
+func newRateLimiter(cfg config.RateLimit, db database.Service) *ratelimit.Limiter {
+	var store ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.Store == "mongo" {
+		store = db.RateLimits()
+	}
+	return ratelimit.New(cfg, store)
+}
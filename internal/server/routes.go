@@ -3,10 +3,14 @@ package server
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
+	"DBackend/internal/apperror"
+	"DBackend/internal/config"
 	"DBackend/internal/database"
+	"DBackend/internal/health"
+	"DBackend/internal/ratelimit"
 	"DBackend/internal/server/middleware"
 	"DBackend/internal/server/routes"
 
@@ -15,48 +19,57 @@ import (
 )
 
 func (s *FiberServer) RegisterFiberRoutes() {
-	api := s.Group("/api/v1", middleware.CORSMiddleware())
-	api.Get("/health", s.healthHandler)
+	api := s.Group("/api/v1", middleware.CORSMiddleware(s.cfg.CORS), middleware.WriteRateLimit(s.limiter))
+	routes.HealthRoutes(api, s.health)
+	routes.DocsRoutes(api)
 	api.Get("/websocket", websocket.New(s.websocketHandler))
 	
 	// Register all other routes
-	routes.UserRoutes(api, s.db)
-	routes.AuthRoutes(api, s.db)
-	routes.FounderRoutes(api, s.db)
-	routes.InvestorRoutes(api, s.db)
-	routes.MatchRoutes(api, s.db)
-	routes.DealFlowRoutes(api, s.db)
+	routes.UserRoutes(api, s.db, s.limiter)
+	routes.AuthRoutes(api, s.db, s.limiter)
+	routes.FounderRoutes(api, s.db, s.cfg.Storage)
+	routes.InvestorRoutes(api, s.db, s.cfg.Calendar)
+	routes.MatchRoutes(api, s.db, s.cfg.ML)
+	routes.DealFlowRoutes(api, s.db, s.cfg.Calendar)
 	
 	// Register new routes
-	routes.GrantRoutes(api, s.db)
+	routes.GrantRoutes(api, s.db, s.cfg.Storage)
 	routes.TaskRoutes(api, s.db)
-	routes.MeetingRoutes(api, s.db)
+	routes.MeetingRoutes(api, s.db, s.cfg.Calendar)
+	routes.CalendarRoutes(api, s.db, s.cfg.Calendar)
+	routes.BookingRoutes(api, s.db, s.cfg.Calendar)
+	routes.SearchRoutes(api, s.db)
+	routes.JobRoutes(api, s.db)
+	routes.EventRoutes(api, s.db)
 }
 
-func (s *FiberServer) healthHandler(c *fiber.Ctx) error {
-	return c.JSON(s.db.Health())
-}
-
-func SetupRoutes(app *fiber.App, db database.Service, prefix string) {
-	api := app.Group("/" + prefix)
-	routes.UserRoutes(api, db)
-	routes.AuthRoutes(api, db)
-	routes.FounderRoutes(api, db)
-	routes.InvestorRoutes(api, db)
-	routes.MatchRoutes(api, db)
-	routes.DealFlowRoutes(api, db)
+func SetupRoutes(app *fiber.App, db database.Service, cfg *config.Config, checker *health.Checker, limiter *ratelimit.Limiter, prefix string) {
+	api := app.Group("/"+prefix, middleware.WriteRateLimit(limiter))
+	routes.HealthRoutes(api, checker)
+	routes.DocsRoutes(api)
+	routes.UserRoutes(api, db, limiter)
+	routes.AuthRoutes(api, db, limiter)
+	routes.FounderRoutes(api, db, cfg.Storage)
+	routes.InvestorRoutes(api, db, cfg.Calendar)
+	routes.MatchRoutes(api, db, cfg.ML)
+	routes.DealFlowRoutes(api, db, cfg.Calendar)
 	
 	// Register new routes
-	routes.GrantRoutes(api, db)
+	routes.GrantRoutes(api, db, cfg.Storage)
 	routes.TaskRoutes(api, db)
-	routes.MeetingRoutes(api, db)
+	routes.MeetingRoutes(api, db, cfg.Calendar)
+	routes.CalendarRoutes(api, db, cfg.Calendar)
+	routes.BookingRoutes(api, db, cfg.Calendar)
+	routes.SearchRoutes(api, db)
+	routes.JobRoutes(api, db)
+	routes.EventRoutes(api, db)
 	
 	NotFoundRoute(app)
 }
 
 func NotFoundRoute(app *fiber.App) {
 	app.Use(func(c *fiber.Ctx) error {
-		return c.Status(404).JSON(fiber.Map{"error": "Route not found"})
+		return apperror.NotFound("route_not_found", "Route not found")
 	})
 }
 
@@ -68,7 +81,7 @@ func (s *FiberServer) websocketHandler(con *websocket.Conn) {
 			_, _, err := con.ReadMessage()
 			if err != nil {
 				cancel()
-				log.Println("Receiver Closing", err)
+				slog.Debug("websocket receiver closing", "error", err)
 				break
 			}
 		}
@@ -81,7 +94,7 @@ func (s *FiberServer) websocketHandler(con *websocket.Conn) {
 		default:
 			payload := fmt.Sprintf("server timestamp: %d", time.Now().UnixNano())
 			if err := con.WriteMessage(websocket.TextMessage, []byte(payload)); err != nil {
-				log.Printf("could not write to socket: %v", err)
+				slog.Debug("could not write to websocket", "error", err)
 				return
 			}
 			time.Sleep(time.Second * 2)
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"DBackend/internal/database"
+	"DBackend/internal/ratelimit"
 	"DBackend/internal/server/handlers"
 	"DBackend/internal/server/middleware"
 
@@ -9,12 +10,13 @@ import (
 )
 
 // AuthRoutes registers authentication routes
-func AuthRoutes(api fiber.Router, db database.Service) {
+func AuthRoutes(api fiber.Router, db database.Service, limiter *ratelimit.Limiter) {
 	ao := api.Group("/auth")
 	authJwT := api.Group("/get", middleware.JWTMiddleware(db))
-	authHandler := handlers.NewAuthHandler(db)
-	ao.Post("/login", authHandler.LoginHandler)
+	authHandler := handlers.NewAuthHandler(db, limiter)
+	ao.Post("/login", middleware.RateLimit(limiter, ratelimit.Login, middleware.EmailAccount), authHandler.LoginHandler)
 	ao.Post("/logout", authHandler.LogoutHandler)
 
 	authJwT.Get("/me", authHandler.MeHandler)
+	authJwT.Put("/me/timezone", authHandler.SetTimezoneHandler)
 }
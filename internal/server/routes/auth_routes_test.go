@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"testing"
+	"time"
+
+	"DBackend/internal/config"
+)
+
+func TestAuthLoginMeLogout(t *testing.T) {
+	a := newTestApp(t)
+	a.do("POST", "/user/register", "", map[string]string{
+		"first_name": "Grace", "second_name": "Hopper", "role": "founder",
+		"email": "grace@example.com", "password": "correct-horse",
+	}, 200)
+
+	a.do("POST", "/auth/login", "", map[string]string{"email": "grace@example.com", "password": "wrong"}, 401)
+	a.do("POST", "/auth/login", "", map[string]string{"email": "nobody@example.com", "password": "correct-horse"}, 401)
+	login := a.do("POST", "/auth/login", "", map[string]string{"email": "GRACE@example.com", "password": "correct-horse"}, 200)
+	token, _ := login["token"].(string)
+	if token == "" {
+		t.Fatalf("login response = %v, want a token", login)
+	}
+
+	me := a.do("GET", "/get/me", token, nil, 200)
+	if roles, _ := me["roles"].([]interface{}); len(roles) != 1 || roles[0] != "founder" {
+		t.Errorf("me roles = %v, want [founder]", me["roles"])
+	}
+
+	a.do("POST", "/auth/logout", token, nil, 200)
+	a.do("GET", "/get/me", token, nil, 401)
+}
+
+func TestLoginLocksAccountAfterRepeatedFailures(t *testing.T) {
+	cfg := config.Default()
+	cfg.RateLimit.Lockout = config.Lockout{Threshold: 3, Window: 15 * time.Minute, Base: time.Minute, Max: time.Hour}
+	a := newTestAppWithConfig(t, cfg)
+	a.do("POST", "/user/register", "", map[string]string{
+		"first_name": "Grace", "second_name": "Hopper", "role": "founder",
+		"email": "grace@example.com", "password": "correct-horse",
+	}, 200)
+
+	wrong := map[string]string{"email": "grace@example.com", "password": "wrong"}
+	a.do("POST", "/auth/login", "", wrong, 401)
+	a.do("POST", "/auth/login", "", wrong, 401)
+	problem := a.do("POST", "/auth/login", "", wrong, 429)
+	if problem["code"] != "account_locked" || a.header.Get("Retry-After") != "60" {
+		t.Errorf("problem = %v, Retry-After = %q; want account_locked for 60s", problem, a.header.Get("Retry-After"))
+	}
+
+	// the right password is refused while locked, and the lock is per account
+	a.do("POST", "/auth/login", "", map[string]string{"email": "Grace@example.com", "password": "correct-horse"}, 429)
+	a.do("POST", "/auth/login", "", map[string]string{"email": "nobody@example.com", "password": "wrong"}, 401)
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"DBackend/internal/config"
+	"DBackend/internal/database"
+	"DBackend/internal/server/handlers"
+	"DBackend/internal/server/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BookingRoutes lets investors publish their availability as booking links
+// and founders in their deal flow book meetings through them
+func BookingRoutes(api fiber.Router, db database.Service, calendar config.Calendar) {
+	handler := handlers.NewBookingHandler(db, calendar)
+	booking := api.Group("/booking")
+	booking.Use(middleware.JWTMiddleware(db))
+
+	// Registered before /:slug so "availability" and "links" are not taken as slugs
+	investor := middleware.RequireRole("investor")
+	booking.Put("/availability", investor, handler.SetAvailabilityHandler)
+	booking.Get("/availability", investor, handler.GetAvailabilityHandler)
+	booking.Post("/links", investor, handler.CreateBookingLinkHandler)
+	booking.Get("/links", investor, handler.ListBookingLinksHandler)
+	booking.Delete("/links/:id", investor, handler.DeleteBookingLinkHandler)
+
+	booking.Get("/:slug/slots", handler.GetSlotsHandler)
+	booking.Post("/:slug", handler.BookHandler)
+}
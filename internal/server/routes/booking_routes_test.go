@@ -0,0 +1,162 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// everyDay is availability around the clock, so slots exist whenever the
+// test runs
+func everyDay() map[string]interface{} {
+	var rules []map[string]string
+	for _, day := range []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"} {
+		rules = append(rules, map[string]string{"day": day, "start": "00:00", "end": "23:30"})
+	}
+	return map[string]interface{}{"timezone": "Europe/Berlin", "rules": rules, "duration": 30, "buffer": 30, "horizon": 2}
+}
+
+func TestBookingLinks(t *testing.T) {
+	a := newTestApp(t)
+	investor, token := a.user("investor")
+	founder, founderToken := a.user("founder")
+	_, outsiderToken := a.user("founder")
+	a.insert("deal_flow", model.DealFlow{InvestorID: investor, StartupID: founder, Stage: "screening"})
+
+	a.do("GET", "/booking/availability", token, nil, 404)
+	a.do("PUT", "/booking/availability", founderToken, everyDay(), 403)
+	invalid := everyDay()
+	invalid["rules"] = []map[string]string{{"day": "monday", "start": "17:00", "end": "09:00"}}
+	problem := a.do("PUT", "/booking/availability", token, invalid, 400)
+	if fields, _ := problem["errors"].([]interface{}); len(fields) != 1 || fields[0].(map[string]interface{})["field"] != "rules[0].end" {
+		t.Errorf("problem = %v, want rules[0].end invalid", problem)
+	}
+	a.do("PUT", "/booking/availability", token, everyDay(), 200)
+	if got := a.do("GET", "/booking/availability", token, nil, 200); got["availability"].(map[string]interface{})["timezone"] != "Europe/Berlin" {
+		t.Errorf("availability = %v", got)
+	}
+
+	created := a.do("POST", "/booking/links", token, map[string]string{"title": "Office hours"}, 201)
+	link, _ := created["link"].(map[string]interface{})
+	slug, _ := link["slug"].(string)
+	if slug == "" || !strings.HasSuffix(created["url"].(string), "/api/v1/booking/"+slug) {
+		t.Fatalf("created = %v, want a link and its URL", created)
+	}
+	if links := a.do("GET", "/booking/links", token, nil, 200)["links"].([]interface{}); len(links) != 1 {
+		t.Errorf("links = %v, want 1", links)
+	}
+
+	a.do("GET", "/booking/nope/slots", founderToken, nil, 404)
+	if problem := a.do("GET", "/booking/"+slug+"/slots", outsiderToken, nil, 403); problem["code"] != "not_in_deal_flow" {
+		t.Errorf("problem = %v, want not_in_deal_flow", problem)
+	}
+	got := a.do("GET", "/booking/"+slug+"/slots", founderToken, nil, 200)
+	slots, _ := got["slots"].([]interface{})
+	if len(slots) < 3 || got["timezone"] != "Europe/Berlin" {
+		t.Fatalf("slots = %v, want several in Europe/Berlin", got)
+	}
+	first := slots[0].(map[string]interface{})
+	second := slots[1].(map[string]interface{})
+
+	a.do("POST", "/booking/"+slug, outsiderToken, map[string]interface{}{"start_time": first["start"]}, 403)
+	booked := a.do("POST", "/booking/"+slug, founderToken, map[string]interface{}{"start_time": first["start"], "notes": "Seed round"}, 201)
+	meeting, _ := booked["meeting"].(map[string]interface{})
+	if meeting["investor_id"] != investor.Hex() || meeting["founder_id"] != founder.Hex() || meeting["title"] != "Office hours" || meeting["meeting_url"] == "" {
+		t.Errorf("meeting = %v, want Office hours between the investor and founder", meeting)
+	}
+	if n := len(a.store.Find("meetings", bson.M{"investor_id": investor, "founder_id": founder})); n != 1 {
+		t.Errorf("meetings = %d, want 1", n)
+	}
+	if n := len(a.store.Find("notifications", bson.M{"founder_id": investor, "notification_type": "meeting"})); n != 1 {
+		t.Errorf("investor notifications = %d, want 1", n)
+	}
+
+	// the slot is gone, and with a 30 minute buffer so is the next one
+	if problem := a.do("POST", "/booking/"+slug, founderToken, map[string]interface{}{"start_time": first["start"]}, 409); problem["code"] != "slot_taken" {
+		t.Errorf("problem = %v, want slot_taken", problem)
+	}
+	a.do("POST", "/booking/"+slug, founderToken, map[string]interface{}{"start_time": second["start"]}, 409)
+	after := a.do("GET", "/booking/"+slug+"/slots", founderToken, nil, 200)["slots"].([]interface{})
+	for _, s := range after {
+		if start := s.(map[string]interface{})["start"]; start == first["start"] || start == second["start"] {
+			t.Errorf("slot %v is still offered", start)
+		}
+	}
+
+	a.do("DELETE", "/booking/links/"+link["id"].(string), founderToken, nil, 403)
+	a.do("DELETE", "/booking/links/"+link["id"].(string), token, nil, 200)
+	a.do("DELETE", "/booking/links/"+link["id"].(string), token, nil, 404)
+	a.do("GET", "/booking/"+slug+"/slots", founderToken, nil, 404)
+}
+
+func TestBookingKeepsNothingWhenTheInviteFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	cfg := config.Default()
+	cfg.Calendar.Provider = "caldav"
+	cfg.Calendar.CalDAV.URL = server.URL + "/cal/"
+	a := newTestAppWithConfig(t, cfg)
+	investor, token := a.user("investor")
+	founder, founderToken := a.user("founder")
+	a.insert("deal_flow", model.DealFlow{InvestorID: investor, StartupID: founder})
+
+	a.do("PUT", "/booking/availability", token, everyDay(), 200)
+	slug := a.do("POST", "/booking/links", token, map[string]string{"title": "Office hours"}, 201)["link"].(map[string]interface{})["slug"].(string)
+	slots := a.do("GET", "/booking/"+slug+"/slots", founderToken, nil, 200)["slots"].([]interface{})
+
+	a.do("POST", "/booking/"+slug, founderToken, map[string]interface{}{"start_time": slots[0].(map[string]interface{})["start"]}, 500)
+	if n := len(a.store.Find("meetings", bson.M{})); n != 0 {
+		t.Errorf("meetings = %d, want none", n)
+	}
+	if n := len(a.store.Find("notifications", bson.M{})); n != 0 {
+		t.Errorf("notifications = %d, want none", n)
+	}
+}
+
+func TestBookingTakesBackTheEventWhenTheSlotIsTaken(t *testing.T) {
+	var a *testApp
+	var investor primitive.ObjectID
+	var slot time.Time
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method)
+		if r.Method == http.MethodPut {
+			// a booking racing this one takes the slot while the event is created
+			a.store.Insert("meetings", model.Meeting{InvestorID: investor, StartTime: slot, BookingLinkID: primitive.NewObjectID()})
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	cfg := config.Default()
+	cfg.Calendar.Provider = "caldav"
+	cfg.Calendar.CalDAV.URL = server.URL + "/cal/"
+	a = newTestAppWithConfig(t, cfg)
+	investor, token := a.user("investor")
+	founder, founderToken := a.user("founder")
+	a.insert("deal_flow", model.DealFlow{InvestorID: investor, StartupID: founder})
+
+	a.do("PUT", "/booking/availability", token, everyDay(), 200)
+	slug := a.do("POST", "/booking/links", token, map[string]string{"title": "Office hours"}, 201)["link"].(map[string]interface{})["slug"].(string)
+	slots := a.do("GET", "/booking/"+slug+"/slots", founderToken, nil, 200)["slots"].([]interface{})
+	slot, _ = time.Parse(time.RFC3339, slots[0].(map[string]interface{})["start"].(string))
+
+	a.do("POST", "/booking/"+slug, founderToken, map[string]interface{}{"start_time": slot}, 409)
+	if strings.Join(requests, ",") != "PUT,DELETE" {
+		t.Errorf("CalDAV requests = %v, want the event created and deleted", requests)
+	}
+	if n := len(a.store.Find("notifications", bson.M{})); n != 0 {
+		t.Errorf("notifications = %d, want none", n)
+	}
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"DBackend/internal/config"
+	"DBackend/internal/database"
+	"DBackend/internal/server/handlers"
+	"DBackend/internal/server/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CalendarRoutes lets users connect the calendar their meetings are created on
+// and subscribe to a feed of their meetings and tasks
+func CalendarRoutes(api fiber.Router, db database.Service, calendar config.Calendar) {
+	handler := handlers.NewCalendarHandler(db, calendar)
+	cal := api.Group("/calendar")
+
+	// Google redirects the browser here without a token; the signed state
+	// names the user, and the nonce cookie set by /google/connect ties it to
+	// the browser that asked
+	cal.Get("/google/callback", handler.GoogleCallbackHandler)
+
+	auth := middleware.JWTMiddleware(db)
+	cal.Get("/google/connect", auth, handler.ConnectGoogleHandler)
+	cal.Get("/google", auth, handler.GetGoogleConnectionHandler)
+	cal.Delete("/google", auth, handler.DisconnectGoogleHandler)
+
+	cal.Post("/feed", auth, handler.CreateFeedHandler)
+	cal.Get("/feed", auth, handler.GetFeedHandler)
+	cal.Delete("/feed", auth, handler.DeleteFeedHandler)
+
+	// Calendar apps subscribe without a token; the secret in the URL names
+	// the user instead
+	cal.Get("/:token.ics", handler.FeedHandler)
+}
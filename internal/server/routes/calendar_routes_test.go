@@ -0,0 +1,283 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/model"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const testRefreshToken = "1//refresh-token-for-ada"
+
+// googleConfig selects the google provider and points its OAuth client at a fake token endpoint that
+// accepts the code "good"
+func googleConfig(t *testing.T) config.Config {
+	t.Helper()
+	tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("code") != "good" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access", "refresh_token": testRefreshToken, "token_type": "Bearer", "expires_in": 3600,
+		})
+	}))
+	t.Cleanup(tokens.Close)
+
+	credentials, _ := json.Marshal(map[string]interface{}{"web": map[string]interface{}{
+		"client_id":     "client",
+		"client_secret": "secret",
+		"auth_uri":      "https://accounts.example.com/auth",
+		"token_uri":     tokens.URL,
+		"redirect_uris": []string{"http://localhost:8080/api/v1/calendar/google/callback"},
+	}})
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	if err := os.WriteFile(path, credentials, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Default()
+	cfg.Calendar.Provider = "google"
+	cfg.Calendar.CredentialsFile = path
+	return cfg
+}
+
+// connectURL asks for the consent page and returns its parsed URL and the
+// nonce cookie the browser was given
+func (a *testApp) connectURL(token string) (*url.URL, string) {
+	a.t.Helper()
+	authURL, _ := a.do("GET", "/calendar/google/connect", token, nil, 200)["auth_url"].(string)
+	u, err := url.Parse(authURL)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	resp := http.Response{Header: a.header}
+	for _, c := range resp.Cookies() {
+		if c.Name == "calendar_oauth_nonce" {
+			if !c.HttpOnly || c.SameSite != http.SameSiteLaxMode || c.Path != "/api/v1/calendar/google/callback" {
+				a.t.Errorf("nonce cookie = %v, want HttpOnly and Lax on the callback", c)
+			}
+			return u, c.Value
+		}
+	}
+	a.t.Fatal("connect set no nonce cookie")
+	return nil, ""
+}
+
+// callback returns to the callback as Google redirects the browser, with
+// the nonce cookie when there is one
+func (a *testApp) callback(state, code, nonce string, wantStatus int) fiber.Map {
+	a.t.Helper()
+	req := httptest.NewRequest("GET", "/api/v1/calendar/google/callback?"+url.Values{"state": {state}, "code": {code}}.Encode(), nil)
+	if nonce != "" {
+		req.AddCookie(&http.Cookie{Name: "calendar_oauth_nonce", Value: nonce})
+	}
+	return a.send(req, "", wantStatus)
+}
+
+func TestCalendarConnectFlow(t *testing.T) {
+	a := newTestAppWithConfig(t, googleConfig(t))
+	userID, token := a.user("investor")
+	founder, _ := a.user("founder")
+
+	if status := a.do("GET", "/calendar/google", token, nil, 200); status["connected"] != false {
+		t.Errorf("status = %v, want not connected", status)
+	}
+	// meetings are created on the organizer's calendar, so it must be connected
+	start := time.Now().Add(24 * time.Hour).UTC()
+	meeting := map[string]interface{}{"title": "Intro", "founder_id": founder, "start_time": start, "end_time": start.Add(time.Hour)}
+	if problem := a.do("POST", "/meetings/", token, meeting, 409); problem["code"] != "calendar_not_connected" {
+		t.Errorf("problem = %v, want calendar_not_connected", problem)
+	}
+
+	consent, nonce := a.connectURL(token)
+	q := consent.Query()
+	if consent.Host != "accounts.example.com" || q.Get("access_type") != "offline" || q.Get("prompt") != "consent" {
+		t.Errorf("auth_url = %s, want offline access with a consent prompt", consent)
+	}
+	state := q.Get("state")
+
+	tampered := strings.Replace(state, userID.Hex(), founder.Hex(), 1)
+	if problem := a.callback(tampered, "good", nonce, 400); problem["code"] != "invalid_oauth_state" {
+		t.Errorf("tampered state problem = %v, want invalid_oauth_state", problem)
+	}
+	// the consent URL opened in another browser, without the cookie or with
+	// that browser's own
+	_, founderToken := a.user("founder")
+	_, otherNonce := a.connectURL(founderToken)
+	for _, n := range []string{"", otherNonce} {
+		if problem := a.callback(state, "good", n, 400); problem["code"] != "invalid_oauth_state" {
+			t.Errorf("callback with nonce %q problem = %v, want invalid_oauth_state", n, problem)
+		}
+	}
+	a.callback(state, "bad", nonce, 400)
+	a.do("GET", "/calendar/google/callback?error=access_denied", "", nil, 400)
+
+	a.callback(state, "good", nonce, 200)
+	resp := http.Response{Header: a.header}
+	if cookies := resp.Cookies(); len(cookies) != 1 || cookies[0].Value != "" || cookies[0].Expires.After(time.Now()) {
+		t.Errorf("callback cookies = %v, want the nonce cleared", cookies)
+	}
+	docs := a.store.Find("calendar_connections", bson.M{"user_id": userID, "provider": model.CalendarGoogle})
+	if len(docs) != 1 {
+		t.Fatalf("connections = %v, want one for the user", docs)
+	}
+	var conn model.CalendarConnection
+	data, _ := bson.Marshal(docs[0])
+	if err := bson.Unmarshal(data, &conn); err != nil {
+		t.Fatal(err)
+	}
+	if len(conn.RefreshToken) == 0 || bytes.Contains(conn.RefreshToken, []byte(testRefreshToken)) {
+		t.Errorf("stored refresh token = %q, want it encrypted", conn.RefreshToken)
+	}
+
+	// reconnecting replaces the connection
+	consent, nonce = a.connectURL(token)
+	a.callback(consent.Query().Get("state"), "good", nonce, 200)
+	if docs := a.store.Find("calendar_connections", bson.M{"user_id": userID}); len(docs) != 1 {
+		t.Errorf("connections after reconnecting = %d, want 1", len(docs))
+	}
+	status := a.do("GET", "/calendar/google", token, nil, 200)
+	if connection, _ := status["connection"].(map[string]interface{}); status["connected"] != true || connection["refresh_token"] != nil {
+		t.Errorf("status = %v, want connected without the token", status)
+	}
+
+	a.do("DELETE", "/calendar/google", token, nil, 200)
+	a.do("DELETE", "/calendar/google", token, nil, 404)
+}
+
+func TestCalendarCallbackRedirectsToReturnURL(t *testing.T) {
+	cfg := googleConfig(t)
+	cfg.Calendar.ReturnURL = "https://app.example.com/settings?tab=calendar"
+	a := newTestAppWithConfig(t, cfg)
+	_, token := a.user("founder")
+	consent, nonce := a.connectURL(token)
+	state := consent.Query().Get("state")
+
+	a.callback(state+"x", "good", nonce, 302)
+	if got := a.header.Get("Location"); got != "https://app.example.com/settings?calendar=invalid_oauth_state&tab=calendar" {
+		t.Errorf("Location = %q, want the error code", got)
+	}
+	a.callback(state, "good", nonce, 302)
+	if got := a.header.Get("Location"); got != "https://app.example.com/settings?calendar=connected&tab=calendar" {
+		t.Errorf("Location = %q, want connected", got)
+	}
+}
+
+// getFeed fetches a calendar feed without a JWT, as calendar apps do
+func (a *testApp) getFeed(feedURL, etag string) (*http.Response, string) {
+	a.t.Helper()
+	req := httptest.NewRequest("GET", feedURL, nil)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := a.app.Test(req, -1)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, strings.ReplaceAll(string(body), "\r\n ", "")
+}
+
+func TestCalendarFeed(t *testing.T) {
+	a := newTestApp(t)
+	userID, token := a.user("investor")
+	other, otherToken := a.user("investor")
+	due := time.Now().Add(72 * time.Hour).Truncate(time.Second).UTC()
+
+	meetingID := a.insert("meetings", model.Meeting{InvestorID: userID, Title: "Intro, seed round", StartTime: due, EndTime: due.Add(time.Hour)})
+	a.insert("meetings", model.Meeting{InvestorID: other, Title: "Not mine", StartTime: due, EndTime: due.Add(time.Hour)})
+	dealTask, undated, assigned := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	a.insert("deal_flow", bson.M{"investor_id": userID, "tasks": bson.A{
+		model.Task{ID: dealTask, Title: "Send term sheet", Priority: "high", DueDate: due, CreatedBy: other},
+		model.Task{ID: undated, Title: "Undated", CreatedBy: userID},
+	}})
+	a.insert("deal_flow", bson.M{"investor_id": other, "tasks": bson.A{
+		model.Task{ID: assigned, Title: "Call references", DueDate: due, CreatedBy: other, AssignedTo: userID},
+		model.Task{ID: primitive.NewObjectID(), Title: "Someone else's", DueDate: due, CreatedBy: other},
+	}})
+	created := a.do("POST", "/tasks/", token, map[string]interface{}{"title": "File taxes", "priority": "low", "DueDate": due}, 201)
+	standalone, _ := created["task"].(map[string]interface{})["UpsertedID"].(string)
+	if mine := a.do("GET", "/tasks/user/"+userID.Hex(), token, nil, 200); len(mine["tasks"].([]interface{})) != 2 {
+		t.Errorf("user tasks = %v, want the undated deal task and the standalone one", mine)
+	}
+
+	if status := a.do("GET", "/calendar/feed", token, nil, 200); status["enabled"] != false {
+		t.Errorf("status = %v, want no feed", status)
+	}
+	feedURL, _ := a.do("POST", "/calendar/feed", token, nil, 201)["url"].(string)
+	if !strings.HasPrefix(feedURL, "http://example.com/api/v1/calendar/") || !strings.HasSuffix(feedURL, ".ics") {
+		t.Fatalf("feed url = %q", feedURL)
+	}
+	if status := a.do("GET", "/calendar/feed", token, nil, 200); status["enabled"] != true {
+		t.Errorf("status = %v, want a feed", status)
+	}
+	if docs := a.store.Find("calendar_feeds", bson.M{"user_id": userID}); len(docs) != 1 || strings.Contains(feedURL, docs[0]["token_hash"].(string)) {
+		t.Errorf("feeds = %v, want one storing a hash of the token", docs)
+	}
+
+	resp, body := a.getFeed(feedURL, "")
+	if resp.StatusCode != 200 || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/calendar") {
+		t.Fatalf("feed: status = %d, content type = %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	for _, want := range []string{
+		"BEGIN:VEVENT\r\nUID:" + meetingID.Hex() + "@dbackend",
+		`SUMMARY:Intro\, seed round`,
+		"BEGIN:VTODO\r\nUID:" + dealTask.Hex() + "@dbackend",
+		"UID:" + assigned.Hex() + "@dbackend",
+		"UID:" + standalone + "@dbackend",
+		"DUE:" + due.Format("20060102T150405Z"),
+		"PRIORITY:1",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("feed does not contain %q:\n%s", want, body)
+		}
+	}
+	for _, unwanted := range []string{"Not mine", "Undated", "Someone else's"} {
+		if strings.Contains(body, unwanted) {
+			t.Errorf("feed contains %q:\n%s", unwanted, body)
+		}
+	}
+
+	etag := resp.Header.Get("ETag")
+	if resp, _ := a.getFeed(feedURL, etag); resp.StatusCode != 304 {
+		t.Errorf("feed with a matching ETag: status = %d, want 304", resp.StatusCode)
+	}
+	a.do("PATCH", "/tasks/"+standalone+"/status", token, map[string]bool{"completed": true}, 200)
+	resp, body = a.getFeed(feedURL, etag)
+	if resp.StatusCode != 200 || resp.Header.Get("ETag") == etag || !strings.Contains(body, "STATUS:COMPLETED") {
+		t.Errorf("feed after completing a task: status = %d, want a new body with the task completed", resp.StatusCode)
+	}
+
+	// rotating the token retires the old URL
+	rotated, _ := a.do("POST", "/calendar/feed", token, nil, 201)["url"].(string)
+	if resp, _ := a.getFeed(feedURL, ""); resp.StatusCode != 404 {
+		t.Errorf("old feed url: status = %d, want 404", resp.StatusCode)
+	}
+	if resp, _ := a.getFeed(rotated, ""); resp.StatusCode != 200 {
+		t.Errorf("rotated feed url: status = %d, want 200", resp.StatusCode)
+	}
+
+	a.do("DELETE", "/calendar/feed", otherToken, nil, 404)
+	a.do("DELETE", "/calendar/feed", token, nil, 200)
+	if resp, _ := a.getFeed(rotated, ""); resp.StatusCode != 404 {
+		t.Errorf("deleted feed url: status = %d, want 404", resp.StatusCode)
+	}
+}
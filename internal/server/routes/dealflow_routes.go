@@ -1,18 +1,20 @@
 package routes
 
 import (
+	"DBackend/internal/config"
 	"DBackend/internal/database"
 	"DBackend/internal/server/handlers"
 	"DBackend/internal/server/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
-func DealFlowRoutes(api fiber.Router, db database.Service) {
-	handler := handlers.NewDealFlowHandler(db)
+func DealFlowRoutes(api fiber.Router, db database.Service, calendar config.Calendar) {
+	handler := handlers.NewDealFlowHandler(db, calendar)
 	dealflow := api.Group("/dealflow", middleware.JWTMiddleware(db))
 
 	dealflow.Post("/", handler.AddDealFlowHandler)
 	dealflow.Get("/:id", handler.GetDealFlowByIDHandler)
+	dealflow.Get("/:id/activity", handler.GetDealActivityHandler)
 	dealflow.Get("/", handler.ListAllDealFlowHandler)
 	dealflow.Put("/:id", handler.UpdateDealFlowHandler)
 	dealflow.Delete("/:id", handler.DeleteDealFlowHandler)
@@ -0,0 +1,140 @@
+package routes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"DBackend/internal/metrics"
+	"DBackend/model"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDealFlowLifecycle(t *testing.T) {
+	a := newTestApp(t)
+	investorID, token := a.user("investor")
+	founderUserID, _ := a.user("founder")
+	founder, err := a.store.Founders().GetFounderByUserID(context.Background(), founderUserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.do("POST", "/dealflow/", token, map[string]string{"UserID": founder.ID.Hex()}, 400)
+	deal := map[string]interface{}{"UserID": founder.ID.Hex(), "FundingStage": "Seed", "MatchScore": 87}
+	added := a.do("POST", "/dealflow/", token, deal, 200)
+	dealID, _ := added["id"].(string)
+	if dealID == "" {
+		t.Fatalf("add deal response = %v, want an id", added)
+	}
+	a.do("POST", "/dealflow/", token, deal, 409)
+	if n := len(a.store.Find("outbox", bson.M{"type": model.EventDealAdded})); n != 1 {
+		t.Errorf("DealAdded events = %d, want 1", n)
+	}
+	a.deliver()
+	if n := len(a.store.Find("notifications", bson.M{"founder_id": founder.ID})); n != 1 {
+		t.Errorf("founder notifications = %d, want 1", n)
+	}
+
+	page := a.do("GET", "/dealflow/", token, nil, 200)
+	items, _ := page["items"].([]interface{})
+	if len(items) != 1 {
+		t.Fatalf("deal list = %v, want 1 deal", page)
+	}
+	if item := items[0].(map[string]interface{}); item["founder_name"] != "Ada Lovelace" {
+		t.Errorf("founder_name = %v, want Ada Lovelace", item["founder_name"])
+	}
+
+	a.do("GET", "/dealflow/nope", token, nil, 400)
+	a.do("GET", "/dealflow/"+primitive.NewObjectID().Hex(), token, nil, 404)
+	a.do("GET", "/dealflow/"+dealID, token, nil, 200)
+
+	a.do("PUT", "/dealflow/"+dealID, token, map[string]string{"priority": "high"}, 200)
+	// a stage change through PUT is published like one through PATCH
+	a.do("PUT", "/dealflow/"+dealID, token, map[string]string{"stage": "dueDiligence", "status": "active"}, 200)
+	if n := len(a.store.Find("outbox", bson.M{"type": model.EventDealStageChanged, "payload.to": "dueDiligence"})); n != 1 {
+		t.Errorf("stage events from PUT = %d, want 1", n)
+	}
+	a.do("PUT", "/dealflow/"+dealID, token, map[string]string{"stage": "bogus"}, 400)
+	a.do("PATCH", "/dealflow/"+dealID+"/stage", token, map[string]string{"stage": "bogus"}, 400)
+	a.do("PATCH", "/dealflow/"+dealID+"/stage", token, map[string]string{"stage": "negotiation"}, 200)
+	if n := len(a.store.Find("outbox", bson.M{"type": model.EventDealStageChanged})); n != 2 {
+		t.Errorf("stage events = %d, want 2", n)
+	}
+	a.do("PATCH", "/dealflow/"+dealID+"/status", token, map[string]string{"status": "paused"}, 200)
+	a.deliver()
+	stored := a.store.Find("deal_flow", bson.M{"stage": "negotiation", "status": "paused", "priority": "high"})
+	if len(stored) != 1 {
+		t.Errorf("deal was not updated")
+	}
+	// two stage moves and the status change
+	if n := len(a.store.Find("activities", bson.M{"investor_id": investorID, "type": "deal_update"})); n != 3 {
+		t.Errorf("deal_update activities = %d, want 3", n)
+	}
+
+	a.do("POST", "/dealflow/"+dealID+"/documents", token, map[string]string{"name": "Deck", "url": "https://example.com/deck.pdf"}, 200)
+	a.do("POST", "/dealflow/"+dealID+"/meetings", token, "not an object", 400)
+	start := time.Now().Add(24 * time.Hour).UTC()
+	a.do("POST", "/dealflow/"+dealID+"/meetings", token, map[string]interface{}{"title": "Diligence", "start_time": start, "end_time": start.Add(time.Hour)}, 200)
+	if meetings, _ := a.store.Find("deal_flow", bson.M{})[0]["meetings"].(bson.A); len(meetings) != 1 || meetings[0].(bson.M)["calendar_provider"] != "local" {
+		t.Errorf("deal meetings = %v, want one scheduled locally", meetings)
+	}
+
+	a.do("DELETE", "/dealflow/"+dealID, token, nil, 200)
+	a.do("GET", "/dealflow/"+dealID, token, nil, 404)
+}
+
+func TestDealFlowTasksAndInvestment(t *testing.T) {
+	a := newTestApp(t)
+	investorID, token := a.user("investor")
+	_, founderToken := a.user("founder")
+	founderID := primitive.NewObjectID()
+	dealID := a.insert("deal_flow", bson.M{"investor_id": investorID, "founder_id": founderID, "fund_required": 1000})
+
+	a.do("POST", "/dealflow/"+dealID.Hex()+"/tasks", token, map[string]string{"title": "Call references"}, 200)
+	task, ok := a.store.Find("deal_flow", bson.M{"_id": dealID})[0]["tasks"].(bson.A)
+	if !ok || len(task) != 1 {
+		t.Fatalf("deal tasks = %v, want 1 task", task)
+	}
+	taskID := task[0].(bson.M)["_id"].(primitive.ObjectID)
+
+	a.do("PATCH", "/dealflow/"+dealID.Hex()+"/tasks/nope", token, map[string]bool{"completed": true}, 400)
+	a.do("PATCH", "/dealflow/"+dealID.Hex()+"/tasks/"+taskID.Hex(), token, map[string]bool{"completed": true}, 200)
+	done, _ := a.store.Tasks().GetTaskByID(context.Background(), taskID)
+	if !done.Completed {
+		t.Errorf("task was not completed")
+	}
+
+	investments := testutil.ToFloat64(metrics.Investments)
+	amount := testutil.ToFloat64(metrics.InvestmentAmount)
+	a.do("POST", "/dealflow/"+dealID.Hex()+"/invest", founderToken, map[string]float64{"investmentAmount": 100}, 403)
+	a.do("POST", "/dealflow/"+dealID.Hex()+"/invest", token, map[string]float64{"investmentAmount": 0}, 400)
+	a.do("POST", "/dealflow/"+primitive.NewObjectID().Hex()+"/invest", token, map[string]float64{"investmentAmount": 100}, 404)
+	a.do("POST", "/dealflow/"+dealID.Hex()+"/invest", token, map[string]float64{"investmentAmount": 250}, 200)
+
+	if n := len(a.store.Find("investments", bson.M{"deal_id": dealID, "investor_id": investorID})); n != 1 {
+		t.Errorf("investments = %d, want 1", n)
+	}
+	if n := len(a.store.Find("deal_flow", bson.M{"_id": dealID, "fund_required": 750.0})); n != 1 {
+		t.Errorf("fund_required was not reduced by the investment")
+	}
+	if got := testutil.ToFloat64(metrics.Investments) - investments; got != 1 {
+		t.Errorf("investments counted %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.InvestmentAmount) - amount; got != 250 {
+		t.Errorf("investment amount counted %v, want 250", got)
+	}
+
+	if n := len(a.store.Find("outbox", bson.M{"type": model.EventInvestmentRecorded, "payload.deal_id": dealID})); n != 1 {
+		t.Fatalf("investment events = %d, want 1", n)
+	}
+	a.deliver()
+	if n := len(a.store.Find("activities", bson.M{"deal_id": dealID, "type": "investment", "description": "Invested 250.00"})); n != 1 {
+		t.Errorf("investment activities = %d, want 1", n)
+	}
+	if n := len(a.store.Find("notifications", bson.M{"founder_id": founderID, "notification_type": "investment"})); n != 1 {
+		t.Errorf("founder investment notifications = %d, want 1", n)
+	}
+}
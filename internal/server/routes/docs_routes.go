@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"DBackend/internal/server/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DocsRoutes registers the OpenAPI document and the docs UI that renders it
+func DocsRoutes(api fiber.Router) {
+	api.Get("/openapi.json", handlers.OpenAPISpec())
+	api.Get("/docs", handlers.Docs)
+}
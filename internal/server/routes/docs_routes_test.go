@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"io"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"DBackend/internal/openapi"
+)
+
+var fiberParam = regexp.MustCompile(`:(\w+)`)
+
+func TestSpecCoversRoutes(t *testing.T) {
+	a := newTestApp(t)
+	spec := openapi.Spec()
+
+	seen := 0
+	for _, route := range a.app.GetRoutes(true) {
+		if route.Method == "HEAD" || !strings.HasPrefix(route.Path, openapi.BasePath) {
+			continue
+		}
+		path := strings.TrimPrefix(route.Path, openapi.BasePath)
+		path = fiberParam.ReplaceAllString(path, "{$1}")
+		if len(path) > 1 {
+			path = strings.TrimSuffix(path, "/")
+		}
+		seen++
+
+		item := spec.Paths[path]
+		if item == nil || item.Operation(route.Method) == nil {
+			t.Errorf("%s %s is registered but missing from the OpenAPI spec", route.Method, path)
+		}
+	}
+	if seen == 0 {
+		t.Fatal("no routes were registered")
+	}
+}
+
+func TestDocsRoutes(t *testing.T) {
+	a := newTestApp(t)
+
+	spec := a.do("GET", "/openapi.json", "", nil, 200)
+	if spec["openapi"] != "3.1.0" {
+		t.Errorf("openapi = %v, want 3.1.0", spec["openapi"])
+	}
+
+	resp, err := a.app.Test(httptest.NewRequest("GET", openapi.BasePath+"/docs", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 || !strings.Contains(string(body), "openapi.json") {
+		t.Errorf("docs = %d %q, want the docs page", resp.StatusCode, body)
+	}
+}
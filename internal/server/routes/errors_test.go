@@ -0,0 +1,118 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"DBackend/internal/server/middleware"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestErrorsAreProblemDetails(t *testing.T) {
+	a := newTestApp(t)
+	_, token := a.user("founder")
+	missing := primitive.NewObjectID().Hex()
+
+	for _, tc := range []struct {
+		path, token string
+		status      int
+		code        string
+	}{
+		{"/grants/" + missing, token, 404, "grant_not_found"},
+		{"/grants/not-an-id", token, 400, "invalid_grant_id"},
+		{"/tasks/?filter=nope:eq:1", token, 400, "invalid_query"},
+		{"/tasks/?cursor=bogus", token, 400, "invalid_cursor"},
+		{"/tasks/", "", 401, "unauthorized"},
+	} {
+		req := httptest.NewRequest("GET", "/api/v1"+tc.path, nil)
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		resp, err := a.app.Test(req, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var problem middleware.Problem
+		err = json.NewDecoder(resp.Body).Decode(&problem)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("GET %s: decode problem: %v", tc.path, err)
+		}
+
+		if got := resp.Header.Get("Content-Type"); got != middleware.ProblemContentType {
+			t.Errorf("GET %s: Content-Type = %q, want %q", tc.path, got, middleware.ProblemContentType)
+		}
+		if resp.StatusCode != tc.status || problem.Status != tc.status || problem.Code != tc.code {
+			t.Errorf("GET %s: status %d, problem %+v; want %d %s", tc.path, resp.StatusCode, problem, tc.status, tc.code)
+		}
+		if problem.Title == "" || problem.Detail == "" || problem.Instance != "/api/v1"+tc.path {
+			t.Errorf("GET %s: problem = %+v, want title, detail and instance", tc.path, problem)
+		}
+		if id := resp.Header.Get(middleware.RequestIDHeader); id == "" || problem.RequestID != id {
+			t.Errorf("GET %s: request_id = %q, header = %q; want the same non-empty ID", tc.path, problem.RequestID, id)
+		}
+	}
+}
+
+func TestRequestIDIsPropagated(t *testing.T) {
+	a := newTestApp(t)
+	req := httptest.NewRequest("GET", "/api/v1/tasks/", nil)
+	req.Header.Set(middleware.RequestIDHeader, "trace-123")
+	resp, err := a.app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var problem middleware.Problem
+	if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Header.Get(middleware.RequestIDHeader); got != "trace-123" || problem.RequestID != "trace-123" {
+		t.Errorf("request ID header = %q, body = %q; want trace-123", got, problem.RequestID)
+	}
+}
+
+func TestValidationErrorsListFields(t *testing.T) {
+	a := newTestApp(t)
+	_, token := a.user("investor")
+	start := time.Now().Add(24 * time.Hour)
+	meetingID := a.insert("meetings", model.Meeting{Title: "Intro", StartTime: start, EndTime: start.Add(time.Hour)})
+
+	for _, tc := range []struct {
+		method, path string
+		body         interface{}
+		want         map[string]string
+	}{
+		{"POST", "/user/register", map[string]string{
+			"first_name": "Grace", "second_name": "Hopper", "role": "admin",
+			"email": "grace@", "password": "short",
+		}, map[string]string{"role": "role", "email": "email", "password": "min"}},
+		{"PUT", "/meetings/" + meetingID.Hex(), map[string]interface{}{
+			"title": "Intro", "start_time": start, "end_time": start.Add(-time.Hour),
+		}, map[string]string{"end_time": "after"}},
+		{"POST", "/tasks/", map[string]string{"title": " "}, map[string]string{"Title": "required"}},
+		{"PATCH", "/dealflow/" + meetingID.Hex() + "/stage", map[string]string{"stage": "bogus"}, map[string]string{
+			"stage": "oneof",
+		}},
+	} {
+		problem := a.do(tc.method, tc.path, token, tc.body, 400)
+		if problem["code"] != "validation_failed" {
+			t.Errorf("%s %s: code = %v, want validation_failed", tc.method, tc.path, problem["code"])
+		}
+		got := map[string]string{}
+		errs, _ := problem["errors"].([]interface{})
+		for _, e := range errs {
+			field, _ := e.(map[string]interface{})
+			got[field["field"].(string)] = field["rule"].(string)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s %s: errors = %v, want %v", tc.method, tc.path, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"DBackend/internal/database"
+	"DBackend/internal/server/handlers"
+	"DBackend/internal/server/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// EventRoutes registers the admin routes that inspect the outbox and retry
+// failed events
+func EventRoutes(api fiber.Router, db database.Service) {
+	events := api.Group("/events", middleware.JWTMiddleware(db), middleware.RequireRole("admin"))
+	events.Get("/", handlers.ListEvents(db))
+	events.Post("/:id/retry", handlers.RetryEvent(db))
+}
@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"context"
+	"testing"
+
+	"DBackend/internal/config"
+	"DBackend/internal/events"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestEvents(t *testing.T) {
+	a := newTestApp(t)
+	_, investorToken := a.user("investor")
+	_, adminToken := a.user("admin")
+
+	ctx := context.Background()
+	for range 3 {
+		if err := a.store.Outbox().Publish(ctx, model.DealAdded{DealID: primitive.NewObjectID()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// events without subscribers are delivered; one whose subscriber
+	// always fails is given up on at once
+	cfg := config.Default().Events
+	cfg.Retries = 0
+	bus := events.New(a.store, cfg)
+	bus.Subscribe(model.EventGrantApplicationSubmitted, "broken", func(context.Context, model.Event) error {
+		return context.DeadlineExceeded
+	})
+	if err := a.store.Outbox().Publish(ctx, model.GrantApplicationSubmitted{ApplicationID: primitive.NewObjectID()}); err != nil {
+		t.Fatal(err)
+	}
+	bus.Deliver(ctx)
+
+	a.do("GET", "/events/", "", nil, 401)
+	a.do("GET", "/events/", investorToken, nil, 403)
+
+	list := a.do("GET", "/events/?limit=2", adminToken, nil, 200)["events"].([]interface{})
+	if len(list) != 2 || list[0].(map[string]interface{})["type"] != model.EventGrantApplicationSubmitted {
+		t.Fatalf("events = %v, want the latest two, newest first", list)
+	}
+	a.do("GET", "/events/?status=lost", adminToken, nil, 400)
+	a.do("GET", "/events/?limit=none", adminToken, nil, 400)
+	failed := a.do("GET", "/events/?status=failed", adminToken, nil, 200)["events"].([]interface{})
+	if len(failed) != 1 || failed[0].(map[string]interface{})["last_error"] != "broken: context deadline exceeded" {
+		t.Fatalf("failed events = %v, want the grant application", failed)
+	}
+	id := failed[0].(map[string]interface{})["id"].(string)
+
+	a.do("POST", "/events/"+id+"/retry", investorToken, nil, 403)
+	a.do("POST", "/events/nope/retry", adminToken, nil, 400)
+	a.do("POST", "/events/"+primitive.NewObjectID().Hex()+"/retry", adminToken, nil, 404)
+	if got := a.do("POST", "/events/"+id+"/retry", adminToken, nil, 202)["id"]; got != id {
+		t.Errorf("retried %v", got)
+	}
+	// only failed events can be retried
+	a.do("POST", "/events/"+id+"/retry", adminToken, nil, 404)
+	pending := a.do("GET", "/events/?status=pending", adminToken, nil, 200)["events"].([]interface{})
+	if len(pending) != 1 || pending[0].(map[string]interface{})["id"] != id || pending[0].(map[string]interface{})["attempt"] != float64(0) {
+		t.Errorf("pending events = %v, want the retried event with fresh retries", pending)
+	}
+}
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"DBackend/internal/config"
 	"DBackend/internal/database"
 	"DBackend/internal/server/handlers"
 	"DBackend/internal/server/middleware"
@@ -8,13 +9,14 @@ import (
 )
 
 // fousnderRoutes registers founder routes (role-based)
-func FounderRoutes(api fiber.Router, db database.Service) {
+func FounderRoutes(api fiber.Router, db database.Service, storage config.Storage) {
 	founder := api.Group("/founder", middleware.JWTMiddleware(db))
-	founderHandler := handlers.NewFounderHandler(db)
+	founderHandler := handlers.NewFounderHandler(db, storage)
 	userHandler := handlers.NewUserHandler(db)
 	founder.Put("/profile", middleware.RequireRole("founder"), founderHandler.UpdateFounderHandler)
 	founder.Get("/profile", middleware.RequireRole("founder"), founderHandler.GetFounderDetailsHandler)
 	founder.Get("/", userHandler.GetUserDetailsHandler)
+	founder.Get("/dashboard", middleware.RequireRole("founder"), founderHandler.GetFounderDashboardHandler)
 	founder.Get("/notifications", middleware.RequireRole("founder"), founderHandler.GetAllNotificationsHandler)
 	founder.Put("/notifications/:notificationID", middleware.RequireRole("founder"), founderHandler.UpdateNotificationHandler)
 	founder.Delete("/notification/:notificationID", middleware.RequireRole("founder"), founderHandler.DeleteNotificationHandler)
@@ -0,0 +1,83 @@
+package routes
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFounderProfile(t *testing.T) {
+	a := newTestApp(t)
+	_, founderToken := a.user("founder")
+	_, investorToken := a.user("investor")
+
+	a.do("GET", "/founder/profile", investorToken, nil, 403)
+	a.form("PUT", "/founder/profile", founderToken, url.Values{}, 400)
+	a.form("PUT", "/founder/profile", founderToken, url.Values{"data": {`{"startup_name":"Acme"}`}}, 400)
+	a.form("PUT", "/founder/profile", founderToken, url.Values{
+		"data": {`{"startup_name":"Acme","industry":"Fintech","funding_stage":"Seed","fund_required":"250000"}`},
+	}, 200)
+
+	profile := a.do("GET", "/founder/profile", founderToken, nil, 200)
+	if profile["startup_name"] != "Acme" || profile["fund_required"] != 250000.0 {
+		t.Errorf("profile = %v, want Acme raising 250000", profile)
+	}
+
+	me := a.do("GET", "/founder/", founderToken, nil, 200)
+	if len(me) == 0 {
+		t.Errorf("user details are empty")
+	}
+}
+
+func TestFounderNotifications(t *testing.T) {
+	a := newTestApp(t)
+	userID, token := a.user("founder")
+	founder, err := a.store.Founders().GetFounderByUserID(context.Background(), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := a.insert("notifications", model.Notification{FounderID: founder.ID, Title: "Deal added"})
+	a.insert("notifications", model.Notification{FounderID: userID, Title: "Someone else's"})
+
+	page := a.do("GET", "/founder/notifications", token, nil, 200)
+	if items, _ := page["items"].([]interface{}); len(items) != 1 || page["total"] != 1.0 {
+		t.Fatalf("notifications = %v, want 1 item", page)
+	}
+	a.do("GET", "/founder/notifications?filter=bogus:eq:1", token, nil, 400)
+
+	a.do("PUT", "/founder/notifications/nope", token, map[string]bool{"read_status": true}, 400)
+	a.do("PUT", "/founder/notifications/"+id.Hex(), token, map[string]interface{}{"Title": "Deal added", "ReadStatus": true}, 200)
+	if docs := a.store.Find("notifications", bson.M{"_id": id, "read_status": true}); len(docs) != 1 {
+		t.Errorf("notification was not marked read")
+	}
+
+	a.do("DELETE", "/founder/notification/"+id.Hex(), token, nil, 200)
+	if docs := a.store.Find("notifications", bson.M{"_id": id}); len(docs) != 0 {
+		t.Errorf("notification was not deleted")
+	}
+}
+
+func TestFounderDashboard(t *testing.T) {
+	a := newTestApp(t)
+	userID, token := a.user("founder")
+	_, investorToken := a.user("investor")
+	a.insert("applications", bson.M{"founder_id": userID, "type": "match", "investor_name": "Grace", "match_score": 88.0})
+
+	a.do("GET", "/founder/dashboard", investorToken, nil, 403)
+	dashboard := a.do("GET", "/founder/dashboard", token, nil, 200)
+	engagement, _ := dashboard["investorEngagement"].(map[string]interface{})
+	matches, _ := engagement["topMatches"].([]interface{})
+	if engagement["totalMatches"] != 1.0 || len(matches) != 1 {
+		t.Fatalf("investorEngagement = %v, want one top match", engagement)
+	}
+	if match := matches[0].(map[string]interface{}); match["name"] != "Grace" || match["matchPercentage"] != 88.0 {
+		t.Errorf("top match = %v, want Grace at 88", match)
+	}
+	if summary, _ := dashboard["fundraisingSummary"].(map[string]interface{}); summary["numberOfInvestors"] != 0.0 {
+		t.Errorf("fundraisingSummary = %v, want no investors yet", summary)
+	}
+}
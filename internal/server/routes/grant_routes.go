@@ -1,6 +1,7 @@
 package routes
 
 import (
+    "DBackend/internal/config"
     "DBackend/internal/database"
     "DBackend/internal/server/handlers"
     "DBackend/internal/server/middleware"
@@ -9,11 +10,17 @@ import (
 )
 
 // GrantRoutes sets up all grant-related routes
-func GrantRoutes(api fiber.Router, db database.Service) {
+func GrantRoutes(api fiber.Router, db database.Service, storage config.Storage) {
     grant := api.Group("/grants")
     
+    // Grant application routes; registered before /:id so "applications" is not taken as an ID
+    applications := grant.Group("/applications", middleware.JWTMiddleware(db))
+    applications.Get("/", handlers.GetGrantApplications(db))
+    applications.Get("/:id", handlers.GetGrantApplicationByID(db))
+    applications.Put("/:id", handlers.UpdateGrantApplication(db))
+    
     // Public routes
-    grant.Get("/", handlers.NewFounderHandler(db).GetGrantsHandler)
+    grant.Get("/", handlers.NewFounderHandler(db, storage).GetGrantsHandler)
     grant.Get("/:id", handlers.GetGrantByID(db))
     
     // Protected routes
@@ -21,10 +28,5 @@ func GrantRoutes(api fiber.Router, db database.Service) {
     grant.Post("/", handlers.CreateGrant(db))
     grant.Put("/:id", handlers.UpdateGrant(db))
     grant.Delete("/:id", handlers.DeleteGrant(db))
-    
-    // Grant application routes
     grant.Post("/apply", handlers.ApplyForGrant(db))
-    grant.Get("/applications", handlers.GetGrantApplications(db))
-    grant.Get("/applications/:id", handlers.GetGrantApplicationByID(db))
-    grant.Put("/applications/:id", handlers.UpdateGrantApplication(db))
 }
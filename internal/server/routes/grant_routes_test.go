@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"testing"
+
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGrants(t *testing.T) {
+	a := newTestApp(t)
+	_, token := a.user("admin")
+	a.insert("grants", model.Grant{Name: "Green Fund", Category: "Climate", Region: "EU"})
+
+	a.do("POST", "/grants/", "", map[string]string{"name": "Anon"}, 401)
+	created := a.do("POST", "/grants/", token, map[string]interface{}{"name": "AI Fund", "category": "AI", "region": "US"}, 201)
+	id, _ := created["id"].(string)
+
+	list := a.do("GET", "/grants/?category=AI", "", nil, 200)
+	if grants, _ := list["grants"].([]interface{}); len(grants) != 1 {
+		t.Errorf("AI grants = %v, want 1", list)
+	}
+
+	a.do("GET", "/grants/nope", "", nil, 400)
+	a.do("GET", "/grants/"+primitive.NewObjectID().Hex(), "", nil, 404)
+	a.do("PUT", "/grants/"+id, token, map[string]interface{}{"name": "AI Fund II", "category": "AI"}, 200)
+	if got := a.do("GET", "/grants/"+id, "", nil, 200); got["name"] != "AI Fund II" {
+		t.Errorf("grant = %v, want the renamed grant", got)
+	}
+	a.do("DELETE", "/grants/"+id, token, nil, 200)
+	a.do("GET", "/grants/"+id, "", nil, 404)
+}
+
+func TestGrantApplications(t *testing.T) {
+	a := newTestApp(t)
+	_, founderToken := a.user("founder")
+	_, otherToken := a.user("founder")
+	_, adminToken := a.user("admin")
+	grantID := a.insert("grants", model.Grant{Name: "Green Fund"})
+
+	applied := a.do("POST", "/grants/apply", founderToken, map[string]string{"grant_id": grantID.Hex(), "startup_name": "Acme"}, 201)
+	appID, _ := applied["applicationId"].(string)
+
+	mine := a.do("GET", "/grants/applications", founderToken, nil, 200)
+	if mine["total"] != 1.0 {
+		t.Errorf("founder applications = %v, want 1", mine)
+	}
+	if theirs := a.do("GET", "/grants/applications", otherToken, nil, 200); theirs["total"] != 0.0 {
+		t.Errorf("other founder sees %v, want none", theirs)
+	}
+	if all := a.do("GET", "/grants/applications", adminToken, nil, 200); all["total"] != 1.0 {
+		t.Errorf("admin applications = %v, want 1", all)
+	}
+
+	a.do("GET", "/grants/applications/"+appID, founderToken, nil, 200)
+	a.do("GET", "/grants/applications/"+appID, otherToken, nil, 403)
+	a.do("GET", "/grants/applications/"+primitive.NewObjectID().Hex(), adminToken, nil, 404)
+
+	a.do("PUT", "/grants/applications/"+appID, founderToken, map[string]string{"status": "Approved"}, 403)
+	a.do("PUT", "/grants/applications/"+appID, adminToken, map[string]string{"status": "Approved"}, 200)
+	if got := a.do("GET", "/grants/applications/"+appID, adminToken, nil, 200); got["status"] != "Approved" {
+		t.Errorf("application status = %v, want Approved", got["status"])
+	}
+}
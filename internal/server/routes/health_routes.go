@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"DBackend/internal/health"
+	"DBackend/internal/server/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HealthRoutes registers the unauthenticated liveness and readiness probes
+func HealthRoutes(api fiber.Router, checker *health.Checker) {
+	h := api.Group("/health")
+	h.Get("/", handlers.Readiness(checker))
+	h.Get("/live", handlers.Liveness)
+	h.Get("/ready", handlers.Readiness(checker))
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"DBackend/internal/health"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHealth(t *testing.T) {
+	a := newTestApp(t)
+	if live := a.do("GET", "/health/live", "", nil, 200); live["status"] != string(health.StatusUp) {
+		t.Errorf("liveness = %v, want up", live)
+	}
+	a.do("GET", "/health/ready", "", nil, 200)
+}
+
+func TestReadinessFailsOnCriticalDependency(t *testing.T) {
+	app := fiber.New()
+	HealthRoutes(app.Group("/api/v1"), health.NewChecker(health.Check{
+		Name: "database", Critical: true,
+		Probe: func(context.Context) error { return errors.New("no servers available") },
+	}))
+
+	for path, want := range map[string]int{"/api/v1/health/ready": 503, "/api/v1/health/live": 200} {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil), -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != want {
+			t.Errorf("%s: status = %d, want %d", path, resp.StatusCode, want)
+		}
+	}
+}
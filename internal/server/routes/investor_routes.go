@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"DBackend/internal/config"
 	"DBackend/internal/database"
 	"DBackend/internal/server/handlers"
 	"DBackend/internal/server/middleware"
@@ -8,9 +9,9 @@ import (
 )
 
 // InvestorRoutes registers investor routes (role-based)
-func InvestorRoutes(api fiber.Router, db database.Service) {
+func InvestorRoutes(api fiber.Router, db database.Service, calendar config.Calendar) {
 	investor := api.Group("/investor", middleware.JWTMiddleware(db))
-	investorHandler := handlers.NewInvestorHandler(db)
+	investorHandler := handlers.NewInvestorHandler(db, calendar)
 	userHandler := handlers.NewUserHandler(db)
 
 	// Profile routes
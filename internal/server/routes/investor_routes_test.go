@@ -0,0 +1,89 @@
+package routes
+
+import (
+	"testing"
+
+	"DBackend/model"
+)
+
+func TestInvestorProfile(t *testing.T) {
+	a := newTestApp(t)
+	_, token := a.user("investor")
+	_, founderToken := a.user("founder")
+
+	a.do("PATCH", "/investor/profile", founderToken, map[string]string{}, 403)
+	a.do("PATCH", "/investor/profile", token, map[string]interface{}{
+		"investor_type": "Angel", "thesis": "Climate", "preferred_industries": []string{"Energy"},
+	}, 200)
+
+	profile := a.do("GET", "/investor/profile", token, nil, 200)
+	if profile["investor_type"] != "Angel" || profile["thesis"] != "Climate" {
+		t.Errorf("profile = %v, want the updated Angel profile", profile)
+	}
+}
+
+func TestInvestorStartupDiscovery(t *testing.T) {
+	a := newTestApp(t)
+	_, token := a.user("investor")
+	for _, f := range []model.Founder{
+		{StartupName: "Acme", Industry: "Fintech", FundingStage: "Seed", FundRequired: 100},
+		{StartupName: "Volt", Industry: "Energy", FundingStage: "Series A", FundRequired: 900},
+	} {
+		f.UserID = a.insert("users", model.User{FirstName: f.StartupName})
+		a.insert("founders", f)
+	}
+
+	page := a.do("GET", "/investor/startups?industry=Fintech", token, nil, 200)
+	founders, _ := page["founders"].([]interface{})
+	if len(founders) != 1 || page["total"] != 1.0 {
+		t.Fatalf("startups = %v, want only Acme", page)
+	}
+	facets, _ := page["facets"].(map[string]interface{})
+	if industries, _ := facets["industry"].([]interface{}); len(industries) != 2 {
+		t.Errorf("industry facet = %v, want both industries despite the industry filter", facets["industry"])
+	}
+	if stages, _ := facets["funding_stage"].([]interface{}); len(stages) != 1 || stages[0].(map[string]interface{})["value"] != "Seed" {
+		t.Errorf("funding_stage facet = %v, want Acme's stage only", facets["funding_stage"])
+	}
+
+	a.do("GET", "/investor/startups?limit=0", token, nil, 400)
+	a.do("GET", "/investor/startups?cursor=garbage", token, nil, 400)
+
+	profiles := a.do("GET", "/investor/founderProfile?industry=Energy", token, nil, 200)
+	if items, _ := profiles["founder_profiles"].([]interface{}); len(items) != 1 {
+		t.Errorf("founder profiles = %v, want only Volt", profiles)
+	}
+}
+
+func TestInvestorDashboardAndNotifications(t *testing.T) {
+	a := newTestApp(t)
+	userID, token := a.user("investor")
+	id := a.insert("notifications", model.Notification{FounderID: userID, Title: "New match"})
+
+	a.insert("deal_flow", model.DealFlow{InvestorID: userID, StartupID: userID, Stage: "screening", MatchScore: 91})
+	dashboard := a.do("GET", "/investor/dashboard", token, nil, 200)
+	pipeline, _ := dashboard["pipelineSummary"].(map[string]interface{})
+	deals, _ := pipeline["recentDeals"].([]interface{})
+	if pipeline["pendingDeals"] != 1.0 || len(deals) != 1 {
+		t.Fatalf("pipelineSummary = %v, want one pending deal", pipeline)
+	}
+	if deal := deals[0].(map[string]interface{}); deal["matchPercentage"] != 91.0 || deal["founderId"] != userID.Hex() {
+		t.Errorf("recent deal = %v, want camelCase keys with the match score", deal)
+	}
+	if portfolio, _ := dashboard["portfolioSummary"].(map[string]interface{}); portfolio["topPerformers"] == nil {
+		t.Errorf("portfolioSummary = %v, want an empty topPerformers list", portfolio)
+	}
+	performance := a.do("GET", "/investor/portfolio/performance?period=1y", token, nil, 200)
+	if metrics, _ := performance["metrics"].(map[string]interface{}); metrics["totalReturn"] != 0.0 {
+		t.Errorf("performance = %v, want zeroed metrics", performance)
+	}
+	a.do("GET", "/investor/portfolio/performance?period=forever", token, nil, 400)
+
+	page := a.do("GET", "/investor/notifications", token, nil, 200)
+	if page["total"] != 1.0 {
+		t.Errorf("notifications = %v, want 1", page)
+	}
+	a.do("PUT", "/investor/notifications/"+id.Hex(), token, map[string]bool{"read_status": true}, 200)
+	a.do("DELETE", "/investor/notification/"+id.Hex(), token, nil, 200)
+	a.do("GET", "/investor/investor/nope/meetings", token, nil, 400)
+}
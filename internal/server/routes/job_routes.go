@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"DBackend/internal/database"
+	"DBackend/internal/server/handlers"
+	"DBackend/internal/server/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JobRoutes registers the admin routes that inspect and trigger background jobs
+func JobRoutes(api fiber.Router, db database.Service) {
+	jobs := api.Group("/jobs", middleware.JWTMiddleware(db), middleware.RequireRole("admin"))
+	jobs.Get("/", handlers.ListJobs(db))
+	jobs.Get("/:name/runs", handlers.GetJobRuns(db))
+	jobs.Post("/:name/run", handlers.TriggerJob(db))
+}
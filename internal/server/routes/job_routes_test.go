@@ -0,0 +1,63 @@
+package routes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"DBackend/model"
+)
+
+func TestJobs(t *testing.T) {
+	a := newTestApp(t)
+	_, investorToken := a.user("investor")
+	_, adminToken := a.user("admin")
+
+	ctx := context.Background()
+	next := time.Now().Add(time.Hour).Truncate(time.Hour)
+	if err := a.store.Jobs().RegisterJob(ctx, "valuations", "0 * * * *", next); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.store.Jobs().RegisterJob(ctx, "matching", "0 2 * * *", next); err != nil {
+		t.Fatal(err)
+	}
+	started := time.Now().Add(-time.Hour)
+	for i := 0; i < 3; i++ {
+		if err := a.store.Jobs().RecordJobRun(ctx, model.JobRun{
+			Job: "valuations", Owner: "api-1", Attempt: i + 1, Status: "failed", Error: "timeout",
+			StartedAt: started.Add(time.Duration(i) * time.Minute), FinishedAt: started.Add(time.Duration(i)*time.Minute + time.Second),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a.do("GET", "/jobs/", "", nil, 401)
+	a.do("GET", "/jobs/", investorToken, nil, 403)
+	a.do("POST", "/jobs/matching/run", investorToken, nil, 403)
+
+	list := a.do("GET", "/jobs/", adminToken, nil, 200)["jobs"].([]interface{})
+	if len(list) != 2 || list[0].(map[string]interface{})["name"] != "matching" {
+		t.Fatalf("jobs = %v, want matching and valuations by name", list)
+	}
+
+	runs := a.do("GET", "/jobs/valuations/runs?limit=2", adminToken, nil, 200)["runs"].([]interface{})
+	if len(runs) != 2 || runs[0].(map[string]interface{})["attempt"] != float64(3) {
+		t.Errorf("runs = %v, want the latest two, newest first", runs)
+	}
+	a.do("GET", "/jobs/valuations/runs?limit=none", adminToken, nil, 400)
+	if runs := a.do("GET", "/jobs/matching/runs", adminToken, nil, 200)["runs"].([]interface{}); len(runs) != 0 {
+		t.Errorf("matching has runs %v", runs)
+	}
+
+	a.do("POST", "/jobs/unknown/run", adminToken, nil, 404)
+	if got := a.do("POST", "/jobs/matching/run", adminToken, nil, 202)["job"]; got != "matching" {
+		t.Errorf("triggered %v", got)
+	}
+	jobs, err := a.store.Jobs().ListJobs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jobs[0].NextRun.Before(time.Now()) {
+		t.Errorf("triggered job is due at %v, want now", jobs[0].NextRun)
+	}
+}
@@ -1,16 +1,17 @@
 package routes
 
 import (
+	"DBackend/internal/config"
 	"DBackend/internal/database"
 	"DBackend/internal/server/handlers"
 	"github.com/gofiber/fiber/v2"
   "DBackend/internal/server/middleware"
 )
 
-func MatchRoutes(api fiber.Router, db database.Service) {
+func MatchRoutes(api fiber.Router, db database.Service, ml config.ML) {
 	matchGroup := api.Group("/match", middleware.JWTMiddleware(db))
 	// This GET route will call the MatchHandler
-	MatchHandler := handlers.NewMatHandler(db)
+	MatchHandler := handlers.NewMatHandler(db, ml)
 
 	matchGroup.Get("/data/:userID", MatchHandler.MatchHandler)
 }
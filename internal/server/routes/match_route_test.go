@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"DBackend/internal/config"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMatchErrors(t *testing.T) {
+	a := newTestApp(t)
+	founderID, founderToken := a.user("founder")
+	_, investorToken := a.user("investor")
+
+	a.do("GET", "/match/data/nope", investorToken, nil, 400)
+	a.do("GET", "/match/data/"+primitive.NewObjectID().Hex(), investorToken, nil, 404)
+	// the caller has no investor profile
+	a.do("GET", "/match/data/"+founderID.Hex(), founderToken, nil, 404)
+}
+
+func TestMatchStoresProbability(t *testing.T) {
+	ml := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"match_probability": 0.82}`))
+	}))
+	defer ml.Close()
+
+	cfg := config.Default()
+	cfg.ML.URL = ml.URL
+	a := newTestAppWithConfig(t, cfg)
+	founderID, _ := a.user("founder")
+	investorID, token := a.user("investor")
+
+	resp := a.do("GET", "/match/data/"+founderID.Hex(), token, nil, 201)
+	if resp["match_probability"] != 0.82 {
+		t.Errorf("match_probability = %v, want 0.82", resp["match_probability"])
+	}
+	stored := a.store.Find("match_founder_investor", bson.M{"founder_id": founderID, "investor_id": investorID})
+	if len(stored) != 1 {
+		t.Errorf("stored matches = %d, want 1", len(stored))
+	}
+}
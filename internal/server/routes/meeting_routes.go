@@ -1,6 +1,7 @@
 package routes
 
 import (
+    "DBackend/internal/config"
     "DBackend/internal/database"
     "DBackend/internal/server/handlers"
     "DBackend/internal/server/middleware"
@@ -9,27 +10,42 @@ import (
 )
 
 // MeetingRoutes sets up all meeting-related routes
-func MeetingRoutes(api fiber.Router, db database.Service) {
+func MeetingRoutes(api fiber.Router, db database.Service, calendar config.Calendar) {
     meeting := api.Group("/meetings")
     
     // All meeting routes are protected
     meeting.Use(middleware.JWTMiddleware(db))
     
+    // User-specific meetings; registered before /:id so "user" is not taken as an ID
+    meeting.Get("/user", handlers.GetUserMeetings(db))
+
     // Meeting CRUD operations
-    meeting.Post("/", handlers.ScheduleMeeting(db))
+    meeting.Post("/", handlers.ScheduleMeeting(db, calendar))
     meeting.Get("/", handlers.GetAllMeetings(db))
     meeting.Get("/:id", handlers.GetMeetingByID(db))
-    meeting.Put("/:id", handlers.UpdateMeeting(db))
-    meeting.Delete("/:id", handlers.CancelMeeting(db))
-    
-    // User-specific meetings
-    meeting.Get("/user", handlers.GetUserMeetings(db))
+    meeting.Get("/:id/invite.ics", handlers.GetMeetingInvite(db, calendar))
+    meeting.Put("/:id", handlers.UpdateMeeting(db, calendar))
+    meeting.Delete("/:id", handlers.CancelMeeting(db, calendar))
+
+    // Occurrences of recurring meetings
+    meeting.Get("/:id/occurrences", handlers.GetMeetingOccurrences(db))
+    meeting.Put("/:id/occurrences", handlers.UpdateMeetingOccurrence(db, calendar))
+    meeting.Delete("/:id/occurrences", handlers.CancelMeetingOccurrence(db, calendar))
     
     // Meeting notes
-    meeting.Post("/:id/notes", handlers.AddMeetingNotes(db))
-    meeting.Get("/:id/notes", handlers.GetMeetingNotes(db))
+    meeting.Get("/:id/notes", handlers.ListMeetingNotes(db))
+    meeting.Post("/:id/notes", handlers.CreateMeetingNote(db))
+    meeting.Get("/:id/notes/:noteId", handlers.GetMeetingNote(db))
+    meeting.Put("/:id/notes/:noteId", handlers.UpdateMeetingNote(db))
+    meeting.Delete("/:id/notes/:noteId", handlers.DeleteMeetingNote(db))
+    meeting.Post("/:id/notes/:noteId/action-items/:itemId/task", handlers.CreateActionItemTask(db))
     
     // Meeting participants
     meeting.Post("/:id/participants", handlers.AddMeetingParticipant(db))
     meeting.Delete("/:id/participants/:userId", handlers.RemoveMeetingParticipant(db))
+    meeting.Get("/:id/participants", handlers.GetMeetingParticipants(db))
+
+    // Answers and what happened
+    meeting.Put("/:id/rsvp", handlers.RespondToMeeting(db))
+    meeting.Post("/:id/outcome", handlers.RecordMeetingOutcome(db))
 }
\ No newline at end of file
@@ -0,0 +1,505 @@
+package routes
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMeetings(t *testing.T) {
+	a := newTestApp(t)
+	userID, token := a.user("investor")
+	guest, _ := a.user("founder")
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+	end := start.Add(time.Hour)
+	id := a.insert("meetings", model.Meeting{InvestorID: userID, FounderID: guest, Title: "Intro", StartTime: start})
+	a.insert("meetings", model.Meeting{InvestorID: guest, FounderID: primitive.NewObjectID(), Title: "Other"})
+	a.insert("meetings", model.Meeting{InvestorID: guest, FounderID: primitive.NewObjectID(), Participants: []primitive.ObjectID{userID}, Title: "Invited"})
+
+	a.do("POST", "/meetings/", token, "not an object", 400)
+
+	// the list holds the caller's own meetings and those they are invited to
+	if page := a.do("GET", "/meetings/", token, nil, 200); page["total"] != 2.0 {
+		t.Errorf("meetings = %v, want 2", page)
+	}
+	_, outsider := a.user("investor")
+	if page := a.do("GET", "/meetings/", outsider, nil, 200); page["total"] != 0.0 {
+		t.Errorf("outsider's meetings = %v, want none", page)
+	}
+	_, admin := a.user("admin")
+	if page := a.do("GET", "/meetings/", admin, nil, 200); page["total"] != 3.0 {
+		t.Errorf("admin's meetings = %v, want all 3", page)
+	}
+	mine := a.do("GET", "/meetings/user", token, nil, 200)
+	if meetings, _ := mine["meetings"].([]interface{}); len(meetings) != 1 {
+		t.Errorf("user meetings = %v, want 1", mine)
+	}
+
+	a.do("GET", "/meetings/nope", token, nil, 400)
+	a.do("GET", "/meetings/"+primitive.NewObjectID().Hex(), token, nil, 404)
+	got := a.do("GET", "/meetings/"+id.Hex(), token, nil, 200)
+	if meeting, _ := got["meeting"].(map[string]interface{}); meeting["title"] != "Intro" {
+		t.Errorf("meeting = %v, want Intro", got)
+	}
+
+	a.do("PUT", "/meetings/"+id.Hex(), token, map[string]interface{}{"title": "Intro call", "start_time": start, "end_time": end}, 200)
+	reminded := a.insert("meetings", model.Meeting{InvestorID: userID, Title: "Follow-up", StartTime: end, EndTime: end.Add(time.Hour), Reminders: []int{30}})
+	a.do("PUT", "/meetings/"+reminded.Hex(), token, map[string]interface{}{"title": "Follow-up", "start_time": end, "end_time": end.Add(time.Hour)}, 200)
+	if docs := a.store.Find("meetings", bson.M{"_id": reminded, "reminders": 30}); len(docs) != 1 {
+		t.Error("an update without reminders dropped the meeting's own")
+	}
+	a.do("PUT", "/meetings/"+reminded.Hex(), token, map[string]interface{}{"title": "Follow-up", "start_time": end, "end_time": end.Add(time.Hour), "reminders": nil}, 200)
+	if docs := a.store.Find("meetings", bson.M{"_id": reminded, "reminders": nil}); len(docs) != 1 {
+		t.Error("null reminders did not restore the defaults")
+	}
+	a.do("PUT", "/meetings/"+id.Hex(), token, map[string]interface{}{"title": "Intro call", "start_time": end, "end_time": start}, 400)
+	a.do("PUT", "/meetings/"+primitive.NewObjectID().Hex(), token, map[string]interface{}{"title": "x", "start_time": start, "end_time": end}, 404)
+
+	a.do("POST", "/meetings/"+id.Hex()+"/notes", token, map[string]string{"notes": "Strong team"}, 400)
+	note := map[string]interface{}{"visibility": "shared", "sections": []map[string]string{{"heading": "Team", "body": "Strong team"}}}
+	a.do("POST", "/meetings/"+id.Hex()+"/notes", token, note, 201)
+	if notes, _ := a.do("GET", "/meetings/"+id.Hex()+"/notes", token, nil, 200)["notes"].([]interface{}); len(notes) != 1 {
+		t.Errorf("notes = %v, want 1", notes)
+	}
+
+	a.do("POST", "/meetings/"+id.Hex()+"/participants", token, map[string]string{"user_id": "nope"}, 400)
+	a.do("POST", "/meetings/"+id.Hex()+"/participants", token, map[string]string{"user_id": guest.Hex()}, 200)
+	a.do("POST", "/meetings/"+id.Hex()+"/participants", token, map[string]string{"user_id": guest.Hex()}, 200)
+	if docs := a.store.Find("meetings", bson.M{"_id": id, "participants": guest}); len(docs) != 1 {
+		t.Fatalf("participant was not added")
+	} else if participants := docs[0]["participants"].(bson.A); len(participants) != 1 {
+		t.Errorf("participants = %v, want the guest once", participants)
+	}
+	a.do("DELETE", "/meetings/"+id.Hex()+"/participants/"+guest.Hex(), token, nil, 200)
+	if docs := a.store.Find("meetings", bson.M{"_id": id, "participants": guest}); len(docs) != 0 {
+		t.Errorf("participant was not removed")
+	}
+
+	a.do("DELETE", "/meetings/"+id.Hex(), token, nil, 200)
+	a.do("DELETE", "/meetings/"+id.Hex(), token, nil, 404)
+}
+
+func TestScheduleMeetingWithLocalProvider(t *testing.T) {
+	a := newTestApp(t)
+	_, token := a.user("investor")
+	founder, _ := a.user("founder")
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+
+	a.do("POST", "/meetings/", token, map[string]interface{}{"title": "Intro", "start_time": start, "end_time": start.Add(time.Hour)}, 400)
+	created := a.do("POST", "/meetings/", token, map[string]interface{}{
+		"title": "Intro, seed round", "founder_id": founder, "start_time": start, "end_time": start.Add(time.Hour),
+	}, 201)
+	id, _ := created["meeting"].(map[string]interface{})["InsertedID"].(string)
+
+	got := a.do("GET", "/meetings/"+id, token, nil, 200)
+	meeting, _ := got["meeting"].(map[string]interface{})
+	link, _ := meeting["meeting_url"].(string)
+	if meeting["calendar_provider"] != "local" || !strings.HasPrefix(link, "https://meet.jit.si/dbackend-") {
+		t.Fatalf("meeting = %v, want a local meeting with a link", meeting)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/meetings/"+id+"/invite.ics", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := a.app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/calendar") {
+		t.Fatalf("invite: status = %d, content type = %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	invite := strings.ReplaceAll(string(body), "\r\n ", "")
+	founderEmail := a.store.Find("users", bson.M{"_id": founder})[0]["email"].(string)
+	for _, want := range []string{"METHOD:REQUEST", "UID:" + id + "@dbackend", `SUMMARY:Intro\, seed round`, "URL:" + link, "mailto:" + founderEmail} {
+		if !strings.Contains(invite, want) {
+			t.Errorf("invite does not contain %q:\n%s", want, invite)
+		}
+	}
+	a.do("GET", "/meetings/"+primitive.NewObjectID().Hex()+"/invite.ics", token, nil, 404)
+}
+
+func TestMeetingChangesReachCalDAV(t *testing.T) {
+	var requests []string
+	var stored string
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.Header.Get("If-None-Match"))
+		switch {
+		case failing:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Method == http.MethodPut:
+			stored = strings.ReplaceAll(string(body), "\r\n ", "")
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+	cfg := config.Default()
+	cfg.Calendar.Provider = "caldav"
+	cfg.Calendar.CalDAV.URL = server.URL + "/cal/"
+	a := newTestAppWithConfig(t, cfg)
+	_, token := a.user("investor")
+	founder, _ := a.user("founder")
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour).UTC()
+	series := map[string]interface{}{
+		"title": "Weekly check-in", "founder_id": founder, "start_time": start, "end_time": start.Add(time.Hour),
+		"recurrence": "FREQ=WEEKLY;COUNT=4",
+	}
+
+	created := a.do("POST", "/meetings/", token, series, 201)
+	id, _ := created["meeting"].(map[string]interface{})["InsertedID"].(string)
+	series["title"] = "Weekly sync"
+	a.do("PUT", "/meetings/"+id, token, series, 200)
+	if !strings.Contains(stored, "SUMMARY:Weekly sync") {
+		t.Errorf("stored event after the update:\n%s", stored)
+	}
+	second := start.AddDate(0, 0, 7)
+	a.do("DELETE", "/meetings/"+id+"/occurrences?start="+second.Format(time.RFC3339), token, nil, 200)
+	if !strings.Contains(stored, "EXDATE") {
+		t.Errorf("stored event after cancelling an occurrence has no EXDATE:\n%s", stored)
+	}
+
+	// a calendar that cannot be reached keeps the meeting, so cancelling it
+	// can be retried
+	failing = true
+	a.do("DELETE", "/meetings/"+id, token, nil, 500)
+	if n := len(a.store.Find("meetings", bson.M{})); n != 1 {
+		t.Errorf("meetings = %d after the calendar failed, want 1", n)
+	}
+	failing = false
+	a.do("DELETE", "/meetings/"+id, token, nil, 200)
+
+	want := []string{"PUT *", "PUT ", "PUT ", "DELETE ", "DELETE "}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("CalDAV requests = %q, want %q", requests, want)
+	}
+}
+
+func TestMeetingOverlaps(t *testing.T) {
+	a := newTestApp(t)
+	_, token := a.user("investor")
+	founder, founderToken := a.user("founder")
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+	meeting := func(title string, start time.Time) map[string]interface{} {
+		return map[string]interface{}{"title": title, "founder_id": founder, "start_time": start, "end_time": start.Add(time.Hour)}
+	}
+
+	a.do("PUT", "/get/me/timezone", founderToken, map[string]string{"timezone": "Mars/Olympus"}, 400)
+	a.do("PUT", "/get/me/timezone", founderToken, map[string]string{"timezone": "America/New_York"}, 200)
+
+	first := a.do("POST", "/meetings/", token, meeting("Intro", start), 201)
+	if _, ok := first["conflicts"]; ok {
+		t.Errorf("created = %v, want no conflicts", first)
+	}
+	problem := a.do("POST", "/meetings/", token, meeting("Follow-up", start.Add(30*time.Minute)), 409)
+	details, _ := problem["details"].([]interface{})
+	if problem["code"] != "meeting_overlap" || len(details) != 2 {
+		t.Fatalf("problem = %v, want an overlap for the investor and the founder", problem)
+	}
+	for _, d := range details {
+		conflict := d.(map[string]interface{})
+		if conflict["participant_id"] == founder.Hex() && conflict["timezone"] != "America/New_York" {
+			t.Errorf("conflict = %v, want the founder's start in New York time", conflict)
+		}
+	}
+	a.do("POST", "/meetings/?on_conflict=maybe", token, meeting("Follow-up", start.Add(30*time.Minute)), 400)
+	warned := a.do("POST", "/meetings/?on_conflict=warn", token, meeting("Follow-up", start.Add(30*time.Minute)), 201)
+	if conflicts, _ := warned["conflicts"].([]interface{}); len(conflicts) != 2 {
+		t.Errorf("created = %v, want the overlaps listed", warned)
+	}
+	a.do("POST", "/meetings/", token, meeting("Later", start.Add(2*time.Hour)), 201)
+
+	// a participant joining is checked against their own meetings only
+	other, _ := a.user("investor")
+	a.insert("meetings", model.Meeting{InvestorID: other, Title: "Busy", StartTime: start, EndTime: start.Add(time.Hour), SeriesEnd: start.Add(time.Hour)})
+	id := a.store.Find("meetings", bson.M{"title": "Later"})[0]["_id"].(primitive.ObjectID)
+	a.do("POST", "/meetings/"+id.Hex()+"/participants", token, map[string]string{"user_id": other.Hex()}, 200)
+	id = a.store.Find("meetings", bson.M{"title": "Intro"})[0]["_id"].(primitive.ObjectID)
+	a.do("POST", "/meetings/"+id.Hex()+"/participants", token, map[string]string{"user_id": other.Hex()}, 409)
+}
+
+func TestRecurringMeetings(t *testing.T) {
+	a := newTestApp(t)
+	_, token := a.user("investor")
+	founder, founderToken := a.user("founder")
+	_, strangerToken := a.user("investor")
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour).UTC()
+	series := map[string]interface{}{
+		"title": "Weekly check-in", "founder_id": founder, "start_time": start, "end_time": start.Add(time.Hour),
+		"timezone": "Europe/Berlin", "recurrence": "FREQ=WEEKLY;COUNT=4",
+	}
+
+	invalid := map[string]interface{}{}
+	for k, v := range series {
+		invalid[k] = v
+	}
+	invalid["recurrence"] = "FREQ=HOURLY"
+	problem := a.do("POST", "/meetings/", token, invalid, 400)
+	if fields, _ := problem["errors"].([]interface{}); len(fields) != 1 || fields[0].(map[string]interface{})["field"] != "recurrence" {
+		t.Errorf("problem = %v, want recurrence invalid", problem)
+	}
+
+	created := a.do("POST", "/meetings/", token, series, 201)
+	id, _ := created["meeting"].(map[string]interface{})["InsertedID"].(string)
+	path := "/meetings/" + id + "/occurrences"
+	occurrences := a.do("GET", path, token, nil, 200)["occurrences"].([]interface{})
+	if len(occurrences) != 4 {
+		t.Fatalf("occurrences = %v, want 4", occurrences)
+	}
+	second := occurrences[1].(map[string]interface{})["original_start"].(string)
+	third := occurrences[2].(map[string]interface{})["original_start"].(string)
+
+	// a one-off meeting the week after the series ends does not overlap it,
+	// but moving the last occurrence onto it does
+	after := start.AddDate(0, 0, 28)
+	a.do("POST", "/meetings/", token, map[string]interface{}{"title": "Board", "founder_id": founder, "start_time": after, "end_time": after.Add(time.Hour)}, 201)
+	last := occurrences[3].(map[string]interface{})["original_start"]
+	a.do("PUT", path, token, map[string]interface{}{"original_start": last, "start_time": after, "end_time": after.Add(time.Hour)}, 409)
+
+	moved := start.AddDate(0, 0, 7).Add(2 * time.Hour)
+	a.do("PUT", path, token, map[string]interface{}{"original_start": second, "start_time": moved, "end_time": moved.Add(time.Hour), "title": "Check-in (moved)"}, 200)
+	a.do("PUT", path, token, map[string]interface{}{"original_start": start.Add(time.Minute), "start_time": moved, "end_time": moved.Add(time.Hour)}, 404)
+	a.do("DELETE", path+"?start="+third, token, nil, 200)
+	a.do("DELETE", path+"?start="+third, token, nil, 404)
+	a.do("DELETE", path+"?start=soon", token, nil, 400)
+
+	occurrences = a.do("GET", path, token, nil, 200)["occurrences"].([]interface{})
+	if len(occurrences) != 3 {
+		t.Fatalf("occurrences = %v, want 3 after cancelling one", occurrences)
+	}
+	if o := occurrences[1].(map[string]interface{}); o["edited"] != true || o["title"] != "Check-in (moved)" || o["start_time"] != moved.Format(time.RFC3339) {
+		t.Errorf("occurrence = %v, want the moved check-in", o)
+	}
+
+	// participants read the series, only the organizer changes it
+	a.do("GET", path, founderToken, nil, 200)
+	a.do("GET", path, strangerToken, nil, 403)
+	for _, caller := range []string{founderToken, strangerToken} {
+		a.do("PUT", path, caller, map[string]interface{}{"original_start": last, "start_time": moved, "end_time": moved.Add(time.Hour)}, 403)
+		a.do("DELETE", path+"?start="+second, caller, nil, 403)
+		a.do("PUT", "/meetings/"+id, caller, series, 403)
+		a.do("DELETE", "/meetings/"+id, caller, nil, 403)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/meetings/"+id+"/invite.ics", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := a.app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	invite := strings.ReplaceAll(string(body), "\r\n ", "")
+	for _, want := range []string{"BEGIN:VTIMEZONE", "TZID:Europe/Berlin", "DTSTART;TZID=Europe/Berlin:", "RRULE:FREQ=WEEKLY;COUNT=4", "EXDATE", "RECURRENCE-ID"} {
+		if !strings.Contains(invite, want) {
+			t.Errorf("invite does not contain %q:\n%s", want, invite)
+		}
+	}
+
+	board := a.store.Find("meetings", bson.M{"title": "Board"})[0]["_id"].(primitive.ObjectID)
+	if problem := a.do("DELETE", "/meetings/"+board.Hex()+"/occurrences?start="+after.Format(time.RFC3339), token, nil, 400); problem["code"] != "meeting_not_recurring" {
+		t.Errorf("problem = %v, want meeting_not_recurring", problem)
+	}
+
+	// a new rule drops the edits and cancellations of the old dates
+	series["recurrence"] = "FREQ=DAILY;COUNT=3"
+	a.do("PUT", "/meetings/"+id, token, series, 200)
+	seriesID, _ := primitive.ObjectIDFromHex(id)
+	doc := a.store.Find("meetings", bson.M{"_id": seriesID})[0]
+	if exceptions, _ := doc["exceptions"].(bson.A); len(exceptions) != 0 {
+		t.Errorf("exceptions = %v, want none", exceptions)
+	}
+	if overrides, _ := doc["overrides"].(bson.A); len(overrides) != 0 {
+		t.Errorf("overrides = %v, want none", overrides)
+	}
+	if occurrences := a.do("GET", path, token, nil, 200)["occurrences"].([]interface{}); len(occurrences) != 3 {
+		t.Errorf("occurrences = %v, want the 3 daily ones", occurrences)
+	}
+}
+
+func TestMeetingRSVPAndOutcome(t *testing.T) {
+	a := newTestApp(t)
+	investor, token := a.user("investor")
+	founder, founderToken := a.user("founder")
+	guest, guestToken := a.user("founder")
+	_, outsiderToken := a.user("investor")
+	profile, err := a.store.Founders().GetFounderByUserID(context.Background(), founder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dealID, _ := a.do("POST", "/dealflow/", token, map[string]interface{}{"UserID": profile.ID.Hex(), "FundingStage": "Seed"}, 200)["id"].(string)
+	a.deliver()
+
+	start := time.Now().Add(-2 * time.Hour).Truncate(time.Second).UTC()
+	past := a.insert("meetings", model.Meeting{InvestorID: investor, FounderID: founder, Participants: []primitive.ObjectID{guest}, Title: "Pitch", StartTime: start, EndTime: start.Add(time.Hour)})
+	upcoming := a.insert("meetings", model.Meeting{InvestorID: investor, FounderID: founder, Title: "Follow-up", StartTime: start.AddDate(0, 0, 2), EndTime: start.AddDate(0, 0, 2).Add(time.Hour)})
+	path := "/meetings/" + past.Hex()
+
+	a.do("PUT", path+"/rsvp", founderToken, map[string]string{"status": "maybe"}, 400)
+	if problem := a.do("PUT", path+"/rsvp", outsiderToken, map[string]string{"status": "accepted"}, 403); problem["code"] != "not_a_participant" {
+		t.Errorf("problem = %v, want not_a_participant", problem)
+	}
+	a.do("PUT", path+"/rsvp", founderToken, map[string]string{"status": "tentative"}, 200)
+	a.do("PUT", path+"/rsvp", founderToken, map[string]string{"status": "accepted", "comment": "See you there"}, 200)
+	a.do("PUT", path+"/rsvp", guestToken, map[string]string{"status": "declined"}, 200)
+	if n := len(a.store.Find("notifications", bson.M{"founder_id": investor, "notification_type": "meeting_rsvp"})); n != 3 {
+		t.Errorf("organizer notifications = %d, want 3", n)
+	}
+
+	participants := a.do("GET", path+"/participants", token, nil, 200)["participants"].([]interface{})
+	statuses := map[string]string{}
+	for _, p := range participants {
+		p := p.(map[string]interface{})
+		statuses[p["user_id"].(string)] = p["status"].(string)
+	}
+	if len(statuses) != 3 || statuses[investor.Hex()] != "pending" || statuses[founder.Hex()] != "accepted" || statuses[guest.Hex()] != "declined" {
+		t.Errorf("participants = %v, want the investor pending, the founder accepted and the guest declined", participants)
+	}
+
+	outcome := map[string]interface{}{"outcome": "positive", "summary": "Moving to diligence", "attendance": []map[string]string{
+		{"user_id": investor.Hex(), "status": "attended"},
+		{"user_id": founder.Hex(), "status": "late"},
+		{"user_id": guest.Hex(), "status": "no_show"},
+	}}
+	if problem := a.do("POST", "/meetings/"+upcoming.Hex()+"/outcome", token, outcome, 409); problem["code"] != "meeting_not_ended" {
+		t.Errorf("problem = %v, want meeting_not_ended", problem)
+	}
+	a.do("POST", path+"/outcome", token, map[string]interface{}{"outcome": "great"}, 400)
+	stranger := map[string]interface{}{"outcome": "neutral", "attendance": []map[string]string{{"user_id": primitive.NewObjectID().Hex(), "status": "attended"}}}
+	problem := a.do("POST", path+"/outcome", token, stranger, 400)
+	if fields, _ := problem["errors"].([]interface{}); len(fields) != 1 || fields[0].(map[string]interface{})["field"] != "attendance[0].user_id" {
+		t.Errorf("problem = %v, want attendance[0].user_id invalid", problem)
+	}
+	a.do("POST", path+"/outcome", token, outcome, 200)
+	a.do("POST", path+"/outcome", founderToken, outcome, 200)
+	if outcomes := a.store.Find("meetings", bson.M{"_id": past})[0]["outcomes"].(bson.A); len(outcomes) != 1 {
+		t.Errorf("outcomes = %v, want the second replacing the first", outcomes)
+	}
+
+	a.do("GET", "/dealflow/"+dealID+"/activity", outsiderToken, nil, 403)
+	a.do("GET", "/dealflow/"+dealID+"/activity", founderToken, nil, 200)
+	activities := a.do("GET", "/dealflow/"+dealID+"/activity", token, nil, 200)["activities"].([]interface{})
+	latest, _ := activities[0].(map[string]interface{})
+	if len(activities) != 3 || latest["type"] != "meeting_outcome" || !strings.Contains(latest["description"].(string), "positive outcome; no-show: Ada Lovelace") {
+		t.Errorf("activities = %v, want two outcomes after the deal was added", activities)
+	}
+}
+
+func TestMeetingNotes(t *testing.T) {
+	a := newTestApp(t)
+	investor, token := a.user("investor")
+	founder, founderToken := a.user("founder")
+	_, outsiderToken := a.user("investor")
+	profile, err := a.store.Founders().GetFounderByUserID(context.Background(), founder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dealID, _ := a.do("POST", "/dealflow/", token, map[string]interface{}{"UserID": profile.ID.Hex(), "FundingStage": "Seed"}, 200)["id"].(string)
+
+	start := time.Now().Add(-2 * time.Hour).Truncate(time.Second).UTC()
+	meeting := a.insert("meetings", model.Meeting{InvestorID: investor, FounderID: founder, Title: "Pitch", StartTime: start, EndTime: start.Add(time.Hour)})
+	path := "/meetings/" + meeting.Hex() + "/notes"
+	due := start.AddDate(0, 0, 7)
+
+	a.do("POST", path, outsiderToken, map[string]interface{}{"visibility": "shared", "sections": []map[string]string{{"heading": "x"}}}, 403)
+	a.do("POST", path, token, map[string]interface{}{"visibility": "public", "sections": []map[string]string{{"heading": "x"}}}, 400)
+	stranger := map[string]interface{}{"visibility": "shared", "sections": []map[string]string{{"heading": "Next steps"}}, "action_items": []map[string]string{
+		{"description": "Send the data room", "assignee_id": primitive.NewObjectID().Hex()},
+	}}
+	problem := a.do("POST", path, token, stranger, 400)
+	if fields, _ := problem["errors"].([]interface{}); len(fields) != 1 || fields[0].(map[string]interface{})["field"] != "action_items[0].assignee_id" {
+		t.Errorf("problem = %v, want action_items[0].assignee_id invalid", problem)
+	}
+
+	private := a.do("POST", path, token, map[string]interface{}{"visibility": "private", "sections": []map[string]string{{"heading": "Concerns", "body": "Burn rate"}}}, 201)["note"].(map[string]interface{})
+	shared := a.do("POST", path, token, map[string]interface{}{
+		"visibility": "shared",
+		"sections":   []map[string]string{{"heading": "Summary", "body": "Good fit"}},
+		"action_items": []map[string]interface{}{
+			{"description": "Send the data room", "assignee_id": founder.Hex(), "due_date": due},
+			{"description": "Intro to partners"},
+		},
+	}, 201)["note"].(map[string]interface{})
+	sharedPath := path + "/" + shared["id"].(string)
+
+	if notes := a.do("GET", path, founderToken, nil, 200)["notes"].([]interface{}); len(notes) != 1 {
+		t.Errorf("founder sees %d notes, want only the shared one", len(notes))
+	}
+	if notes := a.do("GET", path, token, nil, 200)["notes"].([]interface{}); len(notes) != 2 {
+		t.Errorf("author sees %d notes, want 2", len(notes))
+	}
+	a.do("GET", path+"/"+private["id"].(string), founderToken, nil, 404)
+	a.do("DELETE", sharedPath, founderToken, nil, 403)
+
+	// the founder edits the shared note; an edit based on the old version is refused
+	items := shared["action_items"].([]interface{})
+	edit := map[string]interface{}{
+		"visibility":   "shared",
+		"version":      1,
+		"sections":     []map[string]string{{"heading": "Summary", "body": "Good fit, strong team"}},
+		"action_items": items,
+	}
+	a.do("PUT", sharedPath, founderToken, edit, 200)
+	if problem := a.do("PUT", sharedPath, token, edit, 409); problem["code"] != "note_edited" {
+		t.Errorf("problem = %v, want note_edited", problem)
+	}
+	edit["version"], edit["visibility"] = 2, "private"
+	a.do("PUT", sharedPath, founderToken, edit, 403)
+	note := a.do("GET", sharedPath, token, nil, 200)["note"].(map[string]interface{})
+	history, _ := note["history"].([]interface{})
+	if note["version"] != 2.0 || note["edited_by"] != founder.Hex() || len(history) != 1 {
+		t.Errorf("note = %v, want version 2 edited by the founder with one revision", note)
+	}
+
+	item := items[0].(map[string]interface{})
+	taskPath := sharedPath + "/action-items/" + item["id"].(string) + "/task"
+	a.do("POST", sharedPath+"/action-items/"+primitive.NewObjectID().Hex()+"/task", token, nil, 404)
+	task := a.do("POST", taskPath, token, nil, 201)["task"].(map[string]interface{})
+	if task["AssignedTo"] != founder.Hex() || task["MeetingID"] != meeting.Hex() || task["Title"] != "Send the data room" {
+		t.Errorf("task = %v, want the data room task assigned to the founder", task)
+	}
+	if problem := a.do("POST", taskPath, token, nil, 409); problem["code"] != "action_item_has_task" {
+		t.Errorf("problem = %v, want action_item_has_task", problem)
+	}
+	deal, _ := primitive.ObjectIDFromHex(dealID)
+	if deals := a.store.Find("deal_flow", bson.M{"_id": deal}); len(deals) != 1 || len(deals[0]["tasks"].(bson.A)) != 1 {
+		t.Errorf("deal = %v, want the task on it", deals)
+	}
+
+	a.do("PATCH", "/dealflow/"+dealID+"/tasks/"+task["ID"].(string), token, map[string]bool{"completed": true}, 200)
+	note = a.do("GET", sharedPath, founderToken, nil, 200)["note"].(map[string]interface{})
+	done := note["action_items"].([]interface{})[0].(map[string]interface{})
+	if done["done"] != true || done["task_id"] != task["ID"] {
+		t.Errorf("action item = %v, want done and linked to the task", done)
+	}
+
+	// editing the note keeps the link and leaves completion to the task
+	done["done"] = false
+	edit = map[string]interface{}{"visibility": "shared", "sections": note["sections"], "action_items": []interface{}{done}}
+	kept := a.do("PUT", sharedPath, token, edit, 200)["note"].(map[string]interface{})["action_items"].([]interface{})[0].(map[string]interface{})
+	if kept["done"] != true || kept["task_id"] != task["ID"] {
+		t.Errorf("action item = %v, want it still done and linked", kept)
+	}
+
+	a.do("PATCH", "/tasks/"+task["ID"].(string)+"/status", token, map[string]bool{"completed": false}, 200)
+	note = a.do("GET", sharedPath, token, nil, 200)["note"].(map[string]interface{})
+	if reopened := note["action_items"].([]interface{})[0].(map[string]interface{}); reopened["done"] != false {
+		t.Errorf("action item = %v, want it open again", reopened)
+	}
+
+	a.do("DELETE", sharedPath, token, nil, 200)
+	a.do("GET", sharedPath, token, nil, 404)
+}
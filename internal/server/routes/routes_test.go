@@ -0,0 +1,206 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/database/memory"
+	"DBackend/internal/events"
+	"DBackend/internal/health"
+	"DBackend/internal/ratelimit"
+	"DBackend/internal/server/middleware"
+	"DBackend/internal/server/services"
+	"DBackend/model"
+	"DBackend/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// testApp mounts every route group over an in-memory store
+type testApp struct {
+	t     *testing.T
+	app   *fiber.App
+	store *memory.Store
+	bus   *events.Bus
+	// header holds the headers of the last response
+	header http.Header
+}
+
+func newTestApp(t *testing.T) *testApp {
+	return newTestAppWithConfig(t, config.Default())
+}
+
+func newTestAppWithConfig(t *testing.T, cfg config.Config) *testApp {
+	t.Helper()
+	store := memory.New()
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(middleware.RequestIDMiddleware())
+
+	limiter := ratelimit.New(cfg.RateLimit, store.RateLimits())
+	api := app.Group("/api/v1", middleware.WriteRateLimit(limiter))
+	HealthRoutes(api, health.NewChecker())
+	DocsRoutes(api)
+	UserRoutes(api, store, limiter)
+	AuthRoutes(api, store, limiter)
+	FounderRoutes(api, store, cfg.Storage)
+	InvestorRoutes(api, store, cfg.Calendar)
+	MatchRoutes(api, store, cfg.ML)
+	DealFlowRoutes(api, store, cfg.Calendar)
+	GrantRoutes(api, store, cfg.Storage)
+	TaskRoutes(api, store)
+	MeetingRoutes(api, store, cfg.Calendar)
+	CalendarRoutes(api, store, cfg.Calendar)
+	BookingRoutes(api, store, cfg.Calendar)
+	SearchRoutes(api, store)
+	JobRoutes(api, store)
+	EventRoutes(api, store)
+
+	bus := events.New(store, cfg.Events)
+	if err := services.Subscribe(bus, store); err != nil {
+		t.Fatal(err)
+	}
+	return &testApp{t: t, app: app, store: store, bus: bus}
+}
+
+// deliver hands the events published so far to their subscribers, as the
+// bus running in the background would
+func (a *testApp) deliver() {
+	a.t.Helper()
+	if _, err := a.bus.Deliver(context.Background()); err != nil {
+		a.t.Fatal(err)
+	}
+}
+
+// user seeds a user with its role profiles and returns the ID and a token
+func (a *testApp) user(roles ...string) (primitive.ObjectID, string) {
+	a.t.Helper()
+	hash, err := utils.HashPassword("secret")
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	id, err := a.store.Insert("users", model.User{
+		FirstName:  "Ada",
+		SecondName: "Lovelace",
+		Email:      primitive.NewObjectID().Hex() + "@example.com",
+		Password:   hash,
+		Roles:      roles,
+	})
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	for _, role := range roles {
+		if err := a.store.Users().CreateRoleData(context.Background(), id, role); err != nil {
+			a.t.Fatal(err)
+		}
+	}
+	token, err := utils.GenerateJWT(id.Hex(), roles)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return id, token
+}
+
+// insert seeds a document and returns its ID
+func (a *testApp) insert(collection string, v interface{}) primitive.ObjectID {
+	a.t.Helper()
+	id, err := a.store.Insert(collection, v)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return id
+}
+
+// do sends a request with an optional JSON body and bearer token, checks the
+// status and decodes the response body into a map
+func (a *testApp) do(method, path, token string, body interface{}, wantStatus int) fiber.Map {
+	a.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			a.t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, "/api/v1"+path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return a.send(req, token, wantStatus)
+}
+
+// form sends url-encoded form values, as the profile endpoints expect
+func (a *testApp) form(method, path, token string, values url.Values, wantStatus int) fiber.Map {
+	a.t.Helper()
+	req := httptest.NewRequest(method, "/api/v1"+path, strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return a.send(req, token, wantStatus)
+}
+
+func (a *testApp) send(req *http.Request, token string, wantStatus int) fiber.Map {
+	a.t.Helper()
+	method, path := req.Method, strings.TrimPrefix(req.URL.Path, "/api/v1")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := a.app.Test(req, -1)
+	if err != nil {
+		a.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	a.header = resp.Header
+	raw, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != wantStatus {
+		a.t.Fatalf("%s %s: status = %d, want %d; body %s", method, path, resp.StatusCode, wantStatus, raw)
+	}
+
+	out := fiber.Map{}
+	if len(raw) > 0 && raw[0] == '{' {
+		if err := json.Unmarshal(raw, &out); err != nil {
+			a.t.Fatalf("%s %s: decode body: %v", method, path, err)
+		}
+	}
+	return out
+}
+
+func TestProtectedRoutesRequireToken(t *testing.T) {
+	a := newTestApp(t)
+	for _, path := range []string{
+		"/get/me", "/founder/profile", "/investor/profile", "/match/data/x",
+		"/dealflow/", "/tasks/", "/meetings/", "/search/?q=x", "/grants/applications", "/calendar/google",
+		"/booking/links",
+	} {
+		a.do("GET", path, "", nil, 401)
+	}
+	a.do("GET", "/tasks/", "not-a-token", nil, 401)
+}
+
+func TestWritesAreRateLimitedPerAccount(t *testing.T) {
+	cfg := config.Default()
+	cfg.RateLimit.Writes.PerAccount = config.Bucket{Requests: 2, Period: time.Minute}
+	a := newTestAppWithConfig(t, cfg)
+	_, token := a.user("investor")
+	_, other := a.user("investor")
+	path := "/tasks/" + primitive.NewObjectID().Hex() + "/assign"
+
+	for i := 0; i < 2; i++ {
+		a.do("POST", path, token, map[string]string{"user_id": "nope"}, 400)
+	}
+	problem := a.do("POST", path, token, map[string]string{"user_id": "nope"}, 429)
+	if problem["code"] != "rate_limited" || a.header.Get("Retry-After") != "30" {
+		t.Errorf("problem = %v, Retry-After = %q; want rate_limited after 30s", problem, a.header.Get("Retry-After"))
+	}
+	a.do("GET", "/tasks/", token, nil, 200)
+	a.do("POST", path, other, map[string]string{"user_id": "nope"}, 400)
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"DBackend/internal/database"
+	"DBackend/internal/server/handlers"
+	"DBackend/internal/server/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SearchRoutes registers the cross-entity search route
+func SearchRoutes(api fiber.Router, db database.Service) {
+	search := api.Group("/search", middleware.JWTMiddleware(db))
+	search.Get("/", handlers.Search(db))
+}
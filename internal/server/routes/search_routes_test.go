@@ -0,0 +1,79 @@
+package routes
+
+import (
+	"testing"
+
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSearch(t *testing.T) {
+	a := newTestApp(t)
+	investorID, token := a.user("investor")
+	otherID, otherToken := a.user("investor")
+	_, adminToken := a.user("admin")
+
+	founderID := a.insert("founders", model.Founder{StartupName: "Solar Grid", Industry: "Energy"})
+	a.insert("founders", model.Founder{StartupName: "Ledger", Industry: "Fintech"})
+	a.insert("grants", model.Grant{Name: "Solar Futures", Category: "Climate"})
+	a.insert("deal_flow", bson.M{"investor_id": investorID, "founder_id": founderID})
+	a.insert("meetings", model.Meeting{InvestorID: otherID, Title: "Solar pitch"})
+
+	a.do("GET", "/search/", token, nil, 400)
+	a.do("GET", "/search/?q=solar&types=planet", token, nil, 400)
+	a.do("GET", "/search/?q=solar&limit=0", token, nil, 400)
+
+	types := func(resp map[string]interface{}) map[string]int {
+		counts := map[string]int{}
+		results, _ := resp["results"].([]interface{})
+		for _, r := range results {
+			counts[r.(map[string]interface{})["type"].(string)]++
+		}
+		return counts
+	}
+
+	got := types(a.do("GET", "/search/?q=solar", token, nil, 200))
+	want := map[string]int{"founder": 1, "grant": 1, "deal": 1}
+	for k, n := range want {
+		if got[k] != n {
+			t.Errorf("investor results = %v, want %v", got, want)
+			break
+		}
+	}
+	if got["meeting"] != 0 {
+		t.Errorf("investor sees another investor's meeting: %v", got)
+	}
+
+	if got := types(a.do("GET", "/search/?q=solar&types=meeting", otherToken, nil, 200)); got["meeting"] != 1 || len(got) != 1 {
+		t.Errorf("meeting owner results = %v, want only their meeting", got)
+	}
+	if got := types(a.do("GET", "/search/?q=solar", adminToken, nil, 200)); got["meeting"] != 1 || got["deal"] != 1 {
+		t.Errorf("admin results = %v, want every meeting and deal", got)
+	}
+
+	// invited participants find the meetings their list shows them
+	a.insert("meetings", model.Meeting{InvestorID: otherID, Participants: []primitive.ObjectID{investorID}, Title: "Board review"})
+	if got := types(a.do("GET", "/search/?q=board&types=meeting", token, nil, 200)); got["meeting"] != 1 {
+		t.Errorf("participant results = %v, want the meeting they were invited to", got)
+	}
+
+	// startups outside the caller's deal flow ranking higher do not hide it
+	for range 3 {
+		a.insert("founders", model.Founder{StartupName: "Solar Roofs", Industry: "Solar", MissionStatement: "Solar for all"})
+	}
+	results := a.do("GET", "/search/?q=solar&types=deal&limit=1", token, nil, 200)["results"].([]interface{})
+	if len(results) != 1 || results[0].(map[string]interface{})["title"] != "Solar Grid" {
+		t.Errorf("deal results = %v, want the caller's deal on Solar Grid", results)
+	}
+
+	// deals match on their own fields, tasks kept on the deal included
+	a.insert("deal_flow", bson.M{"investor_id": otherID, "founder_id": founderID, "tasks": bson.A{bson.M{"title": "Turbine diligence"}}})
+	if got := types(a.do("GET", "/search/?q=turbine", otherToken, nil, 200)); got["deal"] != 1 || len(got) != 1 {
+		t.Errorf("deal task results = %v, want the deal", got)
+	}
+	if got := types(a.do("GET", "/search/?q=turbine", token, nil, 200)); len(got) != 0 {
+		t.Errorf("investor sees another investor's deal: %v", got)
+	}
+}
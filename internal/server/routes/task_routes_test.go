@@ -0,0 +1,67 @@
+package routes
+
+import (
+	"context"
+	"testing"
+
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTasks(t *testing.T) {
+	a := newTestApp(t)
+	userID, token := a.user("investor")
+	assignee, _ := a.user("investor")
+	taskID := primitive.NewObjectID()
+	a.insert("deal_flow", bson.M{"investor_id": userID, "tasks": bson.A{
+		model.Task{ID: taskID, Title: "Review deck", Priority: "high", CreatedBy: userID},
+		model.Task{ID: primitive.NewObjectID(), Title: "Someone else's", CreatedBy: assignee},
+	}})
+
+	outsiderID, outsider := a.user("investor")
+	a.insert("tasks", model.Task{Title: "Outsider's own", CreatedBy: outsiderID})
+	a.insert("deal_flow", bson.M{"investor_id": outsiderID, "tasks": bson.A{model.Task{ID: primitive.NewObjectID(), Title: "Outsider's deal"}}})
+
+	// the list holds the tasks in the caller's deals and their own
+	page := a.do("GET", "/tasks/", token, nil, 200)
+	if page["total"] != 2.0 {
+		t.Errorf("tasks = %v, want 2", page)
+	}
+	if page := a.do("GET", "/tasks/", outsider, nil, 200); page["total"] != 2.0 {
+		t.Errorf("outsider's tasks = %v, want their own 2", page)
+	}
+	_, admin := a.user("admin")
+	if page := a.do("GET", "/tasks/", admin, nil, 200); page["total"] != 4.0 {
+		t.Errorf("admin's tasks = %v, want all 4", page)
+	}
+	mine := a.do("GET", "/tasks/user/"+userID.Hex(), token, nil, 200)
+	if tasks, _ := mine["tasks"].([]interface{}); len(tasks) != 1 {
+		t.Errorf("user tasks = %v, want 1", mine)
+	}
+
+	a.do("GET", "/tasks/nope", token, nil, 400)
+	a.do("GET", "/tasks/"+primitive.NewObjectID().Hex(), token, nil, 404)
+	got := a.do("GET", "/tasks/"+taskID.Hex(), token, nil, 200)
+	if task, _ := got["task"].(map[string]interface{}); task["Title"] != "Review deck" {
+		t.Errorf("task = %v, want Review deck", got)
+	}
+
+	a.do("PUT", "/tasks/"+taskID.Hex(), token, map[string]interface{}{"title": "Review deck v2", "priority": "low"}, 200)
+	a.do("PATCH", "/tasks/"+primitive.NewObjectID().Hex()+"/status", token, map[string]bool{"completed": true}, 404)
+	a.do("PATCH", "/tasks/"+taskID.Hex()+"/status", token, map[string]bool{"completed": true}, 200)
+	a.do("POST", "/tasks/"+taskID.Hex()+"/assign", token, map[string]string{"user_id": "nope"}, 400)
+	a.do("POST", "/tasks/"+taskID.Hex()+"/assign", token, map[string]string{"user_id": assignee.Hex()}, 200)
+
+	task, err := a.store.Tasks().GetTaskByID(context.Background(), taskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Title != "Review deck v2" || !task.Completed || task.AssignedTo != assignee {
+		t.Errorf("task = %+v, want renamed, completed and assigned", task)
+	}
+
+	a.do("DELETE", "/tasks/"+taskID.Hex(), token, nil, 200)
+	a.do("GET", "/tasks/"+taskID.Hex(), token, nil, 404)
+}
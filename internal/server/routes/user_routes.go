@@ -2,14 +2,16 @@ package routes
 
 import (
 	"DBackend/internal/database"
+	"DBackend/internal/ratelimit"
 	"DBackend/internal/server/handlers"
+	"DBackend/internal/server/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
 // UserRoutes registers user-related routes
-func UserRoutes(api fiber.Router, db database.Service) {
+func UserRoutes(api fiber.Router, db database.Service, limiter *ratelimit.Limiter) {
 	apiV1 := api.Group("/user")
 	userHandler := handlers.NewUserHandler(db)
-	apiV1.Post("/register", userHandler.RegisterHandler)
+	apiV1.Post("/register", middleware.RateLimit(limiter, ratelimit.Register, middleware.EmailAccount), userHandler.RegisterHandler)
 	apiV1.Get("/count", userHandler.GetUserCountHandler)
 }
@@ -0,0 +1,66 @@
+package routes
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"DBackend/internal/config"
+
+	"DBackend/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestUserRegisterAndCount(t *testing.T) {
+	a := newTestApp(t)
+	founders := testutil.ToFloat64(metrics.Registrations.WithLabelValues("founder"))
+	investors := testutil.ToFloat64(metrics.Registrations.WithLabelValues("investor"))
+	register := map[string]string{
+		"first_name": "Grace", "second_name": "Hopper", "role": "founder",
+		"email": "grace@example.com", "password": "correct-horse",
+	}
+	a.do("POST", "/user/register", "", map[string]string{"email": "grace@example.com"}, 400)
+	a.do("POST", "/user/register", "", register, 200)
+	a.do("POST", "/user/register", "", register, 400)
+
+	// emails are matched regardless of case
+	register["role"], register["email"] = "investor", "Grace@Example.com"
+	added := a.do("POST", "/user/register", "", register, 200)
+	if added["message"] != "Role added successfully" {
+		t.Errorf("second role response = %v", added)
+	}
+	if n := len(a.store.Find("investors", nil)); n != 1 {
+		t.Errorf("investor profiles = %d, want 1", n)
+	}
+
+	if got := testutil.ToFloat64(metrics.Registrations.WithLabelValues("founder")) - founders; got != 1 {
+		t.Errorf("founder registrations counted %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.Registrations.WithLabelValues("investor")) - investors; got != 1 {
+		t.Errorf("investor registrations counted %v, want 1", got)
+	}
+
+	count := a.do("GET", "/user/count", "", nil, 200)
+	if count["count"] != 1.0 {
+		t.Errorf("count = %v, want 1", count["count"])
+	}
+}
+
+func TestRegisterIsRateLimitedPerIP(t *testing.T) {
+	cfg := config.Default()
+	cfg.RateLimit.Register.PerIP = config.Bucket{Requests: 2, Period: time.Hour}
+	a := newTestAppWithConfig(t, cfg)
+	register := func(i, status int) {
+		a.do("POST", "/user/register", "", map[string]string{
+			"first_name": "Grace", "second_name": "Hopper", "role": "founder",
+			"email": fmt.Sprintf("grace%d@example.com", i), "password": "correct-horse",
+		}, status)
+	}
+	register(1, 200)
+	register(2, 200)
+	register(3, 429)
+	if a.header.Get("Retry-After") != "1800" {
+		t.Errorf("Retry-After = %q, want 1800", a.header.Get("Retry-After"))
+	}
+}
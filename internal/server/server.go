@@ -1,37 +1,59 @@
 package server
 
 import (
+	"log/slog"
+
+	"DBackend/internal/config"
 	"DBackend/internal/database"
+	"DBackend/internal/health"
+	"DBackend/internal/metrics"
+	"DBackend/internal/ratelimit"
 	"DBackend/internal/server/middleware"
+	"DBackend/utils"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 )
 
 // FiberServer defines the server structure
 type FiberServer struct {
 	*fiber.App
-	db database.Service
+	db      database.Service
+	cfg     *config.Config
+	health  *health.Checker
+	limiter *ratelimit.Limiter
 //   dbc database.DealFlowService
 }
 
-// New creates a new server instance
-func New() *FiberServer {
+// New creates a new server instance from a validated configuration
+func New(cfg *config.Config) *FiberServer {
+	utils.SetJWTSecret(cfg.Auth.JWTSecret)
+
 	server := &FiberServer{
 		App: fiber.New(fiber.Config{
 			ServerHeader: "DBackend",
 			AppName:      "DBackend",
+			ErrorHandler: middleware.ErrorHandler,
 		}),
-		db: database.New(),
+		db:  database.New(cfg.Database),
+		cfg: cfg,
 	}
 
-	if server.db != nil {
-		println("DB is not nil")
-	}
+	// Apply CORS, request ID, tracing, access log and metrics middleware globally
+	server.Use(middleware.CORSMiddleware(cfg.CORS))
+	server.Use(middleware.RequestIDMiddleware())
+	server.Use(middleware.Tracing())
+	server.Use(middleware.AccessLog(slog.Default()))
+	server.Use(middleware.Metrics())
 
-	// Apply CORS middleware globally
-	server.Use(middleware.CORSMiddleware())
+	// Serve Prometheus metrics outside the versioned API
+	if cfg.Metrics.Enabled {
+		server.Get(cfg.Metrics.Path, adaptor.HTTPHandler(metrics.Handler()))
+	}
 
 	// Register routes
-	SetupRoutes(server.App, server.db, "api/v1")
+	server.health = newHealthChecker(cfg, server.db)
+	server.limiter = newRateLimiter(cfg.RateLimit, server.db)
+	SetupRoutes(server.App, server.db, cfg, server.health, server.limiter, "api/v1")
   
 
 	return server
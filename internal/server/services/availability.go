@@ -0,0 +1,121 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"DBackend/model"
+)
+
+// defaultHorizon is how many days ahead slots are offered when an
+// availability does not say
+const defaultHorizon = 14
+
+// Interval is a span of time someone is busy
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Slot is a meeting time that can be booked
+type Slot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// BookingWindow returns the span slots of a are offered in at now: from the
+// minimum notice to the horizon
+func BookingWindow(a model.Availability, now time.Time) (from, to time.Time) {
+	horizon := a.Horizon
+	if horizon == 0 {
+		horizon = defaultHorizon
+	}
+	return now.Add(time.Duration(a.MinNotice) * time.Minute), now.AddDate(0, 0, horizon)
+}
+
+// FreeSlots splits the rules of a into back-to-back slots of a.Duration
+// minutes between from and to, leaving out slots within a.Buffer minutes of
+// a busy interval. Rules are read as wall clock times in a.Timezone, so slots
+// keep their local time across daylight saving changes.
+func FreeSlots(a model.Availability, from, to time.Time, busy []Interval) ([]Slot, error) {
+	loc, err := time.LoadLocation(a.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	duration := time.Duration(a.Duration) * time.Minute
+	buffer := time.Duration(a.Buffer) * time.Minute
+	if duration <= 0 {
+		return nil, fmt.Errorf("availability duration must be positive")
+	}
+
+	seen := map[time.Time]bool{}
+	slots := []Slot{}
+	local := from.In(loc)
+	for day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, rule := range a.Rules {
+			if weekday, ok := weekdays[rule.Day]; !ok || weekday != day.Weekday() {
+				continue
+			}
+			open, err1 := wallClock(day, rule.Start)
+			end, err2 := wallClock(day, rule.End)
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("invalid availability rule %+v", rule)
+			}
+			for start := open; !start.Add(duration).After(end); start = start.Add(duration) {
+				slot := Slot{Start: start.UTC(), End: start.Add(duration).UTC()}
+				if slot.Start.Before(from) || slot.End.After(to) || seen[slot.Start] {
+					continue
+				}
+				if overlaps(busy, slot.Start.Add(-buffer), slot.End.Add(buffer)) {
+					continue
+				}
+				seen[slot.Start] = true
+				slots = append(slots, slot)
+			}
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Start.Before(slots[j].Start) })
+	return slots, nil
+}
+
+// MeetingBusy returns when the occurrences of meetings between from and to
+// take place
+func MeetingBusy(meetings []model.Meeting, from, to time.Time) []Interval {
+	busy := make([]Interval, 0, len(meetings))
+	for _, m := range meetings {
+		occurrences, err := Occurrences(m, from, to)
+		if err != nil {
+			// a series stored before its rule was checked still blocks its first occurrence
+			busy = append(busy, Interval{Start: m.StartTime, End: m.EndTime})
+			continue
+		}
+		for _, o := range occurrences {
+			busy = append(busy, Interval{Start: o.StartTime, End: o.EndTime})
+		}
+	}
+	return busy
+}
+
+// wallClock returns the time on day's date at the HH:MM clock in day's location
+func wallClock(day time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location()), nil
+}
+
+func overlaps(busy []Interval, start, end time.Time) bool {
+	for _, b := range busy {
+		if b.Start.Before(end) && b.End.After(start) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"DBackend/model"
+)
+
+func slotStarts(slots []Slot) []string {
+	starts := make([]string, len(slots))
+	for i, s := range slots {
+		starts[i] = s.Start.Format(time.RFC3339)
+	}
+	return starts
+}
+
+func TestFreeSlotsKeepWallClockAcrossDST(t *testing.T) {
+	a := model.Availability{
+		Timezone: "Europe/Berlin",
+		Rules:    []model.AvailabilityRule{{Day: "sunday", Start: "09:00", End: "10:00"}},
+		Duration: 30,
+	}
+	// Berlin moves from UTC+1 to UTC+2 on 29 March 2026
+	from := time.Date(2026, 3, 21, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC)
+	slots, err := FreeSlots(a, from, to, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"2026-03-22T08:00:00Z", "2026-03-22T08:30:00Z", "2026-03-29T07:00:00Z", "2026-03-29T07:30:00Z"}
+	if got := slotStarts(slots); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("slots = %v, want %v", got, want)
+	}
+	if !slots[0].End.Equal(slots[0].Start.Add(30 * time.Minute)) {
+		t.Errorf("slot = %+v, want 30 minutes", slots[0])
+	}
+}
+
+func TestFreeSlotsKeepBufferAroundBusyTimes(t *testing.T) {
+	a := model.Availability{
+		Timezone: "UTC",
+		Rules:    []model.AvailabilityRule{{Day: "monday", Start: "09:00", End: "12:30"}},
+		Duration: 60,
+		Buffer:   15,
+	}
+	monday := time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC)
+	busy := []Interval{{Start: monday.Add(10 * time.Hour), End: monday.Add(10*time.Hour + 30*time.Minute)}}
+	slots, err := FreeSlots(a, monday, monday.AddDate(0, 0, 1), busy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 09:00 ends too close to the busy time and 10:00 overlaps it; the
+	// remaining half hour is too short for another slot
+	if got := slotStarts(slots); len(got) != 1 || got[0] != "2026-03-30T11:00:00Z" {
+		t.Errorf("slots = %v, want only 11:00", got)
+	}
+}
+
+func TestFreeSlotsStayInsideBookingWindow(t *testing.T) {
+	a := model.Availability{
+		Timezone:  "America/New_York",
+		Rules:     []model.AvailabilityRule{{Day: "monday", Start: "09:00", End: "11:00"}, {Day: "tuesday", Start: "09:00", End: "10:00"}},
+		Duration:  60,
+		MinNotice: 60,
+	}
+	// 08:30 on Monday in New York
+	now := time.Date(2026, 3, 30, 12, 30, 0, 0, time.UTC)
+	from, to := BookingWindow(a, now)
+	if !to.Equal(now.AddDate(0, 0, defaultHorizon)) {
+		t.Errorf("window ends %v, want %d days ahead", to, defaultHorizon)
+	}
+	slots, err := FreeSlots(a, from, to, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 09:00 is within the notice period; 10:00 is the first bookable slot
+	if got := slotStarts(slots); len(got) == 0 || got[0] != "2026-03-30T14:00:00Z" || got[1] != "2026-03-31T13:00:00Z" {
+		t.Errorf("slots = %v, want Monday 10:00 then Tuesday 09:00 New York time", got)
+	}
+	if last := slots[len(slots)-1]; last.End.After(to) {
+		t.Errorf("last slot %+v ends after the window", last)
+	}
+}
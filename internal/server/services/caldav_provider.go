@@ -0,0 +1,110 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/tracing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// caldavProvider stores each meeting as an event resource in one CalDAV
+// calendar collection, such as a shared team calendar
+type caldavProvider struct {
+	cfg    config.CalDAV
+	client *http.Client
+}
+
+func newCalDAVProvider(cfg config.Calendar) *caldavProvider {
+	return &caldavProvider{cfg: cfg.CalDAV, client: &http.Client{Timeout: 10 * time.Second, Transport: tracing.Transport(nil)}}
+}
+
+func (p *caldavProvider) Name() string {
+	return ProviderCalDAV
+}
+
+// CreateEvent PUTs the event as <collection>/<uid>.ics, refusing to overwrite
+// an existing resource (RFC 4791 section 5.3.2)
+func (p *caldavProvider) CreateEvent(ctx context.Context, event CalendarEvent) (ScheduledEvent, error) {
+	resource := strings.TrimRight(p.cfg.URL, "/") + "/" + event.MeetingID.Hex() + ".ics"
+	status, err := p.put(ctx, resource, event, "*")
+	if err != nil {
+		return ScheduledEvent{}, fmt.Errorf("unable to create CalDAV event: %v", err)
+	}
+	if status != http.StatusCreated && status != http.StatusNoContent {
+		return ScheduledEvent{}, fmt.Errorf("unable to create CalDAV event: server answered %d", status)
+	}
+	return ScheduledEvent{Provider: ProviderCalDAV, EventID: resource, MeetingURL: event.MeetingURL}, nil
+}
+
+// UpdateEvent PUTs the event over the resource CreateEvent stored, whose URL
+// is the event ID
+func (p *caldavProvider) UpdateEvent(ctx context.Context, eventID string, event CalendarEvent) error {
+	status, err := p.put(ctx, eventID, event, "")
+	if err != nil {
+		return fmt.Errorf("unable to update CalDAV event: %v", err)
+	}
+	if status != http.StatusOK && status != http.StatusCreated && status != http.StatusNoContent {
+		return fmt.Errorf("unable to update CalDAV event: server answered %d", status)
+	}
+	return nil
+}
+
+// CancelEvent DELETEs the resource CreateEvent stored
+func (p *caldavProvider) CancelEvent(ctx context.Context, eventID string, event CalendarEvent) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, eventID, nil)
+	if err != nil {
+		return err
+	}
+	status, err := p.do(req)
+	if err != nil {
+		return fmt.Errorf("unable to cancel CalDAV event: %v", err)
+	}
+	switch status {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound, http.StatusGone:
+		return nil
+	}
+	return fmt.Errorf("unable to cancel CalDAV event: server answered %d", status)
+}
+
+// put stores event at resource, only if no resource matches ifNoneMatch
+// when it is set, and returns the status the server answered
+func (p *caldavProvider) put(ctx context.Context, resource string, event CalendarEvent, ifNoneMatch string) (int, error) {
+	// stored calendar objects carry no METHOD
+	body := Invite(event, "").Bytes()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, resource, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	return p.do(req)
+}
+
+// do sends req with the configured credentials and returns the status the
+// server answered
+func (p *caldavProvider) do(req *http.Request) (int, error) {
+	if p.cfg.Username != "" {
+		req.SetBasicAuth(p.cfg.Username, p.cfg.Password)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// BusyTimes reports nothing: the collection is shared rather than the
+// user's, and the events in it are meetings the API already knows about
+func (p *caldavProvider) BusyTimes(ctx context.Context, userID primitive.ObjectID, from, to time.Time) ([]Interval, error) {
+	return nil, nil
+}
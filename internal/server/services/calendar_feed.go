@@ -0,0 +1,115 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/ics"
+	"DBackend/model"
+)
+
+// feedName is the calendar name subscribers see
+const feedName = "DBackend meetings and tasks"
+
+// NewFeedToken returns a new secret for a calendar feed URL
+func NewFeedToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// NewBookingSlug returns a new name for a booking link, short enough to share
+// but not guessable
+func NewBookingSlug() (string, error) {
+	b := make([]byte, 9)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// FeedTokenHash is what is stored to recognize a feed token. The token is
+// random, so a plain hash is enough and lets feeds be found by it.
+func FeedTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Feed renders meetings as events and tasks with a due date as to-dos. The
+// output only changes when the data does, so it can be cached by its hash.
+func Feed(cfg config.Calendar, meetings []model.Meeting, tasks []model.Task) ics.Calendar {
+	cal := ics.Calendar{Name: feedName}
+
+	sort.SliceStable(meetings, func(i, j int) bool {
+		if !meetings[i].StartTime.Equal(meetings[j].StartTime) {
+			return meetings[i].StartTime.Before(meetings[j].StartTime)
+		}
+		return meetings[i].ID.Hex() < meetings[j].ID.Hex()
+	})
+	for _, m := range meetings {
+		link := m.MeetingURL
+		if link == "" {
+			link = m.GoogleMeetURL
+		}
+		if link == "" {
+			link = MeetingLink(cfg, m.ID)
+		}
+		ev := ics.Event{
+			UID:      EventUID(m.ID),
+			Stamp:    stamp(m.UpdatedAt, m.CreatedAt, m.StartTime),
+			Start:    m.StartTime,
+			End:      m.EndTime,
+			Summary:  m.Title,
+			Location: link,
+			URL:      link,
+			Status:   "CONFIRMED",
+		}
+		cal.Events = append(cal.Events, seriesEvents(ev, m.Timezone, m.Recurrence, m.Exceptions, m.Overrides)...)
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if !tasks[i].DueDate.Equal(tasks[j].DueDate) {
+			return tasks[i].DueDate.Before(tasks[j].DueDate)
+		}
+		return tasks[i].ID.Hex() < tasks[j].ID.Hex()
+	})
+	for _, t := range tasks {
+		if t.DueDate.IsZero() {
+			continue
+		}
+		status := "NEEDS-ACTION"
+		if t.Completed {
+			status = "COMPLETED"
+		}
+		cal.Todos = append(cal.Todos, ics.Todo{
+			UID:      EventUID(t.ID),
+			Stamp:    stamp(t.UpdatedAt, t.CreatedAt, t.DueDate),
+			Due:      t.DueDate,
+			Summary:  t.Title,
+			Status:   status,
+			Priority: todoPriority[t.Priority],
+		})
+	}
+	return cal
+}
+
+// todoPriority maps task priorities onto the RFC 5545 scale
+var todoPriority = map[string]int{"high": 1, "medium": 5, "low": 9}
+
+// stamp returns the first time that is set, so DTSTAMP stays the same until
+// the item changes
+func stamp(times ...time.Time) time.Time {
+	for _, t := range times {
+		if !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/database"
+	"DBackend/internal/ics"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Calendar provider names, as set in config.Calendar.Provider and stored on
+// meetings
+const (
+	ProviderLocal  = "local"
+	ProviderGoogle = "google"
+	ProviderCalDAV = "caldav"
+)
+
+// CalendarEvent is a meeting as calendar providers see it
+type CalendarEvent struct {
+	MeetingID   primitive.ObjectID
+	Title       string
+	Description string
+	Start       time.Time
+	End         time.Time
+	OrganizerID primitive.ObjectID
+	Organizer   ics.Person
+	Attendees   []ics.Person
+	// MeetingURL is the stable link to join, used when the provider does not
+	// add its own conference
+	MeetingURL string
+	// Timezone is the IANA zone the meeting recurs in; UTC when empty
+	Timezone   string
+	Recurrence string
+	Exceptions []time.Time
+	Overrides  []model.OccurrenceOverride
+}
+
+// ScheduledEvent is what a provider created for a meeting
+type ScheduledEvent struct {
+	Provider   string
+	EventID    string // empty when the provider keeps nothing
+	MeetingURL string
+	// GoogleMeetURL is set when Google added a Meet conference
+	GoogleMeetURL string
+}
+
+// CalendarProvider puts meetings on calendars
+type CalendarProvider interface {
+	Name() string
+	CreateEvent(ctx context.Context, event CalendarEvent) (ScheduledEvent, error)
+	// UpdateEvent replaces the event eventID that CreateEvent returned with
+	// event, the meeting as it is now
+	UpdateEvent(ctx context.Context, eventID string, event CalendarEvent) error
+	// CancelEvent removes the event eventID from the calendar; an event that
+	// is already gone is not an error
+	CancelEvent(ctx context.Context, eventID string, event CalendarEvent) error
+	// BusyTimes returns when the user's calendar outside this API shows them
+	// busy between from and to; nil when the provider cannot tell
+	BusyTimes(ctx context.Context, userID primitive.ObjectID, from, to time.Time) ([]Interval, error)
+}
+
+// NewCalendarProvider returns the provider cfg selects. Nothing is read or
+// dialled here, so a misconfigured provider fails the requests that use it
+// and the health check rather than startup.
+func NewCalendarProvider(cfg config.Calendar, db database.Service) CalendarProvider {
+	switch cfg.Provider {
+	case ProviderGoogle:
+		return &googleProvider{cfg: cfg, db: db}
+	case ProviderCalDAV:
+		return newCalDAVProvider(cfg)
+	default:
+		return localProvider{}
+	}
+}
+
+// Invite returns the RFC 5545 invite for event, which the local provider
+// leaves to clients to import and the CalDAV provider stores
+func Invite(event CalendarEvent, method string) ics.Calendar {
+	ev := ics.Event{
+		UID:       EventUID(event.MeetingID),
+		Stamp:     time.Now(),
+		Start:     event.Start,
+		End:       event.End,
+		Summary:   event.Title,
+		Details:   event.Description,
+		Location:  event.MeetingURL,
+		URL:       event.MeetingURL,
+		Status:    "CONFIRMED",
+		Attendees: event.Attendees,
+	}
+	if event.Organizer.Email != "" {
+		organizer := event.Organizer
+		ev.Organizer = &organizer
+	}
+	return ics.Calendar{Method: method, Events: seriesEvents(ev, event.Timezone, event.Recurrence, event.Exceptions, event.Overrides)}
+}
+
+// seriesEvents returns ev, recurring by recurrence in timezone without the
+// exceptions, followed by an event per edited occurrence
+func seriesEvents(ev ics.Event, timezone, recurrence string, exceptions []time.Time, overrides []model.OccurrenceOverride) []ics.Event {
+	ev.Timezone = timezone
+	ev.RRule = strings.TrimPrefix(recurrence, "RRULE:")
+	if ev.RRule == "" {
+		return []ics.Event{ev}
+	}
+	ev.ExDates = exceptions
+	events := []ics.Event{ev}
+	for _, o := range overrides {
+		occ := ev
+		occ.RRule, occ.ExDates = "", nil
+		occ.RecurrenceID, occ.Start, occ.End = o.OriginalStart, o.StartTime, o.EndTime
+		if o.Title != "" {
+			occ.Summary = o.Title
+		}
+		if o.Notes != "" {
+			occ.Details = o.Notes
+		}
+		events = append(events, occ)
+	}
+	return events
+}
+
+// EventUID is the iCalendar UID of a meeting's event, the same wherever the
+// event is created so clients recognize updates to it
+func EventUID(meetingID primitive.ObjectID) string {
+	return meetingID.Hex() + "@dbackend"
+}
+
+// MeetingLink returns the link participants join a meeting at when no
+// conference is created for it. It is derived from the meeting ID with a key
+// from cfg.TokenKey, so it never changes but cannot be guessed from the ID.
+func MeetingLink(cfg config.Calendar, meetingID primitive.ObjectID) string {
+	mac := hmac.New(sha256.New, deriveKey(cfg.TokenKey, "meeting link"))
+	mac.Write(meetingID[:])
+	room := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(mac.Sum(nil))[:20])
+	return strings.TrimRight(cfg.MeetingURL, "/") + "/dbackend-" + room
+}
+
+// localProvider creates nothing; participants add the meeting from its
+// invite and join at its stable link
+type localProvider struct{}
+
+func (localProvider) Name() string {
+	return ProviderLocal
+}
+
+func (localProvider) CreateEvent(ctx context.Context, event CalendarEvent) (ScheduledEvent, error) {
+	return ScheduledEvent{Provider: ProviderLocal, MeetingURL: event.MeetingURL}, nil
+}
+
+func (localProvider) UpdateEvent(ctx context.Context, eventID string, event CalendarEvent) error {
+	return nil
+}
+
+func (localProvider) CancelEvent(ctx context.Context, eventID string, event CalendarEvent) error {
+	return nil
+}
+
+func (localProvider) BusyTimes(ctx context.Context, userID primitive.ObjectID, from, to time.Time) ([]Interval, error) {
+	return nil, nil
+}
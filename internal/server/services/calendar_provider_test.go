@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/ics"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMeetingLinkIsStableAndUnguessable(t *testing.T) {
+	cfg := config.Calendar{MeetingURL: "https://meet.example.com/", TokenKey: "secret"}
+	id := primitive.NewObjectID()
+	link := MeetingLink(cfg, id)
+	if !strings.HasPrefix(link, "https://meet.example.com/dbackend-") || strings.Contains(link, id.Hex()) {
+		t.Errorf("MeetingLink() = %q, want a room under the base URL not naming the ID", link)
+	}
+	if again := MeetingLink(cfg, id); again != link {
+		t.Errorf("MeetingLink() = %q then %q, want the same link", link, again)
+	}
+	if other := MeetingLink(cfg, primitive.NewObjectID()); other == link {
+		t.Errorf("two meetings share the link %q", link)
+	}
+}
+
+func TestCalDAVProviderStoresEvent(t *testing.T) {
+	var got *http.Request
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		got, body = r, string(data)
+		if strings.HasPrefix(r.URL.Path, "/full/") {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	cfg := config.Calendar{Provider: ProviderCalDAV, CalDAV: config.CalDAV{URL: server.URL + "/cal/team/", Username: "bot", Password: "pw"}}
+	provider := NewCalendarProvider(cfg, nil)
+	start := time.Now().Add(time.Hour).UTC()
+	event := CalendarEvent{
+		MeetingID:  primitive.NewObjectID(),
+		Title:      "Intro",
+		Start:      start,
+		End:        start.Add(time.Hour),
+		Organizer:  ics.Person{Email: "ada@example.com"},
+		MeetingURL: "https://meet.example.com/room",
+	}
+
+	scheduled, err := provider.CreateEvent(context.Background(), event)
+	if err != nil {
+		t.Fatalf("CreateEvent() error = %v", err)
+	}
+	wantPath := "/cal/team/" + event.MeetingID.Hex() + ".ics"
+	if got.Method != http.MethodPut || got.URL.Path != wantPath || scheduled.EventID != server.URL+wantPath {
+		t.Errorf("request = %s %s, event ID = %q; want PUT %s", got.Method, got.URL.Path, scheduled.EventID, wantPath)
+	}
+	if user, pass, _ := got.BasicAuth(); user != "bot" || pass != "pw" {
+		t.Errorf("basic auth = %q:%q, want bot:pw", user, pass)
+	}
+	if !strings.Contains(body, "UID:"+EventUID(event.MeetingID)) || strings.Contains(body, "METHOD:") {
+		t.Errorf("stored event should carry the UID and no METHOD:\n%s", body)
+	}
+	if scheduled.Provider != ProviderCalDAV || scheduled.MeetingURL != event.MeetingURL {
+		t.Errorf("scheduled = %+v, want the caldav event with the meeting link", scheduled)
+	}
+
+	cfg.CalDAV.URL = server.URL + "/full"
+	if _, err := NewCalendarProvider(cfg, nil).CreateEvent(context.Background(), event); err == nil {
+		t.Error("CreateEvent() on a rejecting server succeeded")
+	}
+}
+
+func TestCalDAVProviderUpdatesAndCancelsEvent(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.Path+" "+r.Header.Get("If-None-Match"))
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/gone/"):
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasPrefix(r.URL.Path, "/down/"):
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewCalendarProvider(config.Calendar{Provider: ProviderCalDAV}, nil)
+	start := time.Now().Add(time.Hour).UTC()
+	event := CalendarEvent{MeetingID: primitive.NewObjectID(), Title: "Intro", Start: start, End: start.Add(time.Hour)}
+	ctx := context.Background()
+
+	if err := provider.UpdateEvent(ctx, server.URL+"/cal/a.ics", event); err != nil {
+		t.Errorf("UpdateEvent() error = %v", err)
+	}
+	if err := provider.CancelEvent(ctx, server.URL+"/cal/a.ics", event); err != nil {
+		t.Errorf("CancelEvent() error = %v", err)
+	}
+	if err := provider.CancelEvent(ctx, server.URL+"/gone/a.ics", event); err != nil {
+		t.Errorf("CancelEvent() of a deleted event error = %v", err)
+	}
+	if err := provider.UpdateEvent(ctx, server.URL+"/down/a.ics", event); err == nil {
+		t.Error("UpdateEvent() on a failing server succeeded")
+	}
+	want := []string{"PUT /cal/a.ics ", "DELETE /cal/a.ics ", "DELETE /gone/a.ics ", "PUT /down/a.ics "}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+}
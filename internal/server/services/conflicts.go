@@ -0,0 +1,117 @@
+package services
+
+import (
+	"time"
+
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// conflictHorizon is how far ahead a meeting that recurs without end is
+// checked for overlaps
+const conflictHorizon = 365 * 24 * time.Hour
+
+// maxConflicts caps how many overlaps are reported for one meeting
+const maxConflicts = 50
+
+// Conflict is an occurrence of another meeting a participant is already in
+// when an occurrence of the meeting being planned would take place
+type Conflict struct {
+	ParticipantID primitive.ObjectID `json:"participant_id"`
+	MeetingID     primitive.ObjectID `json:"meeting_id"`
+	Title         string             `json:"title"`
+	StartTime     time.Time          `json:"start_time"`
+	EndTime       time.Time          `json:"end_time"`
+	// Timezone is the participant's and LocalStart the start in it, when the
+	// participant has set one
+	Timezone   string `json:"timezone,omitempty"`
+	LocalStart string `json:"local_start,omitempty"`
+}
+
+// Participants returns everyone taking part in m, once each
+func Participants(m model.Meeting) []primitive.ObjectID {
+	seen := map[primitive.ObjectID]bool{}
+	var ids []primitive.ObjectID
+	for _, id := range append([]primitive.ObjectID{m.InvestorID, m.FounderID}, m.Participants...) {
+		if !id.IsZero() && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// ConflictWindow returns the span m is checked for overlaps in: the whole
+// series, or its first year when it recurs without end
+func ConflictWindow(m model.Meeting, seriesEnd time.Time) (from, to time.Time) {
+	from, to = m.StartTime, seriesEnd
+	for _, o := range m.Overrides {
+		if o.StartTime.Before(from) {
+			from = o.StartTime
+		}
+	}
+	if limit := m.StartTime.Add(conflictHorizon); to.After(limit) {
+		to = limit
+	}
+	return from, to
+}
+
+// FindConflicts returns the occurrences of others that overlap an occurrence
+// of m between from and to and share a participant with it
+func FindConflicts(m model.Meeting, others []model.Meeting, from, to time.Time) ([]Conflict, error) {
+	mine, err := Occurrences(m, from, to)
+	if err != nil {
+		return nil, err
+	}
+	participants := map[primitive.ObjectID]bool{}
+	for _, id := range Participants(m) {
+		participants[id] = true
+	}
+
+	conflicts := []Conflict{}
+	for _, other := range others {
+		if other.ID == m.ID {
+			continue
+		}
+		var shared []primitive.ObjectID
+		for _, id := range Participants(other) {
+			if participants[id] {
+				shared = append(shared, id)
+			}
+		}
+		if len(shared) == 0 {
+			continue
+		}
+		theirs, err := Occurrences(other, from, to)
+		if err != nil {
+			continue // a series stored before its rule was checked cannot be expanded
+		}
+		for _, a := range mine {
+			for _, b := range theirs {
+				if !a.StartTime.Before(b.EndTime) || !a.EndTime.After(b.StartTime) {
+					continue
+				}
+				for _, id := range shared {
+					conflicts = append(conflicts, Conflict{ParticipantID: id, MeetingID: other.ID, Title: b.Title, StartTime: b.StartTime, EndTime: b.EndTime})
+					if len(conflicts) == maxConflicts {
+						return conflicts, nil
+					}
+				}
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// Localize fills in each conflict's start in its participant's timezone
+func Localize(conflicts []Conflict, zones map[primitive.ObjectID]string) {
+	for i, c := range conflicts {
+		loc, err := time.LoadLocation(zones[c.ParticipantID])
+		if zones[c.ParticipantID] == "" || err != nil {
+			continue
+		}
+		conflicts[i].Timezone = zones[c.ParticipantID]
+		conflicts[i].LocalStart = c.StartTime.In(loc).Format(time.RFC3339)
+	}
+}
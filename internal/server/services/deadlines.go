@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"DBackend/internal/database"
+	"DBackend/internal/mail"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// How far ahead of a deadline users are alerted
+const (
+	TaskAlertWindow  = 24 * time.Hour
+	GrantAlertWindow = 3 * 24 * time.Hour
+)
+
+// Deadlines alerts users of deadlines coming up: the assignee of a task
+// about to fall due, and founders in a grant's category who have not applied
+// before it closes
+type Deadlines struct {
+	db   database.Service
+	mail mail.Sender
+}
+
+// NewDeadlines creates the deadline alerter
+func NewDeadlines(db database.Service, sender mail.Sender) *Deadlines {
+	return &Deadlines{db: db, mail: sender}
+}
+
+// Run sends the alerts due now
+func (d *Deadlines) Run(ctx context.Context) error {
+	sent, err := d.Send(ctx, time.Now())
+	if sent > 0 {
+		slog.InfoContext(ctx, "sent deadline alerts", "count", sent)
+	}
+	return err
+}
+
+// Send alerts of the open tasks due within TaskAlertWindow of now and the
+// grants closing within GrantAlertWindow, and returns how many alerts it
+// sent. Each alert is claimed before it is sent, so a user hears of a
+// deadline once even when runs overlap or are retried.
+func (d *Deadlines) Send(ctx context.Context, now time.Time) (int, error) {
+	tasks, err := d.db.Tasks().GetTasksDueBetween(ctx, now, now.Add(TaskAlertWindow))
+	if err != nil {
+		return 0, fmt.Errorf("retrieving tasks due soon: %w", err)
+	}
+	sent := 0
+	for _, task := range tasks {
+		user := task.AssignedTo
+		if user.IsZero() {
+			user = task.CreatedBy
+		}
+		if user.IsZero() {
+			continue
+		}
+		ok, err := d.alert(ctx, model.DeadlineAlert{SubjectID: task.ID, UserID: user, Deadline: task.DueDate, SentAt: now},
+			"task_due", "Task due soon: "+task.Title, fmt.Sprintf("%q is due on %%s", task.Title))
+		if err != nil {
+			return sent, err
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	grants, err := d.db.Grants().GetGrantsClosingBetween(ctx, now, now.Add(GrantAlertWindow))
+	if err != nil {
+		return sent, fmt.Errorf("retrieving grants closing soon: %w", err)
+	}
+	for _, grant := range grants {
+		n, err := d.alertGrant(ctx, grant, now)
+		sent += n
+		if err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// alertGrant alerts the founders in the grant's category who have not
+// applied for it. Grants without a category concern nobody in particular.
+func (d *Deadlines) alertGrant(ctx context.Context, grant model.Grant, now time.Time) (int, error) {
+	if grant.Category == "" {
+		return 0, nil
+	}
+	applicants, err := d.db.Grants().GetGrantApplicantIDs(ctx, grant.ID)
+	if err != nil {
+		return 0, fmt.Errorf("retrieving applicants of grant %s: %w", grant.ID.Hex(), err)
+	}
+	applied := map[primitive.ObjectID]bool{}
+	for _, id := range applicants {
+		applied[id] = true
+	}
+
+	sent := 0
+	q := database.StartupQuery{Industries: []string{grant.Category}, Limit: pageSize}
+	for {
+		page, err := d.db.Founders().DiscoverStartups(ctx, q)
+		if err != nil {
+			return sent, fmt.Errorf("listing founders: %w", err)
+		}
+		for _, founder := range page.Founders {
+			if applied[founder.UserID] {
+				continue
+			}
+			ok, err := d.alert(ctx, model.DeadlineAlert{SubjectID: grant.ID, UserID: founder.UserID, Deadline: grant.Deadline, SentAt: now},
+				"grant_deadline", "Grant closing soon: "+grant.Name, fmt.Sprintf("Applications for %q close on %%s", grant.Name))
+			if err != nil {
+				return sent, err
+			}
+			if ok {
+				sent++
+			}
+		}
+		if page.NextCursor == "" {
+			return sent, nil
+		}
+		q.Cursor = page.NextCursor
+	}
+}
+
+// alert claims the alert and then notifies and emails the user. format
+// holds a %s for the deadline, shown in the user's timezone. Failing to
+// reach the user is logged: the claimed alert is not sent again.
+func (d *Deadlines) alert(ctx context.Context, alert model.DeadlineAlert, kind, title, format string) (bool, error) {
+	claimed, err := d.db.Notifications().ClaimDeadlineAlert(ctx, alert)
+	if err != nil {
+		return false, fmt.Errorf("claiming deadline alert: %w", err)
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	user, err := d.db.Users().FindByID(ctx, alert.UserID)
+	if err != nil {
+		slog.WarnContext(ctx, "skipping deadline alert of a missing user", "subject_id", alert.SubjectID.Hex(), "user_id", alert.UserID.Hex(), "error", err)
+		return false, nil
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	message := fmt.Sprintf(format, alert.Deadline.In(loc).Format("Mon, 02 Jan 2006 15:04 MST"))
+
+	_, err = d.db.Notifications().CreateNotification(ctx, model.Notification{
+		FounderID:        alert.UserID,
+		NotificationType: kind,
+		Title:            title,
+		Message:          message,
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "creating deadline notification failed", "subject_id", alert.SubjectID.Hex(), "user_id", alert.UserID.Hex(), "error", err)
+	}
+	if user.Email != "" {
+		if err := d.mail.Send(ctx, mail.Message{To: user.Email, Subject: title, Body: message + "."}); err != nil {
+			slog.WarnContext(ctx, "emailing deadline alert failed", "subject_id", alert.SubjectID.Hex(), "user_id", alert.UserID.Hex(), "error", err)
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"DBackend/internal/database/memory"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDeadlinesAlertEachUserOnce(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	owner, assignee, founder, applicant, other := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	for _, u := range []model.User{
+		{ID: owner, Email: "owner@example.com"},
+		{ID: assignee, Email: "assignee@example.com", Timezone: "Europe/Berlin"},
+		{ID: founder, Email: "founder@example.com"},
+		{ID: applicant, Email: "applicant@example.com"},
+		{ID: other, Email: "other@example.com"},
+	} {
+		if _, err := store.Insert("users", u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []model.Founder{
+		{UserID: founder, StartupName: "Sunly", Industry: "Climate"},
+		{UserID: applicant, StartupName: "Windy", Industry: "Climate"},
+		{UserID: other, StartupName: "Ledger", Industry: "Fintech"},
+	} {
+		if _, err := store.Insert("founders", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tasks := []model.Task{
+		{ID: primitive.NewObjectID(), Title: "Send deck", CreatedBy: owner, AssignedTo: assignee, DueDate: now.Add(3 * time.Hour)},
+		{ID: primitive.NewObjectID(), Title: "Sign term sheet", CreatedBy: owner, DueDate: now.Add(20 * time.Hour)},
+		{ID: primitive.NewObjectID(), Title: "Done already", CreatedBy: owner, DueDate: now.Add(time.Hour), Completed: true},
+		{ID: primitive.NewObjectID(), Title: "Next week", CreatedBy: owner, DueDate: now.Add(7 * 24 * time.Hour)},
+	}
+	if _, err := store.Tasks().AddTask(ctx, primitive.NilObjectID, tasks[0]); err != nil {
+		t.Fatal(err)
+	}
+	deal := primitive.NewObjectID()
+	if _, err := store.Insert("deal_flow", model.DealFlow{ID: deal, InvestorID: owner, Tasks: tasks[1:]}); err != nil {
+		t.Fatal(err)
+	}
+
+	grant, err := store.Grants().CreateGrant(ctx, model.Grant{Name: "Solar Futures", Category: "Climate", Deadline: now.Add(48 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Grants().CreateGrant(ctx, model.Grant{Name: "Later", Category: "Climate", Deadline: now.Add(30 * 24 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Grants().SubmitGrantApplication(ctx, model.GrantApplication{FounderID: applicant, GrantID: grant}); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &outbox{}
+	deadlines := NewDeadlines(store, sender)
+	// the assignee of the deck task, the owner of the unassigned one and the
+	// climate founder who has not applied
+	if sent, err := deadlines.Send(ctx, now); err != nil || sent != 3 {
+		t.Fatalf("Send = %d, %v, want 3", sent, err)
+	}
+	if sent, err := deadlines.Send(ctx, now.Add(time.Hour)); err != nil || sent != 0 {
+		t.Fatalf("second Send = %d, %v, want 0", sent, err)
+	}
+
+	recipients := map[string]bool{}
+	for _, msg := range sender.sent {
+		recipients[msg.To] = true
+	}
+	for _, want := range []string{"assignee@example.com", "owner@example.com", "founder@example.com"} {
+		if !recipients[want] {
+			t.Errorf("%s was not emailed; emailed %v", want, recipients)
+		}
+	}
+	notes := store.Find("notifications", bson.M{"founder_id": assignee, "notification_type": "task_due"})
+	if len(notes) != 1 || !strings.Contains(notes[0]["message"].(string), "Mon, 02 Mar 2026 16:00 CET") {
+		t.Errorf("assignee notifications = %v, want one in Berlin time", notes)
+	}
+	if notes := store.Find("notifications", bson.M{"founder_id": founder, "notification_type": "grant_deadline"}); len(notes) != 1 {
+		t.Errorf("founder grant notifications = %v, want one", notes)
+	}
+
+	// a moved deadline is alerted again
+	moved := tasks[0]
+	moved.DueDate = now.Add(5 * time.Hour)
+	if err := store.Tasks().UpdateTask(ctx, moved.ID, moved); err != nil {
+		t.Fatal(err)
+	}
+	if sent, err := deadlines.Send(ctx, now.Add(time.Hour)); err != nil || sent != 1 {
+		t.Errorf("Send after moving the deadline = %d, %v, want 1", sent, err)
+	}
+}
@@ -2,119 +2,260 @@ package services
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"log"
+	"log/slog"
 	"net/http"
-	"os"
+	"time"
 
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
+	"DBackend/internal/apperror"
+	"DBackend/internal/config"
+	"DBackend/internal/database"
+	"DBackend/internal/ics"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
 )
 
+// ErrOrganizerCalendarNotConnected is returned by the google provider for an
+// organizer who has not connected a calendar to create the event on
+var ErrOrganizerCalendarNotConnected = apperror.Conflict("calendar_not_connected", "Connect your calendar before scheduling meetings")
+
+// GoogleCalendarService creates events on one user's Google calendar; get
+// one for a connected user from GoogleOAuth.Calendar
 type GoogleCalendarService struct {
 	service *calendar.Service
 }
 
-func NewGoogleCalendarService() (*GoogleCalendarService, error) {
-	//	ctx := context.Background()
-	b, err := ioutil.ReadFile("credentials.json")
-	if err != nil {
-		return nil, fmt.Errorf("unable to read client secret file: %v", err)
+// CreateEvent adds event to the user's primary calendar and emails the
+// attendees Google's invite. With meet it asks for a Google Meet conference,
+// otherwise the event points at event.MeetingURL. The API call is traced as
+// a child of the span in ctx.
+func (s *GoogleCalendarService) CreateEvent(ctx context.Context, event CalendarEvent, meet bool) (*calendar.Event, error) {
+	ev := googleEvent(event)
+	ev.ICalUID = EventUID(event.MeetingID)
+	if meet {
+		ev.ConferenceData = &calendar.ConferenceData{
+			CreateRequest: &calendar.CreateConferenceRequest{
+				// one conference per meeting, however often the insert is retried
+				RequestId:             event.MeetingID.Hex(),
+				ConferenceSolutionKey: &calendar.ConferenceSolutionKey{Type: "hangoutsMeet"},
+			},
+		}
+	} else {
+		ev.Location = event.MeetingURL
 	}
 
-	config, err := google.ConfigFromJSON(b, calendar.CalendarScope)
+	created, err := s.service.Events.Insert("primary", ev).ConferenceDataVersion(1).SendUpdates("all").Context(ctx).Do()
+	if revoked(err) {
+		return nil, ErrCalendarRevoked
+	}
 	if err != nil {
-		return nil, fmt.Errorf("unable to parse client secret file to config: %v", err)
+		return nil, fmt.Errorf("unable to create event: %v", err)
 	}
+	return created, nil
+}
 
-	client := getClient(config)
-	srv, err := calendar.New(client)
+// UpdateEvent changes the event eventID to event and emails the attendees
+// the update. Edited occurrences are instances Google creates itself, so each
+// is patched on its own after the series. A conference already on the event
+// is kept.
+func (s *GoogleCalendarService) UpdateEvent(ctx context.Context, eventID string, event CalendarEvent, meet bool) error {
+	ev := googleEvent(event)
+	if !meet {
+		ev.Location = event.MeetingURL
+	}
+	if event.Recurrence == "" {
+		ev.NullFields = append(ev.NullFields, "Recurrence")
+	}
+	_, err := s.service.Events.Patch("primary", eventID, ev).SendUpdates("all").Context(ctx).Do()
+	if err == nil {
+		for _, o := range event.Overrides {
+			occ := googleEvent(CalendarEvent{Title: o.Title, Description: o.Notes, Start: o.StartTime, End: o.EndTime, Timezone: event.Timezone})
+			if occ.Summary == "" {
+				occ.Summary = event.Title
+			}
+			if occ.Description == "" {
+				occ.Description = event.Description
+			}
+			occ.Attendees = ev.Attendees
+			_, err = s.service.Events.Patch("primary", instanceID(eventID, o.OriginalStart), occ).SendUpdates("all").Context(ctx).Do()
+			if err != nil {
+				break
+			}
+		}
+	}
+	if revoked(err) {
+		return ErrCalendarRevoked
+	}
 	if err != nil {
-		return nil, fmt.Errorf("unable to retrieve Calendar client: %v", err)
+		return fmt.Errorf("unable to update event: %v", err)
 	}
-
-	return &GoogleCalendarService{service: srv}, nil
+	return nil
 }
 
-func (s *GoogleCalendarService) CreateEvent(summary, location, description, startTime, endTime, timeZone string, attendees []string) (*calendar.Event, error) {
-	event := &calendar.Event{
-		Summary: summary, Location: location, Description: description, Start: &calendar.EventDateTime{
-			DateTime: startTime,
-			TimeZone: timeZone,
-		},
-		End: &calendar.EventDateTime{
-			DateTime: endTime,
-			TimeZone: timeZone,
-		},
-		Attendees: make([]*calendar.EventAttendee, len(attendees)),
-		ConferenceData: &calendar.ConferenceData{
-			CreateRequest: &calendar.CreateConferenceRequest{
-				RequestId: "sample123",
-				ConferenceSolutionKey: &calendar.ConferenceSolutionKey{
-					Type: "hangoutsMeet",
-				},
-			},
-		},
+// CancelEvent deletes the event eventID and emails the attendees that it was
+// cancelled
+func (s *GoogleCalendarService) CancelEvent(ctx context.Context, eventID string) error {
+	err := s.service.Events.Delete("primary", eventID).SendUpdates("all").Context(ctx).Do()
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && (apiErr.Code == http.StatusNotFound || apiErr.Code == http.StatusGone) {
+		return nil
+	}
+	if revoked(err) {
+		return ErrCalendarRevoked
+	}
+	if err != nil {
+		return fmt.Errorf("unable to cancel event: %v", err)
 	}
+	return nil
+}
 
-	for i, email := range attendees {
-		event.Attendees[i] = &calendar.EventAttendee{Email: email}
+// googleEvent is event as the Google Calendar API takes it, without the
+// conference or location
+func googleEvent(event CalendarEvent) *calendar.Event {
+	timezone := event.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	ev := &calendar.Event{
+		Summary:     event.Title,
+		Description: event.Description,
+		Start:       &calendar.EventDateTime{DateTime: event.Start.Format(time.RFC3339), TimeZone: timezone},
+		End:         &calendar.EventDateTime{DateTime: event.End.Format(time.RFC3339), TimeZone: timezone},
+	}
+	// Google takes the recurrence as iCalendar lines; edited occurrences are
+	// instances it creates itself, so they cannot be sent with the series
+	if event.Recurrence != "" {
+		series := seriesEvents(ics.Event{Start: event.Start}, event.Timezone, event.Recurrence, event.Exceptions, nil)[0]
+		ev.Recurrence = ics.RecurrenceLines(series)
 	}
+	for _, a := range event.Attendees {
+		ev.Attendees = append(ev.Attendees, &calendar.EventAttendee{Email: a.Email, DisplayName: a.Name})
+	}
+	return ev
+}
+
+// instanceID is the ID Google gives the occurrence of the recurring event
+// eventID that originally starts at start
+func instanceID(eventID string, start time.Time) string {
+	return eventID + "_" + start.UTC().Format("20060102T150405Z")
+}
 
-	event, err := s.service.Events.Insert("primary", event).ConferenceDataVersion(1).Do()
+// BusyTimes returns the busy periods on the user's primary calendar
+func (s *GoogleCalendarService) BusyTimes(ctx context.Context, from, to time.Time) ([]Interval, error) {
+	resp, err := s.service.Freebusy.Query(&calendar.FreeBusyRequest{
+		TimeMin: from.UTC().Format(time.RFC3339),
+		TimeMax: to.UTC().Format(time.RFC3339),
+		Items:   []*calendar.FreeBusyRequestItem{{Id: "primary"}},
+	}).Context(ctx).Do()
+	if revoked(err) {
+		return nil, ErrCalendarRevoked
+	}
 	if err != nil {
-		return nil, fmt.Errorf("unable to create event: %v", err)
+		return nil, fmt.Errorf("unable to query free/busy: %v", err)
 	}
+	var busy []Interval
+	if primary, ok := resp.Calendars["primary"]; ok {
+		for _, period := range primary.Busy {
+			start, err1 := time.Parse(time.RFC3339, period.Start)
+			end, err2 := time.Parse(time.RFC3339, period.End)
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("unable to parse busy period %s/%s", period.Start, period.End)
+			}
+			busy = append(busy, Interval{Start: start, End: end})
+		}
+	}
+	return busy, nil
+}
+
+// googleProvider creates events on the organizer's connected Google calendar
+type googleProvider struct {
+	cfg config.Calendar
+	db  database.Service
+}
 
-	return event, nil
+func (p *googleProvider) Name() string {
+	return ProviderGoogle
 }
 
-func getClient(config *oauth2.Config) *http.Client {
-	tokFile := "token.json"
-	tok, err := tokenFromFile(tokFile)
+func (p *googleProvider) CreateEvent(ctx context.Context, event CalendarEvent) (ScheduledEvent, error) {
+	service, err := p.organizerCalendar(ctx, event)
 	if err != nil {
-		tok = getTokenFromWeb(config)
-		saveToken(tokFile, tok)
+		return ScheduledEvent{}, err
 	}
-	return config.Client(context.Background(), tok)
-}
 
-func tokenFromFile(file string) (*oauth2.Token, error) {
-	f, err := os.Open(file)
+	created, err := service.CreateEvent(ctx, event, p.cfg.GoogleMeet)
 	if err != nil {
-		return nil, err
+		return ScheduledEvent{}, err
+	}
+	scheduled := ScheduledEvent{Provider: ProviderGoogle, EventID: created.Id, MeetingURL: event.MeetingURL}
+	if created.HangoutLink != "" {
+		scheduled.MeetingURL = created.HangoutLink
+		scheduled.GoogleMeetURL = created.HangoutLink
 	}
-	defer f.Close()
-	tok := &oauth2.Token{}
-	err = json.NewDecoder(f).Decode(tok)
-	return tok, err
+	return scheduled, nil
 }
 
-func getTokenFromWeb(config *oauth2.Config) *oauth2.Token {
-	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
-	fmt.Printf("Go to the following link in your browser then type the authorization code: \n%v\n", authURL)
+func (p *googleProvider) UpdateEvent(ctx context.Context, eventID string, event CalendarEvent) error {
+	service, err := p.organizerCalendar(ctx, event)
+	if err != nil {
+		return err
+	}
+	return service.UpdateEvent(ctx, eventID, event, p.cfg.GoogleMeet)
+}
 
-	var authCode string
-	if _, err := fmt.Scan(&authCode); err != nil {
-		log.Fatalf("Unable to read authorization code: %v", err)
+func (p *googleProvider) CancelEvent(ctx context.Context, eventID string, event CalendarEvent) error {
+	service, err := p.organizerCalendar(ctx, event)
+	if err != nil {
+		return err
 	}
+	return service.CancelEvent(ctx, eventID)
+}
 
-	tok, err := config.Exchange(context.Background(), authCode)
+// organizerCalendar returns the calendar the organizer of event connected,
+// which their meetings' events are on
+func (p *googleProvider) organizerCalendar(ctx context.Context, event CalendarEvent) (*GoogleCalendarService, error) {
+	conn, err := p.db.Calendars().GetConnection(ctx, event.OrganizerID, model.CalendarGoogle)
+	if errors.Is(err, database.ErrCalendarNotConnected) {
+		return nil, ErrOrganizerCalendarNotConnected
+	}
 	if err != nil {
-		log.Fatalf("Unable to retrieve token from web: %v", err)
+		return nil, err
+	}
+	oauth, err := NewGoogleOAuth(p.cfg)
+	if err != nil {
+		return nil, err
 	}
-	return tok
+	return oauth.Calendar(ctx, *conn)
 }
 
-func saveToken(path string, token *oauth2.Token) {
-	fmt.Printf("Saving credential file to: %s\n", path)
-	f, err := os.Create(path)
+// BusyTimes asks the user's Google calendar, if they connected one. Users
+// who connected before free/busy access was requested are left out until they
+// reconnect, rather than failing every booking with them.
+func (p *googleProvider) BusyTimes(ctx context.Context, userID primitive.ObjectID, from, to time.Time) ([]Interval, error) {
+	conn, err := p.db.Calendars().GetConnection(ctx, userID, model.CalendarGoogle)
+	if errors.Is(err, database.ErrCalendarNotConnected) {
+		return nil, nil
+	}
 	if err != nil {
-		log.Fatalf("Unable to cache oauth token: %v", err)
+		return nil, err
+	}
+	oauth, err := NewGoogleOAuth(p.cfg)
+	if err != nil {
+		return nil, err
+	}
+	service, err := oauth.Calendar(ctx, *conn)
+	if err != nil {
+		return nil, err
+	}
+	busy, err := service.BusyTimes(ctx, from, to)
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden {
+		slog.WarnContext(ctx, "calendar connection lacks free/busy access", "user_id", userID.Hex())
+		return nil, nil
 	}
-	defer f.Close()
-	json.NewEncoder(f).Encode(token)
+	return busy, err
 }
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"DBackend/internal/apperror"
+	"DBackend/internal/config"
+	"DBackend/internal/tracing"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+// OAuthStateTTL is how long a user has to finish the consent screen, and so
+// how long the browser keeps the nonce the state is bound to
+const OAuthStateTTL = 10 * time.Minute
+
+var (
+	ErrInvalidOAuthState = apperror.Validation("invalid_oauth_state", "Calendar authorization expired or was tampered with, start again")
+	ErrNoRefreshToken    = apperror.Validation("no_refresh_token", "Google did not grant offline access, remove the app from your Google account and connect again")
+	ErrCalendarRevoked   = apperror.Conflict("calendar_revoked", "Calendar access was revoked, connect your calendar again")
+)
+
+// GoogleOAuth connects users' own Google calendars with the OAuth
+// authorization code flow. The browser is sent to Google with a signed state
+// naming the user, Google redirects back to the callback with a code, and the
+// code is exchanged for a refresh token that is stored encrypted. The state
+// also carries the hash of a nonce kept in the browser that started the flow,
+// so a consent URL sent to someone else cannot connect their calendar to the
+// sender's account.
+type GoogleOAuth struct {
+	config   *oauth2.Config
+	cipher   *TokenCipher
+	stateKey []byte
+	now      func() time.Time
+}
+
+// NewGoogleOAuth reads the OAuth client from cfg.CredentialsFile
+func NewGoogleOAuth(cfg config.Calendar) (*GoogleOAuth, error) {
+	b, err := os.ReadFile(cfg.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read client secret file: %v", err)
+	}
+	oauthConfig, err := google.ConfigFromJSON(b, calendar.CalendarEventsScope, calendar.CalendarFreebusyScope)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse client secret file to config: %v", err)
+	}
+	oauthConfig.RedirectURL = cfg.RedirectURL
+
+	tokenCipher, err := NewTokenCipher(cfg.TokenKey)
+	if err != nil {
+		return nil, err
+	}
+	return &GoogleOAuth{
+		config:   oauthConfig,
+		cipher:   tokenCipher,
+		stateKey: deriveKey(cfg.TokenKey, "calendar oauth state"),
+		now:      time.Now,
+	}, nil
+}
+
+// NewOAuthNonce returns a random nonce for the browser starting a consent
+func NewOAuthNonce() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// AuthCodeURL returns the Google consent page URL for userID, bound to the
+// browser holding nonce. Consent is always prompted so Google returns a
+// refresh token even on reconnects.
+func (o *GoogleOAuth) AuthCodeURL(userID primitive.ObjectID, nonce string) string {
+	return o.config.AuthCodeURL(o.state(userID, nonce), oauth2.AccessTypeOffline, oauth2.ApprovalForce)
+}
+
+// Connect checks the state returned to the callback against the nonce of
+// the browser it returned to, and exchanges code for the user's refresh
+// token, returning the connection to store
+func (o *GoogleOAuth) Connect(ctx context.Context, state, nonce, code string) (model.CalendarConnection, error) {
+	userID, err := o.verifyState(state, nonce)
+	if err != nil {
+		return model.CalendarConnection{}, err
+	}
+	tok, err := o.config.Exchange(tracedContext(ctx), code)
+	if err != nil {
+		return model.CalendarConnection{}, apperror.Validation("oauth_exchange_failed", "Google rejected the authorization code")
+	}
+	if tok.RefreshToken == "" {
+		return model.CalendarConnection{}, ErrNoRefreshToken
+	}
+	sealed, err := o.cipher.Seal(userID[:], tok.RefreshToken)
+	if err != nil {
+		return model.CalendarConnection{}, err
+	}
+	return model.CalendarConnection{UserID: userID, Provider: model.CalendarGoogle, RefreshToken: sealed}, nil
+}
+
+// Calendar returns a client acting on the connected user's own calendar
+func (o *GoogleOAuth) Calendar(ctx context.Context, conn model.CalendarConnection) (*GoogleCalendarService, error) {
+	refreshToken, err := o.cipher.Open(conn.UserID[:], conn.RefreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt calendar token: %v", err)
+	}
+	ctx = tracedContext(ctx)
+	client := o.config.Client(ctx, &oauth2.Token{RefreshToken: refreshToken})
+	srv, err := calendar.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve Calendar client: %v", err)
+	}
+	return &GoogleCalendarService{service: srv}, nil
+}
+
+// state signs userID, an expiry and the nonce's hash as
+// "<user>.<unix expiry>.<nonce hash>.<mac>"
+func (o *GoogleOAuth) state(userID primitive.ObjectID, nonce string) string {
+	payload := userID.Hex() + "." + strconv.FormatInt(o.now().Add(OAuthStateTTL).Unix(), 10) + "." + hashNonce(nonce)
+	return payload + "." + o.sign(payload)
+}
+
+func (o *GoogleOAuth) verifyState(state, nonce string) (primitive.ObjectID, error) {
+	i := strings.LastIndex(state, ".")
+	if nonce == "" || i < 0 || !hmac.Equal([]byte(state[i+1:]), []byte(o.sign(state[:i]))) {
+		return primitive.NilObjectID, ErrInvalidOAuthState
+	}
+	parts := strings.Split(state[:i], ".")
+	if len(parts) != 3 || !hmac.Equal([]byte(parts[2]), []byte(hashNonce(nonce))) {
+		return primitive.NilObjectID, ErrInvalidOAuthState
+	}
+	user, expiry := parts[0], parts[1]
+	exp, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || o.now().Unix() > exp {
+		return primitive.NilObjectID, ErrInvalidOAuthState
+	}
+	userID, err := primitive.ObjectIDFromHex(user)
+	if err != nil {
+		return primitive.NilObjectID, ErrInvalidOAuthState
+	}
+	return userID, nil
+}
+
+func hashNonce(nonce string) string {
+	sum := sha256.Sum256([]byte(nonce))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (o *GoogleOAuth) sign(payload string) string {
+	mac := hmac.New(sha256.New, o.stateKey)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// tracedContext makes token exchanges, refreshes and Calendar calls go
+// through a traced base client
+func tracedContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: tracing.Transport(nil)})
+}
+
+// revoked reports whether err means the user withdrew the app's access, so
+// the stored token will never work again
+func revoked(err error) bool {
+	var retrieveErr *oauth2.RetrieveError
+	return errors.As(err, &retrieveErr) && retrieveErr.ErrorCode == "invalid_grant"
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/oauth2"
+)
+
+func newTestOAuth(now *time.Time) *GoogleOAuth {
+	return &GoogleOAuth{
+		config:   &oauth2.Config{},
+		stateKey: deriveKey("secret", "calendar oauth state"),
+		now:      func() time.Time { return *now },
+	}
+}
+
+func TestStateExpires(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	o := newTestOAuth(&now)
+	user := primitive.NewObjectID()
+	state := o.state(user, "nonce")
+
+	now = now.Add(OAuthStateTTL)
+	if got, err := o.verifyState(state, "nonce"); err != nil || got != user {
+		t.Fatalf("verifyState() = %v, %v; want %v", got, err, user)
+	}
+	now = now.Add(time.Second)
+	if _, err := o.verifyState(state, "nonce"); !errors.Is(err, ErrInvalidOAuthState) {
+		t.Errorf("verifyState() after expiry error = %v, want ErrInvalidOAuthState", err)
+	}
+}
+
+func TestStateSignedWithOtherKeyIsRejected(t *testing.T) {
+	now := time.Now()
+	other := newTestOAuth(&now)
+	other.stateKey = deriveKey("another secret", "calendar oauth state")
+	for _, state := range []string{other.state(primitive.NewObjectID(), "nonce"), "", "no-dots", "a.b.c"} {
+		if _, err := newTestOAuth(&now).verifyState(state, "nonce"); !errors.Is(err, ErrInvalidOAuthState) {
+			t.Errorf("verifyState(%q) error = %v, want ErrInvalidOAuthState", state, err)
+		}
+	}
+}
+
+// a consent URL sent to someone else returns to a browser without the
+// sender's nonce
+func TestStateIsBoundToNonce(t *testing.T) {
+	now := time.Now()
+	o := newTestOAuth(&now)
+	state := o.state(primitive.NewObjectID(), "attacker's nonce")
+	for _, nonce := range []string{"victim's nonce", ""} {
+		if _, err := o.verifyState(state, nonce); !errors.Is(err, ErrInvalidOAuthState) {
+			t.Errorf("verifyState() with nonce %q error = %v, want ErrInvalidOAuthState", nonce, err)
+		}
+	}
+}
+
+func TestTokenCipherBindsTokenToID(t *testing.T) {
+	c, err := NewTokenCipher("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ada, grace := primitive.NewObjectID(), primitive.NewObjectID()
+	sealed, err := c.Seal(ada[:], "refresh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token, err := c.Open(ada[:], sealed); err != nil || token != "refresh" {
+		t.Errorf("Open() = %q, %v; want refresh", token, err)
+	}
+	if _, err := c.Open(grace[:], sealed); err == nil {
+		t.Error("Open() with another ID succeeded")
+	}
+	other, _ := NewTokenCipher("another secret")
+	if _, err := other.Open(ada[:], sealed); err == nil {
+		t.Error("Open() with another key succeeded")
+	}
+}
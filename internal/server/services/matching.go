@@ -0,0 +1,201 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/database"
+	"DBackend/internal/metrics"
+	"DBackend/internal/tracing"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MatchMaxAge is how old a match gets before batch matching scores it again
+const MatchMaxAge = 7 * 24 * time.Hour
+
+// pageSize is how many investors or founders the batch jobs read at a time
+const pageSize = 100
+
+// MatchRequest defines the structure of the payload that our FastAPI endpoint expects.
+type MatchRequest struct {
+	Founder  map[string]interface{} `json:"founder"`
+	Investor map[string]interface{} `json:"investor"`
+}
+
+// MatchResponse defines the structure of the response from FastAPI.
+type MatchResponse struct {
+	MatchProbability float64 `json:"match_probability"`
+}
+
+// MatchPayload builds the matcher request for a founder and investor,
+// filling in the defaults the model was trained with for empty fields
+func MatchPayload(founder model.Founder, investor model.Investor) MatchRequest {
+	return MatchRequest{
+		Founder: map[string]interface{}{
+			"fund_required": defaultIfZero(float64(founder.FundRequired), 500000),
+			"industry":      defaultIfEmpty(founder.Industry, "Other"),
+			"funding_stage": defaultIfEmpty(founder.FundingStage, "Seed"),
+		},
+		Investor: map[string]interface{}{
+			"total_invested":          defaultIfZero(investor.TotalInvested, 1000000),
+			"preferred_funding_stage": defaultIfEmpty(investor.PreferredFundingStage, "Seed"),
+			"risk_tolerance":          defaultIfEmpty(investor.RiskTolerance, "Moderate"),
+		},
+	}
+}
+
+// GetMatchProbability sends a POST request to the FastAPI endpoint and returns the match probability.
+// The call is traced as a child of the span in ctx.
+func GetMatchProbability(ctx context.Context, ml config.ML, req MatchRequest) (float64, error) {
+	start := time.Now()
+	outcome := "error"
+	defer func() {
+		metrics.MLRequests.WithLabelValues(outcome).Inc()
+		metrics.MLDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	client := &http.Client{Timeout: ml.Timeout, Transport: tracing.Transport(nil)}
+
+	// Marshal the request into JSON.
+	requestBody, err := json.Marshal(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	// Send the POST request.
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, ml.URL, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		outcome = "bad_status"
+		return 0, fmt.Errorf("match service returned %s", resp.Status)
+	}
+
+	// Read and parse the response body.
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	var matchResp MatchResponse
+	if err := json.Unmarshal(body, &matchResp); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	outcome = "success"
+	return matchResp.MatchProbability, nil
+}
+
+// Matcher scores every investor against every founder with the ML matcher
+// and stores the scores as the investors' matches
+type Matcher struct {
+	db database.Service
+	ml config.ML
+}
+
+// NewMatcher creates the batch matcher
+func NewMatcher(db database.Service, ml config.ML) *Matcher {
+	return &Matcher{db: db, ml: ml}
+}
+
+// Run scores the pairs whose match is missing or older than MatchMaxAge.
+// It stops at the first failed call to the matcher: the pairs scored so far
+// are stored, so a retry carries on where the run stopped.
+func (m *Matcher) Run(ctx context.Context) error {
+	scored, err := m.Match(ctx, time.Now())
+	if scored > 0 {
+		slog.InfoContext(ctx, "scored investor and founder matches", "count", scored)
+	}
+	return err
+}
+
+// Match scores the stale pairs as of now and returns how many it scored
+func (m *Matcher) Match(ctx context.Context, now time.Time) (int, error) {
+	scored := 0
+	after := primitive.NilObjectID
+	for {
+		investors, err := m.db.Investors().ListInvestors(ctx, after, pageSize)
+		if err != nil {
+			return scored, fmt.Errorf("listing investors: %w", err)
+		}
+		for _, investor := range investors {
+			n, err := m.matchInvestor(ctx, investor, now)
+			scored += n
+			if err != nil {
+				return scored, err
+			}
+		}
+		if len(investors) < pageSize {
+			return scored, nil
+		}
+		after = investors[len(investors)-1].ID
+	}
+}
+
+// matchInvestor scores the investor against each founder it has no fresh match with
+func (m *Matcher) matchInvestor(ctx context.Context, investor model.Investor, now time.Time) (int, error) {
+	scoredAt, err := m.db.Investors().GetMatchTimes(ctx, investor.UserID)
+	if err != nil {
+		return 0, fmt.Errorf("retrieving matches of investor %s: %w", investor.UserID.Hex(), err)
+	}
+	scored := 0
+	q := database.StartupQuery{Limit: pageSize}
+	for {
+		page, err := m.db.Founders().DiscoverStartups(ctx, q)
+		if err != nil {
+			return scored, fmt.Errorf("listing founders: %w", err)
+		}
+		for _, founder := range page.Founders {
+			if now.Sub(scoredAt[founder.UserID]) < MatchMaxAge {
+				continue
+			}
+			probability, err := GetMatchProbability(ctx, m.ml, MatchPayload(founder, investor))
+			if err != nil {
+				return scored, err
+			}
+			_, err = m.db.Investors().StoreMatch(ctx, model.MatchInvestorFounder{
+				FounderID:       founder.UserID,
+				InvestorID:      investor.UserID,
+				MatchPercentage: probability,
+			})
+			if err != nil {
+				return scored, fmt.Errorf("storing match: %w", err)
+			}
+			scored++
+		}
+		if page.NextCursor == "" {
+			return scored, nil
+		}
+		q.Cursor = page.NextCursor
+	}
+}
+
+func defaultIfEmpty(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func defaultIfZero(value float64, defaultValue float64) float64 {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
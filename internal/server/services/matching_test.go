@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/database/memory"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMatcherScoresStalePairs(t *testing.T) {
+	ctx := context.Background()
+	var calls, failAfter atomic.Int32
+	failAfter.Store(-1)
+	ml := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n := calls.Add(1); failAfter.Load() >= 0 && n > failAfter.Load() {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		var req MatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Investor["risk_tolerance"] != "Moderate" {
+			t.Errorf("matcher request %+v, %v, want defaults filled in", req, err)
+		}
+		_ = json.NewEncoder(w).Encode(MatchResponse{MatchProbability: 0.8})
+	}))
+	defer ml.Close()
+
+	store := memory.New()
+	investors := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+	founders := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+	for _, id := range investors {
+		if _, err := store.Insert("investors", model.Investor{UserID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, id := range founders {
+		if _, err := store.Insert("founders", model.Founder{UserID: id, CreatedAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	// a fresh match is kept, a stale one is scored again
+	for _, m := range []model.MatchInvestorFounder{
+		{InvestorID: investors[0], FounderID: founders[0], MatchPercentage: 0.1, UpdatedAt: now.Add(-time.Hour)},
+		{InvestorID: investors[0], FounderID: founders[1], MatchPercentage: 0.1, UpdatedAt: now.Add(-MatchMaxAge - time.Hour)},
+	} {
+		if _, err := store.Insert("matches", m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matcher := NewMatcher(store, config.ML{URL: ml.URL, Timeout: time.Second})
+	failAfter.Store(3)
+	if scored, err := matcher.Match(ctx, now); err == nil || scored != 3 {
+		t.Fatalf("Match with a failing matcher = %d, %v, want 3 and an error", scored, err)
+	}
+	failAfter.Store(-1)
+	if scored, err := matcher.Match(ctx, now); err != nil || scored != 2 {
+		t.Fatalf("retried Match = %d, %v, want the 2 pairs left", scored, err)
+	}
+	if calls.Load() != 6 {
+		t.Errorf("the matcher was called %d times, want 6", calls.Load())
+	}
+
+	if got := len(store.Find("matches", nil)); got != 6 {
+		t.Errorf("%d matches stored, want one per pair", got)
+	}
+	kept := store.Find("matches", bson.M{"investor_id": investors[0], "founder_id": founders[0]})
+	if len(kept) != 1 || kept[0]["match_percentage"] != 0.1 {
+		t.Errorf("fresh match = %v, want it kept", kept)
+	}
+	if scored, err := matcher.Match(ctx, now); err != nil || scored != 0 {
+		t.Errorf("Match with every pair fresh = %d, %v", scored, err)
+	}
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"DBackend/model"
+
+	"github.com/teambition/rrule-go"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Forever is the series end of meetings that recur without end
+var Forever = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// maxCount bounds COUNT, so a series can always be listed in full
+const maxCount = 1000
+
+// frequencies are the FREQ values meetings may recur at; finer ones would
+// make series nobody means to schedule
+var frequencies = map[rrule.Frequency]bool{rrule.YEARLY: true, rrule.MONTHLY: true, rrule.WEEKLY: true, rrule.DAILY: true}
+
+// Occurrence is one instance of a meeting, with any edit made to it applied
+type Occurrence struct {
+	MeetingID primitive.ObjectID `json:"meeting_id"`
+	// OriginalStart names the occurrence within its series
+	OriginalStart time.Time `json:"original_start"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	Title         string    `json:"title"`
+	Notes         string    `json:"notes,omitempty"`
+	Edited        bool      `json:"edited"`
+}
+
+// MeetingLocation returns the zone m is planned in
+func MeetingLocation(m model.Meeting) (*time.Location, error) {
+	if m.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(m.Timezone)
+}
+
+// ParseRecurrence returns the rule m recurs by, starting at its start time in
+// its timezone so occurrences keep their wall clock time across daylight
+// saving changes; nil when m does not recur
+func ParseRecurrence(m model.Meeting) (*rrule.RRule, error) {
+	value := strings.TrimPrefix(strings.TrimSpace(m.Recurrence), "RRULE:")
+	if value == "" {
+		return nil, nil
+	}
+	if strings.ContainsAny(value, "\r\n") || strings.Contains(value, "DTSTART") {
+		return nil, fmt.Errorf("must be a single RRULE; the series starts at start_time")
+	}
+	loc, err := MeetingLocation(m)
+	if err != nil {
+		return nil, err
+	}
+	opt, err := rrule.StrToROptionInLocation(value, loc)
+	if err != nil {
+		return nil, err
+	}
+	if !frequencies[opt.Freq] {
+		return nil, fmt.Errorf("FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY")
+	}
+	if opt.Count > maxCount {
+		return nil, fmt.Errorf("COUNT must be at most %d", maxCount)
+	}
+	opt.Dtstart = m.StartTime.In(loc)
+	return rrule.NewRRule(*opt)
+}
+
+// SeriesEnd returns when the last occurrence of m ends, or Forever
+func SeriesEnd(m model.Meeting) (time.Time, error) {
+	r, err := ParseRecurrence(m)
+	if err != nil {
+		return time.Time{}, err
+	}
+	end := m.EndTime
+	if r != nil {
+		duration := m.EndTime.Sub(m.StartTime)
+		switch {
+		case r.OrigOptions.Count > 0:
+			if all := r.All(); len(all) > 0 {
+				end = latest(end, all[len(all)-1].Add(duration))
+			}
+		case !r.OrigOptions.Until.IsZero():
+			end = latest(end, r.OrigOptions.Until.Add(duration))
+		default:
+			return Forever, nil
+		}
+	}
+	for _, o := range m.Overrides {
+		end = latest(end, o.EndTime)
+	}
+	return end, nil
+}
+
+// IsOccurrence reports whether start is when an occurrence of m starts in its
+// series, before edits
+func IsOccurrence(m model.Meeting, start time.Time) (bool, error) {
+	r, err := ParseRecurrence(m)
+	if err != nil || r == nil {
+		return false, err
+	}
+	return inSeries(r, start), nil
+}
+
+// Occurrences returns the occurrences of m overlapping from to to, by start
+func Occurrences(m model.Meeting, from, to time.Time) ([]Occurrence, error) {
+	r, err := ParseRecurrence(m)
+	if err != nil {
+		return nil, err
+	}
+	occurrences := []Occurrence{}
+	if r == nil {
+		if m.StartTime.Before(to) && m.EndTime.After(from) {
+			occurrences = append(occurrences, Occurrence{MeetingID: m.ID, OriginalStart: m.StartTime, StartTime: m.StartTime, EndTime: m.EndTime, Title: m.Title, Notes: m.Notes})
+		}
+		return occurrences, nil
+	}
+
+	duration := m.EndTime.Sub(m.StartTime)
+	starts := r.Between(from.Add(-duration), to, true)
+	// an edited occurrence may have been moved into the span from outside it
+	for _, o := range m.Overrides {
+		if !containsTime(starts, o.OriginalStart) && inSeries(r, o.OriginalStart) {
+			starts = append(starts, o.OriginalStart)
+		}
+	}
+	for _, start := range starts {
+		if containsTime(m.Exceptions, start) {
+			continue
+		}
+		occ := Occurrence{MeetingID: m.ID, OriginalStart: start.UTC(), StartTime: start.UTC(), EndTime: start.Add(duration).UTC(), Title: m.Title, Notes: m.Notes}
+		if o := FindOverride(m, start); o != nil {
+			occ.StartTime, occ.EndTime, occ.Edited = o.StartTime, o.EndTime, true
+			if o.Title != "" {
+				occ.Title = o.Title
+			}
+			if o.Notes != "" {
+				occ.Notes = o.Notes
+			}
+		}
+		if occ.StartTime.Before(to) && occ.EndTime.After(from) {
+			occurrences = append(occurrences, occ)
+		}
+	}
+	sort.Slice(occurrences, func(i, j int) bool { return occurrences[i].StartTime.Before(occurrences[j].StartTime) })
+	return occurrences, nil
+}
+
+// FindOverride returns the edit made to the occurrence of m starting at
+// originalStart, if any
+func FindOverride(m model.Meeting, originalStart time.Time) *model.OccurrenceOverride {
+	for i, o := range m.Overrides {
+		if sameStart(o.OriginalStart, originalStart) {
+			return &m.Overrides[i]
+		}
+	}
+	return nil
+}
+
+// EditOccurrence records o as the edit of the occurrence of m it names,
+// replacing any earlier edit of it
+func EditOccurrence(m *model.Meeting, o model.OccurrenceOverride) {
+	o.OriginalStart = o.OriginalStart.UTC()
+	if existing := FindOverride(*m, o.OriginalStart); existing != nil {
+		*existing = o
+		return
+	}
+	m.Overrides = append(m.Overrides, o)
+}
+
+// CancelOccurrence records the occurrence of m starting at originalStart as
+// cancelled and drops any edit made to it
+func CancelOccurrence(m *model.Meeting, originalStart time.Time) {
+	overrides := []model.OccurrenceOverride{}
+	for _, o := range m.Overrides {
+		if !sameStart(o.OriginalStart, originalStart) {
+			overrides = append(overrides, o)
+		}
+	}
+	m.Overrides = overrides
+	if !IsCancelled(*m, originalStart) {
+		m.Exceptions = append(m.Exceptions, originalStart.UTC())
+	}
+}
+
+// IsCancelled reports whether the occurrence of m starting at originalStart
+// has been cancelled
+func IsCancelled(m model.Meeting, originalStart time.Time) bool {
+	return containsTime(m.Exceptions, originalStart)
+}
+
+func inSeries(r *rrule.RRule, start time.Time) bool {
+	return len(r.Between(start.Add(-time.Second), start.Add(time.Second), true)) > 0
+}
+
+// sameStart compares starts to the second; stored times lose precision
+func sameStart(a, b time.Time) bool {
+	return a.Truncate(time.Second).Equal(b.Truncate(time.Second))
+}
+
+func containsTime(times []time.Time, t time.Time) bool {
+	for _, other := range times {
+		if sameStart(other, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
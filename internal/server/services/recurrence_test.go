@@ -0,0 +1,142 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func occurrenceStarts(occurrences []Occurrence) []string {
+	starts := make([]string, len(occurrences))
+	for i, o := range occurrences {
+		starts[i] = o.StartTime.Format(time.RFC3339)
+	}
+	return starts
+}
+
+// boardMeeting meets on the first Monday of each month at 10:00 in Berlin,
+// starting in February 2026
+func boardMeeting() model.Meeting {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	start := time.Date(2026, 2, 2, 10, 0, 0, 0, berlin).UTC()
+	return model.Meeting{
+		ID:         primitive.NewObjectID(),
+		Title:      "Board meeting",
+		StartTime:  start,
+		EndTime:    start.Add(2 * time.Hour),
+		Timezone:   "Europe/Berlin",
+		Recurrence: "FREQ=MONTHLY;BYDAY=1MO",
+	}
+}
+
+func TestOccurrencesKeepWallClockAcrossDST(t *testing.T) {
+	m := boardMeeting()
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	occurrences, err := Occurrences(m, from, from.AddDate(0, 3, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Berlin moves from UTC+1 to UTC+2 on 29 March 2026
+	want := []string{"2026-02-02T09:00:00Z", "2026-03-02T09:00:00Z", "2026-04-06T08:00:00Z"}
+	if got := occurrenceStarts(occurrences); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("occurrences = %v, want %v", got, want)
+	}
+}
+
+func TestOccurrencesApplyExceptionsAndOverrides(t *testing.T) {
+	m := boardMeeting()
+	march := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	april := time.Date(2026, 4, 6, 8, 0, 0, 0, time.UTC)
+	CancelOccurrence(&m, march)
+	// April's meeting moves a week later, outside the span listed below
+	moved := april.AddDate(0, 0, 7)
+	EditOccurrence(&m, model.OccurrenceOverride{OriginalStart: april, StartTime: moved, EndTime: moved.Add(time.Hour), Title: "Board meeting (moved)"})
+
+	from := time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC)
+	occurrences, err := Occurrences(m, from, from.AddDate(0, 0, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(occurrences) != 1 || !occurrences[0].StartTime.Equal(moved) || !occurrences[0].Edited || occurrences[0].Title != "Board meeting (moved)" {
+		t.Errorf("occurrences = %+v, want only the moved April meeting", occurrences)
+	}
+
+	all, err := Occurrences(m, march.AddDate(0, -1, 0), march.AddDate(0, 2, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, o := range all {
+		if o.OriginalStart.Equal(march) {
+			t.Errorf("cancelled occurrence %v is listed", o.StartTime)
+		}
+	}
+	if !IsCancelled(m, march) {
+		t.Error("March is not cancelled")
+	}
+	if ok, _ := IsOccurrence(m, march.Add(time.Hour)); ok {
+		t.Error("a time outside the series is an occurrence")
+	}
+}
+
+func TestSeriesEnd(t *testing.T) {
+	m := boardMeeting()
+	if end, err := SeriesEnd(m); err != nil || !end.Equal(Forever) {
+		t.Errorf("series end = %v, %v, want forever", end, err)
+	}
+
+	m.Recurrence = "FREQ=MONTHLY;BYDAY=1MO;COUNT=3"
+	if end, err := SeriesEnd(m); err != nil || !end.Equal(time.Date(2026, 4, 6, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("series end = %v, %v, want the end of the April meeting", end, err)
+	}
+
+	m.Recurrence = "RRULE:FREQ=WEEKLY;UNTIL=20260301T000000Z"
+	if end, err := SeriesEnd(m); err != nil || !end.Equal(time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("series end = %v, %v, want two hours after UNTIL", end, err)
+	}
+
+	m.Recurrence = ""
+	if end, err := SeriesEnd(m); err != nil || !end.Equal(m.EndTime) {
+		t.Errorf("series end = %v, %v, want the meeting's end", end, err)
+	}
+
+	for _, rule := range []string{"FREQ=HOURLY", "FREQ=DAILY;COUNT=5000", "DTSTART:20260101T000000Z\nRRULE:FREQ=DAILY", "FREQ=SOMETIMES"} {
+		m.Recurrence = rule
+		if _, err := SeriesEnd(m); err == nil {
+			t.Errorf("recurrence %q was accepted", rule)
+		}
+	}
+}
+
+func TestFindConflictsReportsSharedParticipants(t *testing.T) {
+	investor, founder, outsider := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	m := boardMeeting()
+	m.InvestorID, m.FounderID = investor, founder
+
+	// a one-off meeting of the founder's during the March board meeting, and
+	// one of an outsider's at the same time
+	march := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	clash := model.Meeting{ID: primitive.NewObjectID(), FounderID: founder, Title: "Customer call", StartTime: march, EndTime: march.Add(time.Hour)}
+	unrelated := model.Meeting{ID: primitive.NewObjectID(), FounderID: outsider, Title: "Elsewhere", StartTime: march, EndTime: march.Add(time.Hour)}
+
+	end, err := SeriesEnd(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, to := ConflictWindow(m, end)
+	conflicts, err := FindConflicts(m, []model.Meeting{m, clash, unrelated}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 1 || conflicts[0].MeetingID != clash.ID || conflicts[0].ParticipantID != founder {
+		t.Fatalf("conflicts = %+v, want the founder's customer call", conflicts)
+	}
+
+	Localize(conflicts, map[primitive.ObjectID]string{founder: "America/New_York"})
+	if conflicts[0].LocalStart != "2026-03-02T05:00:00-05:00" {
+		t.Errorf("local start = %q, want 05:00 in New York", conflicts[0].LocalStart)
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/database"
+	"DBackend/internal/mail"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxReminder is the longest a reminder can be sent before a meeting
+const MaxReminder = 4 * 7 * 24 * time.Hour
+
+// Reminders reminds participants of their upcoming meetings by notification
+// and email
+type Reminders struct {
+	db       database.Service
+	mail     mail.Sender
+	defaults []time.Duration
+}
+
+// NewReminders creates the reminder sender. Meetings that do not set their
+// own reminders get cfg.Before.
+func NewReminders(db database.Service, sender mail.Sender, cfg config.Reminders) *Reminders {
+	return &Reminders{db: db, mail: sender, defaults: cfg.Before}
+}
+
+// Run sends the reminders due now; the reminders job runs it every minute
+func (r *Reminders) Run(ctx context.Context) error {
+	sent, err := r.Send(ctx, time.Now())
+	if sent > 0 {
+		slog.InfoContext(ctx, "sent meeting reminders", "count", sent)
+	}
+	return err
+}
+
+// Send sends the reminders due at now and returns how many occurrences
+// participants were reminded of. Only the closest due reminder of an
+// occurrence is sent, so reminders missed while the API was down are not
+// sent late one after the other. Each reminder is claimed before it is sent,
+// so instances running side by side send it once.
+func (r *Reminders) Send(ctx context.Context, now time.Time) (int, error) {
+	meetings, err := r.db.Meetings().GetMeetingsBetween(ctx, now, now.Add(MaxReminder))
+	if err != nil {
+		return 0, fmt.Errorf("retrieving upcoming meetings: %w", err)
+	}
+	sent := 0
+	for _, m := range meetings {
+		offsets := r.offsets(m)
+		if len(offsets) == 0 {
+			continue
+		}
+		occurrences, err := Occurrences(m, now, now.Add(offsets[0]))
+		if err != nil {
+			slog.WarnContext(ctx, "skipping reminders of a meeting with an invalid recurrence", "meeting_id", m.ID.Hex(), "error", err)
+			continue
+		}
+		for _, o := range occurrences {
+			before, due := dueReminder(offsets, o.StartTime, now)
+			if !due {
+				continue
+			}
+			claimed, err := r.db.Meetings().ClaimReminder(ctx, model.MeetingReminder{
+				ID:              primitive.NewObjectID(),
+				MeetingID:       m.ID,
+				OccurrenceStart: o.StartTime,
+				Before:          int(before / time.Minute),
+				SentAt:          now,
+			})
+			if err != nil {
+				return sent, fmt.Errorf("claiming reminder: %w", err)
+			}
+			if !claimed {
+				continue
+			}
+			r.remind(ctx, m, o)
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// offsets returns how long before each occurrence of m its participants are
+// reminded, longest first
+func (r *Reminders) offsets(m model.Meeting) []time.Duration {
+	offsets := r.defaults
+	if m.Reminders != nil {
+		offsets = make([]time.Duration, len(m.Reminders))
+		for i, minutes := range m.Reminders {
+			offsets[i] = time.Duration(minutes) * time.Minute
+		}
+	}
+	sorted := append([]time.Duration(nil), offsets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+	return sorted
+}
+
+// dueReminder returns the closest of offsets, sorted longest first, whose
+// reminder for an occurrence starting at start is due at now
+func dueReminder(offsets []time.Duration, start, now time.Time) (time.Duration, bool) {
+	if !start.After(now) {
+		return 0, false
+	}
+	for i := len(offsets) - 1; i >= 0; i-- {
+		if !start.Add(-offsets[i]).After(now) {
+			return offsets[i], true
+		}
+	}
+	return 0, false
+}
+
+// remind notifies and emails every participant of m who has not declined.
+// Failures are logged: a reminder that reached some participants is not
+// sent again to the others.
+func (r *Reminders) remind(ctx context.Context, m model.Meeting, o Occurrence) {
+	declined := map[primitive.ObjectID]bool{}
+	for _, rsvp := range m.RSVPs {
+		declined[rsvp.UserID] = rsvp.Status == "declined"
+	}
+	for _, id := range Participants(m) {
+		if declined[id] {
+			continue
+		}
+		user, err := r.db.Users().FindByID(ctx, id)
+		if err != nil {
+			slog.WarnContext(ctx, "skipping reminder of a missing participant", "meeting_id", m.ID.Hex(), "user_id", id.Hex(), "error", err)
+			continue
+		}
+		zone := user.Timezone
+		if zone == "" {
+			zone = m.Timezone
+		}
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			loc = time.UTC
+		}
+		title := "Reminder: " + o.Title
+		message := fmt.Sprintf("%q starts on %s", o.Title, o.StartTime.In(loc).Format("Mon, 02 Jan 2006 15:04 MST"))
+
+		_, err = r.db.Notifications().CreateNotification(ctx, model.Notification{
+			FounderID:        id,
+			NotificationType: "meeting_reminder",
+			Title:            title,
+			Message:          message,
+		})
+		if err != nil {
+			slog.WarnContext(ctx, "creating reminder notification failed", "meeting_id", m.ID.Hex(), "user_id", id.Hex(), "error", err)
+		}
+		if user.Email == "" {
+			continue
+		}
+		body := message + "."
+		if link := meetingLink(m); link != "" {
+			body += "\n\nJoin: " + link
+		}
+		if err := r.mail.Send(ctx, mail.Message{To: user.Email, Subject: title, Body: body}); err != nil {
+			slog.WarnContext(ctx, "emailing reminder failed", "meeting_id", m.ID.Hex(), "user_id", id.Hex(), "error", err)
+		}
+	}
+}
+
+// meetingLink returns where participants join m
+func meetingLink(m model.Meeting) string {
+	if m.GoogleMeetURL != "" {
+		return m.GoogleMeetURL
+	}
+	return m.MeetingURL
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"DBackend/internal/config"
+	"DBackend/internal/database/memory"
+	"DBackend/internal/mail"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type outbox struct {
+	mu   sync.Mutex
+	sent []mail.Message
+}
+
+func (o *outbox) Send(_ context.Context, msg mail.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.sent = append(o.sent, msg)
+	return nil
+}
+
+func TestRemindersSendEachReminderOnce(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	investor, founder, guest := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	for _, u := range []model.User{
+		{ID: investor, Email: "investor@example.com", Timezone: "America/New_York"},
+		{ID: founder, Email: "founder@example.com"},
+		{ID: guest, Email: "guest@example.com"},
+	} {
+		if _, err := store.Insert("users", u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	start := now.Add(30 * time.Hour)
+	meeting := model.Meeting{
+		ID: primitive.NewObjectID(), InvestorID: investor, FounderID: founder, Participants: []primitive.ObjectID{guest},
+		Title: "Intro", StartTime: start, EndTime: start.Add(time.Hour), SeriesEnd: start.Add(time.Hour),
+		RSVPs: []model.RSVP{{UserID: guest, Status: "declined"}},
+	}
+	if _, err := store.Meetings().CreateMeeting(ctx, meeting); err != nil {
+		t.Fatal(err)
+	}
+	// a meeting that opted out of reminders
+	quiet := meeting
+	quiet.ID, quiet.Reminders = primitive.NewObjectID(), []int{}
+	if _, err := store.Meetings().CreateMeeting(ctx, quiet); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &outbox{}
+	reminders := NewReminders(store, sender, config.Reminders{Before: []time.Duration{time.Hour, 24 * time.Hour}})
+	send := func(at time.Time, want int) {
+		t.Helper()
+		sent, err := reminders.Send(ctx, at)
+		if err != nil || sent != want {
+			t.Fatalf("Send(%v) = %d, %v, want %d", at, sent, err, want)
+		}
+	}
+
+	send(now, 0)                      // 30 hours ahead: nothing is due
+	send(start.Add(-23*time.Hour), 1) // the day-before reminder
+	send(start.Add(-22*time.Hour), 0) // already sent
+	if len(sender.sent) != 2 {
+		t.Fatalf("sent %d emails, want one each to the investor and founder", len(sender.sent))
+	}
+	for _, msg := range sender.sent {
+		if msg.To == "guest@example.com" {
+			t.Error("the guest who declined was reminded")
+		}
+	}
+	notes := store.Find("notifications", bson.M{"founder_id": investor, "notification_type": "meeting_reminder"})
+	if len(notes) != 1 || !strings.Contains(notes[0]["message"].(string), "Tue, 03 Mar 2026 13:00 EST") {
+		t.Errorf("investor notifications = %v, want one in New York time", notes)
+	}
+
+	// the API was down until 10 minutes before the meeting: only the hour
+	// reminder goes out
+	send(start.Add(-10*time.Minute), 1)
+	send(start.Add(-5*time.Minute), 0)
+	send(start.Add(time.Minute), 0)
+	if len(sender.sent) != 4 {
+		t.Errorf("sent %d emails, want 4", len(sender.sent))
+	}
+}
+
+func TestDueReminder(t *testing.T) {
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	offsets := []time.Duration{24 * time.Hour, time.Hour}
+	for _, tc := range []struct {
+		now    time.Time
+		before time.Duration
+		due    bool
+	}{
+		{start.Add(-25 * time.Hour), 0, false},
+		{start.Add(-24 * time.Hour), 24 * time.Hour, true},
+		{start.Add(-2 * time.Hour), 24 * time.Hour, true},
+		{start.Add(-time.Hour), time.Hour, true},
+		{start, 0, false},
+	} {
+		if before, due := dueReminder(offsets, start, tc.now); before != tc.before || due != tc.due {
+			t.Errorf("dueReminder at %v = %v, %v, want %v, %v", tc.now, before, due, tc.before, tc.due)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"DBackend/internal/database"
+	"DBackend/internal/events"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Subscribe adds the API's subscribers to the bus: the timeline records
+// deal activity and the notifier tells founders what happened. Both write
+// under the event's ID, so an event delivered twice is recorded once.
+func Subscribe(bus *events.Bus, db database.Service) error {
+	t, n := timeline{db}, notifier{db}
+	return errors.Join(
+		events.On(bus, "timeline", t.dealAdded),
+		events.On(bus, "timeline", t.stageChanged),
+		events.On(bus, "timeline", t.investmentRecorded),
+		events.On(bus, "timeline", t.meetingScheduled),
+		events.On(bus, "notifications", n.dealAdded),
+		events.On(bus, "notifications", n.investmentRecorded),
+		events.On(bus, "notifications", n.grantApplicationSubmitted),
+	)
+}
+
+// DealWith returns the investor's deal with a founder. Deals name the
+// founder's startup profile, or the founder's user directly.
+func DealWith(ctx context.Context, db database.Service, investorID, founderUserID primitive.ObjectID) (*model.DealFlow, error) {
+	if founder, err := db.Founders().GetFounderByUserID(ctx, founderUserID); err == nil {
+		if deal, err := db.Deals().FindDeal(ctx, investorID, founder.ID); !errors.Is(err, database.ErrDealNotFound) {
+			return deal, err
+		}
+	}
+	return db.Deals().FindDeal(ctx, investorID, founderUserID)
+}
+
+// timeline puts events on the activity timeline of their deal
+type timeline struct{ db database.Service }
+
+func (t timeline) dealAdded(ctx context.Context, id primitive.ObjectID, e model.DealAdded) error {
+	return t.record(ctx, id, e.InvestorID, e.DealID, "deal", "Added new startup to deal flow")
+}
+
+func (t timeline) stageChanged(ctx context.Context, id primitive.ObjectID, e model.DealStageChanged) error {
+	return t.record(ctx, id, e.InvestorID, e.DealID, "deal_update", fmt.Sprintf("Deal stage updated to %s", e.To))
+}
+
+func (t timeline) investmentRecorded(ctx context.Context, id primitive.ObjectID, e model.InvestmentRecorded) error {
+	return t.record(ctx, id, e.InvestorID, e.DealID, "investment", fmt.Sprintf("Invested %.2f", e.Amount))
+}
+
+// meetingScheduled records meetings between an investor and a founder in
+// their deal flow; other meetings belong to no timeline
+func (t timeline) meetingScheduled(ctx context.Context, id primitive.ObjectID, e model.MeetingScheduled) error {
+	deal, err := DealWith(ctx, t.db, e.InvestorID, e.FounderID)
+	if errors.Is(err, database.ErrDealNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return t.record(ctx, id, deal.InvestorID, deal.ID, "meeting", fmt.Sprintf("Meeting scheduled: %s", e.Title))
+}
+
+// record adds the activity under the event's ID, dated when the event
+// happened. A deal deleted since has no timeline to add to.
+func (t timeline) record(ctx context.Context, id, investorID, dealID primitive.ObjectID, kind, description string) error {
+	err := t.db.Deals().AddActivity(ctx, model.Activity{
+		ID:          id,
+		InvestorID:  investorID,
+		DealID:      dealID,
+		Type:        kind,
+		Description: description,
+		Date:        id.Timestamp(),
+	})
+	if mongo.IsDuplicateKeyError(err) || errors.Is(err, database.ErrDealNotFound) {
+		return nil
+	}
+	return err
+}
+
+// notifier sends in-app notifications of events to the founders concerned
+type notifier struct{ db database.Service }
+
+func (n notifier) dealAdded(ctx context.Context, id primitive.ObjectID, e model.DealAdded) error {
+	return n.notify(ctx, id, e.StartupID, "deal", "Added to a deal flow", "Your deal has been added by an investor.")
+}
+
+func (n notifier) investmentRecorded(ctx context.Context, id primitive.ObjectID, e model.InvestmentRecorded) error {
+	return n.notify(ctx, id, e.FounderID, "investment", "New investment", fmt.Sprintf("An investor invested %.2f in your startup.", e.Amount))
+}
+
+func (n notifier) grantApplicationSubmitted(ctx context.Context, id primitive.ObjectID, e model.GrantApplicationSubmitted) error {
+	name := "a grant"
+	grant, err := n.db.Grants().GetGrantByID(ctx, e.GrantID)
+	if err == nil {
+		name = fmt.Sprintf("%q", grant.Name)
+	} else if !errors.Is(err, database.ErrGrantNotFound) {
+		return err
+	}
+	return n.notify(ctx, id, e.FounderID, "grant_application", "Application received", fmt.Sprintf("Your application for %s was received.", name))
+}
+
+// notify creates the notification under the event's ID
+func (n notifier) notify(ctx context.Context, id, recipient primitive.ObjectID, kind, title, message string) error {
+	_, err := n.db.Notifications().CreateNotification(ctx, model.Notification{
+		ID:               id,
+		FounderID:        recipient,
+		NotificationType: kind,
+		Title:            title,
+		Message:          message,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
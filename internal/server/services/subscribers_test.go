@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"DBackend/internal/config"
+	"DBackend/internal/database/memory"
+	"DBackend/internal/events"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSubscribersRecordEventsOnce(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	bus := events.New(store, config.Default().Events)
+	if err := Subscribe(bus, store); err != nil {
+		t.Fatal(err)
+	}
+
+	investor, founder := primitive.NewObjectID(), primitive.NewObjectID()
+	res, err := store.Deals().AddStartupToDealFlow(ctx, model.DealFlow{InvestorID: investor, StartupID: founder, Stage: "screening"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deal := res.InsertedID.(primitive.ObjectID)
+	if err := store.Outbox().Publish(ctx, model.InvestmentRecorded{DealID: deal, InvestorID: investor, FounderID: founder, Amount: 250}); err != nil {
+		t.Fatal(err)
+	}
+	// a deal deleted before its event is delivered has no timeline left
+	if err := store.Outbox().Publish(ctx, model.DealStageChanged{DealID: primitive.NewObjectID(), InvestorID: investor, To: "closed"}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := bus.Deliver(ctx); err != nil || n != 3 {
+		t.Fatalf("Deliver() = %d, %v; want 3 events", n, err)
+	}
+	if failed, _ := store.Outbox().ListEvents(ctx, model.EventPending, 10); len(failed) != 0 {
+		t.Fatalf("events left pending: %+v", failed)
+	}
+
+	// a replica that stopped before storing the deliveries hands them out
+	// again: replay them from another outbox to the same subscribers
+	replay := memory.New()
+	list, _ := store.Outbox().ListEvents(ctx, model.EventDelivered, 10)
+	for _, event := range list {
+		event.Status, event.Delivered = model.EventPending, []string{}
+		if _, err := replay.Insert("outbox", event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	again := events.New(replay, config.Default().Events)
+	if err := Subscribe(again, store); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := again.Deliver(ctx); n != 3 {
+		t.Fatalf("replayed %d events, want 3", n)
+	}
+	if failed, _ := replay.Outbox().ListEvents(ctx, model.EventPending, 10); len(failed) != 0 {
+		t.Errorf("redelivered events failed: %+v", failed)
+	}
+
+	for kind, want := range map[string]string{"deal": "Added new startup to deal flow", "investment": "Invested 250.00"} {
+		if n := len(store.Find("activities", bson.M{"deal_id": deal, "type": kind, "description": want})); n != 1 {
+			t.Errorf("%s activities = %d, want 1", kind, n)
+		}
+	}
+	if n := len(store.Find("activities", bson.M{})); n != 2 {
+		t.Errorf("activities = %d, want 2", n)
+	}
+	for _, kind := range []string{"deal", "investment"} {
+		if n := len(store.Find("notifications", bson.M{"founder_id": founder, "notification_type": kind})); n != 1 {
+			t.Errorf("%s notifications = %d, want 1", kind, n)
+		}
+	}
+}
+
+func TestTimelineSkipsMeetingsOutsideDeals(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	investor, founderUser := primitive.NewObjectID(), primitive.NewObjectID()
+	res, err := store.Deals().AddStartupToDealFlow(ctx, model.DealFlow{InvestorID: investor, StartupID: founderUser, Stage: "screening"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deal := res.InsertedID.(primitive.ObjectID)
+
+	tl := timeline{store}
+	id := primitive.NewObjectID()
+	if err := tl.meetingScheduled(ctx, id, model.MeetingScheduled{InvestorID: investor, FounderID: founderUser, Title: "Intro"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tl.meetingScheduled(ctx, primitive.NewObjectID(), model.MeetingScheduled{InvestorID: investor, FounderID: primitive.NewObjectID(), Title: "Cold call"}); err != nil {
+		t.Errorf("a meeting outside any deal failed: %v", err)
+	}
+	got := store.Find("activities", bson.M{})
+	if len(got) != 1 || got[0]["deal_id"] != deal || got[0]["description"] != "Meeting scheduled: Intro" || got[0]["_id"] != id {
+		t.Errorf("activities = %v, want the meeting on the deal's timeline", got)
+	}
+}
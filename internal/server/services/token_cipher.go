@@ -0,0 +1,59 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+)
+
+// TokenCipher encrypts OAuth tokens for storage with AES-256-GCM. Each token
+// is bound to the ID it is sealed with, so a ciphertext copied onto another
+// user's record fails to open.
+type TokenCipher struct {
+	aead cipher.AEAD
+}
+
+// NewTokenCipher derives the encryption key from secret
+func NewTokenCipher(secret string) (*TokenCipher, error) {
+	key := deriveKey(secret, "calendar token encryption")
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenCipher{aead: aead}, nil
+}
+
+// Seal encrypts token for the record identified by id
+func (t *TokenCipher) Seal(id []byte, token string) ([]byte, error) {
+	nonce := make([]byte, t.aead.NonceSize(), t.aead.NonceSize()+len(token)+t.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return t.aead.Seal(nonce, nonce, []byte(token), id), nil
+}
+
+// Open decrypts a token sealed for id
+func (t *TokenCipher) Open(id, sealed []byte) (string, error) {
+	if len(sealed) < t.aead.NonceSize() {
+		return "", errors.New("sealed token is too short")
+	}
+	nonce, ciphertext := sealed[:t.aead.NonceSize()], sealed[t.aead.NonceSize():]
+	token, err := t.aead.Open(nil, nonce, ciphertext, id)
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
+
+// deriveKey returns a 32-byte key for purpose, so one configured secret can
+// back several independent keys
+func deriveKey(secret, purpose string) []byte {
+	sum := sha256.Sum256([]byte(purpose + "\x00" + secret))
+	return sum[:]
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"DBackend/internal/database"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Valuations snapshots the value of every investor's portfolio once a day,
+// building the history the performance charts are drawn from
+type Valuations struct {
+	db database.Service
+}
+
+// NewValuations creates the portfolio valuation snapshotter
+func NewValuations(db database.Service) *Valuations {
+	return &Valuations{db: db}
+}
+
+// Run snapshots the portfolios as of today
+func (v *Valuations) Run(ctx context.Context) error {
+	count, err := v.Snapshot(ctx, time.Now())
+	if count > 0 {
+		slog.InfoContext(ctx, "recorded portfolio valuations", "count", count)
+	}
+	return err
+}
+
+// Snapshot records each investor's portfolio value for the UTC day of now
+// and returns how many it recorded. Running it again the same day replaces
+// that day's snapshots, so a retried run does not add duplicates.
+func (v *Valuations) Snapshot(ctx context.Context, now time.Time) (int, error) {
+	day := now.UTC().Truncate(24 * time.Hour)
+	count := 0
+	after := primitive.NilObjectID
+	for {
+		investors, err := v.db.Investors().ListInvestors(ctx, after, pageSize)
+		if err != nil {
+			return count, fmt.Errorf("listing investors: %w", err)
+		}
+		for _, investor := range investors {
+			if _, err := v.db.Investors().RecordValuation(ctx, investor.UserID, day); err != nil {
+				return count, fmt.Errorf("valuing portfolio of investor %s: %w", investor.UserID.Hex(), err)
+			}
+			count++
+		}
+		if len(investors) < pageSize {
+			return count, nil
+		}
+		after = investors[len(investors)-1].ID
+	}
+}
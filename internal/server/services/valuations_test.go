@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"DBackend/internal/database/memory"
+	"DBackend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestValuationsSnapshotOncePerDay(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	investor, empty, startup, grown := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	for _, id := range []primitive.ObjectID{investor, empty} {
+		if _, err := store.Insert("investors", model.Investor{UserID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.Insert("founders", bson.M{"user_id": grown, "startup_name": "Sunly", "growth_rate": 50}); err != nil {
+		t.Fatal(err)
+	}
+	for _, inv := range []struct {
+		startup primitive.ObjectID
+		amount  float64
+	}{{startup, 1000}, {grown, 2000}, {grown, 1000}} {
+		if _, err := store.Investors().UpdateInvestorPortfolio(ctx, investor, inv.startup, inv.amount); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	valuations := NewValuations(store)
+	now := time.Date(2026, 3, 2, 18, 30, 0, 0, time.UTC)
+	for _, at := range []time.Time{now, now.Add(time.Hour)} {
+		if n, err := valuations.Snapshot(ctx, at); err != nil || n != 2 {
+			t.Fatalf("Snapshot(%v) = %d, %v, want 2", at, n, err)
+		}
+	}
+
+	var snapshots []model.PortfolioValuation
+	for _, doc := range store.Find("portfolio_valuations", bson.M{"investor_id": investor}) {
+		var v model.PortfolioValuation
+		b, _ := bson.Marshal(doc)
+		if err := bson.Unmarshal(b, &v); err != nil {
+			t.Fatal(err)
+		}
+		snapshots = append(snapshots, v)
+	}
+	if len(snapshots) != 1 {
+		t.Fatalf("%d snapshots on one day, want 1", len(snapshots))
+	}
+	v := snapshots[0]
+	if !v.Date.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("snapshot dated %v, want the start of the day", v.Date)
+	}
+	// 1000 at cost plus 3000 grown by half
+	if v.Value != 5500 || len(v.Assets) != 2 || v.Assets[1].StartupName != "Sunly" || v.Assets[1].Value != 4500 {
+		t.Errorf("snapshot = %+v", v)
+	}
+
+	if n, err := valuations.Snapshot(ctx, now.Add(24*time.Hour)); err != nil || n != 2 {
+		t.Fatalf("next day's Snapshot = %d, %v", n, err)
+	}
+	if got := len(store.Find("portfolio_valuations", bson.M{"investor_id": investor})); got != 2 {
+		t.Errorf("%d snapshots after two days, want 2", got)
+	}
+}
@@ -0,0 +1,85 @@
+// Package tracing configures OpenTelemetry for the API. Setup installs the
+// global tracer provider and W3C trace context propagation; instrumented code
+// then starts spans from the context it was handed:
+//
+//	ctx, span := tracing.Tracer().Start(c.UserContext(), "score deal")
+//	defer span.End()
+//
+// Incoming requests, MongoDB commands and outbound HTTP calls made through
+// Transport are traced without further code.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"DBackend/internal/config"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies spans started by the API's own code
+const instrumentationName = "DBackend"
+
+// Tracer returns the tracer for spans the API starts itself
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup installs the tracer provider described by cfg and returns a function
+// that flushes buffered spans and stops it. Spans are exported over OTLP/HTTP
+// when cfg.Endpoint is set and written to stdout otherwise. With tracing
+// disabled the no-op provider stays in place, but trace context from callers
+// is still propagated.
+func Setup(ctx context.Context, cfg config.Tracing, stdout io.Writer) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newExporter(ctx, cfg, stdout)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: creating exporter: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(cfg.ServiceName),
+		)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, cfg config.Tracing, stdout io.Writer) (sdktrace.SpanExporter, error) {
+	if cfg.Endpoint == "" {
+		return stdouttrace.New(stdouttrace.WithWriter(stdout))
+	}
+	// Like OTEL_EXPORTER_OTLP_ENDPOINT, the endpoint is the collector's base URL
+	return otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(strings.TrimSuffix(cfg.Endpoint, "/")+"/v1/traces"),
+	)
+}
+
+// Transport wraps base, or http.DefaultTransport when nil, so every request
+// it sends gets a client span and carries the trace context downstream
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
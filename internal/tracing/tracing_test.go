@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"DBackend/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func restoreGlobals(t *testing.T) {
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+}
+
+func TestSetupWritesSpansToStdoutWithoutEndpoint(t *testing.T) {
+	restoreGlobals(t)
+	var out bytes.Buffer
+	shutdown, err := Setup(context.Background(), config.Tracing{Enabled: true, ServiceName: "dbackend-test", SampleRatio: 1}, &out)
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+
+	_, span := Tracer().Start(context.Background(), "score deal")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown error = %v", err)
+	}
+	if !strings.Contains(out.String(), `"Name":"score deal"`) || !strings.Contains(out.String(), "dbackend-test") {
+		t.Errorf("stdout = %s, want the span and service name", out.String())
+	}
+}
+
+func TestSetupDisabledRecordsNothing(t *testing.T) {
+	restoreGlobals(t)
+	var out bytes.Buffer
+	shutdown, err := Setup(context.Background(), config.Tracing{SampleRatio: 1}, &out)
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	_, span := Tracer().Start(context.Background(), "ignored")
+	span.End()
+	if span.SpanContext().IsValid() {
+		t.Errorf("span context is valid with tracing disabled")
+	}
+	if err := shutdown(context.Background()); err != nil || out.Len() != 0 {
+		t.Errorf("shutdown error = %v, output %q", err, out.String())
+	}
+}
+
+func TestTransportPropagatesTrace(t *testing.T) {
+	restoreGlobals(t)
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent string
+	ml := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer ml.Close()
+
+	ctx, parent := Tracer().Start(context.Background(), "match")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, ml.URL, nil)
+	resp, err := (&http.Client{Transport: Transport(nil)}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	parent.End()
+
+	if !strings.Contains(traceparent, parent.SpanContext().TraceID().String()) {
+		t.Errorf("traceparent = %q, want trace %s", traceparent, parent.SpanContext().TraceID())
+	}
+	spans := recorder.Ended()
+	if len(spans) != 2 || spans[0].Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("spans = %v, want a client span under match", spans)
+	}
+}
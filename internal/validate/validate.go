@@ -0,0 +1,336 @@
+// Package validate checks request bodies against rules declared in struct
+// tags, so handlers reject bad input before it reaches the database:
+//
+//	type scheduleRequest struct {
+//		Title     string    `json:"title" validate:"required,max=200"`
+//		StartTime time.Time `json:"start_time" validate:"required,future"`
+//		EndTime   time.Time `json:"end_time" validate:"required,after=StartTime"`
+//	}
+//
+// Rules are comma-separated and take an optional =param. Every rule except
+// required passes on a zero value, so optional fields are only checked when
+// set. Fields are reported by their json (or form) name, and nested structs
+// and slices of structs are checked with dotted paths such as documents[0].url.
+package validate
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"DBackend/internal/apperror"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Rule checks one field. It returns an empty string when value is valid and
+// otherwise a message such as "must be a valid email". param is the text after
+// = in the tag and parent is the struct holding the field.
+type Rule func(value reflect.Value, param string, parent reflect.Value) string
+
+// Roles lists the roles a user may register with
+var Roles = []string{"founder", "investor"}
+
+var (
+	mu    sync.RWMutex
+	rules = map[string]Rule{
+		"required": required,
+		"min":      minimum,
+		"max":      maximum,
+		"oneof":    oneOf,
+		"email":    email,
+		"url":      httpURL,
+		"objectid": objectID,
+		"role":     role,
+		"future":   future,
+		"after":    after,
+		"amount":   amount,
+		"timezone": timezone,
+		"clock":    clock,
+	}
+)
+
+// Register adds a custom rule, replacing any rule with the same name
+func Register(name string, rule Rule) {
+	mu.Lock()
+	defer mu.Unlock()
+	rules[name] = rule
+}
+
+// Struct validates v, which must be a struct or a pointer to one, and returns
+// an apperror validation error listing every invalid field
+func Struct(v interface{}) error {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validate: %T is not a struct", v))
+	}
+	var fields []apperror.FieldError
+	check(value, "", &fields)
+	if len(fields) > 0 {
+		return apperror.Invalid(fields)
+	}
+	return nil
+}
+
+func check(parent reflect.Value, prefix string, fields *[]apperror.FieldError) {
+	t := parent.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		value := parent.Field(i)
+		name := prefix + fieldName(sf)
+
+		if tag := sf.Tag.Get("validate"); tag != "" && tag != "-" {
+			for _, spec := range strings.Split(tag, ",") {
+				ruleName, param, _ := strings.Cut(spec, "=")
+				if ruleName != "required" && isZero(value) {
+					continue
+				}
+				if msg := lookup(ruleName)(deref(value), param, parent); msg != "" {
+					*fields = append(*fields, apperror.FieldError{Field: name, Rule: ruleName, Message: msg})
+					break
+				}
+			}
+		}
+		nested(deref(value), name, fields)
+	}
+}
+
+// nested descends into struct and slice-of-struct fields
+func nested(value reflect.Value, name string, fields *[]apperror.FieldError) {
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.Type() != reflect.TypeOf(time.Time{}) {
+			check(value, name+".", fields)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if item := deref(value.Index(i)); item.Kind() == reflect.Struct {
+				check(item, fmt.Sprintf("%s[%d].", name, i), fields)
+			}
+		}
+	}
+}
+
+func lookup(name string) Rule {
+	mu.RLock()
+	defer mu.RUnlock()
+	rule, ok := rules[name]
+	if !ok {
+		panic("validate: unknown rule " + strconv.Quote(name))
+	}
+	return rule
+}
+
+// fieldName returns the name clients use for a field
+func fieldName(sf reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		if name, _, _ := strings.Cut(sf.Tag.Get(key), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+func deref(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return value
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+func isZero(value reflect.Value) bool {
+	value = deref(value)
+	if !value.IsValid() {
+		return true
+	}
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return value.IsNil()
+	}
+	if t, ok := value.Interface().(time.Time); ok {
+		return t.IsZero()
+	}
+	return value.IsZero()
+}
+
+func required(value reflect.Value, _ string, _ reflect.Value) string {
+	if isZero(value) {
+		return "is required"
+	}
+	if value.Kind() == reflect.String && strings.TrimSpace(value.String()) == "" {
+		return "is required"
+	}
+	return ""
+}
+
+// size returns the measure min and max compare: runes for strings, length
+// for collections and the value itself for numbers
+func size(value reflect.Value) (float64, string, bool) {
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), " characters", true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(value.Len()), " items", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), "", true
+	}
+	return 0, "", false
+}
+
+func bound(param string) float64 {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		panic("validate: bad bound " + strconv.Quote(param))
+	}
+	return n
+}
+
+func minimum(value reflect.Value, param string, _ reflect.Value) string {
+	n, unit, ok := size(value)
+	if ok && n < bound(param) {
+		return "must be at least " + param + unit
+	}
+	return ""
+}
+
+func maximum(value reflect.Value, param string, _ reflect.Value) string {
+	n, unit, ok := size(value)
+	if ok && n > bound(param) {
+		return "must be at most " + param + unit
+	}
+	return ""
+}
+
+func oneOf(value reflect.Value, param string, _ reflect.Value) string {
+	options := strings.Fields(param)
+	if value.Kind() == reflect.String {
+		for _, option := range options {
+			if value.String() == option {
+				return ""
+			}
+		}
+	}
+	return "must be one of " + strings.Join(options, ", ")
+}
+
+func email(value reflect.Value, _ string, _ reflect.Value) string {
+	if value.Kind() == reflect.String {
+		if addr, err := mail.ParseAddress(value.String()); err == nil && addr.Address == value.String() {
+			return ""
+		}
+	}
+	return "must be a valid email address"
+}
+
+func httpURL(value reflect.Value, _ string, _ reflect.Value) string {
+	if value.Kind() == reflect.String {
+		u, err := url.Parse(value.String())
+		if err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+			return ""
+		}
+	}
+	return "must be an http or https URL"
+}
+
+func objectID(value reflect.Value, _ string, _ reflect.Value) string {
+	switch v := value.Interface().(type) {
+	case primitive.ObjectID:
+		return ""
+	case string:
+		if primitive.IsValidObjectID(v) {
+			return ""
+		}
+	}
+	return "must be a valid ObjectID"
+}
+
+func role(value reflect.Value, _ string, parent reflect.Value) string {
+	return oneOf(value, strings.Join(Roles, " "), parent)
+}
+
+func future(value reflect.Value, _ string, _ reflect.Value) string {
+	if t, ok := value.Interface().(time.Time); ok && t.After(time.Now()) {
+		return ""
+	}
+	return "must be in the future"
+}
+
+// after compares a time with the sibling field named by param
+func after(value reflect.Value, param string, parent reflect.Value) string {
+	other := parent.FieldByName(param)
+	if !other.IsValid() {
+		panic("validate: after refers to unknown field " + strconv.Quote(param))
+	}
+	switch t := value.Interface().(type) {
+	case time.Time:
+		if start, _ := deref(other).Interface().(time.Time); start.IsZero() || t.After(start) {
+			return ""
+		}
+	case string:
+		// HH:MM clock times sort as strings
+		if start, _ := deref(other).Interface().(string); start == "" || t > start {
+			return ""
+		}
+	}
+	sf, _ := parent.Type().FieldByName(param)
+	return "must be after " + fieldName(sf)
+}
+
+// amount accepts positive, finite currency values with at most two decimals
+func amount(value reflect.Value, _ string, _ reflect.Value) string {
+	var n float64
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		n = value.Float()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(value.Int())
+	default:
+		return "must be a number"
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) || n <= 0 {
+		return "must be a positive amount"
+	}
+	if cents := n * 100; math.Abs(cents-math.Round(cents)) > 1e-6 {
+		return "must have at most two decimal places"
+	}
+	return ""
+}
+
+// timezone accepts IANA time zone names such as Europe/Berlin
+func timezone(value reflect.Value, _ string, _ reflect.Value) string {
+	if value.Kind() == reflect.String && value.String() != "Local" {
+		if _, err := time.LoadLocation(value.String()); err == nil {
+			return ""
+		}
+	}
+	return "must be an IANA time zone such as Europe/Berlin"
+}
+
+// clock accepts a 24-hour wall clock time written HH:MM
+func clock(value reflect.Value, _ string, _ reflect.Value) string {
+	if value.Kind() == reflect.String && len(value.String()) == 5 {
+		if _, err := time.Parse("15:04", value.String()); err == nil {
+			return ""
+		}
+	}
+	return "must be a time written HH:MM"
+}
@@ -0,0 +1,153 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"DBackend/internal/apperror"
+)
+
+type item struct {
+	URL string `json:"url" validate:"required,url"`
+}
+
+type request struct {
+	Email     string     `json:"email" validate:"required,email"`
+	Password  string     `json:"password" validate:"required,min=8"`
+	Role      string     `json:"role" validate:"role"`
+	UserID    string     `json:"user_id" validate:"objectid"`
+	Priority  string     `form:"priority" validate:"oneof=low high"`
+	Amount    float64    `json:"amount" validate:"amount"`
+	Score     *float64   `json:"score" validate:"min=0,max=100"`
+	StartTime time.Time  `json:"start_time" validate:"future"`
+	EndTime   time.Time  `json:"end_time" validate:"after=StartTime"`
+	Items     []item     `json:"items"`
+	Skipped   string     `json:"-" validate:"required"`
+	Nested    *item      `json:"nested"`
+	Deadline  *time.Time `json:"deadline" validate:"future"`
+	Timezone  string     `json:"timezone" validate:"timezone"`
+	Opens     string     `json:"opens" validate:"clock"`
+	Closes    string     `json:"closes" validate:"clock,after=Opens"`
+}
+
+func fields(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || appErr.Kind != apperror.KindValidation {
+		t.Fatalf("Struct() error = %v, want validation error", err)
+	}
+	got := map[string]string{}
+	for _, f := range appErr.Fields {
+		got[f.Field] = f.Rule
+	}
+	return got
+}
+
+func valid() request {
+	now := time.Now()
+	return request{
+		Email:     "ada@example.com",
+		Password:  "correct horse",
+		Role:      "founder",
+		UserID:    "64b7f0f0f0f0f0f0f0f0f0f0",
+		Priority:  "high",
+		Amount:    1250.50,
+		StartTime: now.Add(time.Hour),
+		EndTime:   now.Add(2 * time.Hour),
+		Items:     []item{{URL: "https://example.com/deck.pdf"}},
+		Skipped:   "x",
+		Timezone:  "Europe/Berlin",
+		Opens:     "09:30",
+		Closes:    "17:00",
+	}
+}
+
+func TestStructAcceptsValidInput(t *testing.T) {
+	r := valid()
+	if err := Struct(&r); err != nil {
+		t.Fatalf("Struct() error = %v", err)
+	}
+	// optional fields are only checked when set
+	if err := Struct(request{Email: "a@b.co", Password: "12345678", Skipped: "x"}); err != nil {
+		t.Fatalf("Struct() with optional fields empty error = %v", err)
+	}
+}
+
+func TestStructReportsEveryInvalidField(t *testing.T) {
+	score := 101.0
+	past := time.Now().Add(-time.Hour)
+	r := valid()
+	r.Email = "Ada <ada@example.com>"
+	r.Password = "short"
+	r.Role = "admin"
+	r.UserID = "42"
+	r.Priority = "urgent"
+	r.Amount = 10.005
+	r.Score = &score
+	r.StartTime = past
+	r.EndTime = past.Add(-time.Minute)
+	r.Items = []item{{URL: "javascript:alert(1)"}}
+	r.Nested = &item{}
+	r.Deadline = &past
+	r.Timezone = "Mars/Olympus_Mons"
+	r.Opens = "9:30"
+	r.Closes = "09:00"
+
+	want := map[string]string{
+		"email":        "email",
+		"password":     "min",
+		"role":         "role",
+		"user_id":      "objectid",
+		"priority":     "oneof",
+		"amount":       "amount",
+		"score":        "max",
+		"start_time":   "future",
+		"end_time":     "after",
+		"items[0].url": "url",
+		"nested.url":   "required",
+		"deadline":     "future",
+		"timezone":     "timezone",
+		"opens":        "clock",
+		"closes":       "after",
+	}
+	if got := fields(t, Struct(r)); !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %v, want %v", got, want)
+	}
+}
+
+func TestRequiredRejectsBlankStrings(t *testing.T) {
+	got := fields(t, Struct(request{Email: "   ", Password: "12345678", Skipped: "x"}))
+	if got["email"] != "required" {
+		t.Errorf("fields = %v, want email required", got)
+	}
+}
+
+func TestMessageNamesFields(t *testing.T) {
+	r := valid()
+	r.Password = ""
+	err := Struct(r)
+	if err == nil || !strings.Contains(err.Error(), "password is required") {
+		t.Errorf("Struct() error = %v, want it to mention password", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("even", func(value reflect.Value, _ string, _ reflect.Value) string {
+		if value.Int()%2 != 0 {
+			return "must be even"
+		}
+		return ""
+	})
+	type counter struct {
+		N int `json:"n" validate:"even"`
+	}
+	if err := Struct(counter{N: 2}); err != nil {
+		t.Errorf("Struct(2) error = %v", err)
+	}
+	if got := fields(t, Struct(counter{N: 3})); got["n"] != "even" {
+		t.Errorf("fields = %v, want n even", got)
+	}
+}
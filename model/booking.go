@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Availability is when an investor takes meetings booked through their
+// booking links: weekly windows in the investor's timezone, split into slots
+// of Duration minutes that keep Buffer minutes free around other meetings
+type Availability struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	InvestorID primitive.ObjectID `bson:"investor_id" json:"investor_id"`
+	Timezone   string             `bson:"timezone" json:"timezone" validate:"required,timezone"`
+	Rules      []AvailabilityRule `bson:"rules" json:"rules" validate:"required,max=50"`
+	Duration   int                `bson:"duration" json:"duration" validate:"required,min=10,max=480"` // minutes
+	Buffer     int                `bson:"buffer" json:"buffer" validate:"min=0,max=240"`               // minutes
+	MinNotice  int                `bson:"min_notice" json:"min_notice" validate:"min=0,max=10080"`     // minutes before a slot it can still be booked
+	Horizon    int                `bson:"horizon" json:"horizon" validate:"min=0,max=90"`              // days ahead slots are offered, 14 when zero
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// AvailabilityRule is a window on one weekday, as HH:MM wall clock times
+type AvailabilityRule struct {
+	Day   string `bson:"day" json:"day" validate:"required,oneof=monday tuesday wednesday thursday friday saturday sunday"`
+	Start string `bson:"start" json:"start" validate:"required,clock"`
+	End   string `bson:"end" json:"end" validate:"required,clock,after=Start"`
+}
+
+// BookingLink lets founders in an investor's deal flow book a meeting with
+// them in a free slot. The slug is the link's public name.
+type BookingLink struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	InvestorID primitive.ObjectID `bson:"investor_id" json:"investor_id"`
+	Slug       string             `bson:"slug" json:"slug"`
+	Title      string             `bson:"title" json:"title" validate:"required,max=200"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
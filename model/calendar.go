@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CalendarGoogle is the provider name of Google Calendar connections
+const CalendarGoogle = "google"
+
+// CalendarConnection links a user to their own calendar account. The refresh
+// token is encrypted before it is stored and is never sent to clients.
+type CalendarConnection struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID       primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Provider     string             `bson:"provider" json:"provider"`
+	RefreshToken []byte             `bson:"refresh_token" json:"-"`
+	ConnectedAt  time.Time          `bson:"connected_at" json:"connected_at"`
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// CalendarFeed is a user's subscribable iCalendar feed. Only a hash of the
+// secret token in the feed URL is stored; rotating the token replaces it.
+type CalendarFeed struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	TokenHash string             `bson:"token_hash" json:"-"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	RotatedAt time.Time          `bson:"rotated_at" json:"rotated_at"`
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Dashboard responses use camelCase JSON keys throughout; the OpenAPI
+// schemas served to the frontend are generated from these types.
+
+// InvestorDashboard is the response of the investor dashboard endpoint
+type InvestorDashboard struct {
+	PortfolioSummary PortfolioSummary `json:"portfolioSummary"`
+	PipelineSummary  PipelineSummary  `json:"pipelineSummary"`
+	RecentActivities []RecentActivity `json:"recentActivities"`
+}
+
+// RecentActivity is an entry in an investor's activity feed
+type RecentActivity struct {
+	ID          primitive.ObjectID `json:"id"`
+	Type        string             `json:"type"`
+	Description string             `json:"description"`
+	Date        time.Time          `json:"date"`
+}
+
+// FounderDashboard is the response of the founder dashboard endpoint
+type FounderDashboard struct {
+	FundraisingSummary FundraisingSummary `json:"fundraisingSummary"`
+	InvestorEngagement InvestorEngagement `json:"investorEngagement"`
+}
+
+// FundraisingSummary represents a founder's progress towards their funding goal
+type FundraisingSummary struct {
+	TotalRaised        float64 `json:"totalRaised"`
+	FundingGoal        float64 `json:"fundingGoal"`
+	PercentageComplete int     `json:"percentageComplete"`
+	NumberOfInvestors  int     `json:"numberOfInvestors"`
+	AverageInvestment  float64 `json:"averageInvestment"`
+}
+
+// InvestorEngagement represents how investors are engaging with a founder
+type InvestorEngagement struct {
+	TotalMatches   int        `json:"totalMatches"`
+	NewThisMonth   int        `json:"newThisMonth"`
+	InDueDiligence int        `json:"inDueDiligence"`
+	TopMatches     []TopMatch `json:"topMatches"`
+}
+
+// TopMatch represents a strongly matched investor on a founder's dashboard
+type TopMatch struct {
+	InvestorID            primitive.ObjectID `json:"investorId"`
+	Name                  string             `json:"name"`
+	MatchPercentage       float64            `json:"matchPercentage"`
+	Industry              string             `json:"industry"`
+	TotalInvested         float64            `json:"totalInvested"`
+	PreferredFundingStage string             `json:"preferredFundingStage"`
+}
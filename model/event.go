@@ -0,0 +1,129 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Domain event types
+const (
+	EventDealAdded                 = "deal_added"
+	EventDealStageChanged          = "deal_stage_changed"
+	EventInvestmentRecorded        = "investment_recorded"
+	EventGrantApplicationSubmitted = "grant_application_submitted"
+	EventMeetingScheduled          = "meeting_scheduled"
+)
+
+// Delivery statuses of an event in the outbox
+const (
+	EventPending   = "pending"
+	EventDelivered = "delivered"
+	EventFailed    = "failed" // retries ran out; waits to be retried by hand
+)
+
+// DomainEvent is something that happened which other parts of the API react
+// to, such as the timeline and notifications
+type DomainEvent interface {
+	EventType() string
+}
+
+// Event is a domain event in the outbox. It is written with the change it
+// records and then delivered to every subscriber of its type at least once.
+type Event struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Type        string             `bson:"type" json:"type"`
+	Payload     bson.M             `bson:"payload" json:"payload"`
+	Status      string             `bson:"status" json:"status" validate:"oneof=pending delivered failed"`
+	Delivered   []string           `bson:"delivered" json:"delivered"` // subscribers that handled it
+	Attempt     int                `bson:"attempt" json:"attempt"`     // failed deliveries so far
+	NextAttempt time.Time          `bson:"next_attempt" json:"next_attempt"`
+	LeaseOwner  string             `bson:"lease_owner" json:"lease_owner,omitempty"`
+	LeaseUntil  time.Time          `bson:"lease_until" json:"lease_until"`
+	LastError   string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	DeliveredAt time.Time          `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+}
+
+// NewEvent wraps a domain event for the outbox, due for delivery at once
+func NewEvent(e DomainEvent) (Event, error) {
+	raw, err := bson.Marshal(e)
+	if err != nil {
+		return Event{}, err
+	}
+	var payload bson.M
+	if err := bson.Unmarshal(raw, &payload); err != nil {
+		return Event{}, err
+	}
+	now := time.Now()
+	return Event{
+		ID:          primitive.NewObjectID(),
+		Type:        e.EventType(),
+		Payload:     payload,
+		Status:      EventPending,
+		Delivered:   []string{},
+		NextAttempt: now,
+		CreatedAt:   now,
+	}, nil
+}
+
+// Decode reads the event's payload into v, a pointer to its domain event
+func (e Event) Decode(v interface{}) error {
+	raw, err := bson.Marshal(e.Payload)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(raw, v)
+}
+
+// DealAdded is published when an investor adds a startup to their deal flow
+type DealAdded struct {
+	DealID     primitive.ObjectID `bson:"deal_id" json:"deal_id"`
+	InvestorID primitive.ObjectID `bson:"investor_id" json:"investor_id"`
+	StartupID  primitive.ObjectID `bson:"startup_id" json:"startup_id"`
+}
+
+func (DealAdded) EventType() string { return EventDealAdded }
+
+// DealStageChanged is published when a deal moves to another pipeline stage
+type DealStageChanged struct {
+	DealID     primitive.ObjectID `bson:"deal_id" json:"deal_id"`
+	InvestorID primitive.ObjectID `bson:"investor_id" json:"investor_id"`
+	StartupID  primitive.ObjectID `bson:"startup_id" json:"startup_id"`
+	From       string             `bson:"from" json:"from"`
+	To         string             `bson:"to" json:"to"`
+}
+
+func (DealStageChanged) EventType() string { return EventDealStageChanged }
+
+// InvestmentRecorded is published when an investor invests through a deal
+type InvestmentRecorded struct {
+	InvestmentID primitive.ObjectID `bson:"investment_id" json:"investment_id"`
+	DealID       primitive.ObjectID `bson:"deal_id" json:"deal_id"`
+	InvestorID   primitive.ObjectID `bson:"investor_id" json:"investor_id"`
+	FounderID    primitive.ObjectID `bson:"founder_id" json:"founder_id"` // the deal's startup
+	Amount       float64            `bson:"amount" json:"amount"`
+}
+
+func (InvestmentRecorded) EventType() string { return EventInvestmentRecorded }
+
+// GrantApplicationSubmitted is published when a founder applies for a grant
+type GrantApplicationSubmitted struct {
+	ApplicationID primitive.ObjectID `bson:"application_id" json:"application_id"`
+	GrantID       primitive.ObjectID `bson:"grant_id" json:"grant_id"`
+	FounderID     primitive.ObjectID `bson:"founder_id" json:"founder_id"` // the applicant's user
+}
+
+func (GrantApplicationSubmitted) EventType() string { return EventGrantApplicationSubmitted }
+
+// MeetingScheduled is published when a meeting is scheduled or booked
+type MeetingScheduled struct {
+	MeetingID  primitive.ObjectID `bson:"meeting_id" json:"meeting_id"`
+	InvestorID primitive.ObjectID `bson:"investor_id" json:"investor_id"`
+	FounderID  primitive.ObjectID `bson:"founder_id" json:"founder_id"`
+	Title      string             `bson:"title" json:"title"`
+	StartTime  time.Time          `bson:"start_time" json:"start_time"`
+}
+
+func (MeetingScheduled) EventType() string { return EventMeetingScheduled }
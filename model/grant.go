@@ -9,9 +9,9 @@ import (
 // Grant represents a funding grant opportunity
 type Grant struct {
     ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-    Name        string             `bson:"name" json:"name"`
+    Name        string             `bson:"name" json:"name" validate:"required,max=200"`
     Description string             `bson:"description" json:"description"`
-    Amount      float64            `bson:"amount" json:"amount"`
+    Amount      float64            `bson:"amount" json:"amount" validate:"amount"`
     Category    string             `bson:"category" json:"category"`
     Region      string             `bson:"region" json:"region"`
     Deadline    time.Time          `bson:"deadline" json:"deadline"`
@@ -24,12 +24,12 @@ type Grant struct {
 type GrantApplication struct {
     ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
     FounderID       primitive.ObjectID `bson:"founder_id" json:"founder_id"`
-    GrantID         int                `bson:"grant_id" json:"grant_id"`
-    StartupName     string             `bson:"startup_name" json:"startup_name"`
-    ContactEmail    string             `bson:"contact_email" json:"contact_email"`
+    GrantID         primitive.ObjectID `bson:"grant_id" json:"grant_id" validate:"required"`
+    StartupName     string             `bson:"startup_name" json:"startup_name" validate:"required,max=200"`
+    ContactEmail    string             `bson:"contact_email" json:"contact_email" validate:"email"`
     ContactPhone    string             `bson:"contact_phone" json:"contact_phone"`
     Description     string             `bson:"description" json:"description"`
-    Website         string             `bson:"website" json:"website"`
+    Website         string             `bson:"website" json:"website" validate:"url"`
     TeamSize        string             `bson:"team_size" json:"team_size"`
     PreviousFunding string             `bson:"previous_funding" json:"previous_funding"`
     PitchDeckPath   string             `bson:"pitch_deck_path" json:"pitch_deck_path"`
@@ -10,8 +10,8 @@ import (
 type InvestorApplication struct {
     ID            primitive.ObjectID `bson:"_id,omitempty"`
     FounderID     primitive.ObjectID `bson:"founder_id"`
-    InvestorID    primitive.ObjectID `bson:"investor_id"`
-    FundingAmount float64            `bson:"funding_amount"`
+    InvestorID    primitive.ObjectID `bson:"investor_id" validate:"required"`
+    FundingAmount float64            `bson:"funding_amount" validate:"required,amount"`
     UseOfFunds    string             `bson:"use_of_funds"`
     Status        string             `bson:"status,omitempty"` // Pending, Approved, Rejected
     CreatedAt     time.Time          `bson:"created_at,omitempty"`
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Job is the schedule and state of a background job, shared by every API
+// replica. A replica runs the job while it holds the lease.
+type Job struct {
+	Name       string    `bson:"_id" json:"name"`
+	Schedule   string    `bson:"schedule" json:"schedule"` // cron expression, in UTC
+	NextRun    time.Time `bson:"next_run" json:"next_run"`
+	Attempt    int       `bson:"attempt" json:"attempt"` // failed attempts at the current run
+	LeaseOwner string    `bson:"lease_owner" json:"lease_owner,omitempty"`
+	LeaseUntil time.Time `bson:"lease_until" json:"lease_until"`
+	LastRun    time.Time `bson:"last_run,omitempty" json:"last_run,omitempty"`
+	LastStatus string    `bson:"last_status,omitempty" json:"last_status,omitempty" validate:"omitempty,oneof=succeeded failed interrupted"`
+	LastError  string    `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	UpdatedAt  time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// JobRun is one attempt at running a job
+type JobRun struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Job          string             `bson:"job" json:"job"`
+	Owner        string             `bson:"owner" json:"owner"` // replica that ran it
+	Attempt      int                `bson:"attempt" json:"attempt"`
+	Status       string             `bson:"status" json:"status" validate:"oneof=succeeded failed interrupted"`
+	Error        string             `bson:"error,omitempty" json:"error,omitempty"`
+	ScheduledFor time.Time          `bson:"scheduled_for" json:"scheduled_for"`
+	StartedAt    time.Time          `bson:"started_at" json:"started_at"`
+	FinishedAt   time.Time          `bson:"finished_at" json:"finished_at"`
+}
+
+// DeadlineAlert records that a user was warned of a deadline, so they are
+// warned once
+type DeadlineAlert struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	SubjectID primitive.ObjectID `bson:"subject_id"` // the task or grant
+	UserID    primitive.ObjectID `bson:"user_id"`
+	Deadline  time.Time          `bson:"deadline"`
+	SentAt    time.Time          `bson:"sent_at"`
+}
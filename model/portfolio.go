@@ -27,22 +27,46 @@ type PortfolioAsset struct {
 
 // PipelineSummary represents a summary of an investor's deal pipeline
 type PipelineSummary struct {
-    TotalDeals     int           `json:"totalDeals"`
-    PendingDeals   int           `json:"pendingDeals"`
-    CompletedDeals int           `json:"completedDeals"`
-    RecentDeals    []PipelineDeal `json:"recentDeals"`
+    TotalDeals   int            `json:"totalDeals"`
+    PendingDeals int            `json:"pendingDeals"`
+    ClosedDeals  int            `json:"closedDeals"`
+    RecentDeals  []PipelineDeal `json:"recentDeals"`
 }
 
 // PipelineDeal represents a deal in an investor's pipeline
 type PipelineDeal struct {
     ID              primitive.ObjectID `json:"id"`
-    FounderID       primitive.ObjectID `json:"founder_id"`
-    StartupName     string             `json:"startup_name"`
+    FounderID       primitive.ObjectID `json:"founderId"`
+    StartupName     string             `json:"startupName"`
     Industry        string             `json:"industry"`
     Status          string             `json:"status"`
     Stage           string             `json:"stage"`
-    MatchPercentage float64            `json:"match_percentage"`
-    UpdatedAt       time.Time          `json:"updated_at"`
+    MatchPercentage float64            `json:"matchPercentage"`
+    UpdatedAt       time.Time          `json:"updatedAt"`
+}
+
+// PortfolioSummary represents the totals and best performers of an investor's portfolio
+type PortfolioSummary struct {
+    TotalInvested float64        `json:"totalInvested"`
+    TotalStartups int            `json:"totalStartups"`
+    AvgReturn     float64        `json:"avgReturn"`
+    TopPerformers []TopPerformer `json:"topPerformers"`
+}
+
+// TopPerformer represents one of the best returning startups in a portfolio
+type TopPerformer struct {
+    ID           primitive.ObjectID `json:"id"`
+    Name         string             `json:"name"`
+    Industry     string             `json:"industry"`
+    Invested     float64            `json:"invested"`
+    CurrentValue float64            `json:"currentValue"`
+    ROI          float64            `json:"roi"`
+}
+
+// PerformancePoint represents the portfolio value on a given date
+type PerformancePoint struct {
+    Date  time.Time `json:"date"`
+    Value float64   `json:"value"`
 }
 
 // PerformanceMetrics represents performance metrics for an investor's portfolio
@@ -50,4 +74,10 @@ type PerformanceMetrics struct {
     TotalReturn      float64 `json:"totalReturn"`
     AnnualizedReturn float64 `json:"annualizedReturn"`
     Volatility       float64 `json:"volatility"`
-}
\ No newline at end of file
+}
+
+// PortfolioPerformance is the response of the portfolio performance endpoint
+type PortfolioPerformance struct {
+    PerformanceData []PerformancePoint `json:"performanceData"`
+    Metrics         PerformanceMetrics `json:"metrics"`
+}
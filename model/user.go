@@ -14,6 +14,7 @@ type User struct {
 	Email      string             `bson:"email"`
 	Password   string             `bson:"password"`
 	Roles      []string           `bson:"roles"`
+	Timezone   string             `bson:"timezone,omitempty"` // IANA zone meeting times are shown to the user in
 	CreatedAt  time.Time          `bson:"created_at"`
 	UpdatedAt  time.Time          `bson:"updated_at"`
 }
@@ -85,16 +86,118 @@ type MatchInvestorFounder struct {
 }
 
 type Meeting struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	InvestorID    primitive.ObjectID `bson:"investor_id" json:"investor_id"`
-	FounderID     primitive.ObjectID `bson:"founder_id" json:"founder_id"`
-	Title         string             `bson:"title" json:"title"`
-	StartTime     time.Time          `bson:"start_time" json:"start_time"`
-	EndTime       time.Time          `bson:"end_time" json:"end_time"`
-	GoogleMeetURL string             `bson:"google_meet_url" json:"google_meet_url"`
-	Notes         string             `bson:"notes" json:"notes"`
-	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
+	ID               primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	InvestorID       primitive.ObjectID   `bson:"investor_id" json:"investor_id"`
+	FounderID        primitive.ObjectID   `bson:"founder_id" json:"founder_id"`
+	Title            string               `bson:"title" json:"title" validate:"required,max=200"`
+	StartTime        time.Time            `bson:"start_time" json:"start_time" validate:"required,future"`
+	EndTime          time.Time            `bson:"end_time" json:"end_time" validate:"required,after=StartTime"`
+	GoogleMeetURL    string               `bson:"google_meet_url" json:"google_meet_url"`
+	MeetingURL       string               `bson:"meeting_url,omitempty" json:"meeting_url,omitempty"`             // Meet link, or a stable link derived from the ID
+	CalendarProvider string               `bson:"calendar_provider,omitempty" json:"calendar_provider,omitempty"` // provider the event was created with
+	CalendarEventID  string               `bson:"calendar_event_id,omitempty" json:"calendar_event_id,omitempty"` // the provider's ID for the event
+	Notes            string               `bson:"notes" json:"notes"`
+	Participants     []primitive.ObjectID `bson:"participants,omitempty" json:"participants,omitempty"`
+	BookingLinkID    primitive.ObjectID   `bson:"booking_link_id,omitempty" json:"booking_link_id,omitempty"`          // link the meeting was booked through
+	Timezone         string               `bson:"timezone,omitempty" json:"timezone,omitempty" validate:"timezone"`    // IANA zone the meeting recurs in; the organizer's, else UTC
+	Recurrence       string               `bson:"recurrence,omitempty" json:"recurrence,omitempty" validate:"max=500"` // RRULE value, e.g. FREQ=MONTHLY;BYDAY=1MO
+	Exceptions       []time.Time          `bson:"exceptions,omitempty" json:"exceptions,omitempty"`                    // original starts of cancelled occurrences
+	Overrides        []OccurrenceOverride `bson:"overrides,omitempty" json:"overrides,omitempty"`                      // occurrences edited on their own
+	SeriesEnd        time.Time            `bson:"series_end" json:"-"`                                                 // end of the last occurrence, for overlap queries
+	Reminders        []int                `bson:"reminders" json:"reminders" validate:"max=5"`                         // minutes before each occurrence; null for the configured defaults, [] for none
+	RSVPs            []RSVP               `bson:"rsvps,omitempty" json:"rsvps,omitempty"`                              // participants' answers; set through the RSVP route
+	Outcomes         []MeetingOutcome     `bson:"outcomes,omitempty" json:"outcomes,omitempty"`                        // attendance and outcome of occurrences that took place
+	CreatedAt        time.Time            `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time            `bson:"updated_at" json:"updated_at"`
+}
+
+// OccurrenceOverride changes one occurrence of a recurring meeting, named by
+// the start it has in the series
+type OccurrenceOverride struct {
+	OriginalStart time.Time `bson:"original_start" json:"original_start" validate:"required"`
+	StartTime     time.Time `bson:"start_time" json:"start_time" validate:"required"`
+	EndTime       time.Time `bson:"end_time" json:"end_time" validate:"required,after=StartTime"`
+	Title         string    `bson:"title,omitempty" json:"title,omitempty" validate:"max=200"`
+	Notes         string    `bson:"notes,omitempty" json:"notes,omitempty" validate:"max=5000"`
+}
+
+// RSVP is a participant's answer to a meeting invitation. Participants who
+// have not answered have none.
+type RSVP struct {
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Status      string             `bson:"status" json:"status" validate:"required,oneof=accepted declined tentative"`
+	Comment     string             `bson:"comment,omitempty" json:"comment,omitempty" validate:"max=500"`
+	RespondedAt time.Time          `bson:"responded_at" json:"responded_at"`
+}
+
+// MeetingOutcome records who attended an occurrence of a meeting and how it
+// went
+type MeetingOutcome struct {
+	OccurrenceStart time.Time          `bson:"occurrence_start" json:"occurrence_start"` // original start of the occurrence; the meeting's start when it does not recur
+	Attendance      []Attendance       `bson:"attendance" json:"attendance" validate:"max=50"`
+	Outcome         string             `bson:"outcome" json:"outcome" validate:"required,oneof=positive neutral negative"`
+	Summary         string             `bson:"summary,omitempty" json:"summary,omitempty" validate:"max=2000"`
+	RecordedBy      primitive.ObjectID `bson:"recorded_by" json:"recorded_by"`
+	RecordedAt      time.Time          `bson:"recorded_at" json:"recorded_at"`
+}
+
+// Attendance is whether a participant turned up to a meeting
+type Attendance struct {
+	UserID primitive.ObjectID `bson:"user_id" json:"user_id" validate:"required"`
+	Status string             `bson:"status" json:"status" validate:"required,oneof=attended late no_show"`
+}
+
+// MeetingReminder records that a reminder went out for an occurrence of a
+// meeting, so each is sent once however many servers run
+type MeetingReminder struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty"`
+	MeetingID       primitive.ObjectID `bson:"meeting_id"`
+	OccurrenceStart time.Time          `bson:"occurrence_start"`
+	Before          int                `bson:"before"` // minutes before the occurrence
+	SentAt          time.Time          `bson:"sent_at"`
+}
+
+// MeetingNote is a note taken in a meeting. Shared notes are shown to, and
+// can be edited by, every participant; private ones only by their author.
+type MeetingNote struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	MeetingID   primitive.ObjectID `bson:"meeting_id" json:"meeting_id"`
+	AuthorID    primitive.ObjectID `bson:"author_id" json:"author_id"`
+	Visibility  string             `bson:"visibility" json:"visibility" validate:"required,oneof=private shared"`
+	Sections    []NoteSection      `bson:"sections" json:"sections" validate:"min=1,max=20"`
+	ActionItems []ActionItem       `bson:"action_items" json:"action_items" validate:"max=50"`
+	Version     int                `bson:"version" json:"version"`                     // counts edits; send it back to detect concurrent ones
+	EditedBy    primitive.ObjectID `bson:"edited_by" json:"edited_by"`                 // who made this version
+	History     []NoteRevision     `bson:"history,omitempty" json:"history,omitempty"` // earlier versions, oldest first
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// NoteSection is a headed part of a meeting note, such as an agenda item
+type NoteSection struct {
+	Heading string `bson:"heading" json:"heading" validate:"required,max=200"`
+	Body    string `bson:"body" json:"body" validate:"max=10000"`
+}
+
+// ActionItem is a follow-up agreed in a meeting. Once turned into a task it
+// is done when the task is completed.
+type ActionItem struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"` // kept across edits when sent back
+	Description string             `bson:"description" json:"description" validate:"required,max=200"`
+	AssigneeID  primitive.ObjectID `bson:"assignee_id,omitempty" json:"assignee_id,omitempty"`
+	DueDate     time.Time          `bson:"due_date,omitempty" json:"due_date,omitempty"`
+	Done        bool               `bson:"done" json:"done"`
+	TaskID      primitive.ObjectID `bson:"task_id,omitempty" json:"task_id,omitempty"`
+}
+
+// NoteRevision is a meeting note as it was before an edit
+type NoteRevision struct {
+	Version     int                `bson:"version" json:"version"`
+	Visibility  string             `bson:"visibility" json:"visibility"`
+	Sections    []NoteSection      `bson:"sections" json:"sections"`
+	ActionItems []ActionItem       `bson:"action_items" json:"action_items"`
+	EditedBy    primitive.ObjectID `bson:"edited_by" json:"edited_by"` // who made this version
+	EditedAt    time.Time          `bson:"edited_at" json:"edited_at"`
 }
 
 // Notification Model
@@ -201,21 +304,24 @@ type RunwayMetrics struct {
 // Document model for storing files
 type Document struct {
 	ID   primitive.ObjectID `bson:"_id,omitempty"`
-	Name string             `bson:"name"`
-	URL  string             `bson:"url"`
+	Name string             `bson:"name" validate:"required,max=200"`
+	URL  string             `bson:"url" validate:"required,url"`
 	Date time.Time          `bson:"date"`
 }
 
 // Task model for investment tracking
 type Task struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`
-	Title     string             `bson:"title"`
-	Completed bool               `bson:"completed"`
-	DueDate   time.Time          `bson:"due_date"`
-	Priority  string             `bson:"priority"`
-	CreatedBy primitive.ObjectID `bson:"created_by"`
-	CreatedAt time.Time          `bson:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at"`
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	Title        string             `bson:"title" validate:"required,max=200"`
+	Completed    bool               `bson:"completed"`
+	DueDate      time.Time          `bson:"due_date"`
+	Priority     string             `bson:"priority" validate:"oneof=low medium high"`
+	CreatedBy    primitive.ObjectID `bson:"created_by"`
+	AssignedTo   primitive.ObjectID `bson:"assigned_to,omitempty"`
+	MeetingID    primitive.ObjectID `bson:"meeting_id,omitempty"`     // meeting whose action item the task was made from
+	ActionItemID primitive.ObjectID `bson:"action_item_id,omitempty"` // completing the task marks it done
+	CreatedAt    time.Time          `bson:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at"`
 }
 
 // Note model for deal flow notes
@@ -230,6 +336,7 @@ type Note struct {
 type Activity struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty"`
 	InvestorID  primitive.ObjectID `bson:"investor_id"`
+	DealID      primitive.ObjectID `bson:"deal_id,omitempty"` // deal whose timeline the activity is on, if any
 	Type        string             `bson:"type"`
 	Description string             `bson:"description"`
 	Date        time.Time          `bson:"date"`
@@ -10,9 +10,14 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
-// Secret key for signing JWT (Use an environment variable in production!)
+// Secret key for signing JWT, set from configuration at startup
 var jwtSecret = []byte("your-secure-secret-key")
 
+// SetJWTSecret replaces the key used to sign and verify tokens
+func SetJWTSecret(secret string) {
+	jwtSecret = []byte(secret)
+}
+
 // Claims struct for JWT payload
 type Claims struct {
 	UserID string   `json:"user_id"`